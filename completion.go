@@ -0,0 +1,344 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gosirys/gobuster/libgobuster"
+	"github.com/gosirys/gobuster/libgobuster/urlnorm"
+)
+
+// completionFlag describes a flag for the completion scripts
+type completionFlag struct {
+	name   string
+	usage  string
+	isBool bool
+	// values lists the accepted values, files and dirs complete paths
+	values []string
+	files  bool
+	dirs   bool
+}
+
+// flagValues lists the values of the flags accepting a fixed set
+var flagValues = map[string][]string{
+	"m":                {libgobuster.ModeDir, libgobuster.ModeDNS, libgobuster.ModeDNSDir},
+	"agent-strategy":   {libgobuster.AgentPerRequest, libgobuster.AgentPerHost, libgobuster.AgentPerRun},
+	"wildcard-charset": {"hex", "lower", "alpha", "alnum"},
+	"syslog":           {"journald", "udp://", "tcp://", "unix:///dev/log"},
+	"wayback-query":    {string(urlnorm.QueryKeys), string(urlnorm.QueryFull), string(urlnorm.QueryIgnore)},
+	"evasion":          libgobuster.EvasionNames,
+	"format":           {libgobuster.FormatPlain, libgobuster.FormatHTTPX, libgobuster.FormatHTTPXAnnotated},
+}
+
+// fileFlags and dirFlags take a path
+var (
+	fileFlags = map[string]bool{"w": true, "wordlist-dirs": true, "wordlist-files": true, "o": true, "random-agent": true, "targeturls": true, "waybackurls": true, "secrets-file": true, "pac-file": true, "debug-http": true, "results-socket": true, "takeover-fingerprints": true}
+	dirFlags  = map[string]bool{"of": true}
+)
+
+// completionCommands are the commands that are not scans
+var completionCommands = map[string][]completionFlag{
+	"history": {
+		{name: "of", usage: "Path to output folder directory", dirs: true},
+		{name: "m", usage: "Mode of the all time matches to search (dir, dns)", values: []string{libgobuster.ModeDir, libgobuster.ModeDNS}},
+	},
+	"report": {
+		{name: "of", usage: "Path to output folder directory", dirs: true},
+	},
+	"compare": {
+		{name: "of", usage: "Path to output folder directory, needed when comparing run ids", dirs: true},
+		{name: "m", usage: "Mode of the all time matches holding the run ids (dir, dns)", values: []string{libgobuster.ModeDir, libgobuster.ModeDNS}},
+		{name: "format", usage: "Report format: markdown or html", values: []string{"markdown", "html"}},
+	},
+	"merge": {
+		{name: "of", usage: "Path to the output folder the others are merged into, created if needed", dirs: true},
+	},
+	"triage": {
+		{name: "of", usage: "Path to output folder directory", dirs: true},
+		{name: "status", usage: "Status of the findings", values: libgobuster.TriageStatuses},
+		{name: "note", usage: "Note added to the findings"},
+		{name: "author", usage: "Author of the status and note"},
+	},
+	"completion": nil,
+}
+
+// completionFlags returns the flags of a scan command, an empty
+// command returns the flat flag set
+func completionFlags(command string) []completionFlag {
+	if flags, ok := completionCommands[command]; ok {
+		return flags
+	}
+
+	fs := flag.NewFlagSet(command, flag.ContinueOnError)
+	o := libgobuster.NewOptions()
+	addCommonFlags(fs, o)
+	shared := command == ""
+	if shared {
+		fs.String("m", libgobuster.ModeDir, "Mode: dir, dns or dns+dir")
+	}
+	if shared || command == libgobuster.ModeDir || command == libgobuster.ModeDNSDir {
+		addDirFlags(fs, o, shared)
+	}
+	if shared || command == libgobuster.ModeDNS || command == libgobuster.ModeDNSDir {
+		addDNSFlags(fs, o, shared)
+	}
+
+	var flags []completionFlag
+	fs.VisitAll(func(f *flag.Flag) {
+		cf := completionFlag{
+			name:   f.Name,
+			usage:  f.Usage,
+			values: flagValues[f.Name],
+			files:  fileFlags[f.Name],
+			dirs:   dirFlags[f.Name],
+		}
+		if b, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && b.IsBoolFlag() {
+			cf.isBool = true
+		}
+		flags = append(flags, cf)
+	})
+	return flags
+}
+
+// completionCommandNames returns every command in a stable order
+func completionCommandNames() []string {
+	var names []string
+	for _, c := range subcommands {
+		names = append(names, c.name)
+	}
+	return names
+}
+
+func completionCommand(args []string) (int, error) {
+	if len(args) != 1 {
+		fmt.Fprintf(os.Stderr, "Usage: %s completion bash|zsh|fish\n", os.Args[0])
+		return exitOptionsError, nil
+	}
+	program := filepath.Base(os.Args[0])
+
+	var script string
+	switch args[0] {
+	case "bash":
+		script = bashCompletion(program)
+	case "zsh":
+		script = zshCompletion(program)
+	case "fish":
+		script = fishCompletion(program)
+	default:
+		return 0, fail(exitOptionsError, "[!] Unsupported shell %q, use bash, zsh or fish", args[0])
+	}
+	fmt.Print(script)
+	return exitFindings, nil
+}
+
+// shellFunctionName returns the program name usable in a function name
+func shellFunctionName(program string) string {
+	return "_" + strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, program)
+}
+
+func bashCompletion(program string) string {
+	fn := shellFunctionName(program)
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "# bash completion for %s, load with: source <(%s completion bash)\n", program, program)
+	fmt.Fprintf(buf, "%s() {\n", fn)
+	fmt.Fprintf(buf, "    local cur=\"${COMP_WORDS[COMP_CWORD]}\" prev=\"${COMP_WORDS[COMP_CWORD-1]}\" cmd=\"${COMP_WORDS[1]}\" flags\n")
+
+	// values of the previous flag by command, the flags taking other
+	// values in a command than in the scans are completed by command
+	var files, dirs []string
+	values := make(map[string][]string)
+	commandValues := make(map[string]map[string][]string)
+	for _, command := range append(completionCommandNames(), "") {
+		for _, f := range completionFlags(command) {
+			switch {
+			case f.files:
+				files = append(files, "-"+f.name)
+			case f.dirs:
+				dirs = append(dirs, "-"+f.name)
+			case len(f.values) > 0:
+				name := "-" + f.name
+				if commandValues[name] == nil {
+					commandValues[name] = make(map[string][]string)
+				}
+				commandValues[name][command] = f.values
+				values[name] = f.values
+			}
+		}
+	}
+	fmt.Fprintf(buf, "    case \"$prev\" in\n")
+	if len(files) > 0 {
+		fmt.Fprintf(buf, "        %s) COMPREPLY=( $(compgen -f -- \"$cur\") ); return;;\n", strings.Join(uniqueSorted(files), "|"))
+	}
+	if len(dirs) > 0 {
+		fmt.Fprintf(buf, "        %s) COMPREPLY=( $(compgen -d -- \"$cur\") ); return;;\n", strings.Join(uniqueSorted(dirs), "|"))
+	}
+	for _, name := range sortedValueFlags(values) {
+		shared := strings.Join(values[name], " ")
+		var own []string
+		for command, v := range commandValues[name] {
+			if command != "" && strings.Join(v, " ") != shared {
+				own = append(own, command)
+			}
+		}
+		if len(own) == 0 {
+			fmt.Fprintf(buf, "        %s) COMPREPLY=( $(compgen -W \"%s\" -- \"$cur\") ); return;;\n", name, shared)
+			continue
+		}
+		sort.Strings(own)
+		fmt.Fprintf(buf, "        %s)\n", name)
+		fmt.Fprintf(buf, "            case \"$cmd\" in\n")
+		for _, command := range own {
+			fmt.Fprintf(buf, "                %s) COMPREPLY=( $(compgen -W \"%s\" -- \"$cur\") );;\n", command, strings.Join(commandValues[name][command], " "))
+		}
+		fmt.Fprintf(buf, "                *) COMPREPLY=( $(compgen -W \"%s\" -- \"$cur\") );;\n", shared)
+		fmt.Fprintf(buf, "            esac\n")
+		fmt.Fprintf(buf, "            return;;\n")
+	}
+	fmt.Fprintf(buf, "    esac\n")
+
+	fmt.Fprintf(buf, "    if [[ $COMP_CWORD -eq 1 && \"$cur\" != -* ]]; then\n")
+	fmt.Fprintf(buf, "        COMPREPLY=( $(compgen -W \"%s\" -- \"$cur\") ); return\n", strings.Join(completionCommandNames(), " "))
+	fmt.Fprintf(buf, "    fi\n")
+
+	fmt.Fprintf(buf, "    case \"$cmd\" in\n")
+	for _, command := range completionCommandNames() {
+		if command == "completion" {
+			fmt.Fprintf(buf, "        completion) COMPREPLY=( $(compgen -W \"bash zsh fish\" -- \"$cur\") ); return;;\n")
+			continue
+		}
+		fmt.Fprintf(buf, "        %s) flags=\"%s\";;\n", command, flagNames(completionFlags(command)))
+	}
+	fmt.Fprintf(buf, "        *) flags=\"%s\";;\n", flagNames(completionFlags("")))
+	fmt.Fprintf(buf, "    esac\n")
+	fmt.Fprintf(buf, "    COMPREPLY=( $(compgen -W \"$flags\" -- \"$cur\") )\n")
+	fmt.Fprintf(buf, "}\n")
+	fmt.Fprintf(buf, "complete -o default -F %s %s\n", fn, program)
+	return buf.String()
+}
+
+func zshCompletion(program string) string {
+	fn := shellFunctionName(program)
+	quote := strings.NewReplacer("'", `'\''`, "[", `\[`, "]", `\]`, ":", `\:`)
+	arguments := func(flags []completionFlag) string {
+		var specs []string
+		for _, f := range flags {
+			spec := fmt.Sprintf("-%s[%s]", f.name, quote.Replace(f.usage))
+			switch {
+			case f.isBool:
+			case f.files:
+				spec += ":file:_files"
+			case f.dirs:
+				spec += ":directory:_files -/"
+			case len(f.values) > 0:
+				spec += fmt.Sprintf(":value:(%s)", strings.Join(f.values, " "))
+			default:
+				spec += ":value:"
+			}
+			specs = append(specs, fmt.Sprintf("'%s'", spec))
+		}
+		return strings.Join(specs, " \\\n                ")
+	}
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "#compdef %s\n", program)
+	fmt.Fprintf(buf, "# zsh completion for %s, load with: source <(%s completion zsh)\n", program, program)
+	fmt.Fprintf(buf, "%s() {\n", fn)
+	fmt.Fprintf(buf, "    local -a commands\n")
+	fmt.Fprintf(buf, "    commands=(\n")
+	for _, c := range subcommands {
+		fmt.Fprintf(buf, "        '%s:%s'\n", quote.Replace(c.name), quote.Replace(c.description))
+	}
+	fmt.Fprintf(buf, "    )\n")
+	fmt.Fprintf(buf, "    if (( CURRENT == 2 )) && [[ $words[2] != -* ]]; then\n")
+	fmt.Fprintf(buf, "        _describe 'command' commands\n")
+	fmt.Fprintf(buf, "        return\n")
+	fmt.Fprintf(buf, "    fi\n")
+	fmt.Fprintf(buf, "    case $words[2] in\n")
+	for _, command := range completionCommandNames() {
+		fmt.Fprintf(buf, "        %s)\n", command)
+		fmt.Fprintf(buf, "            words=(${words[2,-1]}); (( CURRENT-- ))\n")
+		if command == "completion" {
+			fmt.Fprintf(buf, "            _arguments '1:shell:(bash zsh fish)';;\n")
+			continue
+		}
+		fmt.Fprintf(buf, "            _arguments %s;;\n", arguments(completionFlags(command)))
+	}
+	fmt.Fprintf(buf, "        *)\n")
+	fmt.Fprintf(buf, "            _arguments %s;;\n", arguments(completionFlags("")))
+	fmt.Fprintf(buf, "    esac\n")
+	fmt.Fprintf(buf, "}\n")
+	fmt.Fprintf(buf, "compdef %s %s\n", fn, program)
+	return buf.String()
+}
+
+func fishCompletion(program string) string {
+	quote := strings.NewReplacer(`\`, `\\`, "'", `\'`)
+	commands := strings.Join(completionCommandNames(), " ")
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "# fish completion for %s, load with: %s completion fish | source\n", program, program)
+	fmt.Fprintf(buf, "complete -c %s -f\n", program)
+	for _, c := range subcommands {
+		fmt.Fprintf(buf, "complete -c %s -n '__fish_use_subcommand' -a '%s' -d '%s'\n", program, c.name, quote.Replace(c.description))
+	}
+	fmt.Fprintf(buf, "complete -c %s -n '__fish_seen_subcommand_from completion' -a 'bash zsh fish'\n", program)
+
+	write := func(condition string, flags []completionFlag) {
+		for _, f := range flags {
+			line := fmt.Sprintf("complete -c %s -n '%s' -o '%s' -d '%s'", program, condition, f.name, quote.Replace(f.usage))
+			switch {
+			case f.isBool:
+			case f.files:
+				line += " -r -F"
+			case f.dirs:
+				line += " -x -a '(__fish_complete_directories)'"
+			case len(f.values) > 0:
+				line += fmt.Sprintf(" -x -a '%s'", strings.Join(f.values, " "))
+			default:
+				line += " -x"
+			}
+			fmt.Fprintln(buf, line)
+		}
+	}
+	for _, command := range completionCommandNames() {
+		if command == "completion" {
+			continue
+		}
+		write("__fish_seen_subcommand_from "+command, completionFlags(command))
+	}
+	write("not __fish_seen_subcommand_from "+commands, completionFlags(""))
+	return buf.String()
+}
+
+// flagNames returns the dash prefixed names of the flags
+func flagNames(flags []completionFlag) string {
+	var names []string
+	for _, f := range flags {
+		names = append(names, "-"+f.name)
+	}
+	return strings.Join(names, " ")
+}
+
+func uniqueSorted(values []string) []string {
+	unique := libgobuster.NewSet(values...)
+	return unique.Values()
+}
+
+func sortedValueFlags(values map[string][]string) []string {
+	var names []string
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// checkScriptSyntax parses a completion script with its shell, when it
+// is installed
+func checkScriptSyntax(t *testing.T, shell, script string) {
+	t.Helper()
+	path, err := exec.LookPath(shell)
+	if err != nil {
+		return
+	}
+	file := filepath.Join(t.TempDir(), "completion."+shell)
+	if err := ioutil.WriteFile(file, []byte(script), 0600); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if out, err := exec.Command(path, "-n", file).CombinedOutput(); err != nil {
+		t.Fatalf("invalid %s script: %v\n%s", shell, err, out)
+	}
+}
+
+func TestBashCompletion(t *testing.T) {
+	t.Parallel()
+
+	script := bashCompletion("gobuster")
+	checkScriptSyntax(t, "bash", script)
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		t.Skip("bash is not installed")
+	}
+	file := filepath.Join(t.TempDir(), "completion.bash")
+	if err := ioutil.WriteFile(file, []byte(script), 0600); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+
+	tt := []struct {
+		testName string
+		words    string
+		expected string
+	}{
+		{"Commands", `gobuster "tr"`, "triage"},
+		{"Flag values of the scans", `gobuster dir -m ""`, "dir dns dns+dir"},
+		{"Flag values of a command", `gobuster history -m ""`, "dir dns"},
+		{"Flag values of the flat flags", `gobuster -m ""`, "dir dns dns+dir"},
+		{"Flags of a command", `gobuster triage "-st"`, "-status"},
+		{"Shells", `gobuster completion ""`, "bash zsh fish"},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			// complete the last word the way bash does on a tab
+			cmd := `source "$1"; COMP_WORDS=(` + x.words + `); COMP_CWORD=$(( ${#COMP_WORDS[@]} - 1 )); _gobuster; echo "${COMPREPLY[*]}"`
+			out, err := exec.Command(bash, "-c", cmd, "bash", file).CombinedOutput()
+			if err != nil {
+				t.Fatalf("got error: %v\n%s", err, out)
+			}
+			if got := strings.TrimSpace(string(out)); got != x.expected {
+				t.Fatalf("expected %q, got %q", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestZshCompletion(t *testing.T) {
+	t.Parallel()
+
+	script := zshCompletion("gobuster")
+	checkScriptSyntax(t, "zsh", script)
+	for _, expected := range []string{
+		"#compdef gobuster\n",
+		"'triage:Mark the matches recorded in an output folder interesting, false-positive or done, with notes'\n",
+		"'-status[Status of the findings]:value:(interesting false-positive done)'",
+		"'-of[Path to output folder directory]:directory:_files -/'",
+		"'-m[Mode of the all time matches to search (dir, dns)]:value:(dir dns)'",
+		"_arguments '1:shell:(bash zsh fish)';;\n",
+		"compdef _gobuster gobuster\n",
+	} {
+		if !strings.Contains(script, expected) {
+			t.Fatalf("expected the script to contain %q", expected)
+		}
+	}
+}
+
+func TestFishCompletion(t *testing.T) {
+	t.Parallel()
+
+	script := fishCompletion("gobuster")
+	checkScriptSyntax(t, "fish", script)
+	for _, expected := range []string{
+		"complete -c gobuster -f\n",
+		"complete -c gobuster -n '__fish_use_subcommand' -a 'triage' -d 'Mark the matches recorded in an output folder interesting, false-positive or done, with notes'\n",
+		"complete -c gobuster -n '__fish_seen_subcommand_from completion' -a 'bash zsh fish'\n",
+		"complete -c gobuster -n '__fish_seen_subcommand_from history' -o 'm' -d 'Mode of the all time matches to search (dir, dns)' -x -a 'dir dns'\n",
+		"complete -c gobuster -n '__fish_seen_subcommand_from triage' -o 'of' -d 'Path to output folder directory' -x -a '(__fish_complete_directories)'\n",
+		"-o 'w' -d 'Path to the wordlist, may be gzip compressed or a member of an archive (lists.zip:common.txt)' -r -F\n",
+	} {
+		if !strings.Contains(script, expected) {
+			t.Fatalf("expected the script to contain %q", expected)
+		}
+	}
+}
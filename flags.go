@@ -0,0 +1,327 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gosirys/gobuster/libgobuster"
+	"github.com/gosirys/gobuster/libgobuster/urlnorm"
+)
+
+// modeNote returns a function annotating the help of a mode specific
+// flag when all modes share one flag set
+func modeNote(shared bool, mode string) func(string) string {
+	return func(usage string) string {
+		if !shared {
+			return usage
+		}
+		return fmt.Sprintf("%s (%s mode only)", usage, mode)
+	}
+}
+
+// addCommonFlags registers the flags of every scan mode
+func addCommonFlags(fs *flag.FlagSet, o *libgobuster.Options) {
+	fs.IntVar(&o.Threads, "t", 10, "Number of concurrent threads")
+	fs.StringVar(&o.Wordlist, "w", "", "Path to the wordlist, may be gzip compressed or a member of an archive (lists.zip:common.txt)")
+	fs.StringVar(&o.OutputFolder, "of", "", "Path to output folder directory")
+	fs.StringVar(&o.OutputFilename, "o", "", "Output file to write results to (defaults to stdout)")
+	fs.StringVar(&o.URL, "u", "", "The target URL or Domain")
+	fs.StringVar(&o.DoHURL, "doh-url", "", "Resolve hostnames over DNS-over-HTTPS using this endpoint (eg. https://cloudflare-dns.com/dns-query)")
+	fs.StringVar(&o.DoTServer, "dot-server", "", "Resolve hostnames over DNS-over-TLS using this server [host(:port)]")
+	fs.StringVar(&o.Columns, "columns", "", "Comma separated columns of the console output, in their order: time, status, size, url, redirect, title, type, ips, cname and meta (the annotations), by default time,status,size,url,redirect,meta in dir mode")
+	fs.StringVar(&o.Format, "format", libgobuster.FormatPlain, "Output format of the matches: plain, httpx (one url per line, combine with -q to pipe into other tools) or httpx-annotated ([status] [length] after the url)")
+	fs.BoolVar(&o.Verbose, "v", false, "Verbose output (errors)")
+	fs.BoolVar(&o.Quiet, "q", false, "Don't print the banner and other noise")
+	fs.BoolVar(&o.WildcardForced, "fw", false, "Force continued operation when wildcard found")
+	fs.BoolVar(&o.NoProgress, "np", false, "Don't display progress")
+	fs.IntVar(&o.WordlistBufferSize, "wordlist-buffer", 1024*1024, "Maximum length in bytes of a single wordlist line")
+	fs.IntVar(&o.Warmup, "warmup", 0, "Send the first N requests over -warmup-threads only, measuring latency and errors before ramping up to -t threads")
+	fs.IntVar(&o.WarmupThreads, "warmup-threads", 1, "Number of concurrent threads of the -warmup requests")
+	fs.DurationVar(&o.PerTargetTimeout, "per-target-timeout", 0, "Move on from a target whose scan takes longer than this, 0 for no limit")
+	fs.StringVar(&o.Syslog, "syslog", "", "Forward matches and the run summary to syslog (udp://host:514, tcp://host:514, unix:///dev/log) or journald")
+	fs.StringVar(&o.ResultsSocket, "results-socket", "", "Stream matches as NDJSON to the consumers of this unix socket, or to this named pipe if it exists")
+	fs.StringVar(&o.OnResult, "on-result", "", "Command to run for each match, split into arguments like a shell does without running one, supports {url}, {status}, {size} and {redirect} placeholders")
+	fs.IntVar(&o.OnResultConcurrency, "on-result-concurrency", 5, "Maximum number of -on-result commands running at once")
+	fs.StringVar(&o.Plugins, "plugin", "", "Comma separated Go plugins (.so built with -buildmode=plugin) exporting a libgobuster.Extension with custom Process and Filter logic")
+	fs.BoolVar(&o.AllTimeDedup, "all-time-dedup", false, "Append only the endpoint and status combinations missing from the all time matches, the others are annotated with the day they were first seen")
+	fs.DurationVar(&o.DebugStats, "debug-stats", 0, "Log the heap, goroutines, queued words and GC stats at this interval to diagnose long scans, 0 never")
+	fs.StringVar(&o.ErrorThreshold, "error-threshold", "", "Abort the scan when the error rate over the error window exceeds this percentage (eg. 25%)")
+	fs.IntVar(&o.ErrorWindow, "error-window", 100, "Number of most recent requests the error threshold is evaluated over")
+	fs.StringVar(&o.RotateSize, "rotate-size", "", "Compress the output files into numbered .gz archives once they grow past this size (eg. 100MB)")
+	fs.StringVar(&o.ScanProfile, "profile", "", "Load the options of this profile saved in the output folder, flags given on the command line take precedence")
+	fs.StringVar(&o.SaveScanProfile, "save-profile", "", "Save the options of this run as a profile of the output folder once they are validated")
+	fs.BoolVar(&o.PrintCmd, "print-cmd", false, "Print the command line equivalent to the run, defaults and profile applied, and store it with the options as JSON in the output_commands folder")
+}
+
+// addDirFlags registers the flags of dir mode
+func addDirFlags(fs *flag.FlagSet, o *libgobuster.Options, shared bool) {
+	note := modeNote(shared, libgobuster.ModeDir)
+	fs.StringVar(&o.ExcludedStatusCodes, "x", "", note("Excluded status codes"))
+	fs.StringVar(&o.Cookies, "c", "", note("Cookies to use for the requests (or set "+libgobuster.EnvCookies+")"))
+	fs.StringVar(&o.Username, "U", "", note("Username for Basic Auth"))
+	fs.StringVar(&o.Password, "P", "", note("Password for Basic Auth (or set "+libgobuster.EnvPassword+")"))
+	fs.StringVar(&o.BearerToken, "bearer", "", note("Bearer token for the Authorization header (or set "+libgobuster.EnvBearerToken+")"))
+	fs.StringVar(&o.SecretsFile, "secrets-file", "", note("Path to a key=value file holding password, bearer and cookies secrets, - to read it from stdin"))
+	fs.StringVar(&o.Extensions, "ext", "", note("File extension(s) to search for"))
+	fs.StringVar(&o.BasePath, "base-path", "", note("Path of the application root on the target (eg. /app/v2/), the matches are recorded relative to it"))
+	fs.StringVar(&o.UserAgent, "a", "", note("Set the User-Agent string"))
+	fs.StringVar(&o.Proxy, "p", "", note("Proxy to use for requests [http(s)://host:port]"))
+	fs.StringVar(&o.PACURL, "pac-url", "", note("URL of the proxy auto-config (PAC) file picking the proxy of every host, see -pac-file"))
+	fs.StringVar(&o.PACFile, "pac-file", "", note("Path to the proxy auto-config (PAC) file picking the proxy of every host, its PROXY, HTTPS and SOCKS5 answers are used"))
+	fs.BoolVar(&o.Tor, "tor", false, note("Send the requests through the local Tor SOCKS proxy"))
+	fs.StringVar(&o.TorProxy, "tor-proxy", "127.0.0.1:9050", note("Address of the Tor SOCKS proxy"))
+	fs.StringVar(&o.TorControl, "tor-control", "127.0.0.1:9051", note("Address of the Tor control port used to renew circuits"))
+	fs.StringVar(&o.TorPassword, "tor-password", "", note("Password of the Tor control port, the cookie file is used when empty"))
+	fs.IntVar(&o.TorRenewRequests, "tor-renew-requests", 0, note("Ask Tor for a new circuit (NEWNYM) every N requests, 0 never"))
+	fs.DurationVar(&o.TorRenewEvery, "tor-renew-every", 0, note("Ask Tor for a new circuit (NEWNYM) at this interval, 0 never"))
+	fs.DurationVar(&o.Timeout, "to", 10*time.Second, note("HTTP Timeout in seconds"))
+	fs.BoolVar(&o.FollowRedirect, "r", false, note("Follow redirects"))
+	fs.BoolVar(&o.ShowRedirectChain, "show-redirect-chain", false, note("Record the full chain of followed redirects (with -r)"))
+	fs.BoolVar(&o.Expanded, "e", false, note("Expanded mode, print full URLs"))
+	fs.BoolVar(&o.NoStatus, "n", false, note("Don't print status codes"))
+	fs.BoolVar(&o.IncludeLength, "l", false, note("Include the length of the body in the output"))
+	fs.BoolVar(&o.UseSlash, "f", false, note("Append a forward-slash to each directory request"))
+	fs.BoolVar(&o.InsecureSSL, "k", false, note("Skip SSL certificate verification"))
+	fs.Int64Var(&o.MaxResponseSize, "max-response-size", libgobuster.DefaultMaxResponseSize, note("Stop reading response bodies after this many bytes, 0 reads them completely"))
+	fs.DurationVar(&o.StreamTimeout, "stream-timeout", 3*time.Second, note("Stop reading the bodies of streaming responses (event streams, long polls) after this long, or once they stall that long, and record them as streaming, 0 reads them until -timeout"))
+	fs.BoolVar(&o.NoCharset, "no-charset", false, note("Don't convert response bodies in other charsets to UTF-8 before filtering"))
+	fs.StringVar(&o.WordlistDirs, "wordlist-dirs", "", note("Path to a wordlist of directories, requested with a trailing slash after -w"))
+	fs.StringVar(&o.WordlistFiles, "wordlist-files", "", note("Path to a wordlist of files, requested with every extension of -ext after -w and -wordlist-dirs, the words with an extension as is"))
+	fs.StringVar(&o.WaybackUrls, "waybackurls", "", note("Path to the wayback urls"))
+	fs.StringVar(&o.WaybackQuery, "wayback-query", string(urlnorm.QueryKeys), note("When wayback urls of the same path are duplicates: keys (same parameter names), full (same query) or ignore (any query)"))
+	fs.StringVar(&o.WaybackStripExts, "wayback-strip-exts", strings.Join(urlnorm.DefaultStaticExtensions, ","), note("Comma separated extensions of the wayback files reduced to their directory, empty keeps every file"))
+	fs.BoolVar(&o.CommonCrawl, "commoncrawl", false, note("Request the urls of the target host indexed by Common Crawl, deduplicated along with the wayback urls"))
+	fs.StringVar(&o.TargetUrls, "targeturls", "", note("Path to a file of target urls to run dir mode against one after the other, each optionally followed by comma separated tags recording its matches and summary per tag (eg. https://a.example.com admin,prod)"))
+	fs.StringVar(&o.CIDR, "cidr", "", note("Run dir mode against every host of this address range (eg. 10.0.0.0/24)"))
+	fs.StringVar(&o.Ports, "ports", "80,443", note("Comma separated ports of the -cidr hosts, 443 and 8443 use https"))
+	fs.DurationVar(&o.TCPCheck, "tcp-check", 0, note("Skip the -cidr hosts not accepting a TCP connection within this timeout, 0 scans every host"))
+	fs.DurationVar(&o.LivenessTimeout, "liveness-timeout", 5*time.Second, note("Skip the -targeturls and -cidr targets not answering a first request within this timeout, recording why in their summary, 0 scans every target"))
+	fs.StringVar(&o.RandomAgent, "random-agent", "", note("Path to the random agent file"))
+	fs.StringVar(&o.AgentStrategy, "agent-strategy", libgobuster.AgentPerRequest, note("When to pick a new random agent: per-request, per-host or per-run"))
+	fs.StringVar(&o.ExcludeString, "xs", "", note("Response content string to exclude"))
+	fs.BoolVar(&o.ShowContentType, "show-content-type", false, note("Show the Content-Type of the responses"))
+	fs.StringVar(&o.ExcludeContentType, "exclude-content-type", "", note("Comma separated Content-Types to exclude, type/* matches a whole type (eg. text/html,image/*)"))
+	fs.StringVar(&o.IncludeContentType, "include-content-type", "", note("Comma separated Content-Types a result must have to be a match (eg. application/json)"))
+	fs.StringVar(&o.TreatAsMissing, "treat-as-missing", "", note("Comma separated status:pattern rules declaring the responses of a status missing paths, the pattern is * for every path, .ext for an extension, / for directories or a glob of the path (eg. 404:*,403:.php)"))
+	fs.BoolVar(&o.Heuristic404, "heuristic-404", false, note("Take the pages whose title, headings or text read as not found (404, not found, introuvable, no encontrada, nicht gefunden...) for false positives, on top of the wildcard detection"))
+	fs.StringVar(&o.Match, "match", "", note("Expression deciding which results are matches instead of -x and -xs, eg. 'status == 200 && size > 1024 && !title.contains(\"Error\")'"))
+	fs.BoolVar(&o.BlankExtension, "be", false, note("Request word without extension"))
+	fs.StringVar(&o.ExtToken, "ext-token", libgobuster.DefaultExtToken, note("Placeholder of the wordlist lines replaced by every extension, anywhere in the line"))
+	fs.StringVar(&o.SourceIPs, "source-ips", "", note("Comma separated local source addresses to round-robin requests over"))
+	fs.StringVar(&o.DebugHTTP, "debug-http", "", note("Log every request and response to this file, correlated with the results by request id"))
+	fs.IntVar(&o.WildcardProbes, "wildcard-probes", 2, note("Number of random paths requested to calibrate wildcard detection"))
+	fs.StringVar(&o.WildcardLengths, "wildcard-lengths", "16,8", note("Comma separated lengths of the wildcard calibration paths"))
+	fs.StringVar(&o.WildcardCharset, "wildcard-charset", "hex", note("Characters of the wildcard calibration paths: hex, lower, alpha, alnum or a literal set"))
+	fs.BoolVar(&o.API, "api", false, note("Send JSON Accept headers, and Content-Type with a body, and extract error/message fields of JSON responses"))
+	fs.StringVar(&o.FollowUpStatusCodes, "followup-codes", "2xx,3xx", note("Status codes (or classes like 4xx) of the matches that trigger the follow-up actions, -method-enum, -wordlist-from-matches, -learn, -js-endpoints and -upload-check, eg. 2xx,3xx,401,403"))
+	fs.DurationVar(&o.LevelDelay, "level-delay", 0, note("Pause before every level of the targets discovered on the target, the words and endpoints of the matches, 0 never"))
+	fs.StringVar(&o.PrioritizeCodes, "prioritize-codes", "", note("Status codes (or classes like 4xx) of the directories whose discovered targets are requested first in their level, eg. 401,403. It only orders the targets, -followup-codes decides which matches discover some"))
+	fs.BoolVar(&o.HeadFirst, "head-first", false, note("Issue a HEAD request first and only GET when the status is not excluded"))
+	fs.BoolVar(&o.NoBackoff, "no-backoff", false, note("Record 429 and 503 responses instead of pausing on Retry-After and 503 streaks and retrying the words"))
+	fs.BoolVar(&o.WordlistFromTarget, "wordlist-from-target", false, note("Request the words of the target's baseline page, HTML and scripts, after the wordlist"))
+	fs.BoolVar(&o.Learn, "learn", false, note("Request the wordlist again after the scan, joined before and after the directory names and file stems of the matches"))
+	fs.BoolVar(&o.WordlistFromMatches, "wordlist-from-matches", false, note("Also request the words of the pages found, with -wordlist-from-target"))
+	fs.BoolVar(&o.JSEndpoints, "js-endpoints", false, note("Extract the paths and routes of the JavaScript files found, request them and write them to the output_jsendpoints folder"))
+	fs.BoolVar(&o.MethodEnum, "method-enum", false, note("Send OPTIONS and the -methods to every match and record the methods it allows"))
+	fs.BoolVar(&o.ShowDiff, "show-diff", false, note("Write the diff of the body of the matches sharing the status of the wildcard response against it into the output folder, to tell true from false positives"))
+	fs.StringVar(&o.Evasion, "evasion", "", note("Comma separated encodings of the matched 401 and 403 paths requested to find ACL and WAF bypasses, answering a 2xx: double-url, unicode, dot-slash or case, joined with + to combine them (eg. case,dot-slash+double-url)"))
+	fs.BoolVar(&o.UploadCheck, "upload-check", false, note("PUT a uniquely named text file into the writable sounding directories found (uploads, files, tmp, dav...), GET it back and DELETE it, to report WebDAV and misconfigured uploads"))
+	fs.BoolVar(&o.FetchArtifacts, "fetch-artifacts", false, note("Save the body of the matches with the extension of a dump, backup or archive (.sql, .bak, .zip...) into the output_artifacts quarantine folder"))
+	fs.Int64Var(&o.ArtifactMaxSize, "artifact-max-size", libgobuster.DefaultArtifactMaxSize, note("Read and save at most this many bytes of every artifact of -fetch-artifacts, the bodies are also cut by -max-response-size"))
+	fs.BoolVar(&o.PruneExts, "prune-exts", false, note(fmt.Sprintf("Stop requesting the words with an extension of -ext once it had no hit in %d requests, the blank extension and the other wordlist words are always requested", libgobuster.PruneExtsSample)))
+	fs.IntVar(&o.VerifyHits, "verify-hits", 0, note("Request the matches again this many times once the scan is over, at low concurrency, and drop the ones the server never answers with the same status again (flaky 200s of overloaded servers), 0 disables it"))
+	fs.DurationVar(&o.DNSCacheTTL, "dns-cache-ttl", 0, note("Resolve the target with the Go resolver, bypassing the caches of the system, and keep its addresses for this long, shared by the targets of a run, 0 leaves it to the system"))
+	fs.StringVar(&o.StaticResolve, "static-resolve", "", note("Comma separated host=ip pairs dialed instead of resolving the host, like curl --resolve (eg. www.example.com=10.0.0.5)"))
+	fs.DurationVar(&o.ConnRecycle, "conn-recycle", 0, note("Close the idle connections at this interval so long scans spread over the backends of load balanced targets, 0 never"))
+	fs.StringVar(&o.HostFuzz, "host-fuzz", "", note("Keep the url and send the words as the host, x-forwarded-host or both headers, bare words get the domain of the url appended"))
+	fs.StringVar(&o.OOBServer, "oob-server", "", note("Interactsh server (oast.pro, https://oob.example.com) whose callback hostnames are sent in the Referer and X-Forwarded-For headers, the callbacks are reported with the path that triggered them"))
+	fs.StringVar(&o.OOBToken, "oob-token", "", note("Authorization token of the -oob-server"))
+	fs.BoolVar(&o.SafeMode, "safe-mode", false, note(fmt.Sprintf("Conservative mode for production targets: GET and HEAD only, at most %d requests per second, paths like delete, shutdown or reset skipped, and the options sending other methods refused", libgobuster.SafeModeRate)))
+	fs.BoolVar(&o.RespectRobots, "respect-robots", false, note("Fetch robots.txt and skip the paths it disallows, the skipped paths are counted in the summary. A target whose robots.txt answers 5xx is skipped"))
+	fs.StringVar(&o.IfModifiedSince, "if-modified-since", "", note("Send If-Modified-Since with this HTTP date, or now, so the paths that exist answer 304 Not Modified without a body"))
+	fs.StringVar(&o.IfNoneMatch, "if-none-match", "", note("Send If-None-Match with this ETag, * matches any, so the paths that exist answer 304 Not Modified without a body"))
+	fs.BoolVar(&o.OpenAPI, "openapi", false, note("Write the matches, with the methods of -method-enum and their content types, as an OpenAPI 3 skeleton to the output_openapi folder"))
+	fs.StringVar(&o.Methods, "methods", libgobuster.DefaultEnumMethods, note("Comma separated methods tried by -method-enum, beware they may change the target"))
+}
+
+// addDNSFlags registers the flags of dns mode
+func addDNSFlags(fs *flag.FlagSet, o *libgobuster.Options, shared bool) {
+	note := modeNote(shared, libgobuster.ModeDNS)
+	fs.DurationVar(&o.DNSTimeout, "dns-timeout", 5*time.Second, note("Timeout of a single DNS query"))
+	fs.IntVar(&o.DNSRetries, "dns-retries", 2, note("Number of retries for DNS queries failing with SERVFAIL or a timeout"))
+	fs.BoolVar(&o.FastDNS, "fast-dns", false, note("Send raw UDP A and AAAA queries over a socket pool straight to the resolvers, for large wordlists"))
+	fs.StringVar(&o.Resolvers, "resolvers", "", note("Comma separated resolvers of -fast-dns [ip(:port)], defaults to the system nameservers"))
+	fs.BoolVar(&o.ShowIPs, "i", false, note("Show IP addresses"))
+	fs.BoolVar(&o.ShowCNAME, "cn", false, note("Show CNAME records and record them in the output files, cannot be used with '-i' option"))
+	fs.BoolVar(&o.ShowSource, "show-source", false, note("Show whether a subdomain resolved through its own A/AAAA records or a CNAME chain"))
+	fs.BoolVar(&o.Takeover, "takeover", false, note("Flag subdomains whose CNAME points to a takeoverable service or does not resolve"))
+	fs.StringVar(&o.TakeoverFingerprints, "takeover-fingerprints", "", note("Path to a JSON fingerprint list replacing the embedded one of -takeover"))
+	fs.StringVar(&o.WildcardAllow, "wildcard-allow", "", note("Comma separated known wildcard IPs, subdomains resolving to them are ignored without requiring -fw"))
+	fs.StringVar(&o.WebProbePorts, "web-probe-ports", "", note("Comma separated ports of the subdomains found checked for HTTP(S) once the scan is over, as they are found in dns+dir. The live urls are written to the output_webprobe folder (eg. 80,443,8080,8443)"))
+}
+
+// subcommands lists the commands of the cli with their description
+var subcommands = []struct {
+	name        string
+	description string
+}{
+	{libgobuster.ModeDir, "Brute force directories and files of a website"},
+	{libgobuster.ModeDNS, "Brute force subdomains"},
+	{libgobuster.ModeDNSDir, "Brute force subdomains, then run dir mode against every one serving a website"},
+	{"history", "Show when and under which options a path was first and last seen"},
+	{"report", "Print the summaries of the runs stored in an output folder"},
+	{"compare", "Show the endpoints added, removed and changed between two runs"},
+	{"merge", "Merge the output folders of several runs into one"},
+	{"triage", "Mark the matches recorded in an output folder interesting, false-positive or done, with notes"},
+	{"completion", "Print the completion script for bash, zsh or fish"},
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: %s <command> [options]\n\nCommands:\n", os.Args[0])
+	for _, c := range subcommands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", c.name, c.description)
+	}
+	fmt.Fprintf(os.Stderr, "\nRun '%s <command> -h' for the options of a command.\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "The flat form '%s -m <mode> [options]' is still accepted.\n", os.Args[0])
+}
+
+// parseScanFlags parses the flags of a scan command into the options
+func parseScanFlags(mode string, args []string, o *libgobuster.Options) error {
+	fs := flag.NewFlagSet(mode, flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s %s [options]\n", os.Args[0], mode)
+		fs.PrintDefaults()
+	}
+	addCommonFlags(fs, o)
+	if mode == libgobuster.ModeDir || mode == libgobuster.ModeDNSDir {
+		addDirFlags(fs, o, false)
+	}
+	if mode == libgobuster.ModeDNS || mode == libgobuster.ModeDNSDir {
+		addDNSFlags(fs, o, false)
+	}
+	o.Mode = mode
+	return parseWithProfile(fs, args, o)
+}
+
+// parseLegacyFlags parses the flat flag set selecting the mode with -m
+func parseLegacyFlags(o *libgobuster.Options) error {
+	flag.StringVar(&o.Mode, "m", libgobuster.ModeDir, "Mode: dir, dns or dns+dir (dir mode against every subdomain serving a website, as the dns scan finds them)")
+	addCommonFlags(flag.CommandLine, o)
+	addDirFlags(flag.CommandLine, o, true)
+	addDNSFlags(flag.CommandLine, o, true)
+	flag.Usage = func() {
+		usage()
+		fmt.Fprintf(os.Stderr, "\nOptions of the flat form:\n")
+		flag.PrintDefaults()
+	}
+	return parseWithProfile(flag.CommandLine, os.Args[1:], o)
+}
+
+// secretFlags hold secrets, given on each run: they are never stored
+// in a scan profile nor printed or stored by -print-cmd
+var secretFlags = []string{"P", "bearer", "c", "oob-token", "tor-password"}
+
+// profileExcludedFlags are never stored in a scan profile: the secrets
+// are given on each run and the output flags belong to the run
+var profileExcludedFlags = withSecretFlags("m", "of", "o", "profile", "save-profile")
+
+// withSecretFlags returns the set of the flags and the secret flags
+func withSecretFlags(names ...string) map[string]bool {
+	set := make(map[string]bool, len(names)+len(secretFlags))
+	for _, name := range append(names, secretFlags...) {
+		set[name] = true
+	}
+	return set
+}
+
+// pendingProfile is the profile of -save-profile, saved once the options
+// are validated
+var pendingProfile *libgobuster.ScanProfile
+
+// redactedFlags hold secrets, never printed nor stored by -print-cmd
+var redactedFlags = withSecretFlags()
+
+// resolvedFlagsExcluded are resolved already in the command line of
+// -print-cmd
+var resolvedFlagsExcluded = map[string]bool{
+	"profile":      true,
+	"save-profile": true,
+	"print-cmd":    true,
+}
+
+// pendingCommand is the command line of -print-cmd, printed and stored
+// once the options are validated
+var pendingCommand *libgobuster.ResolvedCommand
+
+// resolveCommand returns the command line setting every flag of the
+// set differing from its default
+func resolveCommand(fs *flag.FlagSet, o *libgobuster.Options) *libgobuster.ResolvedCommand {
+	c := &libgobuster.ResolvedCommand{Version: libgobuster.VERSION, Mode: o.Mode}
+	if fs != flag.CommandLine {
+		c.Command = o.Mode
+	}
+	fs.VisitAll(func(f *flag.Flag) {
+		value := f.Value.String()
+		if value == f.DefValue || resolvedFlagsExcluded[f.Name] {
+			return
+		}
+		if redactedFlags[f.Name] {
+			value = "<redacted>"
+		}
+		isBool := false
+		if b, ok := f.Value.(interface{ IsBoolFlag() bool }); ok {
+			isBool = b.IsBoolFlag()
+		}
+		c.Flags = append(c.Flags, libgobuster.CommandFlag{Name: f.Name, Value: value, Bool: isBool})
+	})
+	return c
+}
+
+// parseWithProfile parses the flags, applying the flags stored in the
+// -profile of the output folder first so the command line overrides them
+func parseWithProfile(fs *flag.FlagSet, args []string, o *libgobuster.Options) error {
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+
+	if o.ScanProfile != "" {
+		p, err := libgobuster.LoadProfile(o.OutputFolder, o.ScanProfile)
+		if err != nil {
+			return fail(exitOptionsError, "[!] Profile (-profile): %v", err)
+		}
+		if p.Mode != o.Mode {
+			return fail(exitOptionsError, "[!] Profile (-profile): %s is a profile of %s mode", p.Name, p.Mode)
+		}
+		for name, value := range p.Flags {
+			if err := fs.Set(name, value); err != nil {
+				return fail(exitOptionsError, "[!] Profile (-profile): Invalid value of -%s: %v", name, err)
+			}
+		}
+		if err := fs.Parse(args); err != nil {
+			return errUsage
+		}
+	}
+
+	if o.PrintCmd {
+		pendingCommand = resolveCommand(fs, o)
+	}
+
+	if o.SaveScanProfile != "" {
+		flags := make(map[string]string)
+		fs.Visit(func(f *flag.Flag) {
+			if !profileExcludedFlags[f.Name] {
+				flags[f.Name] = f.Value.String()
+			}
+		})
+		pendingProfile = &libgobuster.ScanProfile{
+			Name:    o.SaveScanProfile,
+			Mode:    o.Mode,
+			Created: time.Now(),
+			Flags:   flags,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+module github.com/gosirys/gobuster
+
+go 1.21
+
+require (
+	github.com/dop251/goja v0.0.0-20240220182346-e401ed450204
+	github.com/google/uuid v1.6.0
+	github.com/gookit/color v1.6.1
+	github.com/hashicorp/go-multierror v1.1.1
+	golang.org/x/crypto v0.21.0
+	golang.org/x/net v0.22.0
+)
+
+require (
+	github.com/dlclark/regexp2 v1.7.0 // indirect
+	github.com/go-sourcemap/sourcemap v2.1.3+incompatible // indirect
+	github.com/google/pprof v0.0.0-20230207041349-798e818bf904 // indirect
+	github.com/hashicorp/errwrap v1.0.0 // indirect
+	github.com/xo/terminfo v0.0.0-20220910002029-abceb7e1c41e // indirect
+	golang.org/x/sys v0.30.0 // indirect
+	golang.org/x/term v0.18.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+)
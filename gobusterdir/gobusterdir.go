@@ -1,326 +1,787 @@
-package gobusterdir
-
-import (
-	"bytes"
-	"fmt"
-	"log"
-	"math/rand"
-	"net/url"
-	"regexp"
-	"strings"
-	"time"
-
-	"yBuster/libgobuster"
-
-	"github.com/google/uuid"
-)
-
-// GobusterDir is the main type to implement the interface
-type GobusterDir struct{}
-
-// Setup is the setup implementation of gobusterdir
-func (d GobusterDir) Setup(g *libgobuster.Gobuster) error {
-	_, _, _, _, err := g.GetRequest(g.Opts.URL)
-	if err != nil {
-		return fmt.Errorf("unable to connect to %s: %v", g.Opts.URL, err)
-	}
-
-	r := regexp.MustCompile(`(?s).*<title>(?P<Title>.*)<\/title>.*`)
-	g.WildcardStatusCode = new(int)
-
-	uuidFile16 := strings.ReplaceAll(uuid.New().String(), "-", "")[0:16]
-	urlFile16 := fmt.Sprintf("%s%s", g.Opts.URL, uuidFile16)
-	wildcardRespFile16, _, wildcardContentFile16, _, errFile16 := g.GetRequest(urlFile16)
-	if errFile16 != nil {
-		return errFile16
-	}
-	cleanWildcardContentFile16 := strings.ReplaceAll(*wildcardContentFile16, urlFile16, "")
-	rsFile16 := r.FindStringSubmatch(*wildcardContentFile16)
-	cleanTitleFile16 := ""
-	if len(rsFile16) > 0 {
-		cleanTitleFile16 = strings.TrimSpace(rsFile16[1])
-	}
-
-	uuidFile8 := strings.ReplaceAll(uuid.New().String(), "-", "")[0:8]
-	urlFile8 := fmt.Sprintf("%s%s", g.Opts.URL, uuidFile8)
-	wildcardRespFile8, _, wildcardContentFile8, _, errFile8 := g.GetRequest(urlFile8)
-	if errFile8 != nil {
-		return errFile8
-	}
-	cleanWildcardContentFile8 := strings.ReplaceAll(*wildcardContentFile8, urlFile8, "")
-	rsFile8 := r.FindStringSubmatch(*wildcardContentFile8)
-	cleanTitleFile8 := ""
-	if len(rsFile8) > 0 {
-		cleanTitleFile8 = strings.TrimSpace(rsFile8[1])
-	}
-
-	if *wildcardRespFile16 == *wildcardRespFile8 {
-		g.WildcardStatusCode = wildcardRespFile16
-		log.Printf("[-] Wildcard response found: %s => %d", urlFile16, *wildcardRespFile16)
-		log.Printf("[-] Wildcard response found: %s => %d", urlFile8, *wildcardRespFile8)
-		if cleanTitleFile16 != "" && cleanTitleFile16 == cleanTitleFile8 {
-			g.IsWildcardFileByTitle = true
-			g.WildcardFileTitle = cleanTitleFile16
-			log.Printf(" --> Wildcard by title: %s", cleanTitleFile16)
-		} else if len(cleanWildcardContentFile16) == len(cleanWildcardContentFile8) {
-			g.IsWildcardFileByContentLength = true
-			g.WildcardFileContentLength = len(cleanWildcardContentFile16)
-			log.Printf(" --> Wildcard by content length: %d", len(cleanWildcardContentFile16))
-		}
-	} else {
-		log.Printf("[-] Wildcard response NOT found: %s => %d", urlFile16, *wildcardRespFile16)
-		log.Printf("[-] Wildcard response NOT found: %s => %d", urlFile8, *wildcardRespFile8)
-	}
-
-	uuidDir16 := fmt.Sprintf("%s%s", strings.ReplaceAll(uuid.New().String(), "-", "")[0:15], "/")
-	urlDir16 := fmt.Sprintf("%s%s", g.Opts.URL, uuidDir16)
-	wildcardRespDir16, _, wildcardContentDir16, _, errDir16 := g.GetRequest(urlDir16)
-	if errDir16 != nil {
-		return errDir16
-	}
-	cleanWildcardContentDir16 := strings.ReplaceAll(*wildcardContentDir16, urlDir16, "")
-	rsDir16 := r.FindStringSubmatch(*wildcardContentDir16)
-	cleanTitleDir16 := ""
-	if len(rsDir16) > 0 {
-		cleanTitleDir16 = strings.TrimSpace(rsDir16[1])
-	}
-
-	uuidDir8 := fmt.Sprintf("%s%s", strings.ReplaceAll(uuid.New().String(), "-", "")[0:7], "/")
-	urlDir8 := fmt.Sprintf("%s%s", g.Opts.URL, uuidDir8)
-	wildcardRespDir8, _, wildcardContentDir8, _, errDir8 := g.GetRequest(urlDir8)
-	if errDir8 != nil {
-		return errDir8
-	}
-	cleanWildcardContentDir8 := strings.ReplaceAll(*wildcardContentDir8, urlDir8, "")
-	rsDir8 := r.FindStringSubmatch(*wildcardContentDir8)
-	cleanTitleDir8 := ""
-	if len(rsDir8) > 0 {
-		cleanTitleDir8 = strings.TrimSpace(rsDir8[1])
-	}
-
-	if *wildcardRespDir16 == *wildcardRespDir8 {
-		g.WildcardStatusCode = wildcardRespDir16
-		log.Printf("[-] Wildcard response found: %s => %d", urlDir16, *wildcardRespDir16)
-		log.Printf("[-] Wildcard response found: %s => %d", urlDir8, *wildcardRespDir8)
-		if cleanTitleDir16 != "" && cleanTitleDir16 == cleanTitleDir8 {
-			g.IsWildcardDirByTitle = true
-			g.WildcardDirTitle = cleanTitleDir16
-			log.Printf(" --> Wildcard by title: %s", cleanTitleDir16)
-		} else if len(cleanWildcardContentDir16) == len(cleanWildcardContentDir8) {
-			g.IsWildcardDirByContentLength = true
-			g.WildcardDirContentLength = len(cleanWildcardContentDir16)
-			log.Printf(" --> Wildcard by content length: %d", len(cleanWildcardContentDir16))
-		}
-	} else {
-		log.Printf("[-] Wildcard response NOT found: %s => %d", urlDir16, *wildcardRespDir16)
-		log.Printf("[-] Wildcard response NOT found: %s => %d", urlDir8, *wildcardRespDir8)
-	}
-
-	return nil
-}
-
-// Process is the process implementation of gobusterdir
-func (d GobusterDir) Process(g *libgobuster.Gobuster, busterTarget *libgobuster.BusterTarget) ([]libgobuster.Result, error) {
-	suffix := ""
-	if g.Opts.UseSlash {
-		suffix = "/"
-	}
-
-	entity := busterTarget.Target
-	isEntityURL := true
-	url := entity
-	var ret []libgobuster.Result
-
-	if !busterTarget.IsURL {
-		word := strings.TrimPrefix(busterTarget.Target, "/")
-		entity = fmt.Sprintf("%s%s", word, suffix)
-		isEntityURL = false
-		url = fmt.Sprintf("%s%s", g.Opts.URL, entity)
-	}
-
-	if len(g.Opts.RandomAgentParsed) > 0 {
-		rand.Seed(time.Now().UTC().UnixNano())
-		randomAgent := g.Opts.RandomAgentParsed[rand.Intn(len(g.Opts.RandomAgentParsed))]
-		g.HTTP.UserAgent = randomAgent
-	}
-
-	dirResp, dirSize, dirContent, redirectURL, err := g.GetRequest(url)
-	if err != nil {
-		return nil, err
-	}
-
-	if dirResp != nil {
-		ret = append(ret, libgobuster.Result{
-			Entity:      entity,
-			Status:      *dirResp,
-			Size:        dirSize,
-			Content:     dirContent,
-			IsEntityURL: isEntityURL,
-			RedirectURL: redirectURL,
-		})
-	}
-
-	return ret, nil
-}
-
-// ResultToString is the to string implementation of gobusterdir
-func (d GobusterDir) ResultToString(g *libgobuster.Gobuster, r *libgobuster.Result) (*string, *string, int, error) {
-	buf := &bytes.Buffer{}
-	allBuf := &bytes.Buffer{}
-	isFalsePositive := false
-	isDir := strings.HasSuffix(r.Entity, "/")
-	rgx := regexp.MustCompile(`(?s).*<title>(?P<Title>.*)<\/title>.*`)
-
-	if r.Status == *g.WildcardStatusCode {
-		if isDir {
-			if g.IsWildcardDirByTitle {
-				rsDir := rgx.FindStringSubmatch(*r.Content)
-				cleanTitleDir := ""
-				if len(rsDir) > 0 {
-					cleanTitleDir = strings.TrimSpace(rsDir[1])
-					if cleanTitleDir == g.WildcardDirTitle {
-						isFalsePositive = true
-					}
-				}
-			} else if g.IsWildcardDirByContentLength {
-				entity := r.Entity
-				if !r.IsEntityURL {
-					entity = fmt.Sprintf("%s%s", g.Opts.URL, entity)
-				}
-				cleanWildcardContentDir := strings.ReplaceAll(*r.Content, entity, "")
-				if len(cleanWildcardContentDir) == g.WildcardDirContentLength {
-					isFalsePositive = true
-				}
-			}
-		} else {
-			if g.IsWildcardFileByTitle {
-				rsFile := rgx.FindStringSubmatch(*r.Content)
-				cleanTitleFile := ""
-				if len(rsFile) > 0 {
-					cleanTitleFile = strings.TrimSpace(rsFile[1])
-					if cleanTitleFile == g.WildcardFileTitle {
-						isFalsePositive = true
-					}
-				}
-			} else if g.IsWildcardFileByContentLength {
-				entity := r.Entity
-				if !r.IsEntityURL {
-					entity = fmt.Sprintf("%s%s", g.Opts.URL, entity)
-				}
-				cleanWildcardContentFile := strings.ReplaceAll(*r.Content, entity, "")
-				if len(cleanWildcardContentFile) == g.WildcardFileContentLength {
-					isFalsePositive = true
-				}
-			}
-		}
-	}
-
-	hasExcludeString := false
-	if g.Opts.ExcludeString != "" {
-		hasExcludeString = strings.Contains(*r.Content, g.Opts.ExcludeString)
-	}
-
-	// Prefix if we're in verbose mode
-	if g.Opts.Verbose {
-		if isFalsePositive {
-			if _, err := fmt.Fprintf(buf, "%-16s", "FALSE POSITIVE"); err != nil {
-				return nil, nil, 0, err
-			}
-		} else if !g.Opts.ExcludedStatusCodesParsed.Contains(r.Status) && !hasExcludeString {
-			if _, err := fmt.Fprintf(buf, "%-16s", "FOUND"); err != nil {
-				return nil, nil, 0, err
-			}
-		} else {
-			if _, err := fmt.Fprintf(buf, "%-16s", "MISSED"); err != nil {
-				return nil, nil, 0, err
-			}
-		}
-	}
-
-	t := time.Now()
-	if !g.Opts.ExcludedStatusCodesParsed.Contains(r.Status) && !isFalsePositive && !hasExcludeString || g.Opts.Verbose {
-		if _, err := fmt.Fprintf(buf, "[%02d:%02d:%02d]", t.Hour(), t.Minute(), t.Second()); err != nil {
-			return nil, nil, 0, err
-		}
-
-		if _, err := fmt.Fprintf(buf, "%8d", r.Status); err != nil {
-			return nil, nil, 0, err
-		}
-
-		if r.Size != nil {
-			if _, err := fmt.Fprintf(buf, "%12d B", *r.Size); err != nil {
-				return nil, nil, 0, err
-			}
-		} else {
-			if _, err := fmt.Fprintf(buf, "%12d B", 0); err != nil {
-				return nil, nil, 0, err
-			}
-		}
-
-		if _, err := fmt.Fprintf(buf, "     -     "); err != nil {
-			return nil, nil, 0, err
-		}
-
-		if !r.IsEntityURL {
-			if _, err := fmt.Fprintf(buf, "%s", g.Opts.URL); err != nil {
-				return nil, nil, 0, err
-			}
-		}
-
-		if _, err := fmt.Fprintf(buf, "%s", r.Entity); err != nil {
-			return nil, nil, 0, err
-		}
-
-		if *r.RedirectURL != "" {
-			if _, err := fmt.Fprintf(buf, "  ->  "); err != nil {
-				return nil, nil, 0, err
-			}
-
-			if _, err := fmt.Fprintf(buf, "%s", *r.RedirectURL); err != nil {
-				return nil, nil, 0, err
-			}
-		}
-
-		if _, err := fmt.Fprintf(buf, "\n"); err != nil {
-			return nil, nil, 0, err
-		}
-
-		if _, err := fmt.Fprintf(allBuf, "[%d-%02d-%02d %02d:%02d:%02d] - ", t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second()); err != nil {
-			return nil, nil, 0, err
-		}
-
-		allBufEntity := r.Entity
-		if r.IsEntityURL {
-			u, err := url.Parse(allBufEntity)
-			if err == nil {
-				schemeHost := fmt.Sprintf("%s://%s/", u.Scheme, u.Host)
-				allBufEntity = strings.ReplaceAll(allBufEntity, schemeHost, "")
-			}
-		}
-
-		if _, err := fmt.Fprintf(allBuf, "/%s - ", allBufEntity); err != nil {
-			return nil, nil, 0, err
-		}
-
-		if _, err := fmt.Fprintf(allBuf, "%d", r.Status); err != nil {
-			return nil, nil, 0, err
-		}
-
-		if *r.RedirectURL != "" {
-			if _, err := fmt.Fprintf(allBuf, "  ->  "); err != nil {
-				return nil, nil, 0, err
-			}
-
-			if _, err := fmt.Fprintf(allBuf, "%s", *r.RedirectURL); err != nil {
-				return nil, nil, 0, err
-			}
-		}
-
-		if _, err := fmt.Fprintf(allBuf, "\n"); err != nil {
-			return nil, nil, 0, err
-		}
-	}
-	s := buf.String()
-	as := allBuf.String()
-	return &s, &as, r.Status, nil
-}
+package gobusterdir
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gosirys/gobuster/libgobuster"
+)
+
+// GobusterDir is the main type to implement the interface
+type GobusterDir struct{}
+
+// New returns a dir mode scan of the options, ready to Start
+func New(c context.Context, opts *libgobuster.Options) (*libgobuster.Gobuster, error) {
+	opts.Mode = libgobuster.ModeDir
+	return libgobuster.NewGobuster(c, opts, GobusterDir{})
+}
+
+// wildcardCharsets are the named character sets for -wildcard-charset,
+// any other value is used as the set of characters itself
+var wildcardCharsets = map[string]string{
+	"hex":   "0123456789abcdef",
+	"lower": "abcdefghijklmnopqrstuvwxyz",
+	"alpha": "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ",
+	"alnum": "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789",
+}
+
+// wildcardProbe is the response to a request for a path that should not exist
+type wildcardProbe struct {
+	url     string
+	status  int
+	title   string
+	length  int
+	content string
+	// rawLength and pathLength model the pages reflecting the path
+	rawLength  int
+	pathLength int
+}
+
+// pathLengthProbes are the lengths of the extra probes fitting the
+// length of the wildcard responses to the length of the path
+var pathLengthProbes = []int{5, 11, 23}
+
+// probeCharset returns the characters of the -wildcard-charset
+func probeCharset(g *libgobuster.Gobuster) string {
+	if charset, ok := wildcardCharsets[g.Opts.WildcardCharset]; ok {
+		return charset
+	}
+	return g.Opts.WildcardCharset
+}
+
+// probeWildcard requests the configured number of random paths, as
+// directories if dir is set or with the given extension, cycling
+// through the configured lengths
+func probeWildcard(g *libgobuster.Gobuster, dir bool, ext string) ([]wildcardProbe, error) {
+	lengths := make([]int, g.Opts.WildcardProbes)
+	for i := range lengths {
+		lengths[i] = g.Opts.WildcardLengthsParsed[i%len(g.Opts.WildcardLengthsParsed)]
+	}
+	return probeLengths(g, dir, ext, lengths)
+}
+
+// probeLengths requests a random path of every length, as a directory
+// if dir is set or with the given extension
+func probeLengths(g *libgobuster.Gobuster, dir bool, ext string, lengths []int) ([]wildcardProbe, error) {
+	charset := probeCharset(g)
+	var probes []wildcardProbe
+	for _, length := range lengths {
+		probePath := libgobuster.RandomString(g.Random(), length, charset)
+		if dir {
+			// keep the total length including the trailing slash
+			probePath = fmt.Sprintf("%s/", probePath[:len(probePath)-1])
+		} else if ext != "" {
+			probePath = fmt.Sprintf("%s.%s", probePath, ext)
+		}
+		probeURL := g.URLFor(probePath)
+		if !g.RobotsAllowed(probeURL) {
+			continue
+		}
+		// conditional like the words, or their 304 would all stand out
+		resp, err := g.GetRequest(probeURL, g.ConditionalRequestOptions(g.NewRequestOptions(probeURL)))
+		if err != nil {
+			return nil, err
+		}
+		probes = append(probes, wildcardProbe{
+			url:    probeURL,
+			status: resp.StatusCode,
+			title:  libgobuster.ExtractTitle(resp.Content),
+			length: len(strings.ReplaceAll(resp.Content, probeURL, "")),
+
+			content:    resp.Content,
+			rawLength:  len(resp.Content),
+			pathLength: len(probePath),
+		})
+	}
+	return probes, nil
+}
+
+// calibrate probes the wildcard responses and derives their baseline.
+// Responses sharing a status only are probed at more lengths, error
+// pages reflecting the path grow with it.
+func calibrate(g *libgobuster.Gobuster, dir bool, ext string) (*libgobuster.WildcardBaseline, error) {
+	probes, err := probeWildcard(g, dir, ext)
+	if err != nil {
+		return nil, err
+	}
+	baseline := evaluateProbes(probes)
+	if baseline != nil && !baseline.ByTitle && !baseline.ByLength {
+		more, err := probeLengths(g, dir, ext, pathLengthProbes)
+		if err != nil {
+			return nil, err
+		}
+		probes = append(probes, more...)
+		baseline = evaluateProbes(probes)
+	}
+	logProbes(g, probes, baseline)
+	return baseline, nil
+}
+
+// evaluateProbes derives the wildcard baseline shared by all probes,
+// nil if the probes do not share the same status
+func evaluateProbes(probes []wildcardProbe) *libgobuster.WildcardBaseline {
+	if len(probes) == 0 {
+		return nil
+	}
+
+	first := probes[0]
+	sameTitle := first.title != ""
+	sameLength := true
+	for _, p := range probes[1:] {
+		if p.status != first.status {
+			return nil
+		}
+		sameTitle = sameTitle && p.title == first.title
+		sameLength = sameLength && p.length == first.length
+	}
+
+	baseline := &libgobuster.WildcardBaseline{Status: first.status, Content: first.content, ContentURL: first.url}
+	if sameTitle {
+		baseline.ByTitle = true
+		baseline.Title = first.title
+	} else if sameLength {
+		baseline.ByLength = true
+		baseline.Length = first.length
+	} else {
+		pathLengths := make([]int, len(probes))
+		lengths := make([]int, len(probes))
+		for i, p := range probes {
+			pathLengths[i], lengths[i] = p.pathLength, p.rawLength
+		}
+		baseline.Intercept, baseline.Slope, baseline.ByPathLength = libgobuster.FitPathLength(pathLengths, lengths)
+	}
+	return baseline
+}
+
+func logProbes(g *libgobuster.Gobuster, probes []wildcardProbe, baseline *libgobuster.WildcardBaseline) {
+	for _, p := range probes {
+		if baseline != nil {
+			g.Logf("[-] Wildcard response found: %s => %d", p.url, p.status)
+		} else {
+			g.Logf("[-] Wildcard response NOT found: %s => %d", p.url, p.status)
+		}
+	}
+	if baseline == nil {
+		return
+	}
+	if baseline.ByTitle {
+		g.Logf(" --> Wildcard by title: %s", baseline.Title)
+	} else if baseline.ByLength {
+		g.Logf(" --> Wildcard by content length: %d", baseline.Length)
+	} else if baseline.ByPathLength {
+		g.Logf(" --> Wildcard by path length: %d + %d per character of the path", baseline.Intercept, baseline.Slope)
+	}
+}
+
+// matchesBaseline reports if the result looks like the wildcard baseline
+func matchesBaseline(g *libgobuster.Gobuster, r *libgobuster.Result, baseline *libgobuster.WildcardBaseline) bool {
+	if r.Status != baseline.Status {
+		return false
+	}
+	if baseline.ByTitle {
+		return libgobuster.ExtractTitle(*r.Content) == baseline.Title
+	}
+	if baseline.ByLength {
+		return len(strings.ReplaceAll(*r.Content, r.FullURL(g), "")) == baseline.Length
+	}
+	if baseline.ByPathLength {
+		return len(*r.Content) == baseline.LengthFor(len(r.FullURL(g))-len(g.BaseURL()))
+	}
+	return false
+}
+
+// collectTargetWords queues the words of the baseline page and of the
+// scripts it loads from the target
+func collectTargetWords(g *libgobuster.Gobuster, base *libgobuster.Response) {
+	n := g.AddTargetWords(base.Content, base.ContentType)
+	for _, src := range libgobuster.ScriptSources(base.Content, g.BaseURL()) {
+		if !g.RobotsAllowed(src) {
+			continue
+		}
+		resp, err := g.GetRequest(src, g.NewRequestOptions(src))
+		if err != nil || resp.StatusCode != http.StatusOK {
+			continue
+		}
+		n += g.AddTargetWords(resp.Content, resp.ContentType)
+	}
+	g.Logf("[+] Collected %d words from the baseline page", n)
+}
+
+// setupHostFuzz requests the url with random hosts, the hosts answered
+// like them are false positives of -host-fuzz
+func setupHostFuzz(g *libgobuster.Gobuster) error {
+	charset := probeCharset(g)
+	var probes []wildcardProbe
+	for i := 0; i < g.Opts.WildcardProbes; i++ {
+		length := g.Opts.WildcardLengthsParsed[i%len(g.Opts.WildcardLengthsParsed)]
+		host := libgobuster.FuzzedHost(libgobuster.RandomString(g.Random(), length, charset), g.BaseURL())
+		resp, err := g.GetRequest(g.BaseURL(), g.HostRequestOptions(g.BaseURL(), host))
+		if err != nil {
+			return err
+		}
+		probes = append(probes, wildcardProbe{
+			url:    host,
+			status: resp.StatusCode,
+			title:  libgobuster.ExtractTitle(resp.Content),
+			length: len(strings.ReplaceAll(resp.Content, host, "")),
+		})
+	}
+	g.HostBaseline = evaluateProbes(probes)
+	logProbes(g, probes, g.HostBaseline)
+	return nil
+}
+
+// processHost requests the url with the host of a word
+func processHost(g *libgobuster.Gobuster, busterTarget *libgobuster.BusterTarget) ([]libgobuster.Result, error) {
+	url := g.BaseURL()
+	host := libgobuster.FuzzedHost(busterTarget.Target, url)
+	ro := g.HostRequestOptions(url, host)
+	resp, err := g.GetRequest(url, ro)
+	if err != nil {
+		return nil, err
+	}
+	result := libgobuster.Result{
+		Entity:        url,
+		Status:        resp.StatusCode,
+		Size:          &resp.Length,
+		Content:       &resp.Content,
+		IsEntityURL:   true,
+		RedirectURL:   &resp.RedirectURL,
+		RedirectChain: resp.RedirectChain,
+		RequestID:     ro.RequestID,
+		ContentType:   resp.ContentType,
+		RetryAfter:    libgobuster.ParseRetryAfter(resp.Header.Get("Retry-After"), g.Now()),
+		VirtualHost:   host,
+	}
+	classifyResult(g, &result)
+	return []libgobuster.Result{result}, nil
+}
+
+// matchesHostBaseline reports if a -host-fuzz result looks like the
+// response to unknown hosts
+func matchesHostBaseline(g *libgobuster.Gobuster, r *libgobuster.Result) bool {
+	baseline := g.HostBaseline
+	if baseline == nil || r.Status != baseline.Status {
+		return false
+	}
+	if baseline.ByTitle {
+		return libgobuster.ExtractTitle(*r.Content) == baseline.Title
+	}
+	if baseline.ByLength {
+		return len(strings.ReplaceAll(*r.Content, r.VirtualHost, "")) == baseline.Length
+	}
+	return false
+}
+
+// Setup is the setup implementation of gobusterdir. With
+// -respect-robots robots.txt is fetched first so every request of the
+// setup follows it.
+func (d GobusterDir) Setup(g *libgobuster.Gobuster) error {
+	if g.Opts.RespectRobots && g.Opts.HostFuzz == "" {
+		if err := g.FetchRobots(); err != nil {
+			return err
+		}
+		if g.RobotsDisallowAll() {
+			return libgobuster.ErrRobotsDisallowAll
+		}
+	}
+	var base *libgobuster.Response
+	if g.RobotsAllowed(g.BaseURL()) {
+		var err error
+		base, err = g.GetRequest(g.BaseURL(), g.NewRequestOptions(g.BaseURL()))
+		if err != nil {
+			return fmt.Errorf("unable to connect to %s: %v", g.BaseURL(), err)
+		}
+	}
+	// the path never changes, only the unknown hosts are calibrated
+	if g.Opts.HostFuzz != "" {
+		return setupHostFuzz(g)
+	}
+	if base != nil {
+		g.Technologies = libgobuster.DetectTechnologies(base)
+		g.LogWAF(base)
+		if g.Opts.WordlistFromTarget {
+			collectTargetWords(g, base)
+		}
+	}
+	g.FetchFavicon()
+
+	g.WildcardStatusCode = new(int)
+
+	fileBaseline, err := calibrate(g, false, "")
+	if err != nil {
+		return err
+	}
+	g.WildcardFileBaseline = fileBaseline
+	if fileBaseline != nil {
+		*g.WildcardStatusCode = fileBaseline.Status
+		g.IsWildcardFileByTitle = fileBaseline.ByTitle
+		g.WildcardFileTitle = fileBaseline.Title
+		g.IsWildcardFileByContentLength = fileBaseline.ByLength
+		g.WildcardFileContentLength = fileBaseline.Length
+	}
+
+	dirBaseline, err := calibrate(g, true, "")
+	if err != nil {
+		return err
+	}
+	g.WildcardDirBaseline = dirBaseline
+	if dirBaseline != nil {
+		*g.WildcardStatusCode = dirBaseline.Status
+		g.IsWildcardDirByTitle = dirBaseline.ByTitle
+		g.WildcardDirTitle = dirBaseline.Title
+		g.IsWildcardDirByContentLength = dirBaseline.ByLength
+		g.WildcardDirContentLength = dirBaseline.Length
+	}
+
+	// sites often answer /foo, /foo.php and /foo.aspx differently
+	for ext := range g.Opts.ExtensionsParsed.Set {
+		extBaseline, err := calibrate(g, false, ext)
+		if err != nil {
+			return err
+		}
+		if extBaseline != nil {
+			g.WildcardExtensions[ext] = extBaseline
+		}
+	}
+
+	return nil
+}
+
+// Process is the process implementation of gobusterdir
+func (d GobusterDir) Process(g *libgobuster.Gobuster, busterTarget *libgobuster.BusterTarget) ([]libgobuster.Result, error) {
+	return process(g, busterTarget)
+}
+
+// process requests a word, or the url of a target, and returns its
+// results along with the bypasses of -evasion, each classified once
+func process(g *libgobuster.Gobuster, busterTarget *libgobuster.BusterTarget) ([]libgobuster.Result, error) {
+	suffix := ""
+	if g.Opts.UseSlash {
+		suffix = "/"
+	}
+
+	entity := busterTarget.Target
+	isEntityURL := true
+	url := entity
+	var ret []libgobuster.Result
+
+	if g.Opts.HostFuzz != "" && !busterTarget.IsURL {
+		return processHost(g, busterTarget)
+	}
+
+	if !busterTarget.IsURL {
+		word := strings.TrimPrefix(busterTarget.Target, "/")
+		// the words of -wordlist-dirs have their slash already
+		if strings.HasSuffix(word, "/") {
+			suffix = ""
+		}
+		entity = fmt.Sprintf("%s%s", word, suffix)
+		isEntityURL = false
+		url = g.URLFor(entity)
+	}
+
+	if !g.RobotsAllowed(url) || !g.SafeModeAllowed(entity) {
+		return ret, nil
+	}
+
+	// the HEAD and GET of a word share the request id and agent
+	ro := g.OOBRequestOptions(g.NewRequestOptions(url), url)
+	if g.Opts.FetchArtifacts && libgobuster.IsArtifactPath(entity) {
+		// a dump is saved up to -artifact-max-size, the rest is not read
+		ro.MaxBodySize = g.Opts.ArtifactMaxSize
+	}
+	conditional := g.ConditionalRequestOptions(ro)
+	if g.Opts.HeadFirst {
+		headResp, err := g.HeadRequest(url, conditional)
+		if err != nil {
+			return nil, err
+		}
+		if !headNeedsGet(g, headResp.StatusCode) {
+			result := libgobuster.Result{
+				Entity:        entity,
+				Status:        headResp.StatusCode,
+				Size:          &headResp.Length,
+				Content:       new(string),
+				IsEntityURL:   isEntityURL,
+				RedirectURL:   &headResp.RedirectURL,
+				RedirectChain: headResp.RedirectChain,
+				RequestID:     ro.RequestID,
+				ContentType:   headResp.ContentType,
+				RetryAfter:    libgobuster.ParseRetryAfter(headResp.Header.Get("Retry-After"), g.Now()),
+			}
+			classifyResult(g, &result)
+			ret = append(ret, result)
+			ret = append(ret, evade(g, busterTarget, &result)...)
+			return ret, nil
+		}
+	}
+
+	dirResp, err := g.GetRequest(url, conditional)
+	if err != nil {
+		return nil, err
+	}
+
+	// the body based filters and the retention of the bodies skip the
+	// images, archives and other binary bodies
+	body := dirResp.Content
+	if dirResp.Binary {
+		dirResp.Content = ""
+	}
+	result := libgobuster.Result{
+		Entity:        entity,
+		Status:        dirResp.StatusCode,
+		Size:          &dirResp.Length,
+		Content:       &dirResp.Content,
+		IsEntityURL:   isEntityURL,
+		RedirectURL:   &dirResp.RedirectURL,
+		RedirectChain: dirResp.RedirectChain,
+		RequestID:     ro.RequestID,
+		ContentType:   dirResp.ContentType,
+		RetryAfter:    libgobuster.ParseRetryAfter(dirResp.Header.Get("Retry-After"), g.Now()),
+	}
+	result.Listable = libgobuster.IsDirectoryListing(dirResp.Content)
+	result.SetMeta(libgobuster.MetaContentType, dirResp.ContentType)
+	result.SetMeta(libgobuster.MetaTitle, libgobuster.ExtractTitle(dirResp.Content))
+	if dirResp.Binary {
+		result.SetMeta(libgobuster.MetaBinary, "true")
+	}
+	if g.FaviconHash != nil {
+		result.SetMeta(libgobuster.MetaFavicon, strconv.Itoa(int(*g.FaviconHash)))
+	}
+	if dirResp.Streaming {
+		// event streams and long polls, cut by -stream-timeout
+		result.SetMeta(libgobuster.MetaStreaming, "true")
+	}
+	classifyResult(g, &result)
+	g.AnnotateWAF(&result, dirResp)
+	if g.Opts.API && libgobuster.IsJSON(dirResp.Content) {
+		result.SetMeta(libgobuster.MetaAPI, libgobuster.JSONSummary(dirResp.Content))
+	}
+	if dirResp.StatusCode == http.StatusUnauthorized {
+		// tells Basic, NTLM or Bearer protected paths apart at a glance
+		result.SetMeta(libgobuster.MetaAuth, libgobuster.AuthSummary(dirResp.Header.Values("WWW-Authenticate")))
+	}
+	// only the matches are worth the requests of every method, their
+	// words, the tokens of their paths, the endpoints of their scripts,
+	// the uploads to their directories and the copy of their dumps. The
+	// follow-up actions sending requests are limited to the matches
+	// answering one of -followup-codes.
+	if g.Opts.MethodEnum || g.Opts.WordlistFromMatches || g.Opts.JSEndpoints || g.Opts.Learn || g.Opts.ShowDiff || g.Opts.UploadCheck || g.Opts.FetchArtifacts {
+		if result.Match {
+			if g.Opts.ShowDiff {
+				showDiff(g, &result)
+			}
+			if g.Opts.IsFollowUpStatus(result.Status) {
+				followUp(g, &result, url, dirResp, ro)
+			}
+			if g.Opts.FetchArtifacts && result.IsArtifact() {
+				fetchArtifact(g, &result, body, dirResp.Truncated)
+			}
+		}
+	}
+	ret = append(ret, result)
+	ret = append(ret, evade(g, busterTarget, &result)...)
+
+	return ret, nil
+}
+
+// followUp runs the follow-up actions of a match answering one of
+// -followup-codes
+func followUp(g *libgobuster.Gobuster, result *libgobuster.Result, url string, dirResp *libgobuster.Response, ro libgobuster.RequestOptions) {
+	if g.Opts.MethodEnum {
+		summary, methods := g.EnumerateMethods(url, ro)
+		result.SetMeta(libgobuster.MetaMethods, summary)
+		result.Methods = methods
+	}
+	if g.Opts.WordlistFromMatches {
+		g.AddTargetWords(dirResp.Content, dirResp.ContentType)
+	}
+	if g.Opts.Learn {
+		g.Learn(result.AppPath(g))
+	}
+	if g.Opts.JSEndpoints && libgobuster.IsJavaScript(url, dirResp.ContentType) {
+		g.AddJSEndpoints(dirResp.Content, url)
+	}
+	if g.Opts.UploadCheck {
+		checkUpload(g, result, url, dirResp.RedirectURL, ro)
+	}
+}
+
+// checkUpload runs -upload-check on a matched directory with a writable
+// sounding name, the paths redirecting to their directory included
+func checkUpload(g *libgobuster.Gobuster, r *libgobuster.Result, url, redirectURL string, ro libgobuster.RequestOptions) {
+	dirURL := url
+	if !strings.HasSuffix(r.Entity, "/") {
+		if redirectURL != url+"/" {
+			return
+		}
+		dirURL = redirectURL
+	}
+	if !libgobuster.IsWritableSounding(r.Entity) {
+		return
+	}
+	upload, err := g.CheckUpload(dirURL, ro)
+	if err != nil {
+		g.Logf("[!] Unable to check the upload to %s: %v", dirURL, err)
+		return
+	}
+	if upload != nil {
+		r.SetMeta(libgobuster.MetaUpload, upload.Detail())
+	}
+}
+
+// fetchArtifact saves the body of a matched dump, backup or archive into
+// the quarantine folder of -fetch-artifacts
+func fetchArtifact(g *libgobuster.Gobuster, r *libgobuster.Result, body string, truncated bool) {
+	artifact, err := g.SaveArtifact(r, body, truncated)
+	if err != nil {
+		g.Logf("[!] Unable to save the artifact %s: %v", r.Entity, err)
+		return
+	}
+	if artifact != nil {
+		r.SetMeta(libgobuster.MetaArtifact, artifact.Detail())
+	}
+}
+
+// showDiff writes the diff of a match sharing the status of its
+// wildcard baseline against the baseline body, the borderline matches
+func showDiff(g *libgobuster.Gobuster, r *libgobuster.Result) {
+	baseline := resultBaseline(g, r)
+	if baseline == nil || baseline.Status != r.Status {
+		return
+	}
+	added, removed, err := g.WriteBaselineDiff(r, baseline)
+	if err != nil {
+		g.Logf("[!] Unable to write the diff of %s: %v", r.Entity, err)
+		return
+	}
+	if added > 0 || removed > 0 {
+		r.SetMeta(libgobuster.MetaDiff, fmt.Sprintf("+%d -%d", added, removed))
+	}
+}
+
+// resultBaseline returns the wildcard baseline a result is classified
+// against, nil when none
+func resultBaseline(g *libgobuster.Gobuster, r *libgobuster.Result) *libgobuster.WildcardBaseline {
+	isDir := strings.HasSuffix(r.Entity, "/")
+	if baseline, ok := g.WildcardExtensions[r.Extension()]; ok && !isDir {
+		return baseline
+	}
+	if isDir {
+		return g.WildcardDirBaseline
+	}
+	return g.WildcardFileBaseline
+}
+
+// evade requests the -evasion encodings of a matched word answering 401
+// or 403 and returns the ones the server answers with a 2xx, the
+// bypasses. The wildcard 403s of a protected tree are not worth them.
+func evade(g *libgobuster.Gobuster, busterTarget *libgobuster.BusterTarget, r *libgobuster.Result) []libgobuster.Result {
+	if busterTarget.IsURL || len(g.Opts.EvasionParsed) == 0 || !libgobuster.IsProtected(r.Status) {
+		return nil
+	}
+	if !r.Match {
+		return nil
+	}
+	entity, status := r.Entity, r.Status
+	var ret []libgobuster.Result
+	for _, evaded := range g.EvadedPaths(entity) {
+		url := g.URLFor(evaded.Path)
+		if !g.RobotsAllowed(url) {
+			continue
+		}
+		ro := g.NewRequestOptions(url)
+		resp, err := g.GetRequest(url, ro)
+		if err != nil || resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+			continue
+		}
+		result := libgobuster.Result{
+			Entity:        evaded.Path,
+			Status:        resp.StatusCode,
+			Size:          &resp.Length,
+			Content:       &resp.Content,
+			RedirectURL:   &resp.RedirectURL,
+			RedirectChain: resp.RedirectChain,
+			RequestID:     ro.RequestID,
+			ContentType:   resp.ContentType,
+		}
+		result.SetMeta(libgobuster.MetaBypass, fmt.Sprintf("%s bypass of %s (%d)", evaded.Evasion, entity, status))
+		classifyResult(g, &result)
+		ret = append(ret, result)
+	}
+	return ret
+}
+
+// headNeedsGet reports whether a HEAD status is interesting enough
+// to issue the full GET needed for title/length/string filtering
+func headNeedsGet(g *libgobuster.Gobuster, status int) bool {
+	// servers not supporting HEAD must be checked with a GET
+	if status == http.StatusMethodNotAllowed || status == http.StatusNotImplemented {
+		return true
+	}
+	// a GET would be answered 304 without a body too
+	if status == http.StatusNotModified {
+		return false
+	}
+	return !g.Opts.ExcludedStatusCodesParsed.Contains(status)
+}
+
+// pathLengthBaseline returns the baseline of the paths without an
+// extension when it models pages reflecting the path
+func pathLengthBaseline(g *libgobuster.Gobuster, isDir bool) *libgobuster.WildcardBaseline {
+	baseline := g.WildcardFileBaseline
+	if isDir {
+		baseline = g.WildcardDirBaseline
+	}
+	if baseline == nil || !baseline.ByPathLength {
+		return nil
+	}
+	return baseline
+}
+
+// classify reports whether a result is a match and whether it is a
+// false positive of the wildcard responses
+func classify(g *libgobuster.Gobuster, r *libgobuster.Result) (bool, bool) {
+	isFalsePositive := false
+	isDir := strings.HasSuffix(r.Entity, "/")
+
+	if r.VirtualHost != "" {
+		isFalsePositive = matchesHostBaseline(g, r)
+	} else if baseline, ok := g.WildcardExtensions[r.Extension()]; ok && !isDir {
+		isFalsePositive = matchesBaseline(g, r, baseline)
+	} else if baseline := pathLengthBaseline(g, isDir); baseline != nil {
+		isFalsePositive = matchesBaseline(g, r, baseline)
+	} else if r.Status == *g.WildcardStatusCode {
+		if isDir {
+			if g.IsWildcardDirByTitle {
+				if libgobuster.ExtractTitle(*r.Content) == g.WildcardDirTitle {
+					isFalsePositive = true
+				}
+			} else if g.IsWildcardDirByContentLength {
+				entity := r.Entity
+				if !r.IsEntityURL {
+					entity = g.URLFor(entity)
+				}
+				cleanWildcardContentDir := strings.ReplaceAll(*r.Content, entity, "")
+				if len(cleanWildcardContentDir) == g.WildcardDirContentLength {
+					isFalsePositive = true
+				}
+			}
+		} else {
+			if g.IsWildcardFileByTitle {
+				if libgobuster.ExtractTitle(*r.Content) == g.WildcardFileTitle {
+					isFalsePositive = true
+				}
+			} else if g.IsWildcardFileByContentLength {
+				entity := r.Entity
+				if !r.IsEntityURL {
+					entity = g.URLFor(entity)
+				}
+				cleanWildcardContentFile := strings.ReplaceAll(*r.Content, entity, "")
+				if len(cleanWildcardContentFile) == g.WildcardFileContentLength {
+					isFalsePositive = true
+				}
+			}
+		}
+	}
+
+	// the soft 404s the baselines missed, like the not found pages
+	// differing by the path they name
+	if !isFalsePositive && g.Opts.Heuristic404 && r.Status != http.StatusNotFound && libgobuster.IsNotFoundPage(*r.Content) {
+		isFalsePositive = true
+	}
+
+	hasExcludeString := false
+	if g.Opts.ExcludeString != "" {
+		hasExcludeString = strings.Contains(*r.Content, g.Opts.ExcludeString)
+	}
+
+	// the protected JSON endpoints of -api are matches as long as their
+	// status is not excluded, their error field shown in the extra column
+	isMatch := !g.Opts.ExcludedStatusCodesParsed.Contains(r.Status) && !isFalsePositive && !hasExcludeString
+	// a match expression replaces the status and exclude string filters
+	if g.Opts.MatchParsed != nil {
+		isMatch = !isFalsePositive && g.Opts.MatchParsed.Eval(r.MatchVars(g))
+	}
+	if !g.Opts.ContentTypeAllowed(r.ContentType) || g.Opts.TreatedAsMissing(r.Status, r.Entity) {
+		isMatch = false
+	}
+	return isMatch, isFalsePositive
+}
+
+// classifyResult stores the classification of a result, the follow-ups,
+// the output and the scan loop read it instead of classifying again
+func classifyResult(g *libgobuster.Gobuster, r *libgobuster.Result) {
+	r.Match, r.FalsePositive = classify(g, r)
+}
+
+// ResultToString is the to string implementation of gobusterdir
+func (d GobusterDir) ResultToString(g *libgobuster.Gobuster, r *libgobuster.Result) (*string, *string, int, error) {
+	buf := &bytes.Buffer{}
+	allBuf := &bytes.Buffer{}
+	isMatch, isFalsePositive := r.Match, r.FalsePositive
+
+	// Prefix if we're in verbose mode
+	if g.Opts.Verbose {
+		if isFalsePositive {
+			if _, err := fmt.Fprintf(buf, "%-16s", "FALSE POSITIVE"); err != nil {
+				return nil, nil, 0, err
+			}
+		} else if isMatch {
+			if _, err := fmt.Fprintf(buf, "%-16s", "FOUND"); err != nil {
+				return nil, nil, 0, err
+			}
+		} else {
+			if _, err := fmt.Fprintf(buf, "%-16s", "MISSED"); err != nil {
+				return nil, nil, 0, err
+			}
+		}
+	}
+
+	t := g.Now()
+	if isMatch || g.Opts.Verbose {
+		if _, err := fmt.Fprintf(buf, "%s\n", g.RenderColumns(r, libgobuster.DefaultDirColumns)); err != nil {
+			return nil, nil, 0, err
+		}
+	}
+
+	// -v prints the missed results and the false positives too, only
+	// the matches get an all time line: the results with one are run
+	// through -on-result and recorded as the matches of the run
+	if isMatch {
+		if _, err := fmt.Fprintf(allBuf, "[%d-%02d-%02d %02d:%02d:%02d] - ", t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second()); err != nil {
+			return nil, nil, 0, err
+		}
+
+		// paths are recorded relative to the application root
+		if _, err := fmt.Fprintf(allBuf, "%s - ", r.AppPath(g)); err != nil {
+			return nil, nil, 0, err
+		}
+
+		if _, err := fmt.Fprintf(allBuf, "%d", r.Status); err != nil {
+			return nil, nil, 0, err
+		}
+
+		if *r.RedirectURL != "" {
+			if _, err := fmt.Fprintf(allBuf, "  ->  "); err != nil {
+				return nil, nil, 0, err
+			}
+
+			if _, err := fmt.Fprintf(allBuf, "%s", *r.RedirectURL); err != nil {
+				return nil, nil, 0, err
+			}
+		}
+
+		if _, err := fmt.Fprintf(allBuf, "\n"); err != nil {
+			return nil, nil, 0, err
+		}
+	}
+	s := buf.String()
+	as := allBuf.String()
+	return &s, &as, r.Status, nil
+}
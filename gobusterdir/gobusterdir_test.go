@@ -0,0 +1,641 @@
+package gobusterdir
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gosirys/gobuster/libgobuster"
+)
+
+// newTestGobuster returns a dir mode scan of the test server, the
+// options tweaked by configure
+func newTestGobuster(t *testing.T, serverURL string, configure func(o *libgobuster.Options)) *libgobuster.Gobuster {
+	t.Helper()
+	return newTestGobusterContext(t, context.Background(), serverURL, configure)
+}
+
+// newTestGobusterContext is newTestGobuster cancelled with the context
+func newTestGobusterContext(t *testing.T, c context.Context, serverURL string, configure func(o *libgobuster.Options)) *libgobuster.Gobuster {
+	t.Helper()
+	dir := t.TempDir()
+	wordlist := filepath.Join(dir, "words.txt")
+	if err := ioutil.WriteFile(wordlist, []byte("admin\n"), 0600); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	o := libgobuster.NewOptions()
+	o.URL = serverURL + "/"
+	o.Wordlist = wordlist
+	o.OutputFolder = dir
+	o.Threads = 1
+	o.WildcardProbes = 2
+	o.WildcardLengths = "16,8"
+	o.WildcardCharset = "hex"
+	o.Logger = log.New(ioutil.Discard, "", 0)
+	if configure != nil {
+		configure(o)
+	}
+	g, err := New(c, o)
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	return g
+}
+
+// runScan starts the scan and drains its output channels
+func runScan(g *libgobuster.Gobuster) error {
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for range g.Results() {
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for range g.Errors() {
+		}
+	}()
+	err := g.Start()
+	wg.Wait()
+	return err
+}
+
+// pathRecorder records the paths requested from a test server
+type pathRecorder struct {
+	mu    sync.Mutex
+	paths []string
+}
+
+func (p *pathRecorder) record(path string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.paths = append(p.paths, path)
+}
+
+func TestEvaluateProbes(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName string
+		probes   []wildcardProbe
+		expected *libgobuster.WildcardBaseline
+	}{
+		{"No probes", nil, nil},
+		{"Statuses differ", []wildcardProbe{
+			{status: 200, title: "Home"},
+			{status: 404, title: "Home"},
+		}, nil},
+		{"Same title", []wildcardProbe{
+			{url: "/a", status: 200, title: "Home", length: 10, content: "a"},
+			{url: "/b", status: 200, title: "Home", length: 20, content: "b"},
+		}, &libgobuster.WildcardBaseline{Status: 200, ByTitle: true, Title: "Home", Content: "a", ContentURL: "/a"}},
+		{"Same length", []wildcardProbe{
+			{url: "/a", status: 404, length: 42, content: "a"},
+			{url: "/b", status: 404, length: 42, content: "b"},
+		}, &libgobuster.WildcardBaseline{Status: 404, ByLength: true, Length: 42, Content: "a", ContentURL: "/a"}},
+		{"Length of the path", []wildcardProbe{
+			{url: "/a", status: 404, length: 1, content: "a", pathLength: 5, rawLength: 110},
+			{url: "/b", status: 404, length: 2, content: "b", pathLength: 11, rawLength: 122},
+			{url: "/c", status: 404, length: 3, content: "c", pathLength: 23, rawLength: 146},
+		}, &libgobuster.WildcardBaseline{Status: 404, ByPathLength: true, Intercept: 100, Slope: 2, Content: "a", ContentURL: "/a"}},
+		{"Lengths without a fit", []wildcardProbe{
+			{url: "/a", status: 404, length: 1, content: "a", pathLength: 5, rawLength: 110},
+			{url: "/b", status: 404, length: 2, content: "b", pathLength: 11, rawLength: 117},
+			{url: "/c", status: 404, length: 3, content: "c", pathLength: 23, rawLength: 101},
+		}, &libgobuster.WildcardBaseline{Status: 404, Content: "a", ContentURL: "/a"}},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			got := evaluateProbes(x.probes)
+			if fmt.Sprintf("%+v", got) != fmt.Sprintf("%+v", x.expected) {
+				t.Fatalf("expected %+v, got %+v", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestCalibrate(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName string
+		handler  func(w http.ResponseWriter, r *http.Request)
+		dir      bool
+		ext      string
+		expected string
+	}{
+		{"Catch-all page", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, "<title>Welcome</title>%s", strings.Repeat("x", len(r.URL.Path)))
+		}, false, "", "title Welcome"},
+		{"Page naming the url", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintf(w, "http://%s%s was not found", r.Host, r.URL.Path)
+		}, false, "", "length 14"},
+		{"Page growing with the path", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, strings.Repeat("ab", len(r.URL.Path)-1))
+		}, true, "", "path length 0 + 2"},
+		{"Status of the length", func(w http.ResponseWriter, r *http.Request) {
+			if len(r.URL.Path) > 15 {
+				w.WriteHeader(http.StatusNotFound)
+			}
+			fmt.Fprint(w, "<title>Maybe</title>")
+		}, false, "php", "none"},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			h := httptest.NewServer(http.HandlerFunc(x.handler))
+			defer h.Close()
+			g := newTestGobuster(t, h.URL, nil)
+
+			baseline, err := calibrate(g, x.dir, x.ext)
+			if err != nil {
+				t.Fatalf("got error: %v", err)
+			}
+			got := "none"
+			switch {
+			case baseline == nil:
+			case baseline.ByTitle:
+				got = "title " + baseline.Title
+			case baseline.ByLength:
+				got = fmt.Sprintf("length %d", baseline.Length)
+			case baseline.ByPathLength:
+				got = fmt.Sprintf("path length %d + %d", baseline.Intercept, baseline.Slope)
+			}
+			if got != x.expected {
+				t.Fatalf("expected %s, got %s", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestProbeWildcard(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName string
+		probes   int
+		lengths  string
+		charset  string
+		dir      bool
+		ext      string
+		expected []int
+		allowed  string
+	}{
+		{"Lengths cycled", 3, "16,8", "hex", false, "", []int{16, 8, 16}, "0123456789abcdef"},
+		{"Directories", 2, "6", "lower", true, "", []int{6, 6}, "abcdefghijklmnopqrstuvwxyz/"},
+		{"Extension", 2, "10", "xyz", false, "php", []int{14, 14}, "xyz.ph"},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			var paths pathRecorder
+			h := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				paths.record(strings.TrimPrefix(r.URL.Path, "/"))
+				w.WriteHeader(http.StatusNotFound)
+			}))
+			defer h.Close()
+			g := newTestGobuster(t, h.URL, func(o *libgobuster.Options) {
+				o.WildcardProbes = x.probes
+				o.WildcardLengths = x.lengths
+				o.WildcardCharset = x.charset
+			})
+
+			probes, err := probeWildcard(g, x.dir, x.ext)
+			if err != nil {
+				t.Fatalf("got error: %v", err)
+			}
+			if len(probes) != len(x.expected) || len(paths.paths) != len(x.expected) {
+				t.Fatalf("expected %d probes, got %d probes and %d requests", len(x.expected), len(probes), len(paths.paths))
+			}
+			for i, p := range paths.paths {
+				if len(p) != x.expected[i] {
+					t.Fatalf("expected a path of %d characters, got %q", x.expected[i], p)
+				}
+				if strings.Trim(p, x.allowed) != "" {
+					t.Fatalf("unexpected characters in %q", p)
+				}
+				if x.dir != strings.HasSuffix(p, "/") {
+					t.Fatalf("expected a directory %v, got %q", x.dir, p)
+				}
+				if x.ext != "" && !strings.HasSuffix(p, "."+x.ext) {
+					t.Fatalf("expected the extension %s, got %q", x.ext, p)
+				}
+				if probes[i].status != http.StatusNotFound {
+					t.Fatalf("expected the status 404, got %d", probes[i].status)
+				}
+			}
+		})
+	}
+}
+
+func TestMatchesBaselinePerExtension(t *testing.T) {
+	t.Parallel()
+
+	const (
+		plainPage = "<title>Nothing here</title>"
+		phpPage   = "<title>PHP script not found</title>"
+	)
+	h := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, ".php") {
+			fmt.Fprint(w, phpPage)
+			return
+		}
+		fmt.Fprint(w, plainPage)
+	}))
+	defer h.Close()
+	g := newTestGobuster(t, h.URL, func(o *libgobuster.Options) {
+		o.Extensions = "php"
+	})
+	if err := (GobusterDir{}).Setup(g); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if g.WildcardExtensions["php"] == nil || g.WildcardExtensions["php"].Title != "PHP script not found" {
+		t.Fatalf("expected the php baseline by its own page, got %+v", g.WildcardExtensions["php"])
+	}
+
+	tt := []struct {
+		entity        string
+		content       string
+		falsePositive bool
+	}{
+		{"x", plainPage, true},
+		{"x", phpPage, false},
+		{"x.php", phpPage, true},
+		{"x.php", plainPage, false},
+	}
+	for _, x := range tt {
+		x := x
+		t.Run(x.entity+" "+x.content, func(t *testing.T) {
+			t.Parallel()
+
+			content := x.content
+			size := int64(len(content))
+			r := &libgobuster.Result{Entity: x.entity, Status: http.StatusOK, Content: &content, Size: &size}
+			if _, got := classify(g, r); got != x.falsePositive {
+				t.Fatalf("expected false positive %v, got %v", x.falsePositive, got)
+			}
+		})
+	}
+}
+
+func TestStartCancelledDuringRetry(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var retried sync.Once
+	h := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "word") {
+			// the retries are waiting for the Retry-After when the
+			// scan is cancelled
+			w.Header().Set("Retry-After", "2")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			retried.Do(func() { time.AfterFunc(100*time.Millisecond, cancel) })
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer h.Close()
+	g := newTestGobusterContext(t, ctx, h.URL, func(o *libgobuster.Options) {
+		o.Threads = 4
+		words := filepath.Join(t.TempDir(), "words.txt")
+		if err := ioutil.WriteFile(words, []byte("word1\nword2\nword3\nword4\nword5\nword6\n"), 0600); err != nil {
+			t.Fatalf("got error: %v", err)
+		}
+		o.Wordlist = words
+	})
+
+	done := make(chan error, 1)
+	go func() { done <- runScan(g) }()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("the cancelled scan did not return")
+	}
+}
+
+func TestSetupRespectsRobots(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName      string
+		robots        int
+		expected      []string
+		expectedError error
+	}{
+		{"Unreachable robots.txt", http.StatusInternalServerError, []string{"robots.txt"}, libgobuster.ErrRobotsDisallowAll},
+		{"Disallowed extension", http.StatusOK, []string{"robots.txt", "", "favicon.ico", "file", "file", "dir/", "dir/"}, nil},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			var paths pathRecorder
+			h := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				path := strings.TrimPrefix(r.URL.Path, "/")
+				switch {
+				case path == "robots.txt":
+					paths.record(path)
+					w.WriteHeader(x.robots)
+					fmt.Fprint(w, "User-agent: *\nDisallow: /*.php$\n")
+					return
+				case path == "favicon.ico":
+					paths.record(path)
+				case strings.HasSuffix(path, "/"):
+					paths.record("dir/")
+				case path != "":
+					paths.record("file")
+				default:
+					paths.record(path)
+				}
+				w.WriteHeader(http.StatusNotFound)
+			}))
+			defer h.Close()
+			g := newTestGobuster(t, h.URL, func(o *libgobuster.Options) {
+				o.RespectRobots = true
+				o.Extensions = "php"
+			})
+
+			if err := (GobusterDir{}).Setup(g); err != x.expectedError {
+				t.Fatalf("expected error %v, got %v", x.expectedError, err)
+			}
+			if fmt.Sprint(paths.paths) != fmt.Sprint(x.expected) {
+				t.Fatalf("expected the requests %q, got %q", x.expected, paths.paths)
+			}
+		})
+	}
+}
+
+func TestSetupFetchesFavicon(t *testing.T) {
+	t.Parallel()
+
+	icon := []byte("\x00\x00\x01\x00icon")
+	h := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/favicon.ico" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "image/x-icon")
+		w.Write(icon)
+	}))
+	defer h.Close()
+	g := newTestGobuster(t, h.URL, nil)
+
+	if err := g.Setup(); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if g.FaviconHash == nil || *g.FaviconHash != libgobuster.FaviconHash(icon) {
+		t.Fatalf("expected the hash %d, got %v", libgobuster.FaviconHash(icon), g.FaviconHash)
+	}
+}
+
+func TestStartStopsBackgroundWork(t *testing.T) {
+	t.Parallel()
+
+	h := httptest.NewServer(http.NotFoundHandler())
+	defer h.Close()
+	g := newTestGobuster(t, h.URL, func(o *libgobuster.Options) {
+		o.ConnRecycle = time.Hour
+		o.DebugStats = time.Hour
+	})
+	if err := runScan(g); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+
+	// the scan's context is never cancelled by the caller
+	running := func() []string {
+		buf := make([]byte, 1<<20)
+		stacks := string(buf[:runtime.Stack(buf, true)])
+		var found []string
+		for _, name := range []string{"recycleEvery", "logStatsEvery"} {
+			if strings.Contains(stacks, name) {
+				found = append(found, name)
+			}
+		}
+		return found
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for len(running()) > 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("goroutines still running after Start returned: %v", running())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestEvadeMatchesOnly(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName string
+		wildcard int
+		evaded   int
+		expected []string
+	}{
+		{"Bypass", http.StatusNotFound, http.StatusOK, []string{"admin", "AdMiN"}},
+		{"Redirect is no bypass", http.StatusNotFound, http.StatusFound, []string{"admin"}},
+		{"Wildcard 403", http.StatusForbidden, http.StatusOK, []string{"admin"}},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			h := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/admin":
+					w.WriteHeader(http.StatusForbidden)
+				case "/AdMiN":
+					w.Header().Set("Location", "/login")
+					w.WriteHeader(x.evaded)
+				default:
+					w.WriteHeader(x.wildcard)
+				}
+			}))
+			defer h.Close()
+			g := newTestGobuster(t, h.URL, func(o *libgobuster.Options) {
+				o.Evasion = libgobuster.EvasionCase
+				o.EvasionParsed = [][]string{{libgobuster.EvasionCase}}
+			})
+			if err := g.Setup(); err != nil {
+				t.Fatalf("got error: %v", err)
+			}
+
+			results, err := (GobusterDir{}).Process(g, &libgobuster.BusterTarget{Target: "admin"})
+			if err != nil {
+				t.Fatalf("got error: %v", err)
+			}
+			var got []string
+			for _, r := range results {
+				got = append(got, r.Entity)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(x.expected) {
+				t.Fatalf("expected the results %q, got %q", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestHeadNeedsGet(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName string
+		status   int
+		expected bool
+	}{
+		{"HEAD not allowed", http.StatusMethodNotAllowed, true},
+		{"HEAD not implemented", http.StatusNotImplemented, true},
+		{"Not modified", http.StatusNotModified, false},
+		{"Excluded status", http.StatusNotFound, false},
+		{"Found", http.StatusOK, true},
+		{"Forbidden", http.StatusForbidden, true},
+	}
+
+	h := httptest.NewServer(http.NotFoundHandler())
+	defer h.Close()
+	g := newTestGobuster(t, h.URL, func(o *libgobuster.Options) {
+		o.ExcludedStatusCodesParsed.Add(http.StatusNotFound)
+	})
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			if got := headNeedsGet(g, x.status); got != x.expected {
+				t.Fatalf("expected %t, got %t", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestProcessHeadFirst(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName string
+		head     int
+		get      bool
+		status   int
+	}{
+		{"HEAD not allowed", http.StatusMethodNotAllowed, true, http.StatusOK},
+		{"HEAD not implemented", http.StatusNotImplemented, true, http.StatusOK},
+		{"Not modified", http.StatusNotModified, false, http.StatusNotModified},
+		{"Excluded status", http.StatusNotFound, false, http.StatusNotFound},
+		{"Found", http.StatusOK, true, http.StatusOK},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			var gets int32
+			h := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/admin" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				if r.Method == http.MethodHead {
+					w.WriteHeader(x.head)
+					return
+				}
+				atomic.AddInt32(&gets, 1)
+				fmt.Fprint(w, "<title>Admin</title>")
+			}))
+			defer h.Close()
+			g := newTestGobuster(t, h.URL, func(o *libgobuster.Options) {
+				o.HeadFirst = true
+				o.ExcludedStatusCodesParsed.Add(http.StatusNotFound)
+			})
+			if err := g.Setup(); err != nil {
+				t.Fatalf("got error: %v", err)
+			}
+
+			results, err := (GobusterDir{}).Process(g, &libgobuster.BusterTarget{Target: "admin"})
+			if err != nil {
+				t.Fatalf("got error: %v", err)
+			}
+			if len(results) != 1 || results[0].Status != x.status {
+				t.Fatalf("expected a result with status %d, got %v", x.status, results)
+			}
+			if got := atomic.LoadInt32(&gets) > 0; got != x.get {
+				t.Fatalf("expected a GET %t, got %t", x.get, got)
+			}
+			// only the GET brings the body of the title filters
+			if hasBody := *results[0].Content != ""; hasBody != x.get {
+				t.Fatalf("expected a body %t, got %q", x.get, *results[0].Content)
+			}
+		})
+	}
+}
+
+func TestResultToStringVerbose(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName string
+		status   int
+		allTime  bool
+	}{
+		{"Match", http.StatusOK, true},
+		{"Missed", http.StatusNotFound, false},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			h := httptest.NewServer(http.NotFoundHandler())
+			defer h.Close()
+			g := newTestGobuster(t, h.URL, func(o *libgobuster.Options) {
+				o.Verbose = true
+				o.ExcludedStatusCodesParsed.Add(http.StatusNotFound)
+			})
+			if err := g.Setup(); err != nil {
+				t.Fatalf("got error: %v", err)
+			}
+			content, redirect := "", ""
+			size := int64(0)
+			r := &libgobuster.Result{Entity: "admin", Status: x.status, Content: &content, Size: &size, RedirectURL: &redirect}
+			classifyResult(g, r)
+			s, as, _, err := (GobusterDir{}).ResultToString(g, r)
+			if err != nil {
+				t.Fatalf("got error: %v", err)
+			}
+			if *s == "" {
+				t.Fatal("expected -v to print the result")
+			}
+			if got := *as != ""; got != x.allTime {
+				t.Fatalf("expected an all time line %t, got %q", x.allTime, *as)
+			}
+		})
+	}
+}
@@ -1,96 +1,192 @@
-package gobusterdns
-
-import (
-	"bytes"
-	"fmt"
-	"log"
-	"strings"
-
-	"yBuster/libgobuster"
-
-	"github.com/google/uuid"
-)
-
-// GobusterDNS is the main type to implement the interface
-type GobusterDNS struct{}
-
-// Setup is the setup implementation of gobusterdns
-func (d GobusterDNS) Setup(g *libgobuster.Gobuster) error {
-	// Resolve a subdomain sthat probably shouldn't exist
-	guid := uuid.New()
-	wildcardIps, err := g.DNSLookup(fmt.Sprintf("%s.%s", guid, g.Opts.URL))
-	if err == nil {
-		g.IsWildcard = true
-		g.WildcardIps.AddRange(wildcardIps)
-		log.Printf("[-] Wildcard DNS found. IP address(es): %s", g.WildcardIps.Stringify())
-		if !g.Opts.WildcardForced {
-			return fmt.Errorf("To force processing of Wildcard DNS, specify the '-fw' switch.")
-		}
-	}
-
-	if !g.Opts.Quiet {
-		// Provide a warning if the base domain doesn't resolve (in case of typo)
-		_, err = g.DNSLookup(g.Opts.URL)
-		if err != nil {
-			// Not an error, just a warning. Eg. `yp.to` doesn't resolve, but `cr.py.to` does!
-			log.Printf("[-] Unable to validate base domain: %s", g.Opts.URL)
-		}
-	}
-
-	return nil
-}
-
-// Process is the process implementation of gobusterdns
-func (d GobusterDNS) Process(g *libgobuster.Gobuster, busterTarget *libgobuster.BusterTarget) ([]libgobuster.Result, error) {
-	subdomain := fmt.Sprintf("%s.%s", busterTarget.Target, g.Opts.URL)
-	ips, err := g.DNSLookup(subdomain)
-	var ret []libgobuster.Result
-	if err == nil {
-		if !g.IsWildcard || !g.WildcardIps.ContainsAny(ips) {
-			result := libgobuster.Result{
-				Entity: subdomain,
-			}
-			if g.Opts.ShowIPs {
-				result.Extra = strings.Join(ips, ", ")
-			} else if g.Opts.ShowCNAME {
-				cname, err := g.DNSLookupCname(subdomain)
-				if err == nil {
-					result.Extra = cname
-				}
-			}
-			ret = append(ret, result)
-		}
-	} else if g.Opts.Verbose {
-		ret = append(ret, libgobuster.Result{
-			Entity: subdomain,
-			Status: 404,
-		})
-	}
-	return ret, nil
-}
-
-// ResultToString is the to string implementation of gobusterdns
-func (d GobusterDNS) ResultToString(g *libgobuster.Gobuster, r *libgobuster.Result) (*string, *string, int, error) {
-	buf := &bytes.Buffer{}
-
-	if r.Status == 404 {
-		if _, err := fmt.Fprintf(buf, "Missing: %s\n", r.Entity); err != nil {
-			return nil, nil, 0, err
-		}
-	} else if g.Opts.ShowIPs {
-		if _, err := fmt.Fprintf(buf, "Found: %s [%s]\n", r.Entity, r.Extra); err != nil {
-			return nil, nil, 0, err
-		}
-	} else if g.Opts.ShowCNAME {
-		if _, err := fmt.Fprintf(buf, "Found: %s [%s]\n", r.Entity, r.Extra); err != nil {
-			return nil, nil, 0, err
-		}
-	} else {
-		if _, err := fmt.Fprintf(buf, "Found: %s\n", r.Entity); err != nil {
-			return nil, nil, 0, err
-		}
-	}
-
-	s := buf.String()
-	return &s, nil, r.Status, nil
-}
+package gobusterdns
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gosirys/gobuster/libgobuster"
+
+	"github.com/google/uuid"
+)
+
+// GobusterDNS is the main type to implement the interface
+type GobusterDNS struct{}
+
+// New returns a dns mode scan of the options, ready to Start
+func New(c context.Context, opts *libgobuster.Options) (*libgobuster.Gobuster, error) {
+	opts.Mode = libgobuster.ModeDNS
+	return libgobuster.NewGobuster(c, opts, GobusterDNS{})
+}
+
+// wildcardProbes is the number of random subdomains resolved to detect
+// wildcard records, which often rotate over several addresses
+const wildcardProbes = 3
+
+// Setup is the setup implementation of gobusterdns
+func (d GobusterDNS) Setup(g *libgobuster.Gobuster) error {
+	// Resolve subdomains that probably shouldn't exist
+	for i := 0; i < wildcardProbes; i++ {
+		wildcardIps, err := g.DNSLookup(fmt.Sprintf("%s.%s", uuid.New(), g.Opts.URL))
+		if err == nil {
+			g.IsWildcard = true
+			g.WildcardIps.AddRange(wildcardIps)
+		}
+	}
+
+	if g.IsWildcard {
+		g.Logf("[-] Wildcard DNS found. IP address(es): %s", g.WildcardIps.Stringify())
+		// known wildcard addresses are filtered without forcing
+		unknown := false
+		for ip := range g.WildcardIps.Set {
+			if !g.Opts.WildcardAllowParsed.Contains(ip) {
+				unknown = true
+			}
+		}
+		if unknown && !g.Opts.WildcardForced {
+			return fmt.Errorf("To force processing of Wildcard DNS, specify the '-fw' switch or list the addresses with '-wildcard-allow'.")
+		}
+	}
+
+	// the allowed addresses are filtered even when the probes missed them
+	if g.Opts.WildcardAllowParsed.Len() > 0 {
+		g.IsWildcard = true
+		g.WildcardIps = g.WildcardIps.Union(g.Opts.WildcardAllowParsed)
+	}
+
+	if !g.Opts.Quiet {
+		// Provide a warning if the base domain doesn't resolve (in case of typo)
+		_, err := g.DNSLookup(g.Opts.URL)
+		if err != nil {
+			// Not an error, just a warning. Eg. `yp.to` doesn't resolve, but `cr.py.to` does!
+			g.Logf("[-] Unable to validate base domain: %s", g.Opts.URL)
+		}
+	}
+
+	return nil
+}
+
+// Process is the process implementation of gobusterdns
+func (d GobusterDNS) Process(g *libgobuster.Gobuster, busterTarget *libgobuster.BusterTarget) ([]libgobuster.Result, error) {
+	subdomain := fmt.Sprintf("%s.%s", busterTarget.Target, g.Opts.URL)
+	ips, err := g.DNSLookup(subdomain)
+	var ret []libgobuster.Result
+	if err == nil {
+		if !g.IsWildcard || !g.WildcardIps.ContainsAny(ips) {
+			result := libgobuster.Result{
+				Entity: subdomain,
+				IPs:    ips,
+			}
+			result.SetMeta(libgobuster.MetaIPs, strings.Join(ips, ", "))
+			// the CNAME costs a lookup per subdomain, it is recorded in
+			// the output files when -cn or -takeover asks for it
+			if g.Opts.ShowCNAME || g.Opts.Takeover {
+				cname, err := g.DNSLookupCname(subdomain)
+				if err == nil && strings.TrimSuffix(cname, ".") != subdomain {
+					result.CNAME = strings.TrimSuffix(cname, ".")
+				}
+				if err == nil {
+					result.SetMeta(libgobuster.MetaCNAME, cname)
+				}
+			}
+			if g.Opts.Takeover && result.CNAME != "" {
+				result.Takeover = libgobuster.MatchTakeover(g.Opts.TakeoverFingerprintsParsed, result.CNAME, false)
+			}
+			ret = append(ret, result)
+		}
+	} else if libgobuster.ClassifyDNSError(err) != libgobuster.DNSErrorNXDomain {
+		// servfail, timeouts and others end up in the error channel
+		return nil, err
+	} else if result, ok := danglingCNAME(g, subdomain); ok {
+		ret = append(ret, result)
+	} else if g.Opts.Verbose {
+		result := libgobuster.Result{
+			Entity: subdomain,
+			Status: 404,
+		}
+		result.SetMeta(libgobuster.MetaError, libgobuster.DNSErrorNXDomain)
+		ret = append(ret, result)
+	}
+	return ret, nil
+}
+
+// danglingCNAME returns a result for a subdomain that does not resolve
+// because its CNAME points to a name that does not exist
+func danglingCNAME(g *libgobuster.Gobuster, subdomain string) (libgobuster.Result, bool) {
+	if !g.Opts.Takeover {
+		return libgobuster.Result{}, false
+	}
+	cname, err := g.DNSLookupCname(subdomain)
+	cname = strings.TrimSuffix(cname, ".")
+	if err != nil || cname == "" || cname == subdomain {
+		return libgobuster.Result{}, false
+	}
+	result := libgobuster.Result{
+		Entity:   subdomain,
+		CNAME:    cname,
+		Takeover: libgobuster.MatchTakeover(g.Opts.TakeoverFingerprintsParsed, cname, true),
+	}
+	result.SetMeta(libgobuster.MetaCNAME, cname)
+	return result, true
+}
+
+// ResultToString is the to string implementation of gobusterdns
+func (d GobusterDNS) ResultToString(g *libgobuster.Gobuster, r *libgobuster.Result) (*string, *string, int, error) {
+	buf := &bytes.Buffer{}
+
+	if len(g.Opts.ColumnsParsed) > 0 {
+		// -columns lays the subdomains out like the paths of dir mode
+		if _, err := fmt.Fprintf(buf, "%s\n", g.RenderColumns(r, nil)); err != nil {
+			return nil, nil, 0, err
+		}
+	} else if r.Status == 404 {
+		if _, err := fmt.Fprintf(buf, "Missing: %s (%s)\n", r.Entity, r.Metadata[libgobuster.MetaError]); err != nil {
+			return nil, nil, 0, err
+		}
+	} else if g.Opts.ShowIPs {
+		if _, err := fmt.Fprintf(buf, "Found: %s [%s]\n", r.Entity, r.Metadata[libgobuster.MetaIPs]); err != nil {
+			return nil, nil, 0, err
+		}
+	} else if g.Opts.ShowCNAME {
+		if _, err := fmt.Fprintf(buf, "Found: %s [%s]\n", r.Entity, r.Metadata[libgobuster.MetaCNAME]); err != nil {
+			return nil, nil, 0, err
+		}
+	} else {
+		if _, err := fmt.Fprintf(buf, "Found: %s\n", r.Entity); err != nil {
+			return nil, nil, 0, err
+		}
+	}
+
+	if r.Takeover != "" {
+		s := strings.TrimSuffix(buf.String(), "\n")
+		buf.Reset()
+		if _, err := fmt.Fprintf(buf, "%s - possible takeover: %s (CNAME %s)\n", s, r.Takeover, r.CNAME); err != nil {
+			return nil, nil, 0, err
+		}
+	}
+
+	if g.Opts.ShowSource && r.Status != 404 {
+		source := r.DNSSource()
+		if r.CNAME != "" {
+			source = fmt.Sprintf("%s %s", source, r.CNAME)
+		}
+		s := strings.TrimSuffix(buf.String(), "\n")
+		buf.Reset()
+		if _, err := fmt.Fprintf(buf, "%s (%s)\n", s, source); err != nil {
+			return nil, nil, 0, err
+		}
+	}
+
+	allBuf := &bytes.Buffer{}
+	if r.Status != 404 {
+		t := g.Now()
+		if _, err := fmt.Fprintf(allBuf, "[%d-%02d-%02d %02d:%02d:%02d] - %s - %s - %s\n", t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), r.Entity, strings.Join(r.IPs, ","), r.CNAME); err != nil {
+			return nil, nil, 0, err
+		}
+	}
+
+	s := buf.String()
+	as := allBuf.String()
+	return &s, &as, r.Status, nil
+}
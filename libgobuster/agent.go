@@ -0,0 +1,79 @@
+package libgobuster
+
+import (
+	"net/url"
+	"sync"
+)
+
+const (
+	// AgentPerRequest picks a new random agent for every request
+	AgentPerRequest = "per-request"
+	// AgentPerHost keeps one random agent per target host
+	AgentPerHost = "per-host"
+	// AgentPerRun keeps one random agent for the whole run
+	AgentPerRun = "per-run"
+)
+
+// agentPicker chooses random user agents following the agent strategy
+type agentPicker struct {
+	mu       sync.Mutex
+	strategy string
+	agents   []string
+	chosen   map[string]string
+	rnd      Rand
+}
+
+func newAgentPicker(strategy string, agents []string, rnd Rand) *agentPicker {
+	return &agentPicker{
+		strategy: strategy,
+		agents:   agents,
+		chosen:   make(map[string]string),
+		rnd:      rnd,
+	}
+}
+
+// pick returns the agent to use for a request to the given url
+func (p *agentPicker) pick(target string) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.strategy == AgentPerRequest {
+		return p.agents[p.rnd.Intn(len(p.agents))]
+	}
+
+	key := ""
+	if p.strategy == AgentPerHost {
+		if u, err := url.Parse(target); err == nil {
+			key = u.Host
+		}
+	}
+	agent, ok := p.chosen[key]
+	if !ok {
+		agent = p.agents[p.rnd.Intn(len(p.agents))]
+		p.chosen[key] = agent
+	}
+	return agent
+}
+
+// sticky returns the agents chosen so far by host ("" for per-run)
+func (p *agentPicker) sticky() map[string]string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.chosen) == 0 {
+		return nil
+	}
+	chosen := make(map[string]string, len(p.chosen))
+	for k, v := range p.chosen {
+		chosen[k] = v
+	}
+	return chosen
+}
+
+// RandomAgent returns the random agent to use for a request to the
+// given url, or an empty string when no random agents are configured
+func (g *Gobuster) RandomAgent(target string) string {
+	if g.agents == nil {
+		return ""
+	}
+	return g.agents.pick(target)
+}
@@ -0,0 +1,61 @@
+package libgobuster
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestAgentPicker(t *testing.T) {
+	t.Parallel()
+
+	var agents []string
+	for i := 0; i < 100; i++ {
+		agents = append(agents, fmt.Sprintf("agent-%d", i))
+	}
+
+	perHost := newAgentPicker(AgentPerHost, agents, NewRand(1))
+	a := perHost.pick("http://a.example.com/x")
+	b := perHost.pick("http://b.example.com/x")
+	for i := 0; i < 20; i++ {
+		if got := perHost.pick(fmt.Sprintf("http://a.example.com/%d", i)); got != a {
+			t.Fatalf("per-host: expected %q for a.example.com, got %q", a, got)
+		}
+		if got := perHost.pick(fmt.Sprintf("http://b.example.com/%d", i)); got != b {
+			t.Fatalf("per-host: expected %q for b.example.com, got %q", b, got)
+		}
+	}
+	if len(perHost.sticky()) != 2 {
+		t.Fatalf("per-host: expected 2 sticky agents, got %v", perHost.sticky())
+	}
+
+	perRun := newAgentPicker(AgentPerRun, agents, NewRand(1))
+	r := perRun.pick("http://a.example.com/")
+	if got := perRun.pick("http://b.example.com/"); got != r {
+		t.Fatalf("per-run: expected %q, got %q", r, got)
+	}
+
+	perRequest := newAgentPicker(AgentPerRequest, agents, NewRand(1))
+	seen := make(map[string]bool)
+	for i := 0; i < 50; i++ {
+		seen[perRequest.pick("http://a.example.com/")] = true
+	}
+	if len(seen) < 2 {
+		t.Fatalf("per-request: expected rotating agents, got %v", seen)
+	}
+	if perRequest.sticky() != nil {
+		t.Fatalf("per-request: expected no sticky agents, got %v", perRequest.sticky())
+	}
+}
+
+func TestAgentPickerSeeded(t *testing.T) {
+	t.Parallel()
+
+	agents := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+	first := newAgentPicker(AgentPerRequest, agents, NewRand(42))
+	second := newAgentPicker(AgentPerRequest, agents, NewRand(42))
+	for i := 0; i < 20; i++ {
+		if a, b := first.pick("http://a.example.com/"), second.pick("http://a.example.com/"); a != b {
+			t.Fatalf("expected the same seed to pick the same agents, got %q and %q", a, b)
+		}
+	}
+}
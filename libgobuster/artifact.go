@@ -0,0 +1,96 @@
+package libgobuster
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// artifactsFolder is the quarantine folder of -fetch-artifacts
+	artifactsFolder = "output_artifacts"
+	// artifactExt is appended to the saved files so none of them opens or
+	// runs by its extension
+	artifactExt = ".quarantine"
+	// maxArtifactsTotal caps the bytes saved by a scan, whatever the size
+	// of every artifact
+	maxArtifactsTotal = 256 * 1024 * 1024
+	// DefaultArtifactMaxSize is the default cap of a saved artifact
+	DefaultArtifactMaxSize = 10 * 1024 * 1024
+)
+
+// artifactExtensions are the extensions of the dumps, backups and archives
+// left on servers, the matches worth a copy
+var artifactExtensions = NewSet(
+	"sql", "dump", "db", "sqlite", "sqlite3", "mdb",
+	"bak", "backup", "old", "orig", "save", "swp",
+	"zip", "tar", "gz", "tgz", "bz2", "xz", "7z", "rar", "war", "jar",
+	"key", "pem", "p12", "pfx", "kdbx",
+)
+
+// Artifact is a match with an interesting extension saved by
+// -fetch-artifacts
+type Artifact struct {
+	Path      string
+	Size      int64
+	Truncated bool
+}
+
+// String returns the annotation of the match saved
+func (a *Artifact) String() string {
+	return "artifact: " + a.Detail()
+}
+
+// Detail returns the size of the artifact saved
+func (a *Artifact) Detail() string {
+	if a.Truncated {
+		return fmt.Sprintf("truncated at %d bytes", a.Size)
+	}
+	return fmt.Sprintf("%d bytes", a.Size)
+}
+
+// IsArtifact reports whether the extension of the result is the one of a
+// dump, backup or archive
+func (r *Result) IsArtifact() bool {
+	return artifactExtensions.Contains(strings.ToLower(r.Extension()))
+}
+
+// IsArtifactPath reports whether a path has the extension of a dump,
+// backup or archive, the requests whose body is read up to
+// -artifact-max-size
+func IsArtifactPath(entity string) bool {
+	r := Result{Entity: entity}
+	return r.IsArtifact()
+}
+
+// SaveArtifact writes the body of a match into the quarantine folder,
+// capped by -artifact-max-size. Truncated tells the body was already cut
+// by -max-response-size. It returns nil once the scan saved its share.
+func (g *Gobuster) SaveArtifact(r *Result, body string, truncated bool) (*Artifact, error) {
+	if int64(len(body)) > g.Opts.ArtifactMaxSize {
+		body = body[:g.Opts.ArtifactMaxSize]
+		truncated = true
+	}
+	size := int64(len(body))
+	g.mu.Lock()
+	full := g.artifactBytes+size > maxArtifactsTotal
+	if !full {
+		g.artifactBytes += size
+	}
+	g.mu.Unlock()
+	if full {
+		return nil, nil
+	}
+
+	folder := filepath.Join(g.Opts.OutputFolder, artifactsFolder)
+	if err := os.MkdirAll(folder, 0700); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(folder, fmt.Sprintf("%s_%s%s", g.OutputFileSuffix(), entityFileName(r.Entity), artifactExt))
+	if err := ioutil.WriteFile(path, []byte(body), 0600); err != nil {
+		return nil, err
+	}
+	return &Artifact{Path: path, Size: size, Truncated: truncated}, nil
+}
@@ -0,0 +1,84 @@
+package libgobuster
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestIsArtifact(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		entity   string
+		expected bool
+	}{
+		{"backup.sql", true},
+		{"site.tar.gz", true},
+		{"db.BAK", true},
+		{"dump.zip?download=1", true},
+		{"index.php", false},
+		{"admin/", false},
+	}
+	for _, x := range tt {
+		x := x
+		t.Run(x.entity, func(t *testing.T) {
+			t.Parallel()
+
+			r := Result{Entity: x.entity}
+			if got := r.IsArtifact(); got != x.expected {
+				t.Fatalf("expected %t, got %t", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestSaveArtifact(t *testing.T) {
+	t.Parallel()
+
+	o := NewOptions()
+	o.OutputFolder = t.TempDir()
+	o.ArtifactMaxSize = 8
+	g := &Gobuster{Opts: o, mu: new(sync.RWMutex)}
+
+	artifact, err := g.SaveArtifact(&Result{Entity: "db/backup.sql"}, "CREATE TABLE users;", false)
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if !artifact.Truncated || artifact.Size != 8 {
+		t.Fatalf("expected a truncated artifact of 8 bytes, got %+v", artifact)
+	}
+	if dir := filepath.Dir(artifact.Path); dir != filepath.Join(o.OutputFolder, artifactsFolder) {
+		t.Fatalf("unexpected folder %s", dir)
+	}
+	if !strings.HasSuffix(artifact.Path, "_db_backup.sql"+artifactExt) {
+		t.Fatalf("unexpected path %s", artifact.Path)
+	}
+	// the name of a path with a slash is not the one of the same path
+	// with an underscore
+	other, err := g.SaveArtifact(&Result{Entity: "db_backup.sql"}, "DROP", false)
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if other.Path == artifact.Path {
+		t.Fatalf("expected distinct paths, got %s twice", other.Path)
+	}
+	content, err := ioutil.ReadFile(artifact.Path)
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if string(content) != "CREATE T" {
+		t.Fatalf("unexpected content %q", content)
+	}
+	if got := artifact.String(); got != "artifact: truncated at 8 bytes" {
+		t.Fatalf("unexpected extra %q", got)
+	}
+
+	// nothing is saved once the scan saved its share
+	g.artifactBytes = maxArtifactsTotal
+	if artifact, err := g.SaveArtifact(&Result{Entity: "site.zip"}, "PK", false); artifact != nil || err != nil {
+		t.Fatalf("expected no artifact, got %+v %v", artifact, err)
+	}
+}
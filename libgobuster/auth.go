@@ -0,0 +1,100 @@
+package libgobuster
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AuthChallenge is a single challenge of a WWW-Authenticate header
+type AuthChallenge struct {
+	Scheme string
+	Realm  string
+}
+
+func (c AuthChallenge) String() string {
+	if c.Realm == "" {
+		return c.Scheme
+	}
+	return fmt.Sprintf("%s realm=%q", c.Scheme, c.Realm)
+}
+
+// splitHeaderList splits a comma separated header value, leaving the
+// commas inside quoted strings alone
+func splitHeaderList(value string) []string {
+	var parts []string
+	var current strings.Builder
+	quoted, escaped := false, false
+	for _, c := range value {
+		switch {
+		case escaped:
+			escaped = false
+		case quoted && c == '\\':
+			escaped = true
+		case c == '"':
+			quoted = !quoted
+		case c == ',' && !quoted:
+			parts = append(parts, strings.TrimSpace(current.String()))
+			current.Reset()
+			continue
+		}
+		current.WriteRune(c)
+	}
+	return append(parts, strings.TrimSpace(current.String()))
+}
+
+// parseAuthParam splits a name=value auth parameter, unquoting the value
+func parseAuthParam(s string) (string, string, bool) {
+	i := strings.IndexByte(s, '=')
+	if i <= 0 || strings.ContainsAny(s[:i], " \t") {
+		return "", "", false
+	}
+	name := strings.ToLower(strings.TrimSpace(s[:i]))
+	value := strings.TrimSpace(s[i+1:])
+	if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+		value = strings.ReplaceAll(value[1:len(value)-1], `\"`, `"`)
+	}
+	return name, value, true
+}
+
+// ParseAuthChallenges returns the challenges of the WWW-Authenticate
+// headers of a response, several challenges may share a header
+func ParseAuthChallenges(values []string) []AuthChallenge {
+	var challenges []AuthChallenge
+	for _, value := range values {
+		for _, part := range splitHeaderList(value) {
+			if part == "" {
+				continue
+			}
+			// a parameter of the previous challenge: realm="x", charset="UTF-8"
+			if name, v, ok := parseAuthParam(part); ok {
+				if name == "realm" && len(challenges) > 0 {
+					challenges[len(challenges)-1].Realm = v
+				}
+				continue
+			}
+			fields := strings.SplitN(part, " ", 2)
+			challenge := AuthChallenge{Scheme: fields[0]}
+			if len(fields) == 2 {
+				if name, v, ok := parseAuthParam(strings.TrimSpace(fields[1])); ok && name == "realm" {
+					challenge.Realm = v
+				}
+			}
+			challenges = append(challenges, challenge)
+		}
+	}
+	return challenges
+}
+
+// AuthSummary returns the challenges of the WWW-Authenticate headers as
+// a short printable string, empty when there are none
+func AuthSummary(values []string) string {
+	challenges := ParseAuthChallenges(values)
+	if len(challenges) == 0 {
+		return ""
+	}
+	parts := make([]string, len(challenges))
+	for i, c := range challenges {
+		parts[i] = c.String()
+	}
+	return "auth: " + strings.Join(parts, ", ")
+}
@@ -0,0 +1,28 @@
+package libgobuster
+
+import "testing"
+
+func TestAuthSummary(t *testing.T) {
+	t.Parallel()
+
+	var tt = []struct {
+		testName string
+		values   []string
+		expected string
+	}{
+		{"None", nil, ""},
+		{"Basic", []string{`Basic realm="Admin Area"`}, `auth: Basic realm="Admin Area"`},
+		{"Basic with charset", []string{`Basic realm="a, b", charset="UTF-8"`}, `auth: Basic realm="a, b"`},
+		{"NTLM and Negotiate", []string{"Negotiate", "NTLM"}, "auth: Negotiate, NTLM"},
+		{"Shared header", []string{`Bearer realm="api", error="invalid_token", Basic realm="x"`}, `auth: Bearer realm="api", Basic realm="x"`},
+		{"Unquoted realm", []string{"Digest realm=files, qop=auth, nonce=abc"}, `auth: Digest realm="files"`},
+		{"Empty", []string{""}, ""},
+	}
+	for _, x := range tt {
+		t.Run(x.testName, func(t *testing.T) {
+			if got := AuthSummary(x.values); got != x.expected {
+				t.Fatalf("Expected %q, got %q", x.expected, got)
+			}
+		})
+	}
+}
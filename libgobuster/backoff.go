@@ -0,0 +1,133 @@
+package libgobuster
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// backoffStreak is the number of 503 in a row taken for maintenance
+	backoffStreak = 5
+	// backoffBasePause is the first pause of a 503 streak, doubled on
+	// every further 503
+	backoffBasePause = 2 * time.Second
+	// backoffMaxPause caps the pauses, Retry-After ones included
+	backoffMaxPause = 5 * time.Minute
+	// backoffMaxRetries is the number of times a word is re-queued
+	// before its response is recorded as is
+	backoffMaxRetries = 3
+)
+
+// ParseRetryAfter returns the delay of a Retry-After header, given in
+// seconds or as an http date, zero when missing or invalid
+func ParseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	t, err := http.ParseTime(value)
+	if err != nil || !t.After(now) {
+		return 0
+	}
+	return t.Sub(now)
+}
+
+// backoff pauses the workers while the target asks to retry later, with
+// a Retry-After header or a streak of 503
+type backoff struct {
+	logger *log.Logger
+	mu     sync.Mutex
+	until  time.Time
+	streak int
+}
+
+// observe records the status of a response and reports whether the
+// request has to be retried once the pause is over
+func (b *backoff) observe(status int, retryAfter time.Duration, now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if status != http.StatusServiceUnavailable && status != http.StatusTooManyRequests {
+		b.streak = 0
+		return false
+	}
+	if status == http.StatusServiceUnavailable {
+		b.streak++
+	}
+	pause := retryAfter
+	if pause <= 0 {
+		if status != http.StatusServiceUnavailable || b.streak < backoffStreak {
+			return false
+		}
+		shift := b.streak - backoffStreak
+		if shift > 8 {
+			shift = 8
+		}
+		pause = backoffBasePause << uint(shift)
+	}
+	if pause > backoffMaxPause {
+		pause = backoffMaxPause
+	}
+	if until := now.Add(pause); until.After(b.until) {
+		if !now.Before(b.until) {
+			orDefault(b.logger).Printf("[!] Target answered %d, pausing for %s and retrying the affected words", status, pause)
+		}
+		b.until = until
+	}
+	return true
+}
+
+// wait blocks until the pause, if any, is over
+func (b *backoff) wait(c context.Context, clock Clock) {
+	b.mu.Lock()
+	pause := b.until.Sub(clock.Now())
+	b.mu.Unlock()
+	if pause <= 0 {
+		return
+	}
+	select {
+	case <-time.After(pause):
+	case <-c.Done():
+	}
+}
+
+// retryLater re-queues a target the server asked to retry later instead
+// of recording its responses. It gives up after backoffMaxRetries.
+func (g *Gobuster) retryLater(wordChan chan<- *BusterTarget, busterTarget *BusterTarget, res []Result) bool {
+	if g.backoff == nil {
+		return false
+	}
+	retry := false
+	for _, r := range res {
+		if g.backoff.observe(r.Status, r.RetryAfter, g.Now()) {
+			retry = true
+		}
+	}
+	if !retry || busterTarget.retries >= backoffMaxRetries {
+		return false
+	}
+	busterTarget.retries++
+	g.DecrementRequests()
+	// counted before the worker is done with it, the queue is not closed
+	// meanwhile
+	g.inFlight.Add(1)
+	go func() {
+		g.backoff.wait(g.context, g.Opts.clock())
+		select {
+		case <-g.context.Done():
+			g.inFlight.Done()
+		case wordChan <- busterTarget:
+		}
+	}()
+	return true
+}
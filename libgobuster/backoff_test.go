@@ -0,0 +1,72 @@
+package libgobuster
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	tt := []struct {
+		testName string
+		value    string
+		expected time.Duration
+	}{
+		{"Missing", "", 0},
+		{"Seconds", "120", 2 * time.Minute},
+		{"Negative", "-5", 0},
+		{"Date", "Thu, 02 Jan 2020 03:05:05 GMT", time.Minute},
+		{"Past date", "Thu, 02 Jan 2020 03:00:00 GMT", 0},
+		{"Invalid", "soon", 0},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			if got := ParseRetryAfter(x.value, now); got != x.expected {
+				t.Fatalf("expected %s, got %s", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestBackoffObserve(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	b := &backoff{}
+
+	if b.observe(200, 0, now) {
+		t.Fatal("expected a 200 not to be retried")
+	}
+	if !b.observe(429, 30*time.Second, now) || !b.until.Equal(now.Add(30*time.Second)) {
+		t.Fatalf("expected a 30s pause on Retry-After, got %s", b.until.Sub(now))
+	}
+	if b.observe(429, 0, now) {
+		t.Fatal("expected a 429 without Retry-After not to be retried")
+	}
+	if !b.observe(503, time.Hour, now) || !b.until.Equal(now.Add(backoffMaxPause)) {
+		t.Fatalf("expected the pause to be capped, got %s", b.until.Sub(now))
+	}
+
+	b = &backoff{}
+	for i := 1; i < backoffStreak; i++ {
+		if b.observe(503, 0, now) {
+			t.Fatalf("expected 503 number %d not to be retried", i)
+		}
+	}
+	if !b.observe(503, 0, now) || !b.until.Equal(now.Add(backoffBasePause)) {
+		t.Fatalf("expected a pause at the end of the streak, got %s", b.until.Sub(now))
+	}
+	if !b.observe(503, 0, now) || !b.until.Equal(now.Add(2*backoffBasePause)) {
+		t.Fatalf("expected the pause to double, got %s", b.until.Sub(now))
+	}
+	b.observe(200, 0, now)
+	if b.streak != 0 {
+		t.Fatalf("expected a success to end the streak, got %d", b.streak)
+	}
+}
@@ -0,0 +1,60 @@
+package libgobuster
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+)
+
+// binarySniffSize is the number of bytes of a body looked at for its
+// magic bytes, the ones http.DetectContentType reads
+const binarySniffSize = 512
+
+// textualMediaTypes are the media types outside of text/ carrying text
+var textualMediaTypes = NewSet(
+	"application/json",
+	"application/javascript",
+	"application/x-javascript",
+	"application/ecmascript",
+	"application/xml",
+	"application/xhtml+xml",
+	"application/x-www-form-urlencoded",
+	"application/x-sh",
+	"application/sql",
+	"application/graphql",
+	"application/yaml",
+	"application/x-yaml",
+	"application/toml",
+	"application/x-ndjson",
+	"application/manifest+json",
+)
+
+// isTextual reports whether a media type is the one of a text body
+func isTextual(mediaType string) bool {
+	return strings.HasPrefix(mediaType, "text/") ||
+		strings.HasSuffix(mediaType, "+json") ||
+		strings.HasSuffix(mediaType, "+xml") ||
+		textualMediaTypes.Contains(mediaType)
+}
+
+// IsBinary reports whether a body is binary content, images, archives,
+// fonts and the like, from its media type or, when the server sent none
+// or application/octet-stream, from its magic bytes
+func IsBinary(mediaType string, body []byte) bool {
+	if len(body) == 0 {
+		return false
+	}
+	if mediaType != "" && mediaType != "application/octet-stream" {
+		return !isTextual(mediaType)
+	}
+	sniff := body
+	if len(sniff) > binarySniffSize {
+		sniff = sniff[:binarySniffSize]
+	}
+	// text never holds NUL bytes, DetectContentType takes some binary
+	// formats it does not know for text
+	if bytes.IndexByte(sniff, 0) >= 0 {
+		return true
+	}
+	return !isTextual(MediaType(http.DetectContentType(sniff)))
+}
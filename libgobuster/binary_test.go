@@ -0,0 +1,37 @@
+package libgobuster
+
+import (
+	"testing"
+)
+
+func TestIsBinary(t *testing.T) {
+	t.Parallel()
+
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	tt := []struct {
+		testName  string
+		mediaType string
+		body      []byte
+		expected  bool
+	}{
+		{"html", "text/html", []byte("<html></html>"), false},
+		{"json", "application/json", []byte(`{"a":1}`), false},
+		{"svg", "image/svg+xml", []byte("<svg></svg>"), false},
+		{"image", "image/png", png, true},
+		{"archive", "application/zip", []byte("PK\x03\x04"), true},
+		{"sniffed image", "", png, true},
+		{"sniffed text", "application/octet-stream", []byte("CREATE TABLE users (id int);"), false},
+		{"nul bytes", "application/octet-stream", []byte("SQLite format 3\x00"), true},
+		{"empty", "image/png", nil, false},
+	}
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			if got := IsBinary(x.mediaType, x.body); got != x.expected {
+				t.Fatalf("expected %t, got %t", x.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,262 @@
+package libgobuster
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	// diffContext is the number of unchanged lines around the changes
+	diffContext = 3
+	// maxDiffLines caps the lines of a body compared, the time of the
+	// diff is quadratic in them
+	maxDiffLines = 2000
+)
+
+// diffFileRegex matches the characters of a path left out of the name
+// of its diff file
+var diffFileRegex = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// entityFileName returns the name of the file saved for a path. The
+// paths whose name had characters replaced get a hash of the path in
+// front, db/backup.sql and db_backup.sql are saved apart.
+func entityFileName(entity string) string {
+	name := strings.Trim(diffFileRegex.ReplaceAllString(entity, "_"), "_")
+	if name == entity {
+		return name
+	}
+	sum := sha256.Sum256([]byte(entity))
+	return fmt.Sprintf("%x_%s", sum[:4], name)
+}
+
+// diffOp is a line of a diff: ' ' unchanged, '-' removed or '+' added
+type diffOp struct {
+	kind byte
+	line string
+}
+
+// diffLines returns the edit script turning a into b, from their
+// longest common subsequence
+func diffLines(a, b []string) []diffOp {
+	return appendDiff(nil, a, b)
+}
+
+// appendDiff appends the edit script turning a into b. The lines of a
+// are split in two halves and b where the common subsequences of both
+// halves are the longest (Hirschberg), the memory used stays linear in
+// the lines on every worker diffing a body.
+func appendDiff(ops []diffOp, a, b []string) []diffOp {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		ops = append(ops, diffOp{' ', a[prefix]})
+		prefix++
+	}
+	a, b = a[prefix:], b[prefix:]
+	suffix := 0
+	for suffix < len(a) && suffix < len(b) && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	common := a[len(a)-suffix:]
+	a, b = a[:len(a)-suffix], b[:len(b)-suffix]
+
+	switch {
+	case len(a) == 0 || len(b) == 0:
+		for _, line := range a {
+			ops = append(ops, diffOp{'-', line})
+		}
+		for _, line := range b {
+			ops = append(ops, diffOp{'+', line})
+		}
+	case len(a) == 1:
+		k := 0
+		for k < len(b) && b[k] != a[0] {
+			k++
+		}
+		if k == len(b) {
+			ops = append(ops, diffOp{'-', a[0]})
+		}
+		for j, line := range b {
+			if j == k {
+				ops = append(ops, diffOp{' ', line})
+			} else {
+				ops = append(ops, diffOp{'+', line})
+			}
+		}
+	default:
+		mid := len(a) / 2
+		forward := lcsLengths(a[:mid], b, false)
+		backward := lcsLengths(a[mid:], b, true)
+		split := 0
+		for k := range forward {
+			if forward[k]+backward[len(b)-k] > forward[split]+backward[len(b)-split] {
+				split = k
+			}
+		}
+		ops = appendDiff(ops, a[:mid], b[:split])
+		ops = appendDiff(ops, a[mid:], b[split:])
+	}
+
+	for _, line := range common {
+		ops = append(ops, diffOp{' ', line})
+	}
+	return ops
+}
+
+// lcsLengths returns the lengths of the longest common subsequences of a
+// and the first j lines of b, of the last j lines when reversed, with
+// two rows of the table
+func lcsLengths(a, b []string, reversed bool) []int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for i := range a {
+		x := a[i]
+		if reversed {
+			x = a[len(a)-1-i]
+		}
+		for j := 1; j <= len(b); j++ {
+			y := b[j-1]
+			if reversed {
+				y = b[len(b)-j]
+			}
+			switch {
+			case x == y:
+				cur[j] = prev[j-1] + 1
+			case prev[j] >= cur[j-1]:
+				cur[j] = prev[j]
+			default:
+				cur[j] = cur[j-1]
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev
+}
+
+// splitBody splits a body into its lines, at most maxDiffLines
+func splitBody(body string) ([]string, bool) {
+	if body == "" {
+		return nil, false
+	}
+	lines := strings.Split(strings.TrimSuffix(body, "\n"), "\n")
+	if len(lines) > maxDiffLines {
+		return lines[:maxDiffLines], true
+	}
+	return lines, false
+}
+
+// UnifiedDiff returns the unified diff of two bodies along with the
+// number of lines added and removed, an empty diff when they are equal
+func UnifiedDiff(fromName, toName, from, to string) (string, int, int) {
+	a, truncatedA := splitBody(from)
+	b, truncatedB := splitBody(to)
+	ops := diffLines(a, b)
+
+	added, removed := 0, 0
+	for _, op := range ops {
+		switch op.kind {
+		case '+':
+			added++
+		case '-':
+			removed++
+		}
+	}
+	if added == 0 && removed == 0 {
+		return "", 0, 0
+	}
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "--- %s\n+++ %s\n", fromName, toName)
+	// the hunks are the changes with their context, merged when the
+	// context of two changes overlaps
+	for start := 0; start < len(ops); {
+		if ops[start].kind == ' ' {
+			start++
+			continue
+		}
+		first := start - diffContext
+		if first < 0 {
+			first = 0
+		}
+		last, unchanged := start, 0
+		for k := start; k < len(ops) && unchanged <= 2*diffContext; k++ {
+			if ops[k].kind == ' ' {
+				unchanged++
+			} else {
+				last, unchanged = k, 0
+			}
+		}
+		end := last + diffContext + 1
+		if end > len(ops) {
+			end = len(ops)
+		}
+
+		lineA, lineB := 1, 1
+		for _, op := range ops[:first] {
+			if op.kind != '+' {
+				lineA++
+			}
+			if op.kind != '-' {
+				lineB++
+			}
+		}
+		countA, countB := 0, 0
+		for _, op := range ops[first:end] {
+			if op.kind != '+' {
+				countA++
+			}
+			if op.kind != '-' {
+				countB++
+			}
+		}
+		// an empty range starts at the line before it
+		if countA == 0 {
+			lineA--
+		}
+		if countB == 0 {
+			lineB--
+		}
+		fmt.Fprintf(&buf, "@@ -%d,%d +%d,%d @@\n", lineA, countA, lineB, countB)
+		for _, op := range ops[first:end] {
+			fmt.Fprintf(&buf, "%c%s\n", op.kind, op.line)
+		}
+		start = end
+	}
+	if truncatedA || truncatedB {
+		fmt.Fprintf(&buf, "# only the first %d lines were compared\n", maxDiffLines)
+	}
+	return buf.String(), added, removed
+}
+
+// WriteBaselineDiff writes the diff of the body of a result against the
+// body of the wildcard baseline sharing its status into the output
+// folder, it returns the number of lines added and removed
+func (g *Gobuster) WriteBaselineDiff(r *Result, baseline *WildcardBaseline) (int, int, error) {
+	resultURL := r.FullURL(g)
+	// the pages reflecting the url or path differ by it only
+	from := strings.ReplaceAll(baseline.Content, baseline.ContentURL, resultURL)
+	if probe, err := url.Parse(baseline.ContentURL); err == nil && probe.Path != "/" {
+		if u, err := url.Parse(resultURL); err == nil {
+			from = strings.ReplaceAll(from, probe.Path, u.Path)
+		}
+	}
+	diff, added, removed := UnifiedDiff(baseline.ContentURL, resultURL, from, *r.Content)
+	if diff == "" {
+		return 0, 0, nil
+	}
+
+	folder := filepath.Join(g.Opts.OutputFolder, "output_diffs")
+	if err := os.MkdirAll(folder, 0755); err != nil {
+		return 0, 0, err
+	}
+	path := filepath.Join(folder, fmt.Sprintf("%s_%s.diff", g.OutputFileSuffix(), entityFileName(r.Entity)))
+	if err := ioutil.WriteFile(path, []byte(diff), 0644); err != nil {
+		return 0, 0, err
+	}
+	return added, removed, nil
+}
@@ -0,0 +1,105 @@
+package libgobuster
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName string
+		from     string
+		to       string
+		expected string
+		added    int
+		removed  int
+	}{
+		{"Equal", "a\nb\n", "a\nb", "", 0, 0},
+		{"Changed line", "a\nb\nc\n", "a\nx\nc\n", "--- from\n+++ to\n@@ -1,3 +1,3 @@\n a\n-b\n+x\n c\n", 1, 1},
+		{"Added lines", "", "a\nb", "--- from\n+++ to\n@@ -0,0 +1,2 @@\n+a\n+b\n", 2, 0},
+		{
+			"Two hunks",
+			"1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n",
+			"x\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\ny\n",
+			"--- from\n+++ to\n@@ -1,4 +1,4 @@\n-1\n+x\n 2\n 3\n 4\n@@ -9,4 +9,4 @@\n 9\n 10\n 11\n-12\n+y\n",
+			2, 2,
+		},
+		{
+			"Merged hunk",
+			"1\n2\n3\n4\n5\n",
+			"1\nx\n3\n4\ny\n",
+			"--- from\n+++ to\n@@ -1,5 +1,5 @@\n 1\n-2\n+x\n 3\n 4\n-5\n+y\n",
+			2, 2,
+		},
+		{"Moved line", "a\nb\nc\nd\n", "b\nc\nd\na\n", "--- from\n+++ to\n@@ -1,4 +1,4 @@\n-a\n b\n c\n d\n+a\n", 1, 1},
+		{"Replaced lines", "a\nb\n", "c\nd\n", "--- from\n+++ to\n@@ -1,2 +1,2 @@\n-a\n-b\n+c\n+d\n", 2, 2},
+	}
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			diff, added, removed := UnifiedDiff("from", "to", x.from, x.to)
+			if diff != x.expected {
+				t.Fatalf("expected %q, got %q", x.expected, diff)
+			}
+			if added != x.added || removed != x.removed {
+				t.Fatalf("expected +%d -%d, got +%d -%d", x.added, x.removed, added, removed)
+			}
+		})
+	}
+}
+
+func TestWriteBaselineDiff(t *testing.T) {
+	t.Parallel()
+
+	o := NewOptions()
+	o.URL = "http://example.com/"
+	o.OutputFolder = t.TempDir()
+	g := &Gobuster{Opts: o, RunID: "run", startTime: time.Unix(1, 0)}
+
+	baseline := &WildcardBaseline{
+		Status:     200,
+		Content:    "<h1>Home</h1>\n<p>No page at /probe1234</p>\n",
+		ContentURL: "http://example.com/probe1234",
+	}
+	// the reflected path alone is no difference
+	same := "<h1>Home</h1>\n<p>No page at /admin</p>\n"
+	r := &Result{Entity: "admin", Status: 200, Content: &same}
+	if added, removed, err := g.WriteBaselineDiff(r, baseline); err != nil || added != 0 || removed != 0 {
+		t.Fatalf("expected no diff, got +%d -%d %v", added, removed, err)
+	}
+
+	content := "<h1>Home</h1>\n<form action=/login></form>\n"
+	r = &Result{Entity: "admin/login", Status: 200, Content: &content}
+	added, removed, err := g.WriteBaselineDiff(r, baseline)
+	if err != nil || added != 1 || removed != 1 {
+		t.Fatalf("expected +1 -1, got +%d -%d %v", added, removed, err)
+	}
+	files, err := filepath.Glob(filepath.Join(o.OutputFolder, "output_diffs", "*_admin_login.diff"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("expected a diff file, got %v %v", files, err)
+	}
+	diff, err := ioutil.ReadFile(files[0])
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if !strings.Contains(string(diff), "-<p>No page at /admin/login</p>\n+<form action=/login></form>\n") {
+		t.Fatalf("unexpected diff %q", diff)
+	}
+
+	// admin_login is not saved over the diff of admin/login
+	r = &Result{Entity: "admin_login", Status: 200, Content: &content}
+	if _, _, err := g.WriteBaselineDiff(r, baseline); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	files, err = filepath.Glob(filepath.Join(o.OutputFolder, "output_diffs", "*_admin_login.diff"))
+	if err != nil || len(files) != 2 {
+		t.Fatalf("expected two diff files, got %v %v", files, err)
+	}
+}
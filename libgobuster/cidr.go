@@ -0,0 +1,134 @@
+package libgobuster
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxCIDRHosts caps the addresses a -cidr range may expand to
+const maxCIDRHosts = 1 << 16
+
+// parsePortList parses ports provided as a comma separated list
+func parsePortList(ports string) ([]int, error) {
+	var parsed []int
+	for _, p := range strings.Split(ports, ",") {
+		p = strings.TrimSpace(p)
+		port, err := strconv.Atoi(p)
+		if err != nil || port < 1 || port > 65535 {
+			return nil, fmt.Errorf("invalid port given: %s", p)
+		}
+		parsed = append(parsed, port)
+	}
+	return parsed, nil
+}
+
+// parsePorts parses the ports of the -cidr hosts
+func (opt *Options) parsePorts() error {
+	ports, err := parsePortList(opt.Ports)
+	if err != nil {
+		return err
+	}
+	opt.PortsParsed = ports
+	return nil
+}
+
+// parseCIDR checks the -cidr range and its ports
+func (opt *Options) parseCIDR() error {
+	_, network, err := net.ParseCIDR(opt.CIDR)
+	if err != nil {
+		return fmt.Errorf("invalid cidr given: %s", opt.CIDR)
+	}
+	ones, bits := network.Mask.Size()
+	if bits-ones > 16 {
+		return fmt.Errorf("cidr %s is larger than %d addresses", opt.CIDR, maxCIDRHosts)
+	}
+	return opt.parsePorts()
+}
+
+// cidrHosts returns the host addresses of the network, leaving out the
+// network and broadcast addresses of IPv4 ranges larger than /31
+func cidrHosts(network *net.IPNet) []net.IP {
+	ones, bits := network.Mask.Size()
+	size := 1 << uint(bits-ones)
+	base := new(big.Int).SetBytes(network.IP)
+
+	var hosts []net.IP
+	for i := 0; i < size; i++ {
+		if bits == 32 && bits-ones > 1 && (i == 0 || i == size-1) {
+			continue
+		}
+		n := new(big.Int).Add(base, big.NewInt(int64(i))).Bytes()
+		ip := make(net.IP, len(network.IP))
+		copy(ip[len(ip)-len(n):], n)
+		hosts = append(hosts, ip)
+	}
+	return hosts
+}
+
+// ExpandCIDR returns the base url of every host:port of the -cidr range,
+// ports 443 and 8443 are requested over https
+func ExpandCIDR(opt *Options) ([]string, error) {
+	if err := opt.parseCIDR(); err != nil {
+		return nil, err
+	}
+	_, network, _ := net.ParseCIDR(opt.CIDR)
+
+	var targets []string
+	for _, ip := range cidrHosts(network) {
+		for _, port := range opt.PortsParsed {
+			scheme := "http"
+			if port == 443 || port == 8443 {
+				scheme = "https"
+			}
+			u := url.URL{Scheme: scheme, Host: net.JoinHostPort(ip.String(), strconv.Itoa(port)), Path: "/"}
+			targets = append(targets, u.String())
+		}
+	}
+	return targets, nil
+}
+
+// ReachableTargets returns the targets accepting a TCP connection within
+// the timeout, in their original order
+func ReachableTargets(c context.Context, targets []string, timeout time.Duration, concurrency int) []string {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	reachable := make([]bool, len(targets))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		u, err := url.Parse(target)
+		if err != nil {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, address string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			d := net.Dialer{Timeout: timeout}
+			conn, err := d.DialContext(c, "tcp", address)
+			if err != nil {
+				return
+			}
+			conn.Close()
+			reachable[i] = true
+		}(i, u.Host)
+	}
+	wg.Wait()
+
+	var live []string
+	for i, target := range targets {
+		if reachable[i] {
+			live = append(live, target)
+		}
+	}
+	return live
+}
@@ -0,0 +1,76 @@
+package libgobuster
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestExpandCIDR(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName string
+		cidr     string
+		ports    string
+		expected []string
+		wantErr  bool
+	}{
+		{"Slash 30", "10.0.0.0/30", "80,443", []string{"http://10.0.0.1:80/", "https://10.0.0.1:443/", "http://10.0.0.2:80/", "https://10.0.0.2:443/"}, false},
+		{"Slash 31", "10.0.0.0/31", "8080", []string{"http://10.0.0.0:8080/", "http://10.0.0.1:8080/"}, false},
+		{"Single host", "192.168.1.7/32", "8443", []string{"https://192.168.1.7:8443/"}, false},
+		{"IPv6", "fd00::/127", "80", []string{"http://[fd00::]:80/", "http://[fd00::1]:80/"}, false},
+		{"Invalid cidr", "10.0.0.0", "80", nil, true},
+		{"Too large", "10.0.0.0/8", "80", nil, true},
+		{"Invalid port", "10.0.0.0/30", "80,http", nil, true},
+		{"Port out of range", "10.0.0.0/30", "70000", nil, true},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			o := NewOptions()
+			o.CIDR = x.cidr
+			o.Ports = x.ports
+			got, err := ExpandCIDR(o)
+			if x.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error for %s", x.cidr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got error: %v", err)
+			}
+			if !reflect.DeepEqual(got, x.expected) {
+				t.Fatalf("expected %v, got %v", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestReachableTargets(t *testing.T) {
+	t.Parallel()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	defer l.Close()
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	closed.Close()
+
+	live := "http://" + l.Addr().String() + "/"
+	targets := []string{"http://" + closed.Addr().String() + "/", live}
+	got := ReachableTargets(context.Background(), targets, time.Second, 2)
+	if !reflect.DeepEqual(got, []string{live}) {
+		t.Fatalf("expected only %s, got %v", live, got)
+	}
+}
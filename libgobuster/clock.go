@@ -0,0 +1,73 @@
+package libgobuster
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Clock tells the time of the timestamps written by a run, tests and
+// embedding applications replace it to get deterministic output
+type Clock interface {
+	Now() time.Time
+}
+
+// Rand is the source of the random choices of a run (wildcard probes,
+// random agents), it must be safe for concurrent use
+type Rand interface {
+	Intn(n int) int
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// SystemClock is the wall clock used unless Options.Clock is set
+var SystemClock Clock = systemClock{}
+
+// FixedClock always returns the same time
+type FixedClock time.Time
+
+// Now returns the fixed time
+func (c FixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+// lockedRand guards a math/rand source, which is not safe for
+// concurrent use on its own
+type lockedRand struct {
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+// NewRand returns a Rand seeded with the given seed, the same seed
+// gives the same choices
+func NewRand(seed int64) Rand {
+	return &lockedRand{rnd: rand.New(rand.NewSource(seed))}
+}
+
+func (r *lockedRand) Intn(n int) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rnd.Intn(n)
+}
+
+// clock returns the configured clock, the system clock by default
+func (opt *Options) clock() Clock {
+	if opt.Clock == nil {
+		return SystemClock
+	}
+	return opt.Clock
+}
+
+// Now returns the current time of the run's clock
+func (g *Gobuster) Now() time.Time {
+	return g.Opts.clock().Now()
+}
+
+// Random returns the source of the random choices of the run
+func (g *Gobuster) Random() Rand {
+	return g.Opts.Rand
+}
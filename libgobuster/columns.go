@@ -0,0 +1,158 @@
+package libgobuster
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// The columns of the console output of -columns
+const (
+	ColumnTime     = "time"
+	ColumnStatus   = "status"
+	ColumnSize     = "size"
+	ColumnURL      = "url"
+	ColumnRedirect = "redirect"
+	ColumnTitle    = "title"
+	ColumnType     = "type"
+	ColumnIPs      = "ips"
+	ColumnCNAME    = "cname"
+	ColumnMeta     = "meta"
+)
+
+// DefaultDirColumns are the columns of the dir mode matches
+var DefaultDirColumns = []string{ColumnTime, ColumnStatus, ColumnSize, ColumnURL, ColumnRedirect, ColumnMeta}
+
+// column renders a value of the results. The separator comes before the
+// value unless it is the first column, the values are padded to the
+// width, on the left when aligned right. The empty values of the
+// optional columns are left out with their separator.
+type column struct {
+	separator string
+	width     int
+	right     bool
+	optional  bool
+	render    func(g *Gobuster, r *Result) string
+}
+
+var columns = map[string]column{
+	ColumnTime: {separator: "  ", render: func(g *Gobuster, r *Result) string {
+		t := g.Now()
+		return fmt.Sprintf("[%02d:%02d:%02d]", t.Hour(), t.Minute(), t.Second())
+	}},
+	ColumnStatus: {width: 8, right: true, render: func(g *Gobuster, r *Result) string {
+		return fmt.Sprintf("%d", r.Status)
+	}},
+	ColumnSize: {width: 14, right: true, render: func(g *Gobuster, r *Result) string {
+		if r.Size == nil {
+			return "0 B"
+		}
+		return fmt.Sprintf("%d B", *r.Size)
+	}},
+	ColumnURL: {separator: "     -     ", render: func(g *Gobuster, r *Result) string {
+		u := r.FullURL(g)
+		if r.VirtualHost != "" {
+			u += fmt.Sprintf("  [host: %s]", r.VirtualHost)
+		}
+		return u
+	}},
+	ColumnRedirect: {separator: "  ->  ", optional: true, render: func(g *Gobuster, r *Result) string {
+		// the first url of the chain is the requested one
+		if len(r.RedirectChain) > 0 {
+			return strings.Join(r.RedirectChain[1:], "  ->  ")
+		}
+		if r.RedirectURL != nil {
+			return *r.RedirectURL
+		}
+		return ""
+	}},
+	ColumnTitle: {separator: "  ", optional: true, render: func(g *Gobuster, r *Result) string {
+		return bracketed(r.Metadata[MetaTitle])
+	}},
+	ColumnType: {separator: "  ", optional: true, render: func(g *Gobuster, r *Result) string {
+		return bracketed(r.ContentType)
+	}},
+	ColumnIPs: {separator: "  ", optional: true, render: func(g *Gobuster, r *Result) string {
+		return bracketed(strings.Join(r.IPs, ", "))
+	}},
+	ColumnCNAME: {separator: "  ", optional: true, render: func(g *Gobuster, r *Result) string {
+		return bracketed(r.CNAME)
+	}},
+	ColumnMeta: {separator: "  ", optional: true, render: func(g *Gobuster, r *Result) string {
+		var tags []string
+		if r.Listable {
+			tags = append(tags, "[listing]")
+		}
+		if r.Status == http.StatusNotModified {
+			tags = append(tags, "[not modified]")
+		}
+		if g.Opts.ShowContentType && r.ContentType != "" {
+			tags = append(tags, bracketed(r.ContentType))
+		}
+		if annotation := r.Annotation(); annotation != "" {
+			tags = append(tags, bracketed(annotation))
+		}
+		if g.Opts.DebugHTTP != "" {
+			tags = append(tags, bracketed(r.RequestID))
+		}
+		return strings.Join(tags, "  ")
+	}},
+}
+
+func bracketed(s string) string {
+	if s == "" {
+		return ""
+	}
+	return "[" + s + "]"
+}
+
+// parseColumns parses the comma separated columns of -columns
+func (opt *Options) parseColumns() error {
+	opt.ColumnsParsed = nil
+	for _, name := range strings.Split(opt.Columns, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if _, ok := columns[name]; !ok {
+			return fmt.Errorf("invalid column given: %s", name)
+		}
+		opt.ColumnsParsed = append(opt.ColumnsParsed, name)
+	}
+	if len(opt.ColumnsParsed) == 0 {
+		return fmt.Errorf("no column given")
+	}
+	return nil
+}
+
+// RenderColumns returns the console line of a result in the columns of
+// -columns, or the given ones by default
+func (g *Gobuster) RenderColumns(r *Result, defaults []string) string {
+	names := g.Opts.ColumnsParsed
+	if len(names) == 0 {
+		names = defaults
+	}
+	var sb strings.Builder
+	// the optional columns left out take their separator with them
+	written := false
+	for _, name := range names {
+		c := columns[name]
+		value := c.render(g, r)
+		if value == "" && c.optional {
+			continue
+		}
+		if written {
+			sb.WriteString(c.separator)
+		}
+		written = true
+		switch {
+		case c.right:
+			fmt.Fprintf(&sb, "%*s", c.width, value)
+		case c.width > 0:
+			fmt.Fprintf(&sb, "%-*s", c.width, value)
+		default:
+			sb.WriteString(value)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,82 @@
+package libgobuster
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRenderColumns(t *testing.T) {
+	t.Parallel()
+
+	size := int64(1234)
+	redirect := "http://example.com/admin/"
+	empty := ""
+	r := Result{
+		Entity:      "admin",
+		Status:      301,
+		Size:        &size,
+		RedirectURL: &redirect,
+		ContentType: "text/html",
+		Metadata:    map[string]string{MetaTitle: "Moved", MetaWAF: "Sucuri"},
+	}
+	plain := Result{Entity: "index.html", Status: 200, Size: &size, RedirectURL: &empty}
+
+	tt := []struct {
+		testName string
+		columns  string
+		result   Result
+		expected string
+	}{
+		{"Default", "", r, "[13:04:05]     301        1234 B     -     http://example.com/admin  ->  http://example.com/admin/  [blocked by Sucuri]"},
+		{"Default without redirect", "", plain, "[13:04:05]     200        1234 B     -     http://example.com/index.html"},
+		{"Custom", "status,url,title,type", r, "     301     -     http://example.com/admin  [Moved]  [text/html]"},
+		{"Url first", "url,size", plain, "http://example.com/index.html        1234 B"},
+		{"Empty optional", "url,title,status", plain, "http://example.com/index.html     200"},
+		{"Empty optional first", "title,url", plain, "http://example.com/index.html"},
+	}
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			o := NewOptions()
+			o.URL = "http://example.com/"
+			o.Clock = FixedClock(time.Date(2021, 1, 2, 13, 4, 5, 0, time.UTC))
+			o.Columns = x.columns
+			if x.columns != "" {
+				if err := o.parseColumns(); err != nil {
+					t.Fatalf("got error: %v", err)
+				}
+			}
+			g := &Gobuster{Opts: o}
+			if got := g.RenderColumns(&x.result, DefaultDirColumns); got != x.expected {
+				t.Fatalf("expected %q, got %q", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestParseColumns(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		columns     string
+		expectedErr bool
+	}{
+		{"time, URL,meta", false},
+		{"url,body", true},
+		{" , ", true},
+	}
+	for _, x := range tt {
+		x := x
+		t.Run(x.columns, func(t *testing.T) {
+			t.Parallel()
+
+			o := NewOptions()
+			o.Columns = x.columns
+			if err := o.parseColumns(); (err != nil) != x.expectedErr {
+				t.Fatalf("expected error %t, got %v", x.expectedErr, err)
+			}
+		})
+	}
+}
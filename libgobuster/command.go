@@ -0,0 +1,84 @@
+package libgobuster
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// commandsFolder is the folder of the output folder holding the
+// commands of -print-cmd
+const commandsFolder = "output_commands"
+
+// CommandFlag is a flag of a resolved command line
+type CommandFlag struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	// Bool flags are given as -name=value
+	Bool bool `json:"bool,omitempty"`
+}
+
+// ResolvedCommand is the command line equivalent to a run, once the
+// defaults and the profile are applied: every flag differing from its
+// default, sorted by name
+type ResolvedCommand struct {
+	Version string `json:"version"`
+	// Command is the subcommand selecting the mode, empty for the flat
+	// form selecting it with -m
+	Command string        `json:"command,omitempty"`
+	Mode    string        `json:"mode"`
+	Flags   []CommandFlag `json:"flags"`
+}
+
+// shellQuote quotes a value for POSIX shells unless it is made of safe
+// characters only
+func shellQuote(value string) string {
+	if value != "" && strings.Trim(value, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./:,=@%+") == "" {
+		return value
+	}
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// CommandLine returns the command line running the program with the
+// resolved flags
+func (c *ResolvedCommand) CommandLine(program string) string {
+	parts := []string{shellQuote(program)}
+	if c.Command != "" {
+		parts = append(parts, c.Command)
+	}
+	for _, f := range c.Flags {
+		if f.Bool {
+			if f.Value == "true" {
+				parts = append(parts, "-"+f.Name)
+			} else {
+				parts = append(parts, fmt.Sprintf("-%s=%s", f.Name, f.Value))
+			}
+			continue
+		}
+		parts = append(parts, "-"+f.Name, shellQuote(f.Value))
+	}
+	return strings.Join(parts, " ")
+}
+
+// Write stores the command line and the flags as JSON in the output
+// folder, it returns the path of the command line
+func (c *ResolvedCommand) Write(outputFolder, program, suffix string) (string, error) {
+	folder := fmt.Sprintf("%s/%s", outputFolder, commandsFolder)
+	if err := os.MkdirAll(folder, 0755); err != nil {
+		return "", fmt.Errorf("failed to create commands folder: %v", err)
+	}
+	path := fmt.Sprintf("%s/command_%s.txt", folder, suffix)
+	if err := ioutil.WriteFile(path, []byte(c.CommandLine(program)+"\n"), 0644); err != nil {
+		return "", fmt.Errorf("failed to write command: %v", err)
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode options: %v", err)
+	}
+	if err := ioutil.WriteFile(fmt.Sprintf("%s/options_%s.json", folder, suffix), data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write options: %v", err)
+	}
+	return path, nil
+}
@@ -0,0 +1,76 @@
+package libgobuster
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestShellQuote(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		value    string
+		expected string
+	}{
+		{"http://example.com:8080/app/", "http://example.com:8080/app/"},
+		{"", "''"},
+		{"a b", "'a b'"},
+		{"it's", `'it'\''s'`},
+		{"status == 200", "'status == 200'"},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.value, func(t *testing.T) {
+			t.Parallel()
+
+			if got := shellQuote(x.value); got != x.expected {
+				t.Fatalf("expected %s, got %s", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestResolvedCommand(t *testing.T) {
+	t.Parallel()
+
+	c := &ResolvedCommand{
+		Version: VERSION,
+		Command: ModeDir,
+		Mode:    ModeDir,
+		Flags: []CommandFlag{
+			{Name: "u", Value: "http://example.com/"},
+			{Name: "w", Value: "/tmp/my words.txt"},
+			{Name: "q", Value: "true", Bool: true},
+			{Name: "wildcard-auto", Value: "false", Bool: true},
+		},
+	}
+	expected := "gobuster dir -u http://example.com/ -w '/tmp/my words.txt' -q -wildcard-auto=false"
+	if got := c.CommandLine("gobuster"); got != expected {
+		t.Fatalf("expected %q, got %q", expected, got)
+	}
+
+	folder := t.TempDir()
+	path, err := c.Write(folder, "gobuster", "1_run")
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	line, _ := ioutil.ReadFile(path)
+	if string(line) != expected+"\n" {
+		t.Fatalf("unexpected command file %q", line)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(folder, commandsFolder, "options_1_run.json"))
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	var decoded ResolvedCommand
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if !reflect.DeepEqual(&decoded, c) {
+		t.Fatalf("expected %+v, got %+v", c, decoded)
+	}
+}
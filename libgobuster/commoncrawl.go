@@ -0,0 +1,200 @@
+package libgobuster
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+const (
+	// commonCrawlCollInfo lists the Common Crawl indexes, newest first
+	commonCrawlCollInfo = "https://index.commoncrawl.org/collinfo.json"
+	// commonCrawlIndexes is the number of the newest indexes queried
+	commonCrawlIndexes = 3
+	// commonCrawlMaxPages caps the result pages fetched of an index
+	commonCrawlMaxPages = 20
+	// commonCrawlTimeout is the timeout of an index query, the index
+	// server is slow on the hosts with many captures
+	commonCrawlTimeout = time.Minute
+)
+
+// commonCrawlClient queries the Common Crawl index server for the urls
+// captured of a host
+type commonCrawlClient struct {
+	client   *http.Client
+	collInfo string
+	logger   *log.Logger
+}
+
+// newCommonCrawlClient returns a client of the index server sending its
+// requests over the transport of the scan, so -p and -tor apply to them
+func newCommonCrawlClient(logger *log.Logger, transport http.RoundTripper) *commonCrawlClient {
+	return &commonCrawlClient{
+		client:   &http.Client{Timeout: commonCrawlTimeout, Transport: transport},
+		collInfo: commonCrawlCollInfo,
+		logger:   logger,
+	}
+}
+
+// get requests a url of the index server, a 404 answers no capture
+func (c *commonCrawlClient) get(ctx context.Context, rawURL string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		resp.Body.Close()
+		return nil, fmt.Errorf("common crawl answered %d to %s", resp.StatusCode, rawURL)
+	}
+	return resp, nil
+}
+
+// indexes returns the query urls of the newest indexes
+func (c *commonCrawlClient) indexes(ctx context.Context) ([]string, error) {
+	resp, err := c.get(ctx, c.collInfo)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	var collections []struct {
+		CDXAPI string `json:"cdx-api"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&collections); err != nil {
+		return nil, fmt.Errorf("invalid common crawl index list: %v", err)
+	}
+	var indexes []string
+	for _, coll := range collections {
+		if coll.CDXAPI != "" && len(indexes) < commonCrawlIndexes {
+			indexes = append(indexes, coll.CDXAPI)
+		}
+	}
+	return indexes, nil
+}
+
+// indexQuery returns the query of the captures of the host in an index
+func indexQuery(index, host string, extra url.Values) string {
+	query := url.Values{"url": {host + "/*"}, "output": {"json"}, "fl": {"url"}}
+	for k, v := range extra {
+		query[k] = v
+	}
+	return index + "?" + query.Encode()
+}
+
+// pages returns the number of result pages of the host in an index
+func (c *commonCrawlClient) pages(ctx context.Context, index, host string) (int, error) {
+	resp, err := c.get(ctx, indexQuery(index, host, url.Values{"showNumPages": {"true"}}))
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return 0, nil
+	}
+	var answer struct {
+		Pages int `json:"pages"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&answer); err != nil {
+		return 0, fmt.Errorf("invalid common crawl page count: %v", err)
+	}
+	if answer.Pages > commonCrawlMaxPages {
+		return commonCrawlMaxPages, nil
+	}
+	return answer.Pages, nil
+}
+
+// fetchPage writes the urls of a result page to w, one per line, and
+// returns their number
+func (c *commonCrawlClient) fetchPage(ctx context.Context, index, host string, page int, w io.Writer) (int, error) {
+	resp, err := c.get(ctx, indexQuery(index, host, url.Values{"page": {fmt.Sprint(page)}}))
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return 0, nil
+	}
+	written := 0
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var capture struct {
+			URL string `json:"url"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &capture); err != nil || capture.URL == "" {
+			continue
+		}
+		if _, err := fmt.Fprintln(w, capture.URL); err != nil {
+			return written, err
+		}
+		written++
+	}
+	return written, scanner.Err()
+}
+
+// fetch writes the urls captured of the host in the newest indexes to
+// w and returns their number. The indexes failing are skipped, only
+// the failure of every index is an error.
+func (c *commonCrawlClient) fetch(ctx context.Context, host string, w io.Writer) (int, error) {
+	indexes, err := c.indexes(ctx)
+	if err != nil {
+		return 0, err
+	}
+	total, failed := 0, 0
+	for _, index := range indexes {
+		pages, err := c.pages(ctx, index, host)
+		for page := 0; err == nil && page < pages; page++ {
+			var written int
+			written, err = c.fetchPage(ctx, index, host, page, w)
+			total += written
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return total, ctx.Err()
+			}
+			orDefault(c.logger).Printf("[!] Skipping the common crawl index %s: %v", index, err)
+			failed++
+		}
+	}
+	if failed > 0 && failed == len(indexes) {
+		return total, fmt.Errorf("every common crawl index failed")
+	}
+	return total, nil
+}
+
+// fetchCommonCrawl stores the urls Common Crawl captured of the target
+// host in the output folder and returns the path of the file
+func (g *Gobuster) fetchCommonCrawl() (string, error) {
+	u, err := url.Parse(g.Opts.URL)
+	if err != nil {
+		return "", err
+	}
+	path := fmt.Sprintf("%s/output_waybackurls/commoncrawl_%s.txt", g.Opts.OutputFolder, g.OutputFileSuffix())
+	f, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to create common crawl urls: %v", err)
+	}
+	defer f.Close()
+	writer := bufio.NewWriter(f)
+
+	g.Logf("Fetching the common crawl urls of %s..", u.Host)
+	fetched, err := newCommonCrawlClient(g.Opts.logger(), g.HTTP.client().Transport).fetch(g.context, u.Host, writer)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch common crawl urls: %v", err)
+	}
+	if err := writer.Flush(); err != nil {
+		return "", fmt.Errorf("failed to write common crawl urls: %v", err)
+	}
+	g.Logf("Fetched %d common crawl urls of %s", fetched, u.Host)
+	return path, nil
+}
@@ -0,0 +1,101 @@
+package libgobuster
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCommonCrawlFetch(t *testing.T) {
+	t.Parallel()
+
+	var h *httptest.Server
+	h = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/collinfo.json":
+			json.NewEncoder(w).Encode([]map[string]string{
+				{"id": "CC-MAIN-3", "cdx-api": h.URL + "/CC-MAIN-3-index"},
+				{"id": "CC-MAIN-2", "cdx-api": h.URL + "/CC-MAIN-2-index"},
+				{"id": "CC-MAIN-1", "cdx-api": h.URL + "/CC-MAIN-1-index"},
+				{"id": "CC-MAIN-0", "cdx-api": h.URL + "/CC-MAIN-0-index"},
+			})
+		case "/CC-MAIN-3-index":
+			if r.URL.Query().Get("url") != "example.com/*" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			if r.URL.Query().Get("showNumPages") == "true" {
+				fmt.Fprint(w, `{"pageSize": 5, "blocks": 6, "pages": 2}`)
+				return
+			}
+			fmt.Fprintf(w, "{\"url\": \"http://example.com/page%s\"}\nnot json\n", r.URL.Query().Get("page"))
+		case "/CC-MAIN-2-index":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case "/CC-MAIN-1-index":
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "No Captures found for: example.com/*"}`)
+		default:
+			t.Errorf("unexpected request of %s", r.URL)
+		}
+	}))
+	defer h.Close()
+
+	c := &commonCrawlClient{client: h.Client(), collInfo: h.URL + "/collinfo.json"}
+	var buf bytes.Buffer
+	fetched, err := c.fetch(context.Background(), "example.com", &buf)
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	expected := "http://example.com/page0\nhttp://example.com/page1\n"
+	if fetched != 2 || buf.String() != expected {
+		t.Fatalf("expected %q, got %d urls %q", expected, fetched, buf.String())
+	}
+}
+
+func TestCommonCrawlFetchFailed(t *testing.T) {
+	t.Parallel()
+
+	var h *httptest.Server
+	h = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/collinfo.json" {
+			json.NewEncoder(w).Encode([]map[string]string{{"cdx-api": h.URL + "/index"}})
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer h.Close()
+
+	c := &commonCrawlClient{client: h.Client(), collInfo: h.URL + "/collinfo.json"}
+	if _, err := c.fetch(context.Background(), "example.com", &bytes.Buffer{}); err == nil {
+		t.Fatal("expected an error when every index failed")
+	}
+}
+
+func TestCommonCrawlProxy(t *testing.T) {
+	t.Parallel()
+
+	// the proxy refuses the tunnel to the index server
+	var proxied []string
+	h := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = append(proxied, r.Method+" "+r.Host)
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer h.Close()
+
+	o := NewOptions()
+	o.Proxy = h.URL
+	c, err := newHTTPClient(context.Background(), o)
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if _, err := newCommonCrawlClient(nil, c.client().Transport).indexes(context.Background()); err == nil {
+		t.Fatal("expected an error when the proxy refused the tunnel")
+	}
+	if len(proxied) != 1 || proxied[0] != "CONNECT index.commoncrawl.org:443" {
+		t.Fatalf("expected the index server through the proxy, got %q", proxied)
+	}
+}
@@ -0,0 +1,208 @@
+package libgobuster
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Endpoint is a match of a run as recorded in the output files
+type Endpoint struct {
+	Path     string
+	Status   int
+	Redirect string
+}
+
+// EndpointChange is an endpoint found by both runs with another outcome
+type EndpointChange struct {
+	Before Endpoint
+	After  Endpoint
+}
+
+// RunDiff lists the differences between the matches of two runs
+type RunDiff struct {
+	Added   []Endpoint
+	Removed []Endpoint
+	Changed []EndpointChange
+}
+
+// Empty reports whether both runs found the same endpoints
+func (d RunDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// matchesLineRegex parses the lines of the per run matches files,
+// verbose runs prefix them with FOUND, MISSED or FALSE POSITIVE
+var matchesLineRegex = regexp.MustCompile(`^(FOUND|MISSED|FALSE POSITIVE)?\s*\[\d\d:\d\d:\d\d\]\s+(\d+)\s+\d+ B\s+-\s+(\S+)(?:\s+->\s+(\S+))?`)
+
+// httpxLineRegex parses the lines written with -format httpx
+var httpxLineRegex = regexp.MustCompile(`^(\w+://\S+)(?:\s+\[(\d+)\])?`)
+
+// endpointKey returns the path of an url, or the entity itself, with a
+// leading slash so both output files use the same keys
+func endpointKey(entity string) string {
+	if u, err := url.Parse(entity); err == nil && u.Scheme != "" && u.Host != "" {
+		entity = u.RequestURI()
+	}
+	return "/" + strings.TrimPrefix(entity, "/")
+}
+
+// ReadMatchesFile reads the endpoints of a per run matches file
+func ReadMatchesFile(r io.Reader) (map[string]Endpoint, error) {
+	endpoints := make(map[string]Endpoint)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		// dns mode records are tab separated
+		if strings.Contains(line, "\t") {
+			name := strings.SplitN(line, "\t", 2)[0]
+			endpoints[name] = Endpoint{Path: name}
+			continue
+		}
+		m := matchesLineRegex.FindStringSubmatch(line)
+		if m == nil {
+			if h := httpxLineRegex.FindStringSubmatch(line); h != nil {
+				status, _ := strconv.Atoi(h[2])
+				key := endpointKey(h[1])
+				endpoints[key] = Endpoint{Path: key, Status: status}
+			}
+			continue
+		}
+		if m[1] != "" && m[1] != "FOUND" {
+			continue
+		}
+		status, _ := strconv.Atoi(m[2])
+		key := endpointKey(m[3])
+		endpoints[key] = Endpoint{Path: key, Status: status, Redirect: m[4]}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return endpoints, nil
+}
+
+// ReadRunMatches reads the endpoints recorded by the given run in an
+// all time matches file
+func ReadRunMatches(r io.Reader, runID string) (map[string]Endpoint, error) {
+	endpoints := make(map[string]Endpoint)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		entity, entry := parseHistoryLine(line)
+		if entry == nil || entry.RunID != runID {
+			continue
+		}
+		e := Endpoint{Path: entity}
+		// dir mode lines continue with "<status>[  ->  <redirect>]"
+		fields := strings.Split(line, " - ")
+		if len(fields) > 2 {
+			parts := strings.SplitN(fields[2], "  ->  ", 2)
+			if status, err := strconv.Atoi(strings.TrimSpace(parts[0])); err == nil {
+				e.Path = endpointKey(entity)
+				e.Status = status
+				if len(parts) == 2 {
+					e.Redirect = strings.TrimSpace(parts[1])
+				}
+			}
+		}
+		endpoints[e.Path] = e
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return endpoints, nil
+}
+
+// CompareRuns returns the endpoints added, removed and changed in run b
+// compared to run a
+func CompareRuns(a, b map[string]Endpoint) RunDiff {
+	var d RunDiff
+	for key, after := range b {
+		before, ok := a[key]
+		if !ok {
+			d.Added = append(d.Added, after)
+		} else if before.Status != after.Status || before.Redirect != after.Redirect {
+			d.Changed = append(d.Changed, EndpointChange{Before: before, After: after})
+		}
+	}
+	for key, before := range a {
+		if _, ok := b[key]; !ok {
+			d.Removed = append(d.Removed, before)
+		}
+	}
+	sort.Slice(d.Added, func(i, j int) bool { return d.Added[i].Path < d.Added[j].Path })
+	sort.Slice(d.Removed, func(i, j int) bool { return d.Removed[i].Path < d.Removed[j].Path })
+	sort.Slice(d.Changed, func(i, j int) bool { return d.Changed[i].After.Path < d.Changed[j].After.Path })
+	return d
+}
+
+// outcome returns the status and redirect of an endpoint as text
+func (e Endpoint) outcome() string {
+	s := ""
+	if e.Status != 0 {
+		s = strconv.Itoa(e.Status)
+	}
+	if e.Redirect != "" {
+		s = fmt.Sprintf("%s -> %s", s, e.Redirect)
+	}
+	return s
+}
+
+// Markdown renders the differences as a markdown report
+func (d RunDiff) Markdown(nameA, nameB string) string {
+	buf := &bytes.Buffer{}
+	escape := strings.NewReplacer("|", `\|`)
+	fmt.Fprintf(buf, "# Comparison of %s and %s\n\n", nameA, nameB)
+	fmt.Fprintf(buf, "%d added, %d removed, %d changed\n", len(d.Added), len(d.Removed), len(d.Changed))
+
+	section := func(title string, endpoints []Endpoint) {
+		if len(endpoints) == 0 {
+			return
+		}
+		fmt.Fprintf(buf, "\n## %s\n\n| Endpoint | Status |\n| --- | --- |\n", title)
+		for _, e := range endpoints {
+			fmt.Fprintf(buf, "| %s | %s |\n", escape.Replace(e.Path), escape.Replace(e.outcome()))
+		}
+	}
+	section("Added", d.Added)
+	section("Removed", d.Removed)
+	if len(d.Changed) > 0 {
+		fmt.Fprintf(buf, "\n## Changed\n\n| Endpoint | Before | After |\n| --- | --- | --- |\n")
+		for _, c := range d.Changed {
+			fmt.Fprintf(buf, "| %s | %s | %s |\n", escape.Replace(c.After.Path), escape.Replace(c.Before.outcome()), escape.Replace(c.After.outcome()))
+		}
+	}
+	return buf.String()
+}
+
+// HTML renders the differences as a standalone html report
+func (d RunDiff) HTML(nameA, nameB string) string {
+	buf := &bytes.Buffer{}
+	title := html.EscapeString(fmt.Sprintf("Comparison of %s and %s", nameA, nameB))
+	fmt.Fprintf(buf, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>%s</title>\n", title)
+	fmt.Fprintf(buf, "<style>table{border-collapse:collapse}td,th{border:1px solid #ccc;padding:2px 8px}.added{background:#e6ffed}.removed{background:#ffeef0}.changed{background:#fff5b1}</style>\n")
+	fmt.Fprintf(buf, "</head>\n<body>\n<h1>%s</h1>\n", title)
+	fmt.Fprintf(buf, "<p>%d added, %d removed, %d changed</p>\n", len(d.Added), len(d.Removed), len(d.Changed))
+	if !d.Empty() {
+		fmt.Fprintf(buf, "<table>\n<tr><th>Endpoint</th><th>Before</th><th>After</th></tr>\n")
+		for _, e := range d.Added {
+			fmt.Fprintf(buf, "<tr class=\"added\"><td>%s</td><td></td><td>%s</td></tr>\n", html.EscapeString(e.Path), html.EscapeString(e.outcome()))
+		}
+		for _, e := range d.Removed {
+			fmt.Fprintf(buf, "<tr class=\"removed\"><td>%s</td><td>%s</td><td></td></tr>\n", html.EscapeString(e.Path), html.EscapeString(e.outcome()))
+		}
+		for _, c := range d.Changed {
+			fmt.Fprintf(buf, "<tr class=\"changed\"><td>%s</td><td>%s</td><td>%s</td></tr>\n", html.EscapeString(c.After.Path), html.EscapeString(c.Before.outcome()), html.EscapeString(c.After.outcome()))
+		}
+		fmt.Fprintf(buf, "</table>\n")
+	}
+	fmt.Fprintf(buf, "</body>\n</html>\n")
+	return buf.String()
+}
@@ -0,0 +1,88 @@
+package libgobuster
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadMatchesFile(t *testing.T) {
+	t.Parallel()
+
+	content := strings.Join([]string{
+		"[10:00:00]     200          12 B     -     http://example.com/admin",
+		"[10:00:01]     301           0 B     -     http://example.com/old  ->  http://example.com/new",
+		"FOUND           [10:00:02]     403           5 B     -     http://example.com/secret",
+		"MISSED          [10:00:03]     404           5 B     -     http://example.com/nothing",
+		"http://example.com/api [200] [42]",
+		"http://example.com/plain",
+		"garbage",
+	}, "\n")
+	got, err := ReadMatchesFile(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	expected := map[string]Endpoint{
+		"/admin":  {Path: "/admin", Status: 200},
+		"/old":    {Path: "/old", Status: 301, Redirect: "http://example.com/new"},
+		"/secret": {Path: "/secret", Status: 403},
+		"/api":    {Path: "/api", Status: 200},
+		"/plain":  {Path: "/plain"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestReadRunMatches(t *testing.T) {
+	t.Parallel()
+
+	content := strings.Join([]string{
+		"# profile aaaa mode=dir",
+		"[2026-01-01 10:00:00] - /admin - 200 - run r1 - profile aaaa",
+		"[2026-01-01 10:00:01] - /old - 301  ->  http://example.com/new - run r1 - profile aaaa",
+		"[2026-01-02 10:00:00] - /admin - 403 - run r2 - profile aaaa",
+	}, "\n")
+	got, err := ReadRunMatches(strings.NewReader(content), "r1")
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	expected := map[string]Endpoint{
+		"/admin": {Path: "/admin", Status: 200},
+		"/old":   {Path: "/old", Status: 301, Redirect: "http://example.com/new"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestCompareRuns(t *testing.T) {
+	t.Parallel()
+
+	a := map[string]Endpoint{
+		"/admin": {Path: "/admin", Status: 200},
+		"/old":   {Path: "/old", Status: 200},
+		"/same":  {Path: "/same", Status: 200},
+	}
+	b := map[string]Endpoint{
+		"/admin": {Path: "/admin", Status: 403},
+		"/new":   {Path: "/new", Status: 200},
+		"/same":  {Path: "/same", Status: 200},
+	}
+	d := CompareRuns(a, b)
+	if len(d.Added) != 1 || d.Added[0].Path != "/new" {
+		t.Fatalf("unexpected added endpoints: %v", d.Added)
+	}
+	if len(d.Removed) != 1 || d.Removed[0].Path != "/old" {
+		t.Fatalf("unexpected removed endpoints: %v", d.Removed)
+	}
+	if len(d.Changed) != 1 || d.Changed[0].Before.Status != 200 || d.Changed[0].After.Status != 403 {
+		t.Fatalf("unexpected changed endpoints: %v", d.Changed)
+	}
+	if !strings.Contains(d.Markdown("a", "b"), "| /admin | 200 | 403 |") {
+		t.Fatalf("markdown misses the changed endpoint:\n%s", d.Markdown("a", "b"))
+	}
+	if !CompareRuns(a, a).Empty() {
+		t.Fatal("expected no differences comparing a run with itself")
+	}
+}
@@ -0,0 +1,42 @@
+package libgobuster
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// parseIfModifiedSince parses the date of -if-modified-since, an HTTP
+// date or now
+func parseIfModifiedSince(value string, now time.Time) (time.Time, error) {
+	if strings.EqualFold(value, "now") {
+		return now.UTC(), nil
+	}
+	t, err := http.ParseTime(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date given: %s", value)
+	}
+	return t, nil
+}
+
+// ConditionalRequestOptions adds the If-Modified-Since and If-None-Match
+// headers of the options to the request, servers and caches honoring them
+// answer 304 without a body for the paths that exist
+func (g *Gobuster) ConditionalRequestOptions(ro RequestOptions) RequestOptions {
+	if g.Opts.IfModifiedSince == "" && g.Opts.IfNoneMatch == "" {
+		return ro
+	}
+	headers := http.Header{}
+	for name, values := range ro.Headers {
+		headers[name] = values
+	}
+	if g.Opts.IfModifiedSince != "" {
+		headers.Set("If-Modified-Since", g.Opts.IfModifiedSinceParsed.UTC().Format(http.TimeFormat))
+	}
+	if g.Opts.IfNoneMatch != "" {
+		headers.Set("If-None-Match", g.Opts.IfNoneMatch)
+	}
+	ro.Headers = headers
+	return ro
+}
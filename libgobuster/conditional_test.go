@@ -0,0 +1,96 @@
+package libgobuster
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParseIfModifiedSince(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2020, 1, 2, 10, 0, 0, 0, time.UTC)
+	tt := []struct {
+		value    string
+		expected time.Time
+		wantErr  bool
+	}{
+		{"now", now, false},
+		{"NOW", now, false},
+		{"Wed, 01 Jan 2020 08:00:00 GMT", time.Date(2020, 1, 1, 8, 0, 0, 0, time.UTC), false},
+		{"2020-01-01", time.Time{}, true},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.value, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseIfModifiedSince(x.value, now)
+			if x.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error parsing %q", x.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got error: %v", err)
+			}
+			if !got.Equal(x.expected) {
+				t.Fatalf("expected %s, got %s", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestConditionalRequestOptions(t *testing.T) {
+	t.Parallel()
+
+	modified := time.Date(2020, 1, 1, 8, 0, 0, 0, time.UTC)
+	tt := []struct {
+		testName        string
+		ifModifiedSince string
+		ifNoneMatch     string
+		expected        int
+	}{
+		{"Unconditional", "", "", http.StatusOK},
+		{"Not modified since", "Thu, 02 Jan 2020 08:00:00 GMT", "", http.StatusNotModified},
+		{"Modified since", "Tue, 31 Dec 2019 08:00:00 GMT", "", http.StatusOK},
+		{"Any etag", "", "*", http.StatusNotModified},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			h := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				http.ServeContent(w, r, "index.html", modified, strings.NewReader("index"))
+			}))
+			defer h.Close()
+
+			o := NewOptions()
+			o.IfModifiedSince = x.ifModifiedSince
+			o.IfNoneMatch = x.ifNoneMatch
+			if x.ifModifiedSince != "" {
+				o.IfModifiedSinceParsed, _ = parseIfModifiedSince(x.ifModifiedSince, time.Now())
+			}
+			c, err := newHTTPClient(context.Background(), o)
+			if err != nil {
+				t.Fatalf("got error: %v", err)
+			}
+			g := &Gobuster{Opts: o, HTTP: c, mu: new(sync.RWMutex)}
+			resp, err := g.GetRequest(h.URL, g.ConditionalRequestOptions(g.NewRequestOptions(h.URL)))
+			if err != nil {
+				t.Fatalf("got error: %v", err)
+			}
+			if resp.StatusCode != x.expected {
+				t.Fatalf("expected %d, got %d", x.expected, resp.StatusCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package libgobuster
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// debugHTTPBodyLimit is the number of response body bytes written to
+// the -debug-http log per response
+const debugHTTPBodyLimit = 4096
+
+// redactedHeaders carry the credentials of the command line, the secrets
+// file and the environment, their values never reach the -debug-http log
+var redactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie"}
+
+// httpDebugLog writes complete request/response pairs tagged with the
+// request id which is also shown next to the result
+type httpDebugLog struct {
+	mu    sync.Mutex
+	f     *os.File
+	clock Clock
+}
+
+func newHTTPDebugLog(path string, clock Clock) (*httpDebugLog, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open debug http file: %v", err)
+	}
+	return &httpDebugLog{f: f, clock: clock}, nil
+}
+
+func (d *httpDebugLog) close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.f.Close()
+}
+
+// redact returns a copy of the request with the values of the credential
+// headers replaced
+func redact(req *http.Request) *http.Request {
+	redacted := req.Clone(req.Context())
+	for _, name := range redactedHeaders {
+		if redacted.Header.Get(name) != "" {
+			redacted.Header.Set(name, "[redacted]")
+		}
+	}
+	return redacted
+}
+
+// log dumps the request and either the response or the error
+func (d *httpDebugLog) log(requestID string, req *http.Request, resp *http.Response, body []byte, reqErr error) {
+	dumpReq, err := httputil.DumpRequestOut(redact(req), false)
+	if err != nil {
+		dumpReq = []byte(fmt.Sprintf("%s %s (dump failed: %v)\r\n", req.Method, req.URL, err))
+	}
+
+	var dumpResp []byte
+	if resp != nil {
+		dumpResp, err = httputil.DumpResponse(resp, false)
+		if err != nil {
+			dumpResp = []byte(fmt.Sprintf("%s (dump failed: %v)\r\n", resp.Status, err))
+		}
+		if len(body) > debugHTTPBodyLimit {
+			body = append(body[:debugHTTPBodyLimit:debugHTTPBodyLimit], []byte("\n[truncated]")...)
+		}
+		dumpResp = append(dumpResp, body...)
+	} else {
+		dumpResp = []byte(fmt.Sprintf("error: %v", reqErr))
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	fmt.Fprintf(d.f, "===== %s %s =====\n%s\n----- response -----\n%s\n\n", requestID, d.clock.Now().Format(time.RFC3339), dumpReq, dumpResp)
+}
+
+// NewRequestID returns a new id to correlate a request with its result
+func (g *Gobuster) NewRequestID() string {
+	return fmt.Sprintf("req-%08x", atomic.AddUint32(&g.requestIDs, 1))
+}
@@ -0,0 +1,49 @@
+package libgobuster
+
+import (
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHTTPDebugLogRedacts(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "debug.log")
+	d, err := newHTTPDebugLog(path, FixedClock(time.Unix(0, 0)))
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	req, err := http.NewRequest(http.MethodGet, "http://example.com/admin", nil)
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	req.SetBasicAuth("admin", "s3cret")
+	req.Header.Set("Cookie", "session=t0ken")
+	req.Header.Set("X-Trace", "visible")
+	d.log("req-00000001", req, nil, nil, http.ErrHandlerTimeout)
+	if err := d.close(); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	for _, secret := range []string{"s3cret", "YWRtaW46czNjcmV0", "t0ken"} {
+		if strings.Contains(string(content), secret) {
+			t.Fatalf("%q written to the debug log:\n%s", secret, content)
+		}
+	}
+	for _, expected := range []string{"Authorization: [redacted]", "Cookie: [redacted]", "X-Trace: visible"} {
+		if !strings.Contains(string(content), expected) {
+			t.Fatalf("%q missing from the debug log:\n%s", expected, content)
+		}
+	}
+	if req.Header.Get("Cookie") != "session=t0ken" {
+		t.Fatal("the request sent was redacted")
+	}
+}
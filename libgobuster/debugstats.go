@@ -0,0 +1,86 @@
+package libgobuster
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"time"
+)
+
+// runtimeStats is a snapshot of the memory, goroutines and queues of a
+// scan, logged by -debug-stats to tell leaks from backlogs on long scans
+type runtimeStats struct {
+	HeapAlloc   uint64
+	HeapObjects uint64
+	Sys         uint64
+	Goroutines  int
+	NumGC       uint32
+	LastPause   time.Duration
+	PauseTotal  time.Duration
+	// Queued and QueueCap are the targets waiting for a worker
+	Queued     int
+	QueueCap   int
+	Discovered int
+	Requests   int
+	Matches    int
+}
+
+// mib formats a number of bytes in MiB
+func mib(b uint64) string {
+	return fmt.Sprintf("%.1f MiB", float64(b)/(1024*1024))
+}
+
+func (s runtimeStats) String() string {
+	return fmt.Sprintf("heap %s (%d objects), sys %s, goroutines %d, queued %d/%d, discovered %d, requests %d, matches %d, gc %d (last pause %s, total %s)",
+		mib(s.HeapAlloc), s.HeapObjects, mib(s.Sys), s.Goroutines, s.Queued, s.QueueCap, s.Discovered, s.Requests, s.Matches, s.NumGC, s.LastPause, s.PauseTotal)
+}
+
+// len returns the number of targets waiting in the queue
+func (q *targetQueue) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.pending)
+}
+
+// runtimeStats takes a snapshot of the scan feeding the workers through
+// wordChan
+func (g *Gobuster) runtimeStats(wordChan chan *BusterTarget) runtimeStats {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	s := runtimeStats{
+		HeapAlloc:   m.HeapAlloc,
+		HeapObjects: m.HeapObjects,
+		Sys:         m.Sys,
+		Goroutines:  runtime.NumGoroutine(),
+		NumGC:       m.NumGC,
+		PauseTotal:  time.Duration(m.PauseTotalNs),
+		Queued:      len(wordChan),
+		QueueCap:    cap(wordChan),
+	}
+	if m.NumGC > 0 {
+		s.LastPause = time.Duration(m.PauseNs[(m.NumGC+255)%256])
+	}
+	if g.discovered != nil {
+		s.Discovered = g.discovered.len()
+	}
+	g.mu.RLock()
+	s.Requests = g.requestsIssued
+	s.Matches = g.matchCount
+	g.mu.RUnlock()
+	return s
+}
+
+// logStatsEvery logs the runtime stats periodically until the context
+// is done
+func (g *Gobuster) logStatsEvery(c context.Context, wordChan chan *BusterTarget, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.Done():
+			return
+		case <-ticker.C:
+			g.Logf("[+] Stats: %s", g.runtimeStats(wordChan))
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package libgobuster
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestRuntimeStats(t *testing.T) {
+	t.Parallel()
+
+	g := &Gobuster{Opts: NewOptions(), mu: new(sync.RWMutex), discovered: newTargetQueue(), requestsIssued: 12, matchCount: 3}
+	g.discovered.add(&BusterTarget{Target: "admin"})
+	wordChan := make(chan *BusterTarget, 4)
+	wordChan <- &BusterTarget{Target: "login"}
+
+	s := g.runtimeStats(wordChan)
+	if s.Queued != 1 || s.QueueCap != 4 || s.Discovered != 1 || s.Requests != 12 || s.Matches != 3 {
+		t.Fatalf("unexpected stats %+v", s)
+	}
+	if s.HeapAlloc == 0 || s.Goroutines == 0 {
+		t.Fatalf("expected the runtime stats, got %+v", s)
+	}
+	if line := s.String(); !strings.Contains(line, "queued 1/4") || !strings.Contains(line, "requests 12") {
+		t.Fatalf("unexpected stats line %q", line)
+	}
+}
@@ -0,0 +1,143 @@
+package libgobuster
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// targetQueue holds the targets discovered while scanning, the words of
+// the target's pages and the endpoints of its scripts, which are
+// requested once the wordlist is done. Every target is queued once.
+type targetQueue struct {
+	mu      sync.Mutex
+	seen    Set[string]
+	pending []*BusterTarget
+}
+
+func newTargetQueue() *targetQueue {
+	return &targetQueue{seen: NewSet[string]()}
+}
+
+// add queues the target unless it was queued before
+func (q *targetQueue) add(t *BusterTarget) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if !q.seen.Add(t.Target) {
+		return false
+	}
+	q.pending = append(q.pending, t)
+	return true
+}
+
+// take returns the queued targets and empties the queue
+func (q *targetQueue) take() []*BusterTarget {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	targets := q.pending
+	q.pending = nil
+	return targets
+}
+
+// waitInFlight waits for the workers to process every target sent to
+// them, the discoveries of the last ones included
+func (g *Gobuster) waitInFlight() {
+	done := make(chan struct{})
+	go func() {
+		g.inFlight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-g.context.Done():
+	}
+}
+
+// waitLevel pauses the scan for -level-delay before the next level of
+// discovered targets, it returns false once the scan is cancelled
+func (g *Gobuster) waitLevel() bool {
+	if g.Opts.LevelDelay <= 0 {
+		return g.context.Err() == nil
+	}
+	select {
+	case <-time.After(g.Opts.LevelDelay):
+		return true
+	case <-g.context.Done():
+		return false
+	}
+}
+
+// recordPriorityDirs records the directories of the matches answering one
+// of -prioritize-codes, the wildcard answers are left out
+func (g *Gobuster) recordPriorityDirs(res []Result) {
+	if g.priorityDirs.Set == nil {
+		return
+	}
+	for _, r := range res {
+		if !r.Match || !g.Opts.PrioritizeCodesParsed.Contains(r.Status) {
+			continue
+		}
+		dir := r.FullURL(g)
+		if i := strings.IndexAny(dir, "?#"); i >= 0 {
+			dir = dir[:i]
+		}
+		if !strings.HasSuffix(dir, "/") {
+			dir += "/"
+		}
+		g.mu.Lock()
+		g.priorityDirs.Add(dir)
+		g.mu.Unlock()
+	}
+}
+
+// prioritize moves the discovered urls under a directory of
+// -prioritize-codes to the front of their level, the order is kept
+// otherwise. It returns the number of targets moved.
+func (g *Gobuster) prioritize(targets []*BusterTarget) int {
+	if g.priorityDirs.Set == nil {
+		return 0
+	}
+	g.mu.RLock()
+	dirs := g.priorityDirs.Values()
+	g.mu.RUnlock()
+	first := make(map[*BusterTarget]bool)
+	for _, t := range targets {
+		if !t.IsURL {
+			continue
+		}
+		for _, dir := range dirs {
+			if strings.HasPrefix(t.Target, dir) && t.Target != dir {
+				first[t] = true
+				break
+			}
+		}
+	}
+	sort.SliceStable(targets, func(i, j int) bool {
+		return first[targets[i]] && !first[targets[j]]
+	})
+	return len(first)
+}
+
+// sendDiscovered requests the discovered targets until no new ones come
+// up, the matches among them may discover more. Every round is a level,
+// the previous one is done before the next is sent.
+func (g *Gobuster) sendDiscovered(wordChan chan<- *BusterTarget) {
+	for {
+		g.waitInFlight()
+		targets := g.discovered.take()
+		if len(targets) == 0 || !g.waitLevel() {
+			return
+		}
+		g.mu.Lock()
+		g.requestsExpected += len(targets)
+		g.mu.Unlock()
+		g.Logf("Requesting %d targets discovered on the target..", len(targets))
+		if n := g.prioritize(targets); n > 0 {
+			g.Logf("[+] Requesting first the %d under the directories answering %s", n, g.Opts.PrioritizeCodes)
+		}
+		for _, t := range targets {
+			g.sendTarget(wordChan, t)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package libgobuster
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestPrioritize(t *testing.T) {
+	t.Parallel()
+
+	o := NewOptions()
+	o.URL = "http://example.com/"
+	if err := parseStatusCodeList("401,403", "prioritize", o.PrioritizeCodesParsed); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	g := &Gobuster{Opts: o, mu: new(sync.RWMutex), priorityDirs: NewSet[string]()}
+	g.recordPriorityDirs([]Result{
+		{Entity: "admin", Status: 403, Match: true},
+		{Entity: "private/", Status: 401, Match: true},
+		{Entity: "public", Status: 200, Match: true},
+		// a wildcard 403
+		{Entity: "secret", Status: 403},
+	})
+
+	targets := []*BusterTarget{
+		{IsURL: true, Target: "http://example.com/public/app.js"},
+		{IsURL: true, Target: "http://example.com/secret/db"},
+		{Target: "admin"},
+		{IsURL: true, Target: "http://example.com/private/keys"},
+		{IsURL: true, Target: "http://example.com/administrator/"},
+		{IsURL: true, Target: "http://example.com/admin/users"},
+	}
+	if n := g.prioritize(targets); n != 2 {
+		t.Fatalf("expected 2 targets prioritized, got %d", n)
+	}
+	var got []string
+	for _, target := range targets {
+		got = append(got, target.Target)
+	}
+	expected := []string{
+		"http://example.com/private/keys",
+		"http://example.com/admin/users",
+		"http://example.com/public/app.js",
+		"http://example.com/secret/db",
+		"admin",
+		"http://example.com/administrator/",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestParseStatusCodeList(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		list        string
+		expectedLen int
+		expectedErr bool
+	}{
+		{"401,403", 2, false},
+		{"4xx, 200", 101, false},
+		{"40x", 0, true},
+	}
+	for _, x := range tt {
+		x := x
+		t.Run(x.list, func(t *testing.T) {
+			t.Parallel()
+
+			parsed := NewSet[int]()
+			err := parseStatusCodeList(x.list, "prioritize", parsed)
+			if (err != nil) != x.expectedErr {
+				t.Fatalf("expected error %v, got %v", x.expectedErr, err)
+			}
+			if !x.expectedErr && parsed.Len() != x.expectedLen {
+				t.Fatalf("expected %d codes, got %d", x.expectedLen, parsed.Len())
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package libgobuster
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+const (
+	// DNSErrorNXDomain means the domain does not exist
+	DNSErrorNXDomain = "nxdomain"
+	// DNSErrorServfail means the server failed to answer the query
+	DNSErrorServfail = "servfail"
+	// DNSErrorTimeout means the query timed out
+	DNSErrorTimeout = "timeout"
+	// DNSErrorOther is any other lookup error
+	DNSErrorOther = "error"
+)
+
+const (
+	// DNSSourceA means the subdomain has address records of its own
+	DNSSourceA = "A"
+	// DNSSourceCNAME means the addresses were reached through a CNAME chain
+	DNSSourceCNAME = "CNAME"
+)
+
+// DNSLookupError is a classified dns lookup error
+type DNSLookupError struct {
+	Domain string
+	Kind   string
+	Err    error
+}
+
+func (e *DNSLookupError) Error() string {
+	return fmt.Sprintf("%s: %s: %v", e.Kind, e.Domain, e.Err)
+}
+
+// ClassifyDNSError returns the kind of a dns lookup error
+func ClassifyDNSError(err error) string {
+	if le, ok := err.(*DNSLookupError); ok {
+		return le.Kind
+	}
+	dnsErr, ok := err.(*net.DNSError)
+	if !ok {
+		return DNSErrorOther
+	}
+	switch {
+	case dnsErr.IsTimeout:
+		return DNSErrorTimeout
+	case dnsErr.IsNotFound:
+		return DNSErrorNXDomain
+	case dnsErr.IsTemporary:
+		return DNSErrorServfail
+	}
+	return DNSErrorOther
+}
+
+// dnsResolver wraps a net.Resolver with a per query timeout and
+// retries for transient failures
+type dnsResolver struct {
+	resolver *net.Resolver
+	// fast replaces the resolver with -fast-dns
+	fast    *fastResolver
+	timeout time.Duration
+	retries int
+}
+
+func newDNSResolver(opt *Options) *dnsResolver {
+	return &dnsResolver{
+		resolver: newNetResolver(opt),
+		timeout:  opt.DNSTimeout,
+		retries:  opt.DNSRetries,
+	}
+}
+
+// lookup runs the query, retrying on servfail and timeouts
+func (r *dnsResolver) lookup(c context.Context, domain string, query func(context.Context) error) error {
+	var err error
+	for attempt := 0; attempt <= r.retries; attempt++ {
+		ctx, cancel := context.WithTimeout(c, r.timeout)
+		err = query(ctx)
+		cancel()
+		if err == nil {
+			return nil
+		}
+		kind := ClassifyDNSError(err)
+		if (kind != DNSErrorServfail && kind != DNSErrorTimeout) || c.Err() != nil {
+			return &DNSLookupError{Domain: domain, Kind: kind, Err: err}
+		}
+	}
+	return &DNSLookupError{Domain: domain, Kind: ClassifyDNSError(err), Err: err}
+}
+
+func (r *dnsResolver) lookupHost(c context.Context, domain string) ([]string, error) {
+	if r.fast != nil {
+		addrs, _, err := r.lookupFast(c, domain)
+		return addrs, err
+	}
+	var addrs []string
+	err := r.lookup(c, domain, func(ctx context.Context) error {
+		var err error
+		addrs, err = r.resolver.LookupHost(ctx, domain)
+		return err
+	})
+	return addrs, err
+}
+
+func (r *dnsResolver) lookupCNAME(c context.Context, domain string) (string, error) {
+	if r.fast != nil {
+		_, cname, err := r.lookupFast(c, domain)
+		if cname != "" {
+			return cname, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		// the canonical name of a domain without CNAME is itself
+		return strings.TrimSuffix(domain, ".") + ".", nil
+	}
+	var cname string
+	err := r.lookup(c, domain, func(ctx context.Context) error {
+		var err error
+		cname, err = r.resolver.LookupCNAME(ctx, domain)
+		return err
+	})
+	return cname, err
+}
+
+// lookupFast resolves the addresses and CNAME with the -fast-dns A and AAAA queries
+func (r *dnsResolver) lookupFast(c context.Context, domain string) ([]string, string, error) {
+	var addrs []string
+	var cname string
+	err := r.lookup(c, domain, func(ctx context.Context) error {
+		var err error
+		addrs, cname, err = r.fast.exchange(ctx, domain)
+		return err
+	})
+	return addrs, cname, err
+}
+
+func (r *dnsResolver) close() {
+	if r.fast != nil {
+		r.fast.close()
+	}
+}
@@ -0,0 +1,156 @@
+package libgobuster
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestClassifyDNSError(t *testing.T) {
+	t.Parallel()
+
+	var tt = []struct {
+		testName string
+		err      error
+		expected string
+	}{
+		{"NXDOMAIN", &net.DNSError{Err: "no such host", IsNotFound: true}, DNSErrorNXDomain},
+		{"Timeout", &net.DNSError{Err: "i/o timeout", IsTimeout: true}, DNSErrorTimeout},
+		{"SERVFAIL", &net.DNSError{Err: "server misbehaving", IsTemporary: true}, DNSErrorServfail},
+		{"Wrapped", &DNSLookupError{Kind: DNSErrorTimeout}, DNSErrorTimeout},
+		{"Other", errors.New("boom"), DNSErrorOther},
+	}
+	for _, x := range tt {
+		t.Run(x.testName, func(t *testing.T) {
+			if kind := ClassifyDNSError(x.err); kind != x.expected {
+				t.Fatalf("Expected %q got %q", x.expected, kind)
+			}
+		})
+	}
+}
+
+func TestDoHConn(t *testing.T) {
+	t.Parallel()
+
+	h := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/dns-message" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		query, _ := ioutil.ReadAll(r.Body)
+		// echo the query back reversed
+		for i, j := 0, len(query)-1; i < j; i, j = i+1, j-1 {
+			query[i], query[j] = query[j], query[i]
+		}
+		w.Write(query)
+	}))
+	defer h.Close()
+
+	c := &dohConn{ctx: context.Background(), client: h.Client(), url: h.URL}
+	// the query arrives in two writes like a buffered stream would
+	if _, err := c.Write([]byte{0, 3, 'a'}); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if _, err := c.Write([]byte{'b', 'c'}); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	answer, err := ioutil.ReadAll(c)
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	expected := []byte{0, 3, 'c', 'b', 'a'}
+	if !bytes.Equal(answer, expected) {
+		t.Fatalf("expected %v, got %v", expected, answer)
+	}
+}
+
+func TestDoHConnDeadline(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	h := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer h.Close()
+	defer close(release)
+
+	tt := []struct {
+		testName string
+		deadline time.Time
+	}{
+		{"Passed", time.Now().Add(-time.Second)},
+		{"Reached while waiting", time.Now().Add(50 * time.Millisecond)},
+	}
+	for _, x := range tt {
+		t.Run(x.testName, func(t *testing.T) {
+			c := &dohConn{ctx: context.Background(), client: h.Client(), url: h.URL}
+			if err := c.SetDeadline(x.deadline); err != nil {
+				t.Fatalf("got error: %v", err)
+			}
+			if _, err := c.Write([]byte{0, 1, 'a'}); !errors.Is(err, os.ErrDeadlineExceeded) {
+				t.Fatalf("expected the deadline exceeded, got %v", err)
+			}
+		})
+	}
+}
+
+func TestDoHClient(t *testing.T) {
+	t.Parallel()
+
+	h := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer h.Close()
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = append(proxied, r.Host)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer proxy.Close()
+
+	tt := []struct {
+		testName string
+		insecure bool
+		proxy    string
+		url      string
+		status   int
+		err      bool
+	}{
+		{"Untrusted certificate", false, "", h.URL, 0, true},
+		{"Untrusted certificate with -k", true, "", h.URL, http.StatusOK, false},
+		{"Proxy", false, proxy.URL, "http://doh.example/dns-query", http.StatusBadGateway, false},
+	}
+	for _, x := range tt {
+		t.Run(x.testName, func(t *testing.T) {
+			o := NewOptions()
+			o.InsecureSSL = x.insecure
+			o.Proxy = x.proxy
+			client, err := newDoHClient(o)
+			if err != nil {
+				t.Fatalf("got error: %v", err)
+			}
+			resp, err := client.Post(x.url, "application/dns-message", nil)
+			if x.err {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got error: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != x.status {
+				t.Fatalf("expected %d, got %d", x.status, resp.StatusCode)
+			}
+		})
+	}
+	if len(proxied) != 1 || proxied[0] != "doh.example" {
+		t.Fatalf("expected the DoH server through the proxy, got %q", proxied)
+	}
+}
@@ -0,0 +1,173 @@
+package libgobuster
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DNSCacheRate is the number of lookups per second of the hosts missing
+// from the cache, a run over hundreds of targets would otherwise flood
+// the resolver when it starts
+const DNSCacheRate = 20
+
+// dnsCacheLookupTimeout caps a lookup of the cache, shared by the dials
+// of its host it is not cut short by the one that started it
+const dnsCacheLookupTimeout = 30 * time.Second
+
+// dnsCacheEntry holds the addresses of a host until they expire
+type dnsCacheEntry struct {
+	addrs   []string
+	expires time.Time
+}
+
+// dnsLookup is a lookup in flight, the dials of its host wait for it
+type dnsLookup struct {
+	done  chan struct{}
+	addrs []string
+	err   error
+}
+
+// DNSCache resolves the hosts of the requests itself, with -dns-cache-ttl,
+// caching the addresses for the ttl only so long scans follow DNS
+// failovers. The connections are spread over every address of a host.
+// One cache is shared by the scans of a run over many targets, the
+// hosts they have in common are looked up once.
+type DNSCache struct {
+	lookup  func(ctx context.Context, host string) ([]string, error)
+	ttl     time.Duration
+	clock   Clock
+	limiter *rateLimiter
+
+	mu      sync.Mutex
+	entries map[string]dnsCacheEntry
+	pending map[string]*dnsLookup
+	next    uint32
+}
+
+// NewDNSCache returns the cache of the addresses of the hosts shared by
+// the scans of a run, with the ttl and resolver of the options
+func NewDNSCache(opt *Options) *DNSCache {
+	var resolver *net.Resolver
+	if opt.DoHURL != "" || opt.DoTServer != "" {
+		resolver = newNetResolver(opt)
+	}
+	return newDNSCache(resolver, opt.DNSCacheTTL, opt.clock())
+}
+
+func newDNSCache(resolver *net.Resolver, ttl time.Duration, clock Clock) *DNSCache {
+	if resolver == nil {
+		// the go resolver queries the nameservers, bypassing the caches
+		// of the system
+		resolver = &net.Resolver{PreferGo: true}
+	}
+	return &DNSCache{
+		lookup:  resolver.LookupHost,
+		ttl:     ttl,
+		clock:   clock,
+		limiter: newRateLimiter(DNSCacheRate, clock),
+		entries: make(map[string]dnsCacheEntry),
+	}
+}
+
+// addrs returns the addresses of the host, looked up again once expired.
+// The concurrent dials of a host share its lookup, which runs on a
+// context of its own so a cancelled dial never fails the others.
+func (d *DNSCache) addrs(ctx context.Context, host string) ([]string, error) {
+	now := d.clock.Now()
+	d.mu.Lock()
+	if entry, ok := d.entries[host]; ok && now.Before(entry.expires) {
+		d.mu.Unlock()
+		return entry.addrs, nil
+	}
+	if l, ok := d.pending[host]; ok {
+		d.mu.Unlock()
+		select {
+		case <-l.done:
+			return l.addrs, l.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if d.pending == nil {
+		d.pending = make(map[string]*dnsLookup)
+	}
+	l := &dnsLookup{done: make(chan struct{})}
+	d.pending[host] = l
+	d.mu.Unlock()
+
+	go func() {
+		lookupCtx, cancel := context.WithTimeout(context.Background(), dnsCacheLookupTimeout)
+		defer cancel()
+		if d.limiter != nil {
+			d.limiter.wait(lookupCtx)
+		}
+		l.addrs, l.err = d.lookup(lookupCtx, host)
+		d.mu.Lock()
+		delete(d.pending, host)
+		if l.err == nil && d.ttl > 0 {
+			d.entries[host] = dnsCacheEntry{addrs: l.addrs, expires: now.Add(d.ttl)}
+		}
+		d.mu.Unlock()
+		close(l.done)
+	}()
+	select {
+	case <-l.done:
+		return l.addrs, l.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// dialContext returns a DialContext dialing the addresses of the host
+// round-robin, the next ones are tried when one fails. The static
+// addresses of -static-resolve are dialed without a lookup, the other
+// hosts go through the dialer's resolver without a ttl.
+func (d *DNSCache) dialContext(dialer *net.Dialer, static map[string][]string) func(ctx context.Context, network, address string) (net.Conn, error) {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(address)
+		if err != nil || net.ParseIP(host) != nil {
+			return dialer.DialContext(ctx, network, address)
+		}
+		addrs, ok := static[strings.ToLower(host)]
+		if !ok && d.ttl <= 0 {
+			return dialer.DialContext(ctx, network, address)
+		}
+		if !ok {
+			addrs, err = d.addrs(ctx, host)
+			if err != nil {
+				return nil, err
+			}
+		}
+		start := int(atomic.AddUint32(&d.next, 1))
+		for i := range addrs {
+			addr := addrs[(start+i)%len(addrs)]
+			var conn net.Conn
+			conn, err = dialer.DialContext(ctx, network, net.JoinHostPort(addr, port))
+			if err == nil {
+				return conn, nil
+			}
+		}
+		return nil, err
+	}
+}
+
+// recycleEvery closes the idle connections at every interval, the next
+// requests open new ones to whichever backend the target routes them
+func (client *httpClient) recycleEvery(c context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.Done():
+			return
+		case <-ticker.C:
+			for _, cl := range client.clients {
+				cl.CloseIdleConnections()
+			}
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package libgobuster
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDNSCacheExpiry(t *testing.T) {
+	t.Parallel()
+
+	lookups := 0
+	now := time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+	d := &DNSCache{
+		lookup: func(ctx context.Context, host string) ([]string, error) {
+			lookups++
+			if lookups == 1 {
+				return []string{"10.0.0.1"}, nil
+			}
+			return []string{"10.0.0.2"}, nil
+		},
+		ttl:     time.Minute,
+		clock:   FixedClock(now),
+		entries: make(map[string]dnsCacheEntry),
+	}
+
+	tt := []struct {
+		after    time.Duration
+		expected []string
+		lookups  int
+	}{
+		{0, []string{"10.0.0.1"}, 1},
+		{30 * time.Second, []string{"10.0.0.1"}, 1},
+		{time.Minute, []string{"10.0.0.2"}, 2},
+	}
+
+	for _, x := range tt {
+		d.clock = FixedClock(now.Add(x.after))
+		addrs, err := d.addrs(context.Background(), "example.com")
+		if err != nil {
+			t.Fatalf("got error: %v", err)
+		}
+		if !reflect.DeepEqual(addrs, x.expected) || lookups != x.lookups {
+			t.Fatalf("after %s expected %v with %d lookups, got %v with %d", x.after, x.expected, x.lookups, addrs, lookups)
+		}
+	}
+}
+
+func TestDNSCacheDialContext(t *testing.T) {
+	t.Parallel()
+
+	// two backends on the same port, 127.0.0.2 refuses the connections
+	first, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	defer first.Close()
+	_, port, _ := net.SplitHostPort(first.Addr().String())
+	second, err := net.Listen("tcp", net.JoinHostPort("127.0.0.3", port))
+	if err != nil {
+		t.Skipf("unable to listen on 127.0.0.3: %v", err)
+	}
+	defer second.Close()
+
+	d := &DNSCache{
+		lookup: func(ctx context.Context, host string) ([]string, error) {
+			return []string{"127.0.0.2", "127.0.0.1", "127.0.0.3"}, nil
+		},
+		ttl:     time.Minute,
+		clock:   SystemClock,
+		entries: make(map[string]dnsCacheEntry),
+	}
+	dial := d.dialContext(&net.Dialer{Timeout: time.Second}, nil)
+	// round-robin over the addresses, the refused one falls back to the
+	// next
+	expected := []string{"127.0.0.1", "127.0.0.3", "127.0.0.1", "127.0.0.1"}
+	for i, ip := range expected {
+		conn, err := dial(context.Background(), "tcp", net.JoinHostPort("target.test", port))
+		if err != nil {
+			t.Fatalf("got error: %v", err)
+		}
+		host, _, _ := net.SplitHostPort(conn.RemoteAddr().String())
+		conn.Close()
+		if host != ip {
+			t.Fatalf("dial %d: expected %s, got %s", i, ip, host)
+		}
+	}
+}
+
+func TestDNSCacheSharedLookup(t *testing.T) {
+	t.Parallel()
+
+	var lookups int32
+	release := make(chan struct{})
+	d := &DNSCache{
+		lookup: func(ctx context.Context, host string) ([]string, error) {
+			atomic.AddInt32(&lookups, 1)
+			<-release
+			return []string{"10.0.0.1"}, nil
+		},
+		ttl:     time.Minute,
+		clock:   SystemClock,
+		entries: make(map[string]dnsCacheEntry),
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			addrs, err := d.addrs(context.Background(), "example.com")
+			if err != nil || !reflect.DeepEqual(addrs, []string{"10.0.0.1"}) {
+				t.Errorf("expected [10.0.0.1], got %v %v", addrs, err)
+			}
+		}()
+	}
+	// let every dial reach the cache before the lookup returns
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if n := atomic.LoadInt32(&lookups); n != 1 {
+		t.Fatalf("expected 1 lookup, got %d", n)
+	}
+}
+
+func TestDNSCacheCancelledDial(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	d := &DNSCache{
+		lookup: func(ctx context.Context, host string) ([]string, error) {
+			select {
+			case <-release:
+				return []string{"10.0.0.1"}, nil
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		},
+		ttl:     time.Minute,
+		clock:   SystemClock,
+		entries: make(map[string]dnsCacheEntry),
+	}
+
+	// the dial starting the lookup gives up, the one waiting for it not
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := d.addrs(ctx, "example.com")
+		first <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	second := make(chan []string, 1)
+	go func() {
+		addrs, _ := d.addrs(context.Background(), "example.com")
+		second <- addrs
+	}()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	if err := <-first; err != context.Canceled {
+		t.Fatalf("expected the first dial cancelled, got %v", err)
+	}
+	close(release)
+	if addrs := <-second; !reflect.DeepEqual(addrs, []string{"10.0.0.1"}) {
+		t.Fatalf("expected [10.0.0.1], got %v", addrs)
+	}
+}
+
+func TestStaticResolveWithoutTTL(t *testing.T) {
+	t.Parallel()
+
+	h := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer h.Close()
+	_, port, _ := net.SplitHostPort(h.Listener.Addr().String())
+
+	d := &DNSCache{
+		lookup: func(ctx context.Context, host string) ([]string, error) {
+			t.Errorf("unexpected lookup of %s", host)
+			return nil, nil
+		},
+		clock:   SystemClock,
+		entries: make(map[string]dnsCacheEntry),
+	}
+	// the hosts not overridden are left to the dialer
+	dial := d.dialContext(&net.Dialer{Timeout: time.Second}, map[string][]string{"static.test": {"127.0.0.1"}})
+	for _, host := range []string{"static.test", "localhost"} {
+		conn, err := dial(context.Background(), "tcp", net.JoinHostPort(host, port))
+		if err != nil {
+			t.Fatalf("%s: got error: %v", host, err)
+		}
+		conn.Close()
+	}
+}
+
+func TestParseStaticResolve(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		value    string
+		expected map[string][]string
+		err      bool
+	}{
+		{"www.example.com=10.0.0.5", map[string][]string{"www.example.com": {"10.0.0.5"}}, false},
+		{"A.test=10.0.0.1, a.test=::1", map[string][]string{"a.test": {"10.0.0.1", "::1"}}, false},
+		{"a.test", nil, true},
+		{"a.test=10.0.0", nil, true},
+		{"=10.0.0.1", nil, true},
+	}
+	for _, x := range tt {
+		x := x
+		t.Run(x.value, func(t *testing.T) {
+			t.Parallel()
+
+			o := NewOptions()
+			o.StaticResolve = x.value
+			err := o.parseStaticResolve()
+			if (err != nil) != x.err {
+				t.Fatalf("expected error %t, got %v", x.err, err)
+			}
+			if err == nil && !reflect.DeepEqual(o.StaticResolveParsed, x.expected) {
+				t.Fatalf("expected %v, got %v", x.expected, o.StaticResolveParsed)
+			}
+		})
+	}
+}
+
+func TestStaticResolveDial(t *testing.T) {
+	t.Parallel()
+
+	h := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer h.Close()
+	_, port, _ := net.SplitHostPort(h.Listener.Addr().String())
+
+	o := NewOptions()
+	o.StaticResolve = "static.test=127.0.0.1"
+	if err := o.parseStaticResolve(); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	c, err := newHTTPClient(context.Background(), o)
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	// static.test resolves nowhere but to the override
+	resp, err := c.makeRequest(http.MethodGet, "http://static.test:"+port+"/", RequestOptions{})
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected a 200, got %+v %v", resp, err)
+	}
+}
@@ -0,0 +1,174 @@
+package libgobuster
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"time"
+)
+
+// newNetResolver returns the resolver used for dns mode lookups and
+// dir mode hostname resolution, going over DNS-over-HTTPS or
+// DNS-over-TLS when configured
+func newNetResolver(opt *Options) *net.Resolver {
+	switch {
+	case opt.DoHURL != "":
+		client, err := newDoHClient(opt)
+		return &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+				// an invalid proxy fails the lookups rather than
+				// sending them around it
+				if err != nil {
+					return nil, err
+				}
+				return &dohConn{ctx: ctx, client: client, url: opt.DoHURL}, nil
+			},
+		}
+	case opt.DoTServer != "":
+		server := opt.DoTServer
+		if _, _, err := net.SplitHostPort(server); err != nil {
+			server = net.JoinHostPort(server, "853")
+		}
+		host, _, _ := net.SplitHostPort(server)
+		dialer := &tls.Dialer{
+			NetDialer: &net.Dialer{Timeout: opt.DNSTimeout},
+			Config:    &tls.Config{ServerName: host},
+		}
+		return &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+				// a stream connection makes the resolver use TCP framing
+				return dialer.DialContext(ctx, "tcp", server)
+			},
+		}
+	}
+	return &net.Resolver{}
+}
+
+// newDoHClient returns the client of the DoH server, going through the
+// proxy of the scan and trusting any certificate with -k
+func newDoHClient(opt *Options) (*http.Client, error) {
+	proxy, err := opt.proxyFunc()
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{
+		Timeout: opt.DNSTimeout,
+		Transport: &http.Transport{
+			Proxy:           proxy,
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: opt.InsecureSSL},
+		},
+	}, nil
+}
+
+// dohConn is a fake stream connection handed to the go resolver. The
+// resolver writes length prefixed queries (TCP framing) which are
+// POSTed to the DoH endpoint, the answer is returned length prefixed.
+// The deadlines the resolver sets bound the exchanges.
+type dohConn struct {
+	ctx           context.Context
+	client        *http.Client
+	url           string
+	query         bytes.Buffer
+	answer        bytes.Buffer
+	readDeadline  time.Time
+	writeDeadline time.Time
+}
+
+func (c *dohConn) Write(b []byte) (int, error) {
+	if expired(c.writeDeadline) {
+		return 0, os.ErrDeadlineExceeded
+	}
+	c.query.Write(b)
+	data := c.query.Bytes()
+	if len(data) < 2 || len(data) < 2+int(binary.BigEndian.Uint16(data)) {
+		// wait for the complete message
+		return len(b), nil
+	}
+	msgLen := int(binary.BigEndian.Uint16(data))
+	msg := make([]byte, msgLen)
+	copy(msg, data[2:2+msgLen])
+	c.query.Next(2 + msgLen)
+
+	answer, err := c.exchange(msg)
+	if err != nil {
+		return 0, err
+	}
+	var prefix [2]byte
+	binary.BigEndian.PutUint16(prefix[:], uint16(len(answer)))
+	c.answer.Write(prefix[:])
+	c.answer.Write(answer)
+	return len(b), nil
+}
+
+func (c *dohConn) exchange(msg []byte) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodPost, c.url, bytes.NewReader(msg))
+	if err != nil {
+		return nil, err
+	}
+	// the answer is waited for up to the earliest deadline
+	ctx := c.ctx
+	for _, deadline := range []time.Time{c.writeDeadline, c.readDeadline} {
+		if !deadline.IsZero() {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithDeadline(ctx, deadline)
+			defer cancel()
+		}
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/dns-message")
+	req.Header.Set("Accept", "application/dns-message")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded && c.ctx.Err() == nil {
+			return nil, os.ErrDeadlineExceeded
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("DoH server returned status %d", resp.StatusCode)
+	}
+	// a dns message is at most 64k
+	return ioutil.ReadAll(io.LimitReader(resp.Body, 65535))
+}
+
+func (c *dohConn) Read(b []byte) (int, error) {
+	if c.answer.Len() == 0 {
+		return 0, io.EOF
+	}
+	return c.answer.Read(b)
+}
+
+// expired reports whether a deadline is set and passed
+func expired(deadline time.Time) bool {
+	return !deadline.IsZero() && !time.Now().Before(deadline)
+}
+
+func (c *dohConn) Close() error         { return nil }
+func (c *dohConn) LocalAddr() net.Addr  { return nil }
+func (c *dohConn) RemoteAddr() net.Addr { return nil }
+
+func (c *dohConn) SetDeadline(t time.Time) error {
+	c.readDeadline, c.writeDeadline = t, t
+	return nil
+}
+
+func (c *dohConn) SetReadDeadline(t time.Time) error {
+	c.readDeadline = t
+	return nil
+}
+
+func (c *dohConn) SetWriteDeadline(t time.Time) error {
+	c.writeDeadline = t
+	return nil
+}
@@ -0,0 +1,52 @@
+package libgobuster
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrErrorThreshold is returned by Start when the scan was aborted
+// because the error rate exceeded -error-threshold
+var ErrErrorThreshold = errors.New("error threshold exceeded, scan aborted")
+
+// errorBudget keeps the outcome of the last requests in a ring buffer
+// and reports when the error rate over that window is too high
+type errorBudget struct {
+	mu        sync.Mutex
+	window    []bool
+	pos       int
+	filled    bool
+	errors    int
+	threshold float64
+}
+
+func newErrorBudget(size int, threshold float64) *errorBudget {
+	return &errorBudget{
+		window:    make([]bool, size),
+		threshold: threshold,
+	}
+}
+
+// record stores the outcome of a request and returns the current
+// error rate in percent and if it exceeds the threshold. The budget is
+// only evaluated once the window has been filled.
+func (b *errorBudget) record(isError bool) (float64, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.window[b.pos] {
+		b.errors--
+	}
+	b.window[b.pos] = isError
+	if isError {
+		b.errors++
+	}
+	b.pos++
+	if b.pos == len(b.window) {
+		b.pos = 0
+		b.filled = true
+	}
+
+	rate := float64(b.errors) * 100.0 / float64(len(b.window))
+	return rate, b.filled && rate > b.threshold
+}
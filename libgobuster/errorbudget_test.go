@@ -0,0 +1,32 @@
+package libgobuster
+
+import "testing"
+
+func TestErrorBudget(t *testing.T) {
+	t.Parallel()
+
+	b := newErrorBudget(4, 50)
+	var tt = []struct {
+		isError  bool
+		exceeded bool
+	}{
+		// window not filled yet
+		{true, false},
+		{true, false},
+		{true, false},
+		// 3 errors out of 4
+		{false, true},
+		// oldest errors leave the window
+		{false, false},
+		{false, false},
+		{true, false},
+		{true, false},
+		// 3 out of 4 again
+		{true, true},
+	}
+	for i, x := range tt {
+		if _, exceeded := b.record(x.isError); exceeded != x.exceeded {
+			t.Fatalf("request %d: expected exceeded=%v", i, x.exceeded)
+		}
+	}
+}
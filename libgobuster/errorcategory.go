@@ -0,0 +1,130 @@
+package libgobuster
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"syscall"
+)
+
+const (
+	// ErrorTimeout means the request timed out
+	ErrorTimeout = "timeout"
+	// ErrorRefused means the target refused the connection
+	ErrorRefused = "refused"
+	// ErrorReset means the connection was reset or closed mid request
+	ErrorReset = "reset"
+	// ErrorTLS means the TLS handshake or the certificate failed
+	ErrorTLS = "tls"
+	// ErrorDNS means the name of the target did not resolve
+	ErrorDNS = "dns"
+	// ErrorProxy means the proxy failed to connect to the target
+	ErrorProxy = "proxy"
+	// ErrorServer counts the 5xx responses, which are results but tell
+	// a struggling target
+	ErrorServer = "5xx"
+	// ErrorOther is any other error
+	ErrorOther = "other"
+)
+
+// ClassifyError returns the category of a request error, telling the
+// network side errors from the target side ones
+func ClassifyError(err error) string {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "proxyconnect" {
+		return ErrorProxy
+	}
+	var dnsErr *net.DNSError
+	var lookupErr *DNSLookupError
+	if errors.As(err, &dnsErr) || errors.As(err, &lookupErr) {
+		return ErrorDNS
+	}
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return ErrorTimeout
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return ErrorRefused
+	}
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE) {
+		return ErrorReset
+	}
+	var recordErr tls.RecordHeaderError
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+	if errors.As(err, &recordErr) || errors.As(err, &unknownAuthority) || errors.As(err, &hostnameErr) || errors.As(err, &invalidErr) {
+		return ErrorTLS
+	}
+
+	// some errors only keep their message, like the certificate errors
+	// of makeRequest and the ones of the socks dialer
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "socks connect"), strings.Contains(msg, "proxyconnect"):
+		return ErrorProxy
+	case strings.Contains(msg, "invalid certificate"), strings.Contains(msg, "x509"), strings.Contains(msg, "tls:"):
+		return ErrorTLS
+	case strings.Contains(msg, "connection refused"):
+		return ErrorRefused
+	case strings.Contains(msg, "connection reset"), strings.HasSuffix(msg, ": eof"):
+		return ErrorReset
+	case strings.Contains(msg, "timeout"):
+		return ErrorTimeout
+	}
+	return ErrorOther
+}
+
+// countError records an error of the category, the mutex must be held
+func (g *Gobuster) countError(category string) {
+	if g.errorCategories == nil {
+		g.errorCategories = make(map[string]int)
+	}
+	g.errorCategories[category]++
+}
+
+// countServerErrors records the 5xx responses of the results as errors
+func (g *Gobuster) countServerErrors(res []Result) {
+	count := 0
+	for _, r := range res {
+		if r.Status >= 500 && r.Status <= 599 {
+			count++
+		}
+	}
+	if count == 0 {
+		return
+	}
+	g.mu.Lock()
+	g.errorCount += count
+	g.serverErrors += count
+	if g.errorCategories == nil {
+		g.errorCategories = make(map[string]int)
+	}
+	g.errorCategories[ErrorServer] += count
+	g.mu.Unlock()
+}
+
+// formatErrorCategories returns the counts of the categories, the most
+// frequent first
+func formatErrorCategories(categories map[string]int) string {
+	names := make([]string, 0, len(categories))
+	for name := range categories {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if categories[names[i]] != categories[names[j]] {
+			return categories[names[i]] > categories[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s %d", name, categories[name])
+	}
+	return strings.Join(parts, ", ")
+}
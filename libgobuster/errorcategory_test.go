@@ -0,0 +1,80 @@
+package libgobuster
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"reflect"
+	"sync"
+	"syscall"
+	"testing"
+)
+
+func TestClassifyError(t *testing.T) {
+	t.Parallel()
+
+	dial := func(err error) error {
+		return &url.Error{Op: "Get", URL: "http://example.com/", Err: &net.OpError{Op: "dial", Net: "tcp", Err: err}}
+	}
+
+	tt := []struct {
+		testName string
+		err      error
+		expected string
+	}{
+		{"Timeout", &url.Error{Op: "Get", URL: "http://example.com/", Err: context.DeadlineExceeded}, ErrorTimeout},
+		{"Refused", dial(os.NewSyscallError("connect", syscall.ECONNREFUSED)), ErrorRefused},
+		{"Reset", dial(os.NewSyscallError("read", syscall.ECONNRESET)), ErrorReset},
+		{"DNS", dial(&net.DNSError{Err: "no such host", Name: "example.com", IsNotFound: true}), ErrorDNS},
+		{"Proxy", &url.Error{Op: "Get", URL: "http://example.com/", Err: &net.OpError{Op: "proxyconnect", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}}, ErrorProxy},
+		{"Socks", errors.New("socks connect tcp 127.0.0.1:9050->example.com:80: unknown error general SOCKS server failure"), ErrorProxy},
+		{"Certificate", &url.Error{Op: "Get", URL: "https://example.com/", Err: x509.UnknownAuthorityError{}}, ErrorTLS},
+		{"Certificate message", fmt.Errorf("Invalid certificate: %v", x509.UnknownAuthorityError{}), ErrorTLS},
+		{"Other", errors.New("boom"), ErrorOther},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			if got := ClassifyError(x.err); got != x.expected {
+				t.Fatalf("expected %s, got %s", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestFormatErrorCategories(t *testing.T) {
+	t.Parallel()
+
+	got := formatErrorCategories(map[string]int{ErrorTimeout: 2, ErrorDNS: 2, ErrorServer: 5})
+	if expected := "5xx 5, dns 2, timeout 2"; got != expected {
+		t.Fatalf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestCountServerErrors(t *testing.T) {
+	t.Parallel()
+
+	g := &Gobuster{Opts: NewOptions(), mu: new(sync.RWMutex), requestsIssued: 4}
+	g.countServerErrors([]Result{{Status: 500}, {Status: 200}, {Status: 503}})
+	g.RecordError(os.NewSyscallError("connect", syscall.ECONNREFUSED))
+	g.IncrementErrorCount()
+	// an error ending a request is no longer counted as issued
+	g.DecrementRequests()
+	g.DecrementRequests()
+
+	s := g.Summary()
+	if s.Errors != 4 || s.Requests != 4 {
+		t.Fatalf("expected 4 errors of 4 requests, got %d of %d", s.Errors, s.Requests)
+	}
+	expected := map[string]int{ErrorServer: 2, ErrorRefused: 1, ErrorOther: 1}
+	if !reflect.DeepEqual(s.ErrorCategories, expected) {
+		t.Fatalf("expected %v, got %v", expected, s.ErrorCategories)
+	}
+}
@@ -0,0 +1,123 @@
+package libgobuster
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode"
+)
+
+const (
+	// EvasionDoubleURL double url encodes the first character of the
+	// last segment: admin becomes %2561dmin
+	EvasionDoubleURL = "double-url"
+	// EvasionUnicode sends the first character of the last segment as
+	// an overlong UTF-8 sequence: admin becomes %C1%A1dmin
+	EvasionUnicode = "unicode"
+	// EvasionDotSlash puts a ./ before the last segment: ./admin
+	EvasionDotSlash = "dot-slash"
+	// EvasionCase alternates the case of the last segment: AdMiN
+	EvasionCase = "case"
+)
+
+// evasionMutators are the encodings of -evasion, each rewrites the last
+// segment of a path so that ACLs and WAFs matching it literally miss it
+var evasionMutators = map[string]func(string) string{
+	EvasionDoubleURL: func(segment string) string {
+		return fmt.Sprintf("%%25%02X%s", segment[0], segment[1:])
+	},
+	EvasionUnicode: func(segment string) string {
+		if segment[0] >= 0x80 {
+			return segment
+		}
+		return fmt.Sprintf("%%%02X%%%02X%s", 0xC0|segment[0]>>6, 0x80|segment[0]&0x3F, segment[1:])
+	},
+	EvasionDotSlash: func(segment string) string {
+		return "./" + segment
+	},
+	EvasionCase: func(segment string) string {
+		runes := []rune(segment)
+		upper := true
+		for i, r := range runes {
+			if !unicode.IsLetter(r) {
+				continue
+			}
+			if upper {
+				runes[i] = unicode.ToUpper(r)
+			} else {
+				runes[i] = unicode.ToLower(r)
+			}
+			upper = !upper
+		}
+		return string(runes)
+	},
+}
+
+// EvasionNames are the encodings of -evasion, in the order of the help
+var EvasionNames = []string{EvasionDoubleURL, EvasionUnicode, EvasionDotSlash, EvasionCase}
+
+// parseEvasion parses the comma separated variants of -evasion, each a
+// + separated chain of encodings applied in order
+func (opt *Options) parseEvasion() error {
+	opt.EvasionParsed = nil
+	for _, variant := range strings.Split(opt.Evasion, ",") {
+		variant = strings.TrimSpace(variant)
+		if variant == "" {
+			continue
+		}
+		var chain []string
+		for _, name := range strings.Split(variant, "+") {
+			name = strings.ToLower(strings.TrimSpace(name))
+			if _, ok := evasionMutators[name]; !ok {
+				return fmt.Errorf("invalid evasion given: %s, use %s", name, strings.Join(EvasionNames, ", "))
+			}
+			chain = append(chain, name)
+		}
+		opt.EvasionParsed = append(opt.EvasionParsed, chain)
+	}
+	return nil
+}
+
+// EvadedPath is a path encoded by a variant of -evasion
+type EvadedPath struct {
+	Evasion string
+	Path    string
+}
+
+// evadePath applies a chain of encodings to the last segment of a path,
+// the trailing slash of a directory is kept
+func evadePath(p string, chain []string) string {
+	trimmed := strings.TrimSuffix(p, "/")
+	dir, segment := "", trimmed
+	if i := strings.LastIndex(trimmed, "/"); i >= 0 {
+		dir, segment = trimmed[:i+1], trimmed[i+1:]
+	}
+	if segment == "" {
+		return p
+	}
+	for _, name := range chain {
+		segment = evasionMutators[name](segment)
+	}
+	return dir + segment + p[len(trimmed):]
+}
+
+// EvadedPaths returns the -evasion variants of a protected path, the
+// variants leaving it unchanged are left out
+func (g *Gobuster) EvadedPaths(p string) []EvadedPath {
+	var paths []EvadedPath
+	seen := NewSet[string]()
+	seen.Add(p)
+	for _, chain := range g.Opts.EvasionParsed {
+		evaded := evadePath(p, chain)
+		if seen.Add(evaded) {
+			paths = append(paths, EvadedPath{Evasion: strings.Join(chain, "+"), Path: evaded})
+		}
+	}
+	return paths
+}
+
+// IsProtected reports whether the status of a path denies access to
+// it, the paths -evasion tries to bypass
+func IsProtected(status int) bool {
+	return status == http.StatusUnauthorized || status == http.StatusForbidden
+}
@@ -0,0 +1,82 @@
+package libgobuster
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEvadePath(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName string
+		path     string
+		chain    []string
+		expected string
+	}{
+		{"Double url", "admin", []string{EvasionDoubleURL}, "%2561dmin"},
+		{"Unicode", "admin", []string{EvasionUnicode}, "%C1%A1dmin"},
+		{"Unicode dot", ".git", []string{EvasionUnicode}, "%C0%AEgit"},
+		{"Dot slash", "api/admin", []string{EvasionDotSlash}, "api/./admin"},
+		{"Case", "admin-panel", []string{EvasionCase}, "AdMiN-pAnEl"},
+		{"Directory", "api/admin/", []string{EvasionCase}, "api/AdMiN/"},
+		{"Chain", "admin", []string{EvasionCase, EvasionDotSlash}, "./AdMiN"},
+		{"Root", "/", []string{EvasionCase}, "/"},
+	}
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			if got := evadePath(x.path, x.chain); got != x.expected {
+				t.Fatalf("expected %q, got %q", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestParseEvasion(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		evasion  string
+		expected [][]string
+		err      bool
+	}{
+		{"case", [][]string{{EvasionCase}}, false},
+		{"double-url, Dot-Slash+case", [][]string{{EvasionDoubleURL}, {EvasionDotSlash, EvasionCase}}, false},
+		{"case+rot13", nil, true},
+	}
+	for _, x := range tt {
+		x := x
+		t.Run(x.evasion, func(t *testing.T) {
+			t.Parallel()
+
+			o := NewOptions()
+			o.Evasion = x.evasion
+			err := o.parseEvasion()
+			if (err != nil) != x.err {
+				t.Fatalf("expected error %t, got %v", x.err, err)
+			}
+			if err == nil && !reflect.DeepEqual(o.EvasionParsed, x.expected) {
+				t.Fatalf("expected %q, got %q", x.expected, o.EvasionParsed)
+			}
+		})
+	}
+}
+
+func TestEvadedPaths(t *testing.T) {
+	t.Parallel()
+
+	o := NewOptions()
+	o.Evasion = "case,dot-slash"
+	if err := o.parseEvasion(); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	g := &Gobuster{Opts: o}
+	// the case of a path without letters is the path itself
+	expected := []EvadedPath{{Evasion: EvasionDotSlash, Path: "./123"}}
+	if got := g.EvadedPaths("123"); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, got)
+	}
+}
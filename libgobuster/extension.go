@@ -0,0 +1,101 @@
+package libgobuster
+
+import (
+	"fmt"
+	"plugin"
+	"strings"
+)
+
+// ExtensionSymbol is the name of the variable a -plugin exports
+const ExtensionSymbol = "Extension"
+
+// Extension holds the custom logic of a -plugin, a Go plugin built with
+// go build -buildmode=plugin against this version of libgobuster and
+// exporting an Extension variable. Both funcs are optional.
+type Extension struct {
+	// Name is shown in the config
+	Name string
+	// Process replaces the processing of the targets by the mode, for
+	// the protocols and checks the mode does not know
+	Process func(*Gobuster, *BusterTarget) ([]Result, error)
+	// Filter reports whether a result is kept, it may annotate it
+	Filter func(*Gobuster, *Result) bool
+}
+
+// LoadExtension opens the plugin at path and returns its Extension
+func LoadExtension(path string) (*Extension, error) {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load plugin %s: %v", path, err)
+	}
+	sym, err := p.Lookup(ExtensionSymbol)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load plugin %s: %v", path, err)
+	}
+	ext, ok := sym.(*Extension)
+	if !ok {
+		return nil, fmt.Errorf("unable to load plugin %s: %s is a %T, not a libgobuster.Extension", path, ExtensionSymbol, sym)
+	}
+	if ext.Name == "" {
+		ext.Name = path
+	}
+	return ext, nil
+}
+
+// loadExtensions loads the comma separated -plugin paths
+func loadExtensions(paths string) ([]*Extension, error) {
+	var exts []*Extension
+	for _, path := range strings.Split(paths, ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		ext, err := LoadExtension(path)
+		if err != nil {
+			return nil, err
+		}
+		exts = append(exts, ext)
+	}
+	return exts, nil
+}
+
+// process runs the Process of the first extension providing one, the
+// one of the mode otherwise
+func (g *Gobuster) process(busterTarget *BusterTarget) ([]Result, error) {
+	for _, ext := range g.extensions {
+		if ext.Process != nil {
+			return ext.Process(g, busterTarget)
+		}
+	}
+	return g.plugin.Process(g, busterTarget)
+}
+
+// filter drops the results an extension does not keep
+func (g *Gobuster) filter(res []Result) []Result {
+	if len(g.extensions) == 0 {
+		return res
+	}
+	kept := res[:0]
+	for _, r := range res {
+		keep := true
+		for _, ext := range g.extensions {
+			if ext.Filter != nil && !ext.Filter(g, &r) {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+// extensionNames returns the names of the loaded extensions
+func (g *Gobuster) extensionNames() string {
+	names := make([]string, len(g.extensions))
+	for i, ext := range g.extensions {
+		names[i] = ext.Name
+	}
+	return strings.Join(names, ", ")
+}
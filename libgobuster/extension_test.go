@@ -0,0 +1,63 @@
+package libgobuster
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExtensionFilter(t *testing.T) {
+	t.Parallel()
+
+	g := &Gobuster{extensions: []*Extension{
+		{Name: "annotate", Filter: func(g *Gobuster, r *Result) bool {
+			r.SetMeta("seen", "true")
+			return true
+		}},
+		{Name: "no-5xx", Filter: func(g *Gobuster, r *Result) bool {
+			return r.Status < 500
+		}},
+		{Name: "process only"},
+	}}
+	res := g.filter([]Result{{Entity: "a", Status: 200}, {Entity: "b", Status: 503}, {Entity: "c", Status: 404}})
+	seen := map[string]string{"seen": "true"}
+	expected := []Result{{Entity: "a", Status: 200, Metadata: seen}, {Entity: "c", Status: 404, Metadata: seen}}
+	if !reflect.DeepEqual(res, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, res)
+	}
+	if names := g.extensionNames(); names != "annotate, no-5xx, process only" {
+		t.Fatalf("unexpected names %q", names)
+	}
+}
+
+func TestExtensionProcess(t *testing.T) {
+	t.Parallel()
+
+	g := &Gobuster{extensions: []*Extension{
+		{Name: "filter only", Filter: func(g *Gobuster, r *Result) bool { return true }},
+		{Name: "gopher", Process: func(g *Gobuster, b *BusterTarget) ([]Result, error) {
+			return []Result{{Entity: "gopher://" + b.Target, Status: 200, IsEntityURL: true}}, nil
+		}},
+	}}
+	res, err := g.process(&BusterTarget{Target: "host/1"})
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if len(res) != 1 || res[0].Entity != "gopher://host/1" {
+		t.Fatalf("unexpected results %+v", res)
+	}
+}
+
+func TestLoadExtensions(t *testing.T) {
+	t.Parallel()
+
+	if exts, err := loadExtensions(" , "); err != nil || len(exts) != 0 {
+		t.Fatalf("expected no extensions, got %v, %v", exts, err)
+	}
+	missing := filepath.Join(t.TempDir(), "missing.so")
+	_, err := loadExtensions(missing)
+	if err == nil || !strings.HasPrefix(err.Error(), "unable to load plugin "+missing) {
+		t.Fatalf("expected a load error, got %v", err)
+	}
+}
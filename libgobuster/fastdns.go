@@ -0,0 +1,295 @@
+package libgobuster
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// fastDNSSockets is the number of UDP sockets the queries of -fast-dns
+// are spread over, each one holds up to 65536 queries in flight
+const fastDNSSockets = 16
+
+// fastDNSBufferSize fits any answer to a query without EDNS
+const fastDNSBufferSize = 1500
+
+// fastResolver sends hand crafted A and AAAA queries straight to the
+// resolvers over a pool of UDP sockets, answers are matched to the
+// waiting query by their id. Each query returns both the addresses and
+// the CNAME.
+type fastResolver struct {
+	servers []*net.UDPAddr
+	conns   []*fastConn
+	next    uint32
+}
+
+type fastConn struct {
+	conn    *net.UDPConn
+	rand    Rand
+	mu      sync.Mutex
+	pending map[uint16]fastQuery
+}
+
+// fastQuery is a query waiting for the answer of the server it was sent to
+type fastQuery struct {
+	server *net.UDPAddr
+	answer chan []byte
+}
+
+// newFastResolver opens the socket pool, the resolvers default to the
+// nameservers of /etc/resolv.conf. The query ids are drawn from r.
+func newFastResolver(resolvers []string, r Rand) (*fastResolver, error) {
+	if len(resolvers) == 0 {
+		var err error
+		resolvers, err = systemNameservers("/etc/resolv.conf")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	f := &fastResolver{}
+	for _, s := range resolvers {
+		addr, err := net.ResolveUDPAddr("udp", s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid resolver %s: %v", s, err)
+		}
+		f.servers = append(f.servers, addr)
+	}
+	for i := 0; i < fastDNSSockets; i++ {
+		conn, err := net.ListenUDP("udp", nil)
+		if err != nil {
+			f.close()
+			return nil, fmt.Errorf("unable to open dns socket: %v", err)
+		}
+		fc := &fastConn{conn: conn, rand: r, pending: make(map[uint16]fastQuery)}
+		f.conns = append(f.conns, fc)
+		go fc.read()
+	}
+	return f, nil
+}
+
+// systemNameservers returns the nameservers of a resolv.conf file
+func systemNameservers(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the system resolvers, use -resolvers: %v", err)
+	}
+	defer f.Close()
+
+	var servers []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "nameserver" {
+			servers = append(servers, net.JoinHostPort(fields[1], "53"))
+		}
+	}
+	if len(servers) == 0 {
+		return nil, fmt.Errorf("no nameserver found in %s, use -resolvers", path)
+	}
+	return servers, scanner.Err()
+}
+
+// read hands every answer to the query waiting for its id, the answers
+// coming from another address than the queried server are dropped
+func (c *fastConn) read() {
+	buf := make([]byte, fastDNSBufferSize)
+	for {
+		n, from, err := c.conn.ReadFromUDP(buf)
+		if err != nil {
+			return
+		}
+		if n < 2 {
+			continue
+		}
+		id := uint16(buf[0])<<8 | uint16(buf[1])
+		c.mu.Lock()
+		q, ok := c.pending[id]
+		if ok && q.server.IP.Equal(from.IP) && q.server.Port == from.Port {
+			delete(c.pending, id)
+		} else {
+			ok = false
+		}
+		c.mu.Unlock()
+		if ok {
+			answer := make([]byte, n)
+			copy(answer, buf[:n])
+			q.answer <- answer
+		}
+	}
+}
+
+// register reserves an unused random query id for a query to the server
+func (c *fastConn) register(server *net.UDPAddr) (uint16, chan []byte) {
+	ch := make(chan []byte, 1)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for {
+		id := uint16(c.rand.Intn(1 << 16))
+		if _, taken := c.pending[id]; !taken {
+			c.pending[id] = fastQuery{server: server, answer: ch}
+			return id, ch
+		}
+	}
+}
+
+func (c *fastConn) unregister(id uint16) {
+	c.mu.Lock()
+	delete(c.pending, id)
+	c.mu.Unlock()
+}
+
+func (r *fastResolver) close() {
+	for _, c := range r.conns {
+		c.conn.Close()
+	}
+}
+
+// exchange resolves the A and AAAA records and the CNAME of a domain,
+// both queries are sent at once. The errors are net.DNSErrors so they
+// are classified and retried like the ones of the system resolver.
+func (r *fastResolver) exchange(ctx context.Context, domain string) ([]string, string, error) {
+	name, err := dnsmessage.NewName(strings.TrimSuffix(domain, ".") + ".")
+	if err != nil {
+		return nil, "", &net.DNSError{Err: err.Error(), Name: domain}
+	}
+
+	types := []dnsmessage.Type{dnsmessage.TypeA, dnsmessage.TypeAAAA}
+	ips := make([][]string, len(types))
+	cnames := make([]string, len(types))
+	errs := make([]error, len(types))
+	var wg sync.WaitGroup
+	for i, qtype := range types {
+		wg.Add(1)
+		go func(i int, qtype dnsmessage.Type) {
+			defer wg.Done()
+			ips[i], cnames[i], errs[i] = r.query(ctx, name, qtype, domain)
+		}(i, qtype)
+	}
+	wg.Wait()
+
+	var addrs []string
+	var cname string
+	for i := range types {
+		addrs = append(addrs, ips[i]...)
+		if cname == "" {
+			cname = cnames[i]
+		}
+	}
+	if len(addrs) > 0 {
+		return addrs, cname, nil
+	}
+	// a failed query hides whether the domain exists, so it takes
+	// precedence over the not found answer of the other one
+	for _, err := range errs {
+		if dnsErr, ok := err.(*net.DNSError); ok && !dnsErr.IsNotFound {
+			return nil, cname, err
+		}
+	}
+	return nil, cname, errs[0]
+}
+
+// query sends a single question of the given type and waits for its answer
+func (r *fastResolver) query(ctx context.Context, name dnsmessage.Name, qtype dnsmessage.Type, domain string) ([]string, string, error) {
+	n := atomic.AddUint32(&r.next, 1)
+	conn := r.conns[int(n)%len(r.conns)]
+	server := r.servers[int(n)%len(r.servers)]
+
+	id, ch := conn.register(server)
+	defer conn.unregister(id)
+
+	query, err := buildQuery(id, name, qtype)
+	if err != nil {
+		return nil, "", &net.DNSError{Err: err.Error(), Name: domain}
+	}
+	if _, err := conn.conn.WriteToUDP(query, server); err != nil {
+		return nil, "", &net.DNSError{Err: err.Error(), Name: domain, Server: server.String(), IsTemporary: true}
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, "", &net.DNSError{Err: "i/o timeout", Name: domain, Server: server.String(), IsTimeout: true}
+	case answer := <-ch:
+		return parseAnswer(answer, name, domain, server.String())
+	}
+}
+
+func buildQuery(id uint16, name dnsmessage.Name, qtype dnsmessage.Type) ([]byte, error) {
+	b := dnsmessage.NewBuilder(make([]byte, 0, 512), dnsmessage.Header{ID: id, RecursionDesired: true})
+	if err := b.StartQuestions(); err != nil {
+		return nil, err
+	}
+	if err := b.Question(dnsmessage.Question{Name: name, Type: qtype, Class: dnsmessage.ClassINET}); err != nil {
+		return nil, err
+	}
+	return b.Finish()
+}
+
+// parseAnswer returns the addresses and the last CNAME of the chain
+func parseAnswer(answer []byte, name dnsmessage.Name, domain, server string) ([]string, string, error) {
+	var p dnsmessage.Parser
+	h, err := p.Start(answer)
+	if err != nil {
+		return nil, "", &net.DNSError{Err: err.Error(), Name: domain, Server: server, IsTemporary: true}
+	}
+	q, err := p.Question()
+	if err != nil || !strings.EqualFold(q.Name.String(), name.String()) {
+		// not the answer to this query
+		return nil, "", &net.DNSError{Err: "mismatched answer", Name: domain, Server: server, IsTemporary: true}
+	}
+	// a name error still carries the CNAME of a dangling record
+	if h.RCode != dnsmessage.RCodeSuccess && h.RCode != dnsmessage.RCodeNameError {
+		return nil, "", &net.DNSError{Err: h.RCode.String(), Name: domain, Server: server, IsTemporary: true}
+	}
+	if err := p.SkipAllQuestions(); err != nil {
+		return nil, "", &net.DNSError{Err: err.Error(), Name: domain, Server: server, IsTemporary: true}
+	}
+
+	var ips []string
+	var cname string
+	for {
+		rh, err := p.AnswerHeader()
+		if err == dnsmessage.ErrSectionDone {
+			break
+		}
+		if err != nil {
+			return nil, "", &net.DNSError{Err: err.Error(), Name: domain, Server: server, IsTemporary: true}
+		}
+		switch rh.Type {
+		case dnsmessage.TypeA:
+			a, err := p.AResource()
+			if err != nil {
+				return nil, "", &net.DNSError{Err: err.Error(), Name: domain, Server: server, IsTemporary: true}
+			}
+			ips = append(ips, net.IP(a.A[:]).String())
+		case dnsmessage.TypeAAAA:
+			aaaa, err := p.AAAAResource()
+			if err != nil {
+				return nil, "", &net.DNSError{Err: err.Error(), Name: domain, Server: server, IsTemporary: true}
+			}
+			ips = append(ips, net.IP(aaaa.AAAA[:]).String())
+		case dnsmessage.TypeCNAME:
+			c, err := p.CNAMEResource()
+			if err != nil {
+				return nil, "", &net.DNSError{Err: err.Error(), Name: domain, Server: server, IsTemporary: true}
+			}
+			cname = c.CNAME.String()
+		default:
+			if err := p.SkipAnswer(); err != nil {
+				return nil, "", &net.DNSError{Err: err.Error(), Name: domain, Server: server, IsTemporary: true}
+			}
+		}
+	}
+	if len(ips) == 0 {
+		// NXDOMAIN and NODATA, reported like the system resolver does
+		return nil, cname, &net.DNSError{Err: "no such host", Name: domain, Server: server, IsNotFound: true}
+	}
+	return ips, cname, nil
+}
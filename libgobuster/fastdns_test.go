@@ -0,0 +1,190 @@
+package libgobuster
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// serveTestDNS answers the A and AAAA queries of the names in records,
+// a CNAME value is given as "cname:target"
+func serveTestDNS(t *testing.T, records map[string][]string) string {
+	t.Helper()
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	t.Cleanup(func() { pc.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := pc.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			var p dnsmessage.Parser
+			h, err := p.Start(buf[:n])
+			if err != nil {
+				continue
+			}
+			q, err := p.Question()
+			if err != nil {
+				continue
+			}
+			values, ok := records[q.Name.String()]
+			h.Response = true
+			if !ok {
+				h.RCode = dnsmessage.RCodeNameError
+			}
+			b := dnsmessage.NewBuilder(nil, h)
+			b.StartQuestions()
+			b.Question(q)
+			b.StartAnswers()
+			rh := dnsmessage.ResourceHeader{Name: q.Name, Class: dnsmessage.ClassINET, TTL: 60}
+			for _, v := range values {
+				if len(v) > 6 && v[:6] == "cname:" {
+					b.CNAMEResource(rh, dnsmessage.CNAMEResource{CNAME: dnsmessage.MustNewName(v[6:])})
+					continue
+				}
+				ip := net.ParseIP(v)
+				if ip4 := ip.To4(); ip4 != nil && q.Type == dnsmessage.TypeA {
+					var a [4]byte
+					copy(a[:], ip4)
+					b.AResource(rh, dnsmessage.AResource{A: a})
+				} else if ip4 == nil && q.Type == dnsmessage.TypeAAAA {
+					var aaaa [16]byte
+					copy(aaaa[:], ip)
+					b.AAAAResource(rh, dnsmessage.AAAAResource{AAAA: aaaa})
+				}
+			}
+			msg, _ := b.Finish()
+			pc.WriteTo(msg, addr)
+		}
+	}()
+	return pc.LocalAddr().String()
+}
+
+func TestFastResolver(t *testing.T) {
+	t.Parallel()
+
+	server := serveTestDNS(t, map[string][]string{
+		"www.example.com.":  {"10.0.0.1", "10.0.0.2"},
+		"blog.example.com.": {"cname:example.github.io.", "10.0.0.3"},
+		"gone.example.com.": {"cname:missing.herokuapp.com."},
+		"ipv6.example.com.": {"2001:db8::1"},
+		"dual.example.com.": {"10.0.0.4", "2001:db8::2"},
+	})
+	fast, err := newFastResolver([]string{server}, NewRand(1))
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	defer fast.close()
+	r := &dnsResolver{fast: fast, timeout: time.Second, retries: 1}
+	ctx := context.Background()
+
+	ips, err := r.lookupHost(ctx, "www.example.com")
+	if err != nil || len(ips) != 2 || ips[0] != "10.0.0.1" {
+		t.Fatalf("unexpected answer %v (%v)", ips, err)
+	}
+
+	// IPv6 only hosts resolve through their AAAA records
+	ips, err = r.lookupHost(ctx, "ipv6.example.com")
+	if err != nil || len(ips) != 1 || ips[0] != "2001:db8::1" {
+		t.Fatalf("unexpected answer %v (%v)", ips, err)
+	}
+	ips, err = r.lookupHost(ctx, "dual.example.com")
+	if err != nil || len(ips) != 2 || ips[0] != "10.0.0.4" || ips[1] != "2001:db8::2" {
+		t.Fatalf("unexpected answer %v (%v)", ips, err)
+	}
+
+	cname, err := r.lookupCNAME(ctx, "blog.example.com")
+	if err != nil || cname != "example.github.io." {
+		t.Fatalf("unexpected cname %q (%v)", cname, err)
+	}
+	cname, err = r.lookupCNAME(ctx, "www.example.com")
+	if err != nil || cname != "www.example.com." {
+		t.Fatalf("unexpected cname %q (%v)", cname, err)
+	}
+
+	_, err = r.lookupHost(ctx, "nope.example.com")
+	if kind := ClassifyDNSError(err); kind != DNSErrorNXDomain {
+		t.Fatalf("expected nxdomain, got %s (%v)", kind, err)
+	}
+	// dangling records still report their CNAME
+	cname, err = r.lookupCNAME(ctx, "gone.example.com")
+	if err != nil || cname != "missing.herokuapp.com." {
+		t.Fatalf("unexpected cname %q (%v)", cname, err)
+	}
+}
+
+func TestParseResolvers(t *testing.T) {
+	t.Parallel()
+
+	o := NewOptions()
+	o.Resolvers = "1.1.1.1, 8.8.8.8:5353,[2606:4700::1111]"
+	if err := o.parseResolvers(); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	expected := []string{"1.1.1.1:53", "8.8.8.8:5353", "[2606:4700::1111]:53"}
+	for i, r := range expected {
+		if o.ResolversParsed[i] != r {
+			t.Fatalf("expected %v, got %v", expected, o.ResolversParsed)
+		}
+	}
+
+	o.Resolvers = "dns.google"
+	if err := o.parseResolvers(); err == nil {
+		t.Fatal("expected an error for a hostname")
+	}
+}
+
+func TestFastConnAnswerSource(t *testing.T) {
+	t.Parallel()
+
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	defer conn.Close()
+	c := &fastConn{conn: conn, rand: NewRand(1), pending: make(map[uint16]fastQuery)}
+	go c.read()
+
+	var senders []*net.UDPConn
+	for i := 0; i < 2; i++ {
+		sender, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+		if err != nil {
+			t.Fatalf("got error: %v", err)
+		}
+		defer sender.Close()
+		senders = append(senders, sender)
+	}
+	server, spoofer := senders[0], senders[1]
+	id, ch := c.register(server.LocalAddr().(*net.UDPAddr))
+	answer := []byte{byte(id >> 8), byte(id), 'a'}
+
+	// an answer with the right id from another address is dropped
+	if _, err := spoofer.WriteToUDP(answer, conn.LocalAddr().(*net.UDPAddr)); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	select {
+	case <-ch:
+		t.Fatal("expected the spoofed answer dropped")
+	case <-time.After(50 * time.Millisecond):
+	}
+	if _, err := server.WriteToUDP(answer, conn.LocalAddr().(*net.UDPAddr)); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	select {
+	case got := <-ch:
+		if string(got) != string(answer) {
+			t.Fatalf("expected %q, got %q", answer, got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the answer of the server")
+	}
+}
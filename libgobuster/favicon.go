@@ -0,0 +1,90 @@
+package libgobuster
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"math/bits"
+	"net/http"
+	"strings"
+)
+
+// mmh3 returns the 32 bit x86 MurmurHash3 of the data with seed 0 as
+// the signed value computed by the python mmh3 module
+func mmh3(data []byte) int32 {
+	const (
+		c1 = 0xcc9e2d51
+		c2 = 0x1b873593
+	)
+	var h uint32
+	n := len(data)
+	for len(data) >= 4 {
+		k := binary.LittleEndian.Uint32(data)
+		k *= c1
+		k = bits.RotateLeft32(k, 15)
+		k *= c2
+		h ^= k
+		h = bits.RotateLeft32(h, 13)
+		h = h*5 + 0xe6546b64
+		data = data[4:]
+	}
+
+	var k uint32
+	switch len(data) {
+	case 3:
+		k ^= uint32(data[2]) << 16
+		fallthrough
+	case 2:
+		k ^= uint32(data[1]) << 8
+		fallthrough
+	case 1:
+		k ^= uint32(data[0])
+		k *= c1
+		k = bits.RotateLeft32(k, 15)
+		k *= c2
+		h ^= k
+	}
+
+	h ^= uint32(n)
+	h ^= h >> 16
+	h *= 0x85ebca6b
+	h ^= h >> 13
+	h *= 0xc2b2ae35
+	h ^= h >> 16
+	return int32(h)
+}
+
+// FaviconHash returns the favicon hash searched by Shodan's
+// http.favicon.hash filter: the mmh3 of the base64 encoded icon, wrapped
+// at 76 characters with a trailing newline
+func FaviconHash(icon []byte) int32 {
+	encoded := base64.StdEncoding.EncodeToString(icon)
+	var b strings.Builder
+	for len(encoded) > 76 {
+		b.WriteString(encoded[:76])
+		b.WriteByte('\n')
+		encoded = encoded[76:]
+	}
+	b.WriteString(encoded)
+	b.WriteByte('\n')
+	return mmh3([]byte(b.String()))
+}
+
+// FetchFavicon records the favicon hash of a dir mode target, which cross
+// references the application stack on Shodan. The setup of dir mode
+// fetches it.
+func (g *Gobuster) FetchFavicon() {
+	if g.Opts.Mode != ModeDir || g.Opts.HostFuzz != "" {
+		return
+	}
+	url := g.URLFor("favicon.ico")
+	if !g.RobotsAllowed(url) {
+		return
+	}
+	resp, err := g.GetRequest(url, g.NewRequestOptions(url))
+	// catch-all pages answering every path are no icon
+	if err != nil || resp.StatusCode != http.StatusOK || resp.Content == "" || resp.Truncated || strings.HasPrefix(resp.ContentType, "text/") {
+		return
+	}
+	hash := FaviconHash([]byte(resp.Content))
+	g.FaviconHash = &hash
+}
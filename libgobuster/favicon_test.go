@@ -0,0 +1,77 @@
+package libgobuster
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestMMH3(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		data     string
+		expected int32
+	}{
+		{"", 0},
+		{"hello", 613153351},
+		{"The quick brown fox jumps over the lazy dog", 776992547},
+	}
+	for _, x := range tt {
+		if got := mmh3([]byte(x.data)); got != x.expected {
+			t.Fatalf("%q: expected %d, got %d", x.data, x.expected, got)
+		}
+	}
+}
+
+func TestFaviconHash(t *testing.T) {
+	t.Parallel()
+
+	// 512 bytes encode to several wrapped base64 lines
+	icon := make([]byte, 512)
+	for i := range icon {
+		icon[i] = byte(i)
+	}
+	if got := FaviconHash(icon); got != -1173581353 {
+		t.Fatalf("expected -1173581353, got %d", got)
+	}
+}
+
+func TestFetchFavicon(t *testing.T) {
+	t.Parallel()
+
+	icon := []byte("\x00\x00\x01\x00icon")
+	h := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/favicon.ico" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "image/x-icon")
+		w.Write(icon)
+	}))
+	defer h.Close()
+
+	o := NewOptions()
+	o.Mode = ModeDir
+	o.URL = h.URL + "/"
+	c, err := newHTTPClient(context.Background(), o)
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	g := &Gobuster{Opts: o, HTTP: c, mu: new(sync.RWMutex)}
+	g.FetchFavicon()
+	if g.FaviconHash == nil || *g.FaviconHash != FaviconHash(icon) {
+		t.Fatalf("expected the hash %d, got %v", FaviconHash(icon), g.FaviconHash)
+	}
+	config, err := g.GetConfigString()
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if line := fmt.Sprintf("[+] Favicon hash          : %d ", FaviconHash(icon)); !strings.Contains(config, line) {
+		t.Fatalf("favicon hash not in the config:\n%s", config)
+	}
+}
@@ -0,0 +1,60 @@
+package libgobuster
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"sort"
+)
+
+// maxSummaryGroups caps the number of clusters printed in the summary
+const maxSummaryGroups = 10
+
+// resultGroups clusters matches sharing the same page title or body
+type resultGroups struct {
+	titles map[string]int
+	hashes map[string]int
+}
+
+// ResultGroup is a single cluster of matches
+type ResultGroup struct {
+	Key   string `json:"key"`
+	Count int    `json:"count"`
+}
+
+func newResultGroups() resultGroups {
+	return resultGroups{
+		titles: map[string]int{},
+		hashes: map[string]int{},
+	}
+}
+
+func (rg *resultGroups) add(r *Result) {
+	if r.Content == nil || *r.Content == "" {
+		return
+	}
+	if title := ExtractTitle(*r.Content); title != "" {
+		rg.titles[title]++
+	}
+	sum := md5.Sum([]byte(*r.Content))
+	rg.hashes[hex.EncodeToString(sum[:])]++
+}
+
+// topGroups returns the clusters with more than one member, largest first
+func topGroups(m map[string]int) []ResultGroup {
+	var groups []ResultGroup
+	for k, v := range m {
+		if v > 1 {
+			groups = append(groups, ResultGroup{Key: k, Count: v})
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Count == groups[j].Count {
+			return groups[i].Key < groups[j].Key
+		}
+		return groups[i].Count > groups[j].Count
+	})
+	if len(groups) > maxSummaryGroups {
+		groups = groups[:maxSummaryGroups]
+	}
+	return groups
+}
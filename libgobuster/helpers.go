@@ -1,108 +1,202 @@
-package libgobuster
-
-import (
-	"bytes"
-	"fmt"
-	"io"
-	"sort"
-	"strings"
-)
-
-type intSet struct {
-	Set map[int]bool
-}
-
-type stringSet struct {
-	Set map[string]bool
-}
-
-func newStringSet() stringSet {
-	return stringSet{Set: map[string]bool{}}
-}
-
-// Add an element to a set
-func (set *stringSet) Add(s string) bool {
-	_, found := set.Set[s]
-	set.Set[s] = true
-	return !found
-}
-
-// Add a list of elements to a set
-func (set *stringSet) AddRange(ss []string) {
-	for _, s := range ss {
-		set.Set[s] = true
-	}
-}
-
-// Test if an element is in a set
-func (set *stringSet) Contains(s string) bool {
-	_, found := set.Set[s]
-	return found
-}
-
-// Check if any of the elements exist
-func (set *stringSet) ContainsAny(ss []string) bool {
-	for _, s := range ss {
-		if set.Set[s] {
-			return true
-		}
-	}
-	return false
-}
-
-// Stringify the set
-func (set *stringSet) Stringify() string {
-	values := []string{}
-	for s := range set.Set {
-		values = append(values, s)
-	}
-	return strings.Join(values, ",")
-}
-
-func newIntSet() intSet {
-	return intSet{Set: map[int]bool{}}
-}
-
-// Add an element to a set
-func (set *intSet) Add(i int) bool {
-	_, found := set.Set[i]
-	set.Set[i] = true
-	return !found
-}
-
-// Test if an element is in a set
-func (set *intSet) Contains(i int) bool {
-	_, found := set.Set[i]
-	return found
-}
-
-// Stringify the set
-func (set *intSet) Stringify() string {
-	values := []int{}
-	for s := range set.Set {
-		values = append(values, s)
-	}
-	sort.Ints(values)
-
-	delim := ","
-	return strings.Trim(strings.Join(strings.Fields(fmt.Sprint(values)), delim), "[]")
-}
-
-func lineCounter(r io.Reader) (int, error) {
-	buf := make([]byte, 32*1024)
-	count := 1
-	lineSep := []byte{'\n'}
-
-	for {
-		c, err := r.Read(buf)
-		count += bytes.Count(buf[:c], lineSep)
-
-		switch {
-		case err == io.EOF:
-			return count, nil
-
-		case err != nil:
-			return count, err
-		}
-	}
-}
+package libgobuster
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"io"
+	"mime"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var titleRegex = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+
+// listingRegexes match the directory listings of common web servers
+var listingRegexes = []*regexp.Regexp{
+	// apache, nginx, lighttpd and python http.server titles
+	regexp.MustCompile(`(?i)<title>\s*(index of|directory listing for) /`),
+	regexp.MustCompile(`(?i)<h1>\s*index of /`),
+	// apache autoindex
+	regexp.MustCompile(`(?i)>\s*parent directory\s*</a>`),
+	// iis
+	regexp.MustCompile(`(?i)\[to parent directory\]`),
+}
+
+// Set is a set of strings or numbers, the extensions, status codes,
+// wildcard IPs and the values already seen of a scan
+type Set[T cmp.Ordered] struct {
+	Set map[T]bool
+}
+
+// NewSet returns a set holding the given values
+func NewSet[T cmp.Ordered](values ...T) Set[T] {
+	set := Set[T]{Set: make(map[T]bool, len(values))}
+	set.AddRange(values)
+	return set
+}
+
+// Add an element to a set, it returns false if it was there already
+func (set *Set[T]) Add(v T) bool {
+	_, found := set.Set[v]
+	set.Set[v] = true
+	return !found
+}
+
+// Add a list of elements to a set
+func (set *Set[T]) AddRange(vs []T) {
+	for _, v := range vs {
+		set.Set[v] = true
+	}
+}
+
+// Test if an element is in a set
+func (set *Set[T]) Contains(v T) bool {
+	_, found := set.Set[v]
+	return found
+}
+
+// Check if any of the elements exist
+func (set *Set[T]) ContainsAny(vs []T) bool {
+	for _, v := range vs {
+		if set.Set[v] {
+			return true
+		}
+	}
+	return false
+}
+
+// Len returns the number of elements of a set
+func (set *Set[T]) Len() int {
+	return len(set.Set)
+}
+
+// Values returns the elements of a set in order
+func (set *Set[T]) Values() []T {
+	values := make([]T, 0, len(set.Set))
+	for v := range set.Set {
+		values = append(values, v)
+	}
+	slices.Sort(values)
+	return values
+}
+
+// Union returns the elements of either set
+func (set *Set[T]) Union(other Set[T]) Set[T] {
+	union := NewSet[T]()
+	for v := range set.Set {
+		union.Add(v)
+	}
+	for v := range other.Set {
+		union.Add(v)
+	}
+	return union
+}
+
+// Intersection returns the elements of both sets
+func (set *Set[T]) Intersection(other Set[T]) Set[T] {
+	intersection := NewSet[T]()
+	for v := range set.Set {
+		if other.Contains(v) {
+			intersection.Add(v)
+		}
+	}
+	return intersection
+}
+
+// Stringify the set, in order
+func (set *Set[T]) Stringify() string {
+	values := make([]string, 0, len(set.Set))
+	for _, v := range set.Values() {
+		values = append(values, fmt.Sprint(v))
+	}
+	return strings.Join(values, ",")
+}
+
+func lineCounter(r io.Reader) (int, error) {
+	buf := make([]byte, 32*1024)
+	count := 1
+	lineSep := []byte{'\n'}
+
+	for {
+		c, err := r.Read(buf)
+		count += bytes.Count(buf[:c], lineSep)
+
+		switch {
+		case err == io.EOF:
+			return count, nil
+
+		case err != nil:
+			return count, err
+		}
+	}
+}
+
+// ExtractTitle returns the trimmed content of the last html title tag
+func ExtractTitle(content string) string {
+	// the wildcard detection always kept the last one
+	m := titleRegex.FindAllStringSubmatch(content, -1)
+	if len(m) == 0 {
+		return ""
+	}
+	return strings.TrimSpace(m[len(m)-1][1])
+}
+
+// IsDirectoryListing reports whether the content is an auto generated
+// directory listing
+func IsDirectoryListing(content string) bool {
+	for _, re := range listingRegexes {
+		if re.MatchString(content) {
+			return true
+		}
+	}
+	return false
+}
+
+// MediaType returns the lowercased media type of a Content-Type header
+// without its parameters
+func MediaType(contentType string) string {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		return mediaType
+	}
+	// keep what can be made of malformed headers
+	return strings.ToLower(strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0]))
+}
+
+// matchesMediaType reports whether the media type matches one of the
+// patterns, which are full types or type/* wildcards
+func matchesMediaType(mediaType string, patterns []string) bool {
+	for _, p := range patterns {
+		if p == mediaType || (strings.HasSuffix(p, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(p, "*"))) {
+			return true
+		}
+	}
+	return false
+}
+
+// expandExtToken returns the words a wordlist line expands to. Every
+// token of the line, wherever it appears, is replaced by the same
+// extension; the blank extension also drops the dot in front of it.
+func expandExtToken(word, token string, exts Set[string], blank bool) []string {
+	if !strings.Contains(word, token) {
+		return []string{word}
+	}
+	var words []string
+	if blank {
+		words = append(words, strings.ReplaceAll(strings.ReplaceAll(word, "."+token, ""), token, ""))
+	}
+	for ext := range exts.Set {
+		words = append(words, strings.ReplaceAll(word, token, ext))
+	}
+	return words
+}
+
+// RandomString returns a random string of length n using the given characters
+func RandomString(rnd Rand, n int, charset string) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = charset[rnd.Intn(len(charset))]
+	}
+	return string(b)
+}
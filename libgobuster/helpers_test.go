@@ -1,168 +1,333 @@
-package libgobuster
-
-import (
-	"strings"
-	"testing"
-	"testing/iotest"
-)
-
-func TestNewStringSet(t *testing.T) {
-	if newStringSet().Set == nil {
-		t.Fatal("newStringSet returned nil Set")
-	}
-}
-
-func TestNewIntSet(t *testing.T) {
-	if newIntSet().Set == nil {
-		t.Fatal("newIntSet returned nil Set")
-	}
-}
-
-func TestStringSetAdd(t *testing.T) {
-	x := newStringSet()
-	x.Add("test")
-	if len(x.Set) != 1 {
-		t.Fatalf("Unexptected size. Should have 1 Got %v", len(x.Set))
-	}
-}
-
-func TestStringSetAddDouble(t *testing.T) {
-	x := newStringSet()
-	x.Add("test")
-	x.Add("test")
-	if len(x.Set) != 1 {
-		t.Fatalf("Unexptected size. Should have 1 Got %d", len(x.Set))
-	}
-}
-
-func TestStringSetAddRange(t *testing.T) {
-	x := newStringSet()
-	x.AddRange([]string{"asdf", "ghjk"})
-	if len(x.Set) != 2 {
-		t.Fatalf("Unexptected size. Should have 2 Got %d", len(x.Set))
-	}
-}
-
-func TestStringSetAddRangeDouble(t *testing.T) {
-	x := newStringSet()
-	x.AddRange([]string{"asdf", "ghjk", "asdf", "ghjk"})
-	if len(x.Set) != 2 {
-		t.Fatalf("Unexptected size. Should have 2 Got %d", len(x.Set))
-	}
-}
-
-func TestStringSetContains(t *testing.T) {
-	x := newStringSet()
-	v := []string{"asdf", "ghjk", "1234", "5678"}
-	x.AddRange(v)
-	for _, y := range v {
-		if !x.Contains(y) {
-			t.Fatalf("Did not find value %s in array. %v", y, x.Set)
-		}
-	}
-}
-
-func TestStringSetContainsAny(t *testing.T) {
-	x := newStringSet()
-	v := []string{"asdf", "ghjk", "1234", "5678"}
-	x.AddRange(v)
-	if !x.ContainsAny(v) {
-		t.Fatalf("Did not find any")
-	}
-
-	// test not found
-	if x.ContainsAny([]string{"mmmm", "nnnnn"}) {
-		t.Fatal("Found unexpected values")
-	}
-}
-
-func TestStringSetStringify(t *testing.T) {
-	x := newStringSet()
-	v := []string{"asdf", "ghjk", "1234", "5678"}
-	x.AddRange(v)
-	z := x.Stringify()
-	// order is random
-	for _, y := range v {
-		if !strings.Contains(z, y) {
-			t.Fatalf("Did not find value %q in %q", y, z)
-		}
-	}
-}
-
-func TestIntSetAdd(t *testing.T) {
-	x := newIntSet()
-	x.Add(1)
-	if len(x.Set) != 1 {
-		t.Fatalf("Unexptected size. Should have 1 Got %d", len(x.Set))
-	}
-}
-
-func TestIntSetAddDouble(t *testing.T) {
-	x := newIntSet()
-	x.Add(1)
-	x.Add(1)
-	if len(x.Set) != 1 {
-		t.Fatalf("Unexptected size. Should have 1 Got %d", len(x.Set))
-	}
-}
-
-func TestIntSetContains(t *testing.T) {
-	x := newIntSet()
-	v := []int{1, 2, 3, 4}
-	for _, y := range v {
-		x.Add(y)
-	}
-	for _, y := range v {
-		if !x.Contains(y) {
-			t.Fatalf("Did not find value %d in array. %v", y, x.Set)
-		}
-	}
-}
-
-func TestIntSetStringify(t *testing.T) {
-	x := newIntSet()
-	v := []int{1, 3, 2, 4}
-	expected := "1,2,3,4"
-	for _, y := range v {
-		x.Add(y)
-	}
-	z := x.Stringify()
-	// should be sorted
-	if expected != z {
-		t.Fatalf("Expected %q got %q", expected, z)
-	}
-}
-
-func TestLineCounter(t *testing.T) {
-	var tt = []struct {
-		testName string
-		s        string
-		expected int
-	}{
-		{"One Line", "test", 1},
-		{"3 Lines", "TestString\nTest\n1234", 3},
-		{"Trailing newline", "TestString\nTest\n1234\n", 4},
-		{"3 Lines cr lf", "TestString\r\nTest\r\n1234", 3},
-		{"Empty", "", 1},
-	}
-	for _, x := range tt {
-		t.Run(x.testName, func(t *testing.T) {
-			r := strings.NewReader(x.s)
-			l, err := lineCounter(r)
-			if err != nil {
-				t.Fatalf("Got error: %v", err)
-			}
-			if l != x.expected {
-				t.Fatalf("wrong line count! Got %d expected %d", l, x.expected)
-			}
-		})
-	}
-}
-
-func TestLineCounterError(t *testing.T) {
-	r := iotest.TimeoutReader(strings.NewReader("test"))
-	_, err := lineCounter(r)
-	if err != iotest.ErrTimeout {
-		t.Fatalf("Got wrong error! %v", err)
-	}
-}
+package libgobuster
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestNewStringSet(t *testing.T) {
+	if NewSet[string]().Set == nil {
+		t.Fatal("NewSet[string] returned nil Set")
+	}
+}
+
+func TestNewIntSet(t *testing.T) {
+	if NewSet[int]().Set == nil {
+		t.Fatal("NewSet[int] returned nil Set")
+	}
+}
+
+func TestStringSetAdd(t *testing.T) {
+	x := NewSet[string]()
+	x.Add("test")
+	if len(x.Set) != 1 {
+		t.Fatalf("Unexptected size. Should have 1 Got %v", len(x.Set))
+	}
+}
+
+func TestStringSetAddDouble(t *testing.T) {
+	x := NewSet[string]()
+	x.Add("test")
+	x.Add("test")
+	if len(x.Set) != 1 {
+		t.Fatalf("Unexptected size. Should have 1 Got %d", len(x.Set))
+	}
+}
+
+func TestStringSetAddRange(t *testing.T) {
+	x := NewSet[string]()
+	x.AddRange([]string{"asdf", "ghjk"})
+	if len(x.Set) != 2 {
+		t.Fatalf("Unexptected size. Should have 2 Got %d", len(x.Set))
+	}
+}
+
+func TestStringSetAddRangeDouble(t *testing.T) {
+	x := NewSet[string]()
+	x.AddRange([]string{"asdf", "ghjk", "asdf", "ghjk"})
+	if len(x.Set) != 2 {
+		t.Fatalf("Unexptected size. Should have 2 Got %d", len(x.Set))
+	}
+}
+
+func TestStringSetContains(t *testing.T) {
+	x := NewSet[string]()
+	v := []string{"asdf", "ghjk", "1234", "5678"}
+	x.AddRange(v)
+	for _, y := range v {
+		if !x.Contains(y) {
+			t.Fatalf("Did not find value %s in array. %v", y, x.Set)
+		}
+	}
+}
+
+func TestStringSetContainsAny(t *testing.T) {
+	x := NewSet[string]()
+	v := []string{"asdf", "ghjk", "1234", "5678"}
+	x.AddRange(v)
+	if !x.ContainsAny(v) {
+		t.Fatalf("Did not find any")
+	}
+
+	// test not found
+	if x.ContainsAny([]string{"mmmm", "nnnnn"}) {
+		t.Fatal("Found unexpected values")
+	}
+}
+
+func TestStringSetStringify(t *testing.T) {
+	x := NewSet[string]()
+	v := []string{"asdf", "ghjk", "1234", "5678"}
+	x.AddRange(v)
+	z := x.Stringify()
+	// order is random
+	for _, y := range v {
+		if !strings.Contains(z, y) {
+			t.Fatalf("Did not find value %q in %q", y, z)
+		}
+	}
+}
+
+func TestIntSetAdd(t *testing.T) {
+	x := NewSet[int]()
+	x.Add(1)
+	if len(x.Set) != 1 {
+		t.Fatalf("Unexptected size. Should have 1 Got %d", len(x.Set))
+	}
+}
+
+func TestIntSetAddDouble(t *testing.T) {
+	x := NewSet[int]()
+	x.Add(1)
+	x.Add(1)
+	if len(x.Set) != 1 {
+		t.Fatalf("Unexptected size. Should have 1 Got %d", len(x.Set))
+	}
+}
+
+func TestIntSetContains(t *testing.T) {
+	x := NewSet[int]()
+	v := []int{1, 2, 3, 4}
+	for _, y := range v {
+		x.Add(y)
+	}
+	for _, y := range v {
+		if !x.Contains(y) {
+			t.Fatalf("Did not find value %d in array. %v", y, x.Set)
+		}
+	}
+}
+
+func TestIntSetStringify(t *testing.T) {
+	x := NewSet[int]()
+	v := []int{1, 3, 2, 4}
+	expected := "1,2,3,4"
+	for _, y := range v {
+		x.Add(y)
+	}
+	z := x.Stringify()
+	// should be sorted
+	if expected != z {
+		t.Fatalf("Expected %q got %q", expected, z)
+	}
+}
+
+func TestSetUnionIntersection(t *testing.T) {
+	t.Parallel()
+
+	a := NewSet(200, 301, 403)
+	b := NewSet(403, 404, 200)
+	union := a.Union(b)
+	if got := union.Stringify(); got != "200,301,403,404" {
+		t.Fatalf("Expected union 200,301,403,404 got %q", got)
+	}
+	intersection := a.Intersection(b)
+	if got := intersection.Values(); !reflect.DeepEqual(got, []int{200, 403}) {
+		t.Fatalf("Expected intersection [200 403] got %v", got)
+	}
+	if a.Len() != 3 || b.Len() != 3 {
+		t.Fatalf("Union and intersection changed the sets: %v %v", a.Set, b.Set)
+	}
+}
+
+func TestLineCounter(t *testing.T) {
+	var tt = []struct {
+		testName string
+		s        string
+		expected int
+	}{
+		{"One Line", "test", 1},
+		{"3 Lines", "TestString\nTest\n1234", 3},
+		{"Trailing newline", "TestString\nTest\n1234\n", 4},
+		{"3 Lines cr lf", "TestString\r\nTest\r\n1234", 3},
+		{"Empty", "", 1},
+	}
+	for _, x := range tt {
+		t.Run(x.testName, func(t *testing.T) {
+			r := strings.NewReader(x.s)
+			l, err := lineCounter(r)
+			if err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			if l != x.expected {
+				t.Fatalf("wrong line count! Got %d expected %d", l, x.expected)
+			}
+		})
+	}
+}
+
+func TestLineCounterError(t *testing.T) {
+	r := iotest.TimeoutReader(strings.NewReader("test"))
+	_, err := lineCounter(r)
+	if err != iotest.ErrTimeout {
+		t.Fatalf("Got wrong error! %v", err)
+	}
+}
+
+func TestExtractTitle(t *testing.T) {
+	var tt = []struct {
+		testName string
+		content  string
+		expected string
+	}{
+		{"Simple", "<html><title>Login</title></html>", "Login"},
+		{"Whitespace", "<TITLE>\n  Login page \n</TITLE>", "Login page"},
+		{"Attributes", "<title lang=\"en\">Home</title>", "Home"},
+		{"Last title", "<title>Home</title><svg><title>Icon</title></svg>", "Icon"},
+		{"Missing", "<html></html>", ""},
+	}
+	for _, x := range tt {
+		t.Run(x.testName, func(t *testing.T) {
+			if title := ExtractTitle(x.content); title != x.expected {
+				t.Fatalf("Expected %q got %q", x.expected, title)
+			}
+		})
+	}
+}
+
+func TestMediaType(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		contentType string
+		expected    string
+	}{
+		{"text/html; charset=UTF-8", "text/html"},
+		{"Application/JSON", "application/json"},
+		{"", ""},
+		{"text/html;;", "text/html"},
+	}
+	for _, x := range tt {
+		if got := MediaType(x.contentType); got != x.expected {
+			t.Fatalf("%q: expected %q, got %q", x.contentType, x.expected, got)
+		}
+	}
+}
+
+func TestContentTypeAllowed(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName  string
+		include   string
+		exclude   string
+		mediaType string
+		expected  bool
+	}{
+		{"No filters", "", "", "text/html", true},
+		{"Excluded", "", "text/html, image/*", "text/html", false},
+		{"Excluded by wildcard", "", "text/html,image/*", "image/png", false},
+		{"Not excluded", "", "text/html", "application/json", true},
+		{"Included", "application/json", "", "application/json", true},
+		{"Not included", "application/json", "", "text/html", false},
+		{"Not included without type", "application/json", "", "", false},
+		{"Included and excluded", "application/*", "application/xml", "application/xml", false},
+	}
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			o := NewOptions()
+			o.IncludeContentTypeParsed = parseContentTypes(x.include)
+			o.ExcludeContentTypeParsed = parseContentTypes(x.exclude)
+			if got := o.ContentTypeAllowed(x.mediaType); got != x.expected {
+				t.Fatalf("expected %t, got %t", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestIsDirectoryListing(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName string
+		content  string
+		expected bool
+	}{
+		{"Apache", `<html><head><title>Index of /backup</title></head><body><h1>Index of /backup</h1><a href="/">Parent Directory</a></body></html>`, true},
+		{"Nginx", "<html>\n<head><title>Index of /files/</title></head>\n<body>\n<h1>Index of /files/</h1><hr><pre><a href=\"../\">../</a>", true},
+		{"Python", `<title>Directory listing for /admin/</title>`, true},
+		{"IIS", `<pre><A HREF="/">[To Parent Directory]</A><br><br>`, true},
+		{"Regular page", `<html><head><title>Index of products</title></head></html>`, false},
+		{"Empty", "", false},
+	}
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			if got := IsDirectoryListing(x.content); got != x.expected {
+				t.Fatalf("expected %t, got %t", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestExpandExtToken(t *testing.T) {
+	t.Parallel()
+
+	exts := NewSet[string]()
+	exts.AddRange([]string{"php", "bak"})
+
+	tt := []struct {
+		testName string
+		word     string
+		token    string
+		blank    bool
+		expected []string
+	}{
+		{"No token", "admin", DefaultExtToken, true, []string{"admin"}},
+		{"Suffix", "index.%EXT%", DefaultExtToken, false, []string{"index.bak", "index.php"}},
+		{"Blank", "index.%EXT%", DefaultExtToken, true, []string{"index", "index.bak", "index.php"}},
+		{"Middle of word", "config%EXT%.old", DefaultExtToken, true, []string{"config.old", "configbak.old", "configphp.old"}},
+		{"Query string", "view.%EXT%?file=a.%EXT%", DefaultExtToken, false, []string{"view.bak?file=a.bak", "view.php?file=a.php"}},
+		{"Custom token", "index.{ext}", "{ext}", false, []string{"index.bak", "index.php"}},
+		{"Default token with custom one", "index.%EXT%", "{ext}", false, []string{"index.%EXT%"}},
+	}
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			got := expandExtToken(x.word, x.token, exts, x.blank)
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, x.expected) {
+				t.Fatalf("expected %v, got %v", x.expected, got)
+			}
+
+			opt := NewOptions()
+			opt.ExtToken = x.token
+			opt.ExtensionsParsed = exts
+			opt.BlankExtension = x.blank
+			g := &Gobuster{Opts: opt}
+			if weight := g.wordWeight(x.word); weight != len(got) {
+				t.Fatalf("expected a weight of %d, got %d", len(got), weight)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package libgobuster
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// profilePrefix starts the lines of the all time matches describing
+// the options a fingerprint stands for
+const profilePrefix = "# profile "
+
+// Profile returns a description of the options that influence which
+// results a scan produces. Secrets and output locations are left out.
+func (opt *Options) Profile() string {
+	fields := []string{
+		fmt.Sprintf("mode=%s", opt.Mode),
+		// the application root, however its path was given
+		fmt.Sprintf("url=%s", opt.URL+strings.TrimPrefix(opt.BasePath, "/")),
+		fmt.Sprintf("wordlist=%s", opt.Wordlist),
+	}
+	if opt.Mode == ModeDir {
+		fields = append(fields,
+			fmt.Sprintf("ext=%s", opt.Extensions),
+			fmt.Sprintf("x=%s", opt.ExcludedStatusCodes),
+			fmt.Sprintf("xs=%q", opt.ExcludeString),
+			fmt.Sprintf("match=%q", opt.Match),
+			fmt.Sprintf("a=%q", opt.UserAgent),
+			fmt.Sprintf("random-agent=%s", opt.RandomAgent),
+			fmt.Sprintf("r=%t", opt.FollowRedirect),
+			fmt.Sprintf("f=%t", opt.UseSlash),
+			fmt.Sprintf("be=%t", opt.BlankExtension),
+			fmt.Sprintf("head-first=%t", opt.HeadFirst),
+			fmt.Sprintf("api=%t", opt.API),
+			fmt.Sprintf("auth=%t", opt.Username != "" || opt.BearerToken != "" || opt.Cookies != ""),
+			fmt.Sprintf("wildcard=%d/%s/%s", opt.WildcardProbes, opt.WildcardLengths, opt.WildcardCharset),
+		)
+		// left out when unset, the fingerprints of earlier runs still match
+		if opt.HostFuzz != "" {
+			fields = append(fields, fmt.Sprintf("host-fuzz=%s", opt.HostFuzz))
+		}
+		if opt.WordlistDirs != "" {
+			fields = append(fields, fmt.Sprintf("wordlist-dirs=%s", opt.WordlistDirs))
+		}
+		if opt.WordlistFiles != "" {
+			fields = append(fields, fmt.Sprintf("wordlist-files=%s", opt.WordlistFiles))
+		}
+		if opt.Heuristic404 {
+			fields = append(fields, "heuristic-404=true")
+		}
+		if opt.PruneExts {
+			fields = append(fields, "prune-exts=true")
+		}
+		if opt.VerifyHits > 0 {
+			fields = append(fields, fmt.Sprintf("verify-hits=%d", opt.VerifyHits))
+		}
+	}
+	if opt.Mode == ModeDNS {
+		fields = append(fields,
+			fmt.Sprintf("doh=%s", opt.DoHURL),
+			fmt.Sprintf("dot=%s", opt.DoTServer),
+		)
+	}
+	fields = append(fields, fmt.Sprintf("fw=%t", opt.WildcardForced))
+	return strings.Join(fields, " ")
+}
+
+// ProfileFingerprint returns a short hash of the profile
+func (opt *Options) ProfileFingerprint() string {
+	sum := sha256.Sum256([]byte(opt.Profile()))
+	return hex.EncodeToString(sum[:6])
+}
+
+// profileFingerprint returns the ProfileFingerprint of the options of
+// the scan, hashed once
+func (g *Gobuster) profileFingerprint() string {
+	g.fingerprintOnce.Do(func() {
+		g.fingerprint = g.Opts.ProfileFingerprint()
+	})
+	return g.fingerprint
+}
+
+// ProfileLine returns the line written once per run to the all time
+// matches so the fingerprint of later lines can be resolved
+func (g *Gobuster) ProfileLine() string {
+	return fmt.Sprintf("%s%s %s", profilePrefix, g.profileFingerprint(), g.Opts.Profile())
+}
+
+// Provenance returns the suffix appended to each all time matches line
+func (g *Gobuster) Provenance() string {
+	return fmt.Sprintf(" - run %s - profile %s", g.RunID, g.profileFingerprint())
+}
+
+// HistoryEntry is a single sighting of an entity in the all time matches
+type HistoryEntry struct {
+	Date    string
+	Line    string
+	RunID   string
+	Profile string
+}
+
+// History holds the first and last sighting of an entity
+type History struct {
+	Entity   string
+	Seen     int
+	First    *HistoryEntry
+	Last     *HistoryEntry
+	Profiles map[string]string
+}
+
+// normalizeEntity makes "admin", "/admin" and "/admin/" compare equal
+func normalizeEntity(entity string) string {
+	return strings.Trim(strings.TrimSpace(entity), "/")
+}
+
+// parseHistoryLine splits an all time matches line of the form
+// "[date] - entity - ... - run <id> - profile <fingerprint>"
+func parseHistoryLine(line string) (string, *HistoryEntry) {
+	fields := strings.Split(line, " - ")
+	if len(fields) < 2 || !strings.HasPrefix(fields[0], "[") {
+		return "", nil
+	}
+	entry := &HistoryEntry{
+		Date: strings.Trim(fields[0], "[]"),
+		Line: line,
+	}
+	for _, field := range fields[2:] {
+		if strings.HasPrefix(field, "run ") {
+			entry.RunID = strings.TrimPrefix(field, "run ")
+		} else if strings.HasPrefix(field, "profile ") {
+			entry.Profile = strings.TrimPrefix(field, "profile ")
+		}
+	}
+	return fields[1], entry
+}
+
+// ReadHistory scans an all time matches file for the given path or
+// subdomain. Lines written before provenance was recorded have an
+// empty run id and profile.
+func ReadHistory(r io.Reader, entity string) (*History, error) {
+	h := &History{
+		Entity:   entity,
+		Profiles: make(map[string]string),
+	}
+	want := normalizeEntity(entity)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, profilePrefix) {
+			parts := strings.SplitN(strings.TrimPrefix(line, profilePrefix), " ", 2)
+			if len(parts) == 2 {
+				h.Profiles[parts[0]] = parts[1]
+			}
+			continue
+		}
+		lineEntity, entry := parseHistoryLine(line)
+		if entry == nil || normalizeEntity(lineEntity) != want {
+			continue
+		}
+		h.Seen++
+		if h.First == nil {
+			h.First = entry
+		}
+		h.Last = entry
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
@@ -0,0 +1,74 @@
+package libgobuster
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadHistory(t *testing.T) {
+	t.Parallel()
+
+	content := strings.Join([]string{
+		"[2026-01-01 10:00:00] - /admin - 301",
+		"# profile aaaa mode=dir url=http://a",
+		"[2026-02-01 10:00:00] - /admin - 200 - run r1 - profile aaaa",
+		"[2026-02-01 10:00:01] - /login - 200 - run r1 - profile aaaa",
+		"# profile bbbb mode=dir url=http://b",
+		"[2026-03-01 10:00:00] - /admin - 403 - run r2 - profile bbbb",
+	}, "\n")
+
+	var tt = []struct {
+		testName  string
+		entity    string
+		seen      int
+		firstDate string
+		lastRun   string
+	}{
+		{"Leading slash", "/admin", 3, "2026-01-01 10:00:00", "r2"},
+		{"No slash", "admin/", 3, "2026-01-01 10:00:00", "r2"},
+		{"Single", "login", 1, "2026-02-01 10:00:01", "r1"},
+		{"Missing", "nothing", 0, "", ""},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+			h, err := ReadHistory(strings.NewReader(content), x.entity)
+			if err != nil {
+				t.Fatalf("got error: %v", err)
+			}
+			if h.Seen != x.seen {
+				t.Fatalf("expected %d sightings, got %d", x.seen, h.Seen)
+			}
+			if x.seen == 0 {
+				return
+			}
+			if h.First.Date != x.firstDate {
+				t.Fatalf("expected first date %q, got %q", x.firstDate, h.First.Date)
+			}
+			if h.Last.RunID != x.lastRun {
+				t.Fatalf("expected last run %q, got %q", x.lastRun, h.Last.RunID)
+			}
+			if h.Profiles[h.Last.Profile] == "" {
+				t.Fatalf("profile %q not resolved", h.Last.Profile)
+			}
+		})
+	}
+}
+
+func TestProvenance(t *testing.T) {
+	t.Parallel()
+
+	o := NewOptions()
+	o.Mode = ModeDir
+	o.URL = "http://example.com/"
+	g := &Gobuster{Opts: o, RunID: "run"}
+	fingerprint := o.ProfileFingerprint()
+	if got := g.Provenance(); got != " - run run - profile "+fingerprint {
+		t.Fatalf("unexpected provenance %q", got)
+	}
+	if got := g.ProfileLine(); !strings.HasPrefix(got, profilePrefix+fingerprint+" mode=dir ") {
+		t.Fatalf("unexpected profile line %q", got)
+	}
+}
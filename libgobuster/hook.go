@@ -0,0 +1,130 @@
+package libgobuster
+
+import (
+	"fmt"
+	"log"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// resultHook runs the user supplied -on-result command for every match
+type resultHook struct {
+	args   []string
+	sem    chan struct{}
+	wg     sync.WaitGroup
+	logger *log.Logger
+}
+
+func newResultHook(command string, concurrency int) (*resultHook, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	args, err := splitCommand(command)
+	if err != nil {
+		return nil, err
+	}
+	return &resultHook{
+		args: args,
+		sem:  make(chan struct{}, concurrency),
+	}, nil
+}
+
+// splitCommand splits a command into its arguments the way a shell does:
+// single quotes keep their content as is, double quotes and a backslash
+// outside of single quotes escape the next character. Nothing else of
+// the shell syntax is interpreted.
+func splitCommand(command string) ([]string, error) {
+	var args []string
+	var arg strings.Builder
+	inArg, escaped := false, false
+	var quote rune
+	for _, c := range command {
+		switch {
+		case escaped:
+			arg.WriteRune(c)
+			escaped = false
+		case c == '\\' && quote != '\'':
+			escaped, inArg = true, true
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote != 0:
+			arg.WriteRune(c)
+		case c == '\'' || c == '"':
+			quote, inArg = c, true
+		case c == ' ' || c == '\t' || c == '\n':
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			arg.WriteRune(c)
+			inArg = true
+		}
+	}
+	if escaped || quote != 0 {
+		return nil, fmt.Errorf("unterminated quote or escape in %q", command)
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	return args, nil
+}
+
+// expandHookArgs replaces the {url}, {status}, {size} and {redirect}
+// placeholders in each argument. The command is never passed through
+// a shell so values can not inject additional commands.
+func expandHookArgs(args []string, url string, status int, size int64, redirect string) []string {
+	r := strings.NewReplacer(
+		"{url}", url,
+		"{status}", strconv.Itoa(status),
+		"{size}", strconv.FormatInt(size, 10),
+		"{redirect}", redirect,
+	)
+	expanded := make([]string, len(args))
+	for i, a := range args {
+		expanded[i] = r.Replace(a)
+	}
+	return expanded
+}
+
+func (h *resultHook) run(args []string) {
+	// blocks once the concurrency cap is reached
+	h.sem <- struct{}{}
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+		defer func() { <-h.sem }()
+		// not bound to the scan context, results are still being
+		// handled after the scan itself has been cancelled
+		out, err := exec.Command(args[0], args[1:]...).CombinedOutput()
+		if err != nil {
+			orDefault(h.logger).Printf("[!] on-result command %q failed: %v: %s", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+		}
+	}()
+}
+
+// RunResultHook runs the -on-result command for the given result
+func (g *Gobuster) RunResultHook(r *Result) {
+	if g.hook == nil || len(g.hook.args) == 0 {
+		return
+	}
+	size := int64(0)
+	if r.Size != nil {
+		size = *r.Size
+	}
+	redirect := ""
+	if r.RedirectURL != nil {
+		redirect = *r.RedirectURL
+	}
+	g.hook.run(expandHookArgs(g.hook.args, r.FullURL(g), r.Status, size, redirect))
+}
+
+// WaitResultHooks waits for all running -on-result commands to finish
+func (g *Gobuster) WaitResultHooks() {
+	if g.hook != nil {
+		g.hook.wg.Wait()
+	}
+}
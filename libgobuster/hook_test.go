@@ -0,0 +1,60 @@
+package libgobuster
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExpandHookArgs(t *testing.T) {
+	t.Parallel()
+
+	args := []string{"curl", "-s", "{url}", "-H", "X-Status: {status}/{size}", "{redirect}"}
+	expected := []string{"curl", "-s", "http://localhost/a b;id", "-H", "X-Status: 301/12", "http://localhost/a/"}
+	got := expandHookArgs(args, "http://localhost/a b;id", 301, 12, "http://localhost/a/")
+	if !reflect.DeepEqual(expected, got) {
+		t.Fatalf("Expected %v but got %v", expected, got)
+	}
+	if args[2] != "{url}" {
+		t.Fatalf("template arguments were modified: %v", args)
+	}
+}
+
+func TestSplitCommand(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName string
+		command  string
+		expected []string
+		err      bool
+	}{
+		{"Words", "curl -s  {url}", []string{"curl", "-s", "{url}"}, false},
+		{"Double quotes", `curl -H "X-Status: {status}" {url}`, []string{"curl", "-H", "X-Status: {status}", "{url}"}, false},
+		{"Single quotes", `sh -c 'echo "$1" \ done' x`, []string{"sh", "-c", `echo "$1" \ done`, "x"}, false},
+		{"Escapes", `notify a\ b "say \"hi\""`, []string{"notify", "a b", `say "hi"`}, false},
+		{"Empty argument", `cmd ""`, []string{"cmd", ""}, false},
+		{"Unterminated quote", `cmd "a`, nil, true},
+		{"Trailing escape", `cmd a\`, nil, true},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := splitCommand(x.command)
+			if x.err {
+				if err == nil {
+					t.Fatalf("expected an error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got error: %v", err)
+			}
+			if !reflect.DeepEqual(x.expected, got) {
+				t.Fatalf("expected %q, got %q", x.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package libgobuster
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// FuzzedHost returns the host -host-fuzz sends for a word: words with a
+// dot are hosts already, bare words get the domain of the url appended.
+// The port of the url is kept.
+func FuzzedHost(word, targetURL string) string {
+	u, err := url.Parse(targetURL)
+	if err != nil {
+		return word
+	}
+	host := word
+	if !strings.Contains(word, ".") {
+		domain := u.Hostname()
+		// subdomains of an address make no sense
+		if net.ParseIP(domain) == nil {
+			host = word + "." + domain
+		}
+	}
+	if port := u.Port(); port != "" && !strings.Contains(host, ":") {
+		host = net.JoinHostPort(host, port)
+	}
+	return host
+}
+
+// HostRequestOptions returns the options of a -host-fuzz request sending
+// host in the fuzzed headers
+func (g *Gobuster) HostRequestOptions(url, host string) RequestOptions {
+	ro := g.NewRequestOptions(url)
+	if g.Opts.HostFuzz == HostFuzzHost || g.Opts.HostFuzz == HostFuzzBoth {
+		ro.Host = host
+	}
+	if g.Opts.HostFuzz == HostFuzzForwarded || g.Opts.HostFuzz == HostFuzzBoth {
+		ro.Headers = http.Header{"X-Forwarded-Host": []string{host}}
+	}
+	return ro
+}
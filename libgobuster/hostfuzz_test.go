@@ -0,0 +1,83 @@
+package libgobuster
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestFuzzedHost(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName string
+		word     string
+		url      string
+		expected string
+	}{
+		{"Bare word", "admin", "https://www.example.com/", "admin.www.example.com"},
+		{"Host", "intranet.local", "https://www.example.com/", "intranet.local"},
+		{"Port kept", "dev", "http://example.com:8080/", "dev.example.com:8080"},
+		{"Host with port", "dev.local:81", "http://example.com:8080/", "dev.local:81"},
+		{"Address", "admin", "http://10.0.0.1/", "admin"},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			if got := FuzzedHost(x.word, x.url); got != x.expected {
+				t.Fatalf("expected %q, got %q", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestHostRequestOptions(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName  string
+		fuzz      string
+		host      string
+		forwarded string
+	}{
+		{"Host", HostFuzzHost, "admin.example.com", ""},
+		{"Forwarded", HostFuzzForwarded, "", "admin.example.com"},
+		{"Both", HostFuzzBoth, "admin.example.com", "admin.example.com"},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			var gotHost, gotForwarded string
+			h := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotHost, gotForwarded = r.Host, r.Header.Get("X-Forwarded-Host")
+			}))
+			defer h.Close()
+
+			o := NewOptions()
+			o.HostFuzz = x.fuzz
+			c, err := newHTTPClient(context.Background(), o)
+			if err != nil {
+				t.Fatalf("got error: %v", err)
+			}
+			g := &Gobuster{Opts: o, HTTP: c, mu: new(sync.RWMutex)}
+			if _, err := g.GetRequest(h.URL, g.HostRequestOptions(h.URL, "admin.example.com")); err != nil {
+				t.Fatalf("got error: %v", err)
+			}
+			expectedHost := x.host
+			if expectedHost == "" {
+				expectedHost = h.Listener.Addr().String()
+			}
+			if gotHost != expectedHost || gotForwarded != x.forwarded {
+				t.Fatalf("expected host %q and forwarded %q, got %q and %q", expectedHost, x.forwarded, gotHost, gotForwarded)
+			}
+		})
+	}
+}
@@ -1,143 +1,456 @@
-package libgobuster
-
-import (
-	"context"
-	"crypto/tls"
-	"fmt"
-	"io"
-	"io/ioutil"
-	"net/http"
-	"net/url"
-	"strings"
-	"unicode/utf8"
-)
-
-type httpClient struct {
-	client        *http.Client
-	context       context.Context
-	UserAgent     string
-	username      string
-	password      string
-	includeLength bool
-}
-
-// NewHTTPClient returns a new HTTPClient
-func newHTTPClient(c context.Context, opt *Options) (*httpClient, error) {
-	var proxyURLFunc func(*http.Request) (*url.URL, error)
-	var client httpClient
-	proxyURLFunc = http.ProxyFromEnvironment
-
-	if opt == nil {
-		return nil, fmt.Errorf("options is nil")
-	}
-
-	if opt.Proxy != "" {
-		proxyURL, err := url.Parse(opt.Proxy)
-		if err != nil {
-			return nil, fmt.Errorf("proxy URL is invalid (%v)", err)
-		}
-		proxyURLFunc = http.ProxyURL(proxyURL)
-	}
-
-	var redirectFunc func(req *http.Request, via []*http.Request) error
-	if !opt.FollowRedirect {
-		redirectFunc = func(req *http.Request, via []*http.Request) error {
-			return http.ErrUseLastResponse
-		}
-	} else {
-		redirectFunc = nil
-	}
-
-	client.client = &http.Client{
-		Timeout:       opt.Timeout,
-		CheckRedirect: redirectFunc,
-		Transport: &http.Transport{
-			Proxy: proxyURLFunc,
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: opt.InsecureSSL,
-			},
-		}}
-	client.context = c
-	client.username = opt.Username
-	client.password = opt.Password
-	client.includeLength = opt.IncludeLength
-	client.UserAgent = opt.UserAgent
-	return &client, nil
-}
-
-// MakeRequest makes a request to the specified url
-func (client *httpClient) makeRequest(fullURL, cookie string) (*int, *int64, *string, *string, error) {
-	req, err := http.NewRequest(http.MethodGet, fullURL, nil)
-
-	if err != nil {
-		return nil, nil, nil, nil, err
-	}
-
-	// add the context so we can easily cancel out
-	req = req.WithContext(client.context)
-
-	if cookie != "" {
-		req.Header.Set("Cookie", cookie)
-	}
-
-	ua := fmt.Sprintf("gobuster %s", VERSION)
-	if client.UserAgent != "" {
-		ua = client.UserAgent
-	}
-	req.Header.Set("User-Agent", ua)
-
-	if client.username != "" {
-		req.SetBasicAuth(client.username, client.password)
-	}
-
-	resp, err := client.client.Do(req)
-	if err != nil {
-		if ue, ok := err.(*url.Error); ok {
-
-			if strings.HasPrefix(ue.Err.Error(), "x509") {
-				return nil, nil, nil, nil, fmt.Errorf("Invalid certificate: %v", ue.Err)
-			}
-		}
-		return nil, nil, nil, nil, err
-	}
-
-	defer resp.Body.Close()
-
-	var length *int64
-	length = new(int64)
-	var content *string
-	content = new(string)
-
-	body, err2 := ioutil.ReadAll(resp.Body)
-	if err2 == nil {
-		*content = string(body)
-		*length = int64(utf8.RuneCountInString(*content))
-	}
-
-	if client.includeLength {
-		if resp.ContentLength > 0 {
-			*length = resp.ContentLength
-		}
-	} else {
-		// DO NOT REMOVE!
-		// absolutely needed so golang will reuse connections!
-		_, err = io.Copy(ioutil.Discard, resp.Body)
-		if err != nil {
-			return nil, nil, nil, nil, err
-		}
-	}
-
-	var redirectURL *string
-	redirectURL = new(string)
-	if resp.StatusCode == 301 || resp.StatusCode == 302 {
-		value, err := resp.Location()
-		if err != nil {
-			return nil, nil, nil, nil, err
-		}
-		*redirectURL = value.String()
-	} else {
-		*redirectURL = ""
-	}
-
-	return &resp.StatusCode, length, content, redirectURL, nil
-}
+package libgobuster
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/net/html/charset"
+)
+
+const (
+	// maxDrainSize is the amount of an unread body still read to keep
+	// the connection alive
+	maxDrainSize = 64 * 1024
+	// DefaultMaxResponseSize is the default cap of the bodies read, a
+	// path serving a large file would hold a worker and its memory
+	DefaultMaxResponseSize = 10 * 1024 * 1024
+)
+
+type httpClient struct {
+	clients       []*http.Client
+	next          uint32
+	context       context.Context
+	userAgent     string
+	username      string
+	password      string
+	bearerToken   string
+	includeLength bool
+	debugLog      *httpDebugLog
+	api           bool
+	redirectChain bool
+	decodeCharset bool
+	maxBodySize   int64
+	streamTimeout time.Duration
+	safeMode      bool
+	limiter       *rateLimiter
+}
+
+// proxyFunc returns the proxy of the requests: the -p proxy, Tor or the
+// PAC file, the environment's otherwise
+func (opt *Options) proxyFunc() (func(*http.Request) (*url.URL, error), error) {
+	proxy := opt.Proxy
+	if opt.Tor {
+		// socks5 leaves the name resolution to Tor as well
+		proxy = "socks5://" + opt.TorProxy
+	}
+	proxyURLFunc := http.ProxyFromEnvironment
+	if proxy != "" {
+		proxyURL, err := url.Parse(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("proxy URL is invalid (%v)", err)
+		}
+		proxyURLFunc = http.ProxyURL(proxyURL)
+	}
+	if opt.PAC != nil {
+		proxyURLFunc = opt.PAC.Proxy
+	}
+	return proxyURLFunc, nil
+}
+
+// NewHTTPClient returns a new HTTPClient
+func newHTTPClient(c context.Context, opt *Options) (*httpClient, error) {
+	var client httpClient
+
+	if opt == nil {
+		return nil, fmt.Errorf("options is nil")
+	}
+
+	proxyURLFunc, err := opt.proxyFunc()
+	if err != nil {
+		return nil, err
+	}
+
+	var redirectFunc func(req *http.Request, via []*http.Request) error
+	if !opt.FollowRedirect {
+		redirectFunc = func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	} else {
+		redirectFunc = nil
+	}
+
+	// one transport per source address so each keeps its own connections,
+	// a nil address lets the system pick the outgoing interface
+	localAddrs := []net.Addr{nil}
+	if len(opt.SourceIPsParsed) > 0 {
+		localAddrs = nil
+		for _, ip := range opt.SourceIPsParsed {
+			localAddrs = append(localAddrs, &net.TCPAddr{IP: ip})
+		}
+	}
+
+	// hostnames are resolved over DoH/DoT when configured
+	var resolver *net.Resolver
+	if opt.DoHURL != "" || opt.DoTServer != "" {
+		resolver = newNetResolver(opt)
+	}
+	// the scans of a run share its cache, a scan of its own uses one of
+	// its own
+	cache := opt.DNSCache
+	if cache == nil && (opt.DNSCacheTTL > 0 || len(opt.StaticResolveParsed) > 0) {
+		cache = newDNSCache(resolver, opt.DNSCacheTTL, opt.clock())
+	}
+
+	for _, localAddr := range localAddrs {
+		transport := &http.Transport{
+			Proxy: proxyURLFunc,
+			TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: opt.InsecureSSL,
+			},
+		}
+		if localAddr != nil || resolver != nil || cache != nil {
+			dialer := &net.Dialer{
+				LocalAddr: localAddr,
+				Timeout:   opt.Timeout,
+				KeepAlive: 30 * time.Second,
+				Resolver:  resolver,
+			}
+			transport.DialContext = dialer.DialContext
+			if cache != nil {
+				transport.DialContext = cache.dialContext(dialer, opt.StaticResolveParsed)
+			}
+		}
+		client.clients = append(client.clients, &http.Client{
+			Timeout:       opt.Timeout,
+			CheckRedirect: redirectFunc,
+			Transport:     transport,
+		})
+	}
+	client.context = c
+	client.username = opt.Username
+	client.password = opt.Password
+	client.bearerToken = opt.BearerToken
+	client.includeLength = opt.IncludeLength
+	client.userAgent = opt.UserAgent
+	client.api = opt.API
+	client.redirectChain = opt.FollowRedirect && opt.ShowRedirectChain
+	client.decodeCharset = !opt.NoCharset
+	client.maxBodySize = opt.MaxResponseSize
+	client.streamTimeout = opt.StreamTimeout
+	if opt.SafeMode {
+		client.safeMode = true
+		client.limiter = newRateLimiter(SafeModeRate, opt.clock())
+	}
+	return &client, nil
+}
+
+// client returns the next http client, round-robin over the source addresses
+func (client *httpClient) client() *http.Client {
+	if len(client.clients) == 1 {
+		return client.clients[0]
+	}
+	n := atomic.AddUint32(&client.next, 1)
+	return client.clients[int(n)%len(client.clients)]
+}
+
+// Response is the outcome of a single request
+type Response struct {
+	StatusCode    int
+	Length        int64
+	Content       string
+	RedirectURL   string
+	RedirectChain []string
+	Header        http.Header
+	ContentType   string
+	// Truncated is set when the body exceeded the maximum response size
+	Truncated bool
+	// Streaming is set when the body was cut by the stream timeout
+	Streaming bool
+	// Binary is set for the images, archives and other binary bodies,
+	// their length is in bytes
+	Binary bool
+}
+
+// streamingMediaTypes are the media types of the bodies sent for as
+// long as the connection is open
+var streamingMediaTypes = []string{
+	"text/event-stream",
+	"multipart/x-mixed-replace",
+	"application/x-ndjson",
+	"application/stream+json",
+	"application/grpc",
+}
+
+// IsStreaming reports whether a media type is one of a streaming body
+func IsStreaming(mediaType string) bool {
+	return matchesMediaType(mediaType, streamingMediaTypes)
+}
+
+// streamReader cuts a body once it stalls for the timeout, or after
+// the timeout for streaming bodies, which never stall
+type streamReader struct {
+	body      io.ReadCloser
+	timer     *time.Timer
+	timeout   time.Duration
+	streaming bool
+	// state is streamReading until the body ends or is cut
+	state int32
+}
+
+const (
+	streamReading int32 = iota
+	streamCut
+	streamEnded
+)
+
+func newStreamReader(body io.ReadCloser, timeout time.Duration, streaming bool) *streamReader {
+	r := &streamReader{body: body, timeout: timeout, streaming: streaming}
+	r.timer = time.AfterFunc(timeout, func() {
+		if atomic.CompareAndSwapInt32(&r.state, streamReading, streamCut) {
+			body.Close()
+		}
+	})
+	return r
+}
+
+func (r *streamReader) Read(p []byte) (int, error) {
+	n, err := r.body.Read(p)
+	if err != nil {
+		if !atomic.CompareAndSwapInt32(&r.state, streamReading, streamEnded) && r.wasCut() {
+			// the body read so far is the response
+			return n, io.EOF
+		}
+		return n, err
+	}
+	if n > 0 && !r.streaming {
+		r.timer.Reset(r.timeout)
+	}
+	return n, nil
+}
+
+// stop stops the timer once the body was read and reports whether it
+// was cut
+func (r *streamReader) stop() bool {
+	r.timer.Stop()
+	return r.wasCut()
+}
+
+func (r *streamReader) wasCut() bool {
+	return atomic.LoadInt32(&r.state) == streamCut
+}
+
+// redirectChain returns the urls the request was redirected through
+// when following redirects, ending with the final url
+func redirectChain(resp *http.Response) []string {
+	var chain []string
+	for r := resp; r != nil && r.Request != nil; r = r.Request.Response {
+		chain = append([]string{r.Request.URL.String()}, chain...)
+	}
+	if len(chain) < 2 {
+		return nil
+	}
+	return chain
+}
+
+// decodeBody converts textual bodies in another charset to UTF-8 so
+// titles and exclude strings match, the charset is taken from the
+// Content-Type header, a BOM or the meta tags
+func decodeBody(body []byte, contentType string) []byte {
+	mediaType := strings.ToLower(contentType)
+	if mediaType != "" && !strings.HasPrefix(mediaType, "text/") && !strings.Contains(mediaType, "html") &&
+		!strings.Contains(mediaType, "xml") && !strings.Contains(mediaType, "json") && !strings.Contains(mediaType, "javascript") {
+		return body
+	}
+	if utf8.Valid(body) && !strings.Contains(mediaType, "charset=") {
+		return body
+	}
+	enc, name, _ := charset.DetermineEncoding(body, contentType)
+	if name == "utf-8" {
+		return body
+	}
+	decoded, err := enc.NewDecoder().Bytes(body)
+	if err != nil {
+		return body
+	}
+	return decoded
+}
+
+// RequestOptions customizes a single request without touching the
+// client shared by all workers
+type RequestOptions struct {
+	// RequestID correlates the request with the debug http log
+	RequestID string
+	// UserAgent overrides the user agent of the client when set
+	UserAgent string
+	Cookies   string
+	// Host overrides the Host header of the url when set
+	Host string
+	// Headers are set after all other headers
+	Headers http.Header
+	// Body is sent as the body of the request when set
+	Body string
+	// MaxBodySize lowers the maximum response size when set
+	MaxBodySize int64
+}
+
+// MakeRequest makes a request to the specified url
+func (client *httpClient) makeRequest(method, fullURL string, ro RequestOptions) (*Response, error) {
+	if err := checkSafeMethod(client.safeMode, method); err != nil {
+		return nil, err
+	}
+	var reqBody io.Reader
+	if ro.Body != "" {
+		reqBody = strings.NewReader(ro.Body)
+	}
+	req, err := http.NewRequest(method, fullURL, reqBody)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// add the context so we can easily cancel out
+	req = req.WithContext(client.context)
+
+	if ro.Cookies != "" {
+		req.Header.Set("Cookie", ro.Cookies)
+	}
+
+	if ro.Host != "" {
+		req.Host = ro.Host
+	}
+
+	ua := fmt.Sprintf("gobuster %s", VERSION)
+	if ro.UserAgent != "" {
+		ua = ro.UserAgent
+	} else if client.userAgent != "" {
+		ua = client.userAgent
+	}
+	req.Header.Set("User-Agent", ua)
+
+	if client.api {
+		req.Header.Set("Accept", "application/json")
+		// only the requests with a body describe its type
+		if reqBody != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+	}
+
+	if client.username != "" {
+		req.SetBasicAuth(client.username, client.password)
+	} else if client.bearerToken != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", client.bearerToken))
+	}
+
+	for name, values := range ro.Headers {
+		req.Header.Del(name)
+		for _, v := range values {
+			req.Header.Add(name, v)
+		}
+	}
+
+	if client.limiter != nil {
+		client.limiter.wait(client.context)
+	}
+	resp, err := client.client().Do(req)
+	if err != nil {
+		if client.debugLog != nil {
+			client.debugLog.log(ro.RequestID, req, nil, nil, err)
+		}
+		if ue, ok := err.(*url.Error); ok {
+
+			if strings.HasPrefix(ue.Err.Error(), "x509") {
+				return nil, fmt.Errorf("Invalid certificate: %v", ue.Err)
+			}
+		}
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	response := &Response{
+		StatusCode:  resp.StatusCode,
+		Header:      resp.Header,
+		ContentType: MediaType(resp.Header.Get("Content-Type")),
+	}
+
+	var bodyReader io.Reader = resp.Body
+	var stream *streamReader
+	if client.streamTimeout > 0 {
+		stream = newStreamReader(resp.Body, client.streamTimeout, IsStreaming(response.ContentType))
+		bodyReader = stream
+	}
+	maxBodySize := client.maxBodySize
+	if ro.MaxBodySize > 0 && (maxBodySize == 0 || ro.MaxBodySize < maxBodySize) {
+		maxBodySize = ro.MaxBodySize
+	}
+	if maxBodySize > 0 {
+		// read one byte more to know whether the body was cut
+		bodyReader = io.LimitReader(bodyReader, maxBodySize+1)
+	}
+	body, err2 := ioutil.ReadAll(bodyReader)
+	if stream != nil {
+		response.Streaming = stream.stop()
+	}
+	if maxBodySize > 0 && int64(len(body)) > maxBodySize {
+		body = body[:maxBodySize]
+		response.Truncated = true
+	}
+	if err2 == nil {
+		response.Binary = IsBinary(response.ContentType, body)
+		if client.decodeCharset && !response.Binary {
+			body = decodeBody(body, resp.Header.Get("Content-Type"))
+		}
+		response.Content = string(body)
+		if response.Binary {
+			response.Length = int64(len(body))
+		} else {
+			response.Length = int64(utf8.RuneCountInString(response.Content))
+		}
+	}
+
+	if client.debugLog != nil {
+		client.debugLog.log(ro.RequestID, req, resp, body, nil)
+	}
+
+	if client.includeLength || response.Truncated {
+		if resp.ContentLength > 0 {
+			response.Length = resp.ContentLength
+		}
+	}
+	// the rest of a streaming body never ends
+	if !client.includeLength && !response.Streaming && !IsStreaming(response.ContentType) {
+		// DO NOT REMOVE!
+		// absolutely needed so golang will reuse connections!
+		// huge bodies are only drained up to maxDrainSize, past that
+		// the connection is closed instead of reading gigabytes
+		_, err = io.CopyN(ioutil.Discard, resp.Body, maxDrainSize)
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+	}
+
+	// every 3xx carrying a Location, not only 301/302 (303, 307, 308...)
+	if resp.StatusCode >= 300 && resp.StatusCode < 400 && resp.Header.Get("Location") != "" {
+		value, err := resp.Location()
+		if err != nil {
+			return nil, err
+		}
+		response.RedirectURL = value.String()
+	}
+
+	if client.redirectChain {
+		response.RedirectChain = redirectChain(resp)
+	}
+
+	return response, nil
+}
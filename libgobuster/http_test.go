@@ -1,37 +1,300 @@
-package libgobuster
-
-import (
-	"context"
-	"fmt"
-	"net/http"
-	"net/http/httptest"
-	"testing"
-)
-
-func httpServer(t *testing.T, content string) *httptest.Server {
-	t.Helper()
-	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		fmt.Fprint(w, content)
-	}))
-	return ts
-}
-
-func TestMakeRequest(t *testing.T) {
-	h := httpServer(t, "test")
-	defer h.Close()
-	o := NewOptions()
-	c, err := newHTTPClient(context.Background(), o)
-	if err != nil {
-		t.Fatalf("Got Error: %v", err)
-	}
-	a, b, _, _, err := c.makeRequest(h.URL, "")
-	if err != nil {
-		t.Fatalf("Got Error: %v", err)
-	}
-	if *a != 200 {
-		t.Fatalf("Invalid status returned: %d", a)
-	}
-	if b != nil && *b != int64(len("test")) {
-		t.Fatalf("Invalid length returned: %d", b)
-	}
-}
+package libgobuster
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func httpServer(t *testing.T, content string) *httptest.Server {
+	t.Helper()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, content)
+	}))
+	return ts
+}
+
+func TestMakeRequest(t *testing.T) {
+	h := httpServer(t, "test")
+	defer h.Close()
+	o := NewOptions()
+	c, err := newHTTPClient(context.Background(), o)
+	if err != nil {
+		t.Fatalf("Got Error: %v", err)
+	}
+	resp, err := c.makeRequest(http.MethodGet, h.URL, RequestOptions{})
+	if err != nil {
+		t.Fatalf("Got Error: %v", err)
+	}
+	if resp.StatusCode != 200 {
+		t.Fatalf("Invalid status returned: %d", resp.StatusCode)
+	}
+	if resp.Length != int64(len("test")) {
+		t.Fatalf("Invalid length returned: %d", resp.Length)
+	}
+}
+
+func TestMakeRequestRedirect(t *testing.T) {
+	var tt = []struct {
+		testName string
+		status   int
+	}{
+		{"301", http.StatusMovedPermanently},
+		{"303", http.StatusSeeOther},
+		{"307", http.StatusTemporaryRedirect},
+		{"308", http.StatusPermanentRedirect},
+	}
+	for _, x := range tt {
+		t.Run(x.testName, func(t *testing.T) {
+			h := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/final" {
+					fmt.Fprint(w, "final")
+					return
+				}
+				http.Redirect(w, r, "/final", x.status)
+			}))
+			defer h.Close()
+
+			o := NewOptions()
+			c, err := newHTTPClient(context.Background(), o)
+			if err != nil {
+				t.Fatalf("Got Error: %v", err)
+			}
+			resp, err := c.makeRequest(http.MethodGet, h.URL+"/start", RequestOptions{})
+			if err != nil {
+				t.Fatalf("Got Error: %v", err)
+			}
+			if resp.StatusCode != x.status || resp.RedirectURL != h.URL+"/final" {
+				t.Fatalf("Invalid redirect returned: %d %q", resp.StatusCode, resp.RedirectURL)
+			}
+
+			o.FollowRedirect = true
+			o.ShowRedirectChain = true
+			c, err = newHTTPClient(context.Background(), o)
+			if err != nil {
+				t.Fatalf("Got Error: %v", err)
+			}
+			resp, err = c.makeRequest(http.MethodGet, h.URL+"/start", RequestOptions{})
+			if err != nil {
+				t.Fatalf("Got Error: %v", err)
+			}
+			if len(resp.RedirectChain) != 2 || resp.RedirectChain[1] != h.URL+"/final" {
+				t.Fatalf("Invalid redirect chain returned: %v", resp.RedirectChain)
+			}
+		})
+	}
+}
+
+func TestMakeRequestOptions(t *testing.T) {
+	h := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%s|%s|%s", r.UserAgent(), r.Header.Get("Cookie"), r.Header.Get("X-Test"))
+	}))
+	defer h.Close()
+	o := NewOptions()
+	o.UserAgent = "default-agent"
+	c, err := newHTTPClient(context.Background(), o)
+	if err != nil {
+		t.Fatalf("Got Error: %v", err)
+	}
+
+	var tt = []struct {
+		testName string
+		ro       RequestOptions
+		expected string
+	}{
+		{"Client defaults", RequestOptions{}, "default-agent||"},
+		{"Per request", RequestOptions{UserAgent: "random-agent", Cookies: "a=b", Headers: http.Header{"X-Test": {"1"}}}, "random-agent|a=b|1"},
+	}
+
+	for _, x := range tt {
+		t.Run(x.testName, func(t *testing.T) {
+			resp, err := c.makeRequest(http.MethodGet, h.URL, x.ro)
+			if err != nil {
+				t.Fatalf("Got Error: %v", err)
+			}
+			if resp.Content != x.expected {
+				t.Fatalf("Expected %q but got %q", x.expected, resp.Content)
+			}
+		})
+	}
+}
+
+func TestMakeRequestAPIHeaders(t *testing.T) {
+	t.Parallel()
+
+	var tt = []struct {
+		testName    string
+		body        string
+		contentType string
+	}{
+		{"Without body", "", ""},
+		{"With body", `{"a":1}`, "application/json"},
+	}
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			h := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, "%s|%s", r.Header.Get("Accept"), r.Header.Get("Content-Type"))
+			}))
+			defer h.Close()
+			o := NewOptions()
+			o.API = true
+			c, err := newHTTPClient(context.Background(), o)
+			if err != nil {
+				t.Fatalf("Got Error: %v", err)
+			}
+			resp, err := c.makeRequest(http.MethodPost, h.URL, RequestOptions{Body: x.body})
+			if err != nil {
+				t.Fatalf("Got Error: %v", err)
+			}
+			if expected := "application/json|" + x.contentType; resp.Content != expected {
+				t.Fatalf("Expected the headers %q but got %q", expected, resp.Content)
+			}
+		})
+	}
+}
+
+func TestDecodeBody(t *testing.T) {
+	t.Parallel()
+
+	var tt = []struct {
+		testName    string
+		body        []byte
+		contentType string
+		expected    string
+	}{
+		{"UTF-8", []byte("<title>Caf\xc3\xa9</title>"), "text/html; charset=utf-8", "<title>Café</title>"},
+		{"ISO-8859-1 header", []byte("<title>Caf\xe9</title>"), "text/html; charset=iso-8859-1", "<title>Café</title>"},
+		{"Meta tag", []byte(`<meta charset="iso-8859-1"><title>Caf` + "\xe9</title>"), "text/html", `<meta charset="iso-8859-1"><title>Café</title>`},
+		{"Shift_JIS", []byte("\x93\xfa\x96\x7b"), "text/plain; charset=shift_jis", "日本"},
+		{"Binary untouched", []byte("\xff\xd8\xff\xe0"), "image/jpeg", "\xff\xd8\xff\xe0"},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+			got := string(decodeBody(x.body, x.contentType))
+			if got != x.expected {
+				t.Fatalf("Expected %q but got %q", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestMakeRequestMaxResponseSize(t *testing.T) {
+	h := httpServer(t, strings.Repeat("a", 1000))
+	defer h.Close()
+
+	var tt = []struct {
+		testName          string
+		maxSize           int64
+		requestMaxSize    int64
+		expectedLength    int
+		expectedTruncated bool
+	}{
+		{"Unlimited", 0, 0, 1000, false},
+		{"Exact", 1000, 0, 1000, false},
+		{"Truncated", 100, 0, 100, true},
+		{"Request limit", 0, 10, 10, true},
+		{"Lower request limit", 100, 10, 10, true},
+		{"Higher request limit", 100, 500, 100, true},
+	}
+
+	for _, x := range tt {
+		t.Run(x.testName, func(t *testing.T) {
+			o := NewOptions()
+			o.MaxResponseSize = x.maxSize
+			c, err := newHTTPClient(context.Background(), o)
+			if err != nil {
+				t.Fatalf("Got Error: %v", err)
+			}
+			resp, err := c.makeRequest(http.MethodGet, h.URL, RequestOptions{MaxBodySize: x.requestMaxSize})
+			if err != nil {
+				t.Fatalf("Got Error: %v", err)
+			}
+			if len(resp.Content) != x.expectedLength {
+				t.Fatalf("Expected %d bytes but got %d", x.expectedLength, len(resp.Content))
+			}
+			if resp.Truncated != x.expectedTruncated {
+				t.Fatalf("Expected truncated %t but got %t", x.expectedTruncated, resp.Truncated)
+			}
+		})
+	}
+}
+
+func TestMakeRequestStreaming(t *testing.T) {
+	h := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/events":
+			// an event every 10ms, forever
+			w.Header().Set("Content-Type", "text/event-stream")
+			for {
+				if _, err := fmt.Fprint(w, "data: tick\n\n"); err != nil {
+					return
+				}
+				w.(http.Flusher).Flush()
+				select {
+				case <-r.Context().Done():
+					return
+				case <-time.After(10 * time.Millisecond):
+				}
+			}
+		case "/poll":
+			// a long poll answering nothing after its first bytes
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, "[")
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		case "/slow":
+			// a slow body never stalling for the stream timeout
+			for i := 0; i < 5; i++ {
+				fmt.Fprint(w, "a")
+				w.(http.Flusher).Flush()
+				time.Sleep(20 * time.Millisecond)
+			}
+		}
+	}))
+	defer h.Close()
+
+	var tt = []struct {
+		path              string
+		expectedStreaming bool
+		expectedContent   string
+	}{
+		{"/events", true, "data: tick"},
+		{"/poll", true, "["},
+		{"/slow", false, "aaaaa"},
+	}
+
+	for _, x := range tt {
+		t.Run(x.path, func(t *testing.T) {
+			o := NewOptions()
+			o.StreamTimeout = 100 * time.Millisecond
+			c, err := newHTTPClient(context.Background(), o)
+			if err != nil {
+				t.Fatalf("Got Error: %v", err)
+			}
+			start := time.Now()
+			resp, err := c.makeRequest(http.MethodGet, h.URL+x.path, RequestOptions{})
+			if err != nil {
+				t.Fatalf("Got Error: %v", err)
+			}
+			if resp.Streaming != x.expectedStreaming {
+				t.Fatalf("Expected streaming %t but got %t", x.expectedStreaming, resp.Streaming)
+			}
+			if !strings.HasPrefix(resp.Content, x.expectedContent) {
+				t.Fatalf("Expected %q first but got %q", x.expectedContent, resp.Content)
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Fatalf("Expected the body to be cut, took %s", elapsed)
+			}
+		})
+	}
+}
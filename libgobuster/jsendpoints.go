@@ -0,0 +1,136 @@
+package libgobuster
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// jsEndpointRegex matches the quoted urls, paths and routes of a script,
+// in the fashion of LinkFinder
+var jsEndpointRegex = regexp.MustCompile("[\"'`]" + `(` +
+	// absolute and protocol relative urls
+	`(?:https?:)?//[A-Za-z0-9.-]+\.[A-Za-z]{2,}(?::\d+)?(?:/[^"'` + "`" + `\s<>]*)?` +
+	// absolute and dot relative paths
+	`|(?:/|\.\./|\./)[A-Za-z0-9_\-~.%/]+(?:\?[^"'` + "`" + `\s<>]*)?` +
+	// relative paths to files of a known extension
+	`|[A-Za-z0-9_\-/.]+\.(?:php|asp|aspx|ashx|jsp|jspx|do|action|json|xml|txt|html|cgi|pl)(?:\?[^"'` + "`" + `\s<>]*)?` +
+	// relative routes of two segments or more
+	`|[A-Za-z0-9_\-]+/[A-Za-z0-9_\-/]{3,}(?:\?[^"'` + "`" + `\s<>]*)?` +
+	`)` + "[\"'`]")
+
+// mimeTypeRegex matches the media types a script mentions, they look
+// like relative routes
+var mimeTypeRegex = regexp.MustCompile(`^(?:application|text|image|audio|video|font|multipart|message|model)/[A-Za-z0-9.+\-]+$`)
+
+// ExtractJSEndpoints returns the urls, paths and routes mentioned by a
+// script, in order and without duplicates
+func ExtractJSEndpoints(content string) []string {
+	var endpoints []string
+	seen := NewSet[string]()
+	for _, m := range jsEndpointRegex.FindAllStringSubmatch(content, -1) {
+		e := m[1]
+		if mimeTypeRegex.MatchString(e) || e == "/" || e == "//" {
+			continue
+		}
+		if seen.Add(e) {
+			endpoints = append(endpoints, e)
+		}
+	}
+	return endpoints
+}
+
+// IsJavaScript reports whether a response is a script, by its url or
+// its content type
+func IsJavaScript(rawURL, contentType string) bool {
+	if strings.Contains(strings.ToLower(contentType), "javascript") {
+		return true
+	}
+	if u, err := url.Parse(rawURL); err == nil {
+		rawURL = u.Path
+	}
+	return strings.EqualFold(path.Ext(rawURL), ".js")
+}
+
+// resolveJSEndpoint returns the absolute url of an endpoint of a script,
+// paths relative to the page are relative to the application root
+func resolveJSEndpoint(endpoint, scriptURL, baseURL string) (*url.URL, error) {
+	base := baseURL
+	if strings.HasPrefix(endpoint, "/") || strings.HasPrefix(endpoint, "./") || strings.HasPrefix(endpoint, "../") {
+		base = scriptURL
+	}
+	b, err := url.Parse(base)
+	if err != nil {
+		return nil, err
+	}
+	return b.Parse(endpoint)
+}
+
+// jsEndpointFile records the endpoints extracted from the scripts of
+// the run
+type jsEndpointFile struct {
+	mu   sync.Mutex
+	path string
+	f    *os.File
+}
+
+func (j *jsEndpointFile) write(endpoint, script string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.f == nil {
+		if err := os.MkdirAll(path.Dir(j.path), 0755); err != nil {
+			return err
+		}
+		f, err := os.Create(j.path)
+		if err != nil {
+			return err
+		}
+		j.f = f
+	}
+	_, err := fmt.Fprintf(j.f, "%s\t%s\n", endpoint, script)
+	return err
+}
+
+func (j *jsEndpointFile) close() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.f != nil {
+		j.f.Close()
+	}
+}
+
+// AddJSEndpoints extracts the endpoints of a script found on the target,
+// with -js-endpoints. They are written to the output_jsendpoints folder
+// and the ones of the target's host are queued as url targets. It
+// returns the number of new endpoints queued.
+func (g *Gobuster) AddJSEndpoints(content, scriptURL string) int {
+	if g.jsEndpoints == nil {
+		return 0
+	}
+	base, err := url.Parse(g.BaseURL())
+	if err != nil {
+		return 0
+	}
+	added := 0
+	for _, e := range ExtractJSEndpoints(content) {
+		u, err := resolveJSEndpoint(e, scriptURL, g.BaseURL())
+		if err != nil {
+			continue
+		}
+		u.Fragment = ""
+		if err := g.jsEndpoints.write(u.String(), scriptURL); err != nil {
+			g.Logf("[!] failed to write js endpoints: %v", err)
+		}
+		if u.Host != base.Host || (u.Scheme != "http" && u.Scheme != "https") {
+			continue
+		}
+		if g.discovered.add(&BusterTarget{IsURL: true, Target: u.String()}) {
+			added++
+		}
+	}
+	return added
+}
@@ -0,0 +1,106 @@
+package libgobuster
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExtractJSEndpoints(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName string
+		content  string
+		expected []string
+	}{
+		{"Absolute path", `fetch("/api/v1/users?id=" + id)`, []string{"/api/v1/users?id="}},
+		{"Url", `const cdn = 'https://static.example.com/lib/app.js';`, []string{"https://static.example.com/lib/app.js"}},
+		{"Relative file", "axios.get(`reports/export.php`)", []string{"reports/export.php"}},
+		{"Relative route", `route: "admin/settings"`, []string{"admin/settings"}},
+		{"Media types", `headers: {"Content-Type": "application/json", accept: 'text/html'}`, nil},
+		{"Plain strings", `var msg = "hello world"; var sep = "/";`, nil},
+		{"Duplicates", `a("/x/y"); b("/x/y")`, []string{"/x/y"}},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			if got := ExtractJSEndpoints(x.content); !reflect.DeepEqual(got, x.expected) {
+				t.Fatalf("expected %v, got %v", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestIsJavaScript(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName    string
+		url         string
+		contentType string
+		expected    bool
+	}{
+		{"Extension", "http://example.com/static/app.JS?v=3", "", true},
+		{"Content type", "http://example.com/bundle", "application/javascript; charset=utf-8", true},
+		{"Page", "http://example.com/index.php", "text/html", false},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			if got := IsJavaScript(x.url, x.contentType); got != x.expected {
+				t.Fatalf("expected %t, got %t", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestAddJSEndpoints(t *testing.T) {
+	t.Parallel()
+
+	folder := t.TempDir()
+	o := NewOptions()
+	o.URL = "http://example.com/"
+	o.BasePath = "/app/"
+	g := &Gobuster{
+		Opts:        o,
+		discovered:  newTargetQueue(),
+		jsEndpoints: &jsEndpointFile{path: filepath.Join(folder, "output_jsendpoints", "jsendpoints.txt")},
+	}
+
+	script := "http://example.com/app/static/main.js"
+	content := `get("/api/items"); get("./chunk.json"); get("users/list"); get("https://other.com/x/y")`
+	if n := g.AddJSEndpoints(content, script); n != 3 {
+		t.Fatalf("expected 3 endpoints queued, got %d", n)
+	}
+	g.jsEndpoints.close()
+
+	var queued []string
+	for _, target := range g.discovered.take() {
+		if !target.IsURL {
+			t.Fatalf("expected an url target, got %+v", target)
+		}
+		queued = append(queued, target.Target)
+	}
+	expected := []string{"http://example.com/api/items", "http://example.com/app/static/chunk.json", "http://example.com/app/users/list"}
+	if !reflect.DeepEqual(queued, expected) {
+		t.Fatalf("expected %v, got %v", expected, queued)
+	}
+
+	written, err := ioutil.ReadFile(g.jsEndpoints.path)
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(written)), "\n")
+	if len(lines) != 4 || lines[3] != "https://other.com/x/y\t"+script {
+		t.Fatalf("unexpected endpoints file %q", written)
+	}
+}
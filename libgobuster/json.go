@@ -0,0 +1,62 @@
+package libgobuster
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// jsonSummaryFields are the top level fields REST backends typically
+// use to explain a response, in the order they are reported
+var jsonSummaryFields = []string{"error", "error_description", "message", "msg", "detail"}
+
+// jsonSummaryMaxLength caps the length of a single extracted value
+const jsonSummaryMaxLength = 100
+
+// IsJSON reports if the content is a JSON object or array
+func IsJSON(content string) bool {
+	trimmed := strings.TrimSpace(content)
+	if !strings.HasPrefix(trimmed, "{") && !strings.HasPrefix(trimmed, "[") {
+		return false
+	}
+	return json.Valid([]byte(trimmed))
+}
+
+// JSONSummary extracts the error and message like fields of a JSON
+// object into a short printable string
+func JSONSummary(content string) string {
+	var obj map[string]interface{}
+	if err := json.Unmarshal([]byte(content), &obj); err != nil {
+		return ""
+	}
+
+	var parts []string
+	for _, field := range jsonSummaryFields {
+		value, ok := obj[field]
+		if !ok {
+			continue
+		}
+		// {"error": {"message": "..."}}
+		if nested, ok := value.(map[string]interface{}); ok {
+			value = nested["message"]
+		}
+		var s string
+		switch v := value.(type) {
+		case string:
+			s = v
+		case nil:
+			continue
+		default:
+			s = fmt.Sprint(v)
+		}
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		if len(s) > jsonSummaryMaxLength {
+			s = s[:jsonSummaryMaxLength] + "..."
+		}
+		parts = append(parts, fmt.Sprintf("%s: %s", field, s))
+	}
+	return strings.Join(parts, "; ")
+}
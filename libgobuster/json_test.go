@@ -0,0 +1,49 @@
+package libgobuster
+
+import "testing"
+
+func TestIsJSON(t *testing.T) {
+	t.Parallel()
+
+	var tt = []struct {
+		testName string
+		content  string
+		expected bool
+	}{
+		{"Object", ` {"a": 1}`, true},
+		{"Array", `[1, 2]`, true},
+		{"HTML", `<html></html>`, false},
+		{"Invalid", `{"a": `, false},
+		{"Scalar", `"a"`, false},
+	}
+	for _, x := range tt {
+		t.Run(x.testName, func(t *testing.T) {
+			if IsJSON(x.content) != x.expected {
+				t.Fatalf("Expected %v for %q", x.expected, x.content)
+			}
+		})
+	}
+}
+
+func TestJSONSummary(t *testing.T) {
+	t.Parallel()
+
+	var tt = []struct {
+		testName string
+		content  string
+		expected string
+	}{
+		{"Error and message", `{"message": "denied", "error": "Forbidden"}`, "error: Forbidden; message: denied"},
+		{"Nested error", `{"error": {"code": 401, "message": "token expired"}}`, "error: token expired"},
+		{"Non string", `{"detail": 42}`, "detail: 42"},
+		{"No fields", `{"id": 1}`, ""},
+		{"Array", `[1]`, ""},
+	}
+	for _, x := range tt {
+		t.Run(x.testName, func(t *testing.T) {
+			if s := JSONSummary(x.content); s != x.expected {
+				t.Fatalf("Expected %q got %q", x.expected, s)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package libgobuster
+
+import (
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// learnTokenLimit is the number of tokens -learn recombines, every one
+// costs a request per word and separator
+const learnTokenLimit = 50
+
+// learnSeparators join the learned tokens and the words of the wordlist
+var learnSeparators = []string{"", "-", "_"}
+
+var learnSplitRegex = regexp.MustCompile(`[-_.\s]+`)
+
+// learnedTokens holds the tokens of the paths found by the first pass
+type learnedTokens struct {
+	mu     sync.Mutex
+	seen   Set[string]
+	tokens []string
+}
+
+func newLearnedTokens() *learnedTokens {
+	return &learnedTokens{seen: NewSet[string]()}
+}
+
+// splitCamel splits a word where a lower case letter is followed by an
+// upper case one
+func splitCamel(word string) []string {
+	var parts []string
+	runes := []rune(word)
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		if unicode.IsLower(runes[i-1]) && unicode.IsUpper(runes[i]) {
+			parts = append(parts, string(runes[start:i]))
+			start = i
+		}
+	}
+	return append(parts, string(runes[start:]))
+}
+
+// hasLetter reports whether the token has a letter, numbers alone are
+// not worth recombining
+func hasLetter(token string) bool {
+	return strings.IndexFunc(token, unicode.IsLetter) >= 0
+}
+
+// LearnTokens returns the components of a found path -learn recombines
+// with the wordlist: its directory names and file stem, then their parts
+// split on separators and case changes
+func LearnTokens(p string) []string {
+	var tokens []string
+	seen := NewSet[string]()
+	add := func(token string) {
+		if len(token) >= 3 && hasLetter(token) && seen.Add(token) {
+			tokens = append(tokens, token)
+		}
+	}
+	for _, segment := range strings.Split(strings.Trim(p, "/"), "/") {
+		stem := strings.TrimSuffix(segment, path.Ext(segment))
+		add(stem)
+		for _, part := range learnSplitRegex.Split(stem, -1) {
+			for _, camel := range splitCamel(part) {
+				add(camel)
+			}
+		}
+	}
+	return tokens
+}
+
+// Learn records the tokens of a path found by the first pass, with
+// -learn, and returns the number of new ones
+func (g *Gobuster) Learn(p string) int {
+	if g.learned == nil {
+		return 0
+	}
+	g.learned.mu.Lock()
+	defer g.learned.mu.Unlock()
+	added := 0
+	for _, token := range LearnTokens(p) {
+		if len(g.learned.tokens) == learnTokenLimit {
+			break
+		}
+		if g.learned.seen.Add(token) {
+			g.learned.tokens = append(g.learned.tokens, token)
+			added++
+		}
+	}
+	return added
+}
+
+// learnedCandidates returns the joins of the learned tokens before and
+// after a word of the wordlist
+func learnedCandidates(tokens []string, word string) []string {
+	var candidates []string
+	for _, token := range tokens {
+		if strings.EqualFold(token, word) {
+			continue
+		}
+		for _, sep := range learnSeparators {
+			candidates = append(candidates, token+sep+word, word+sep+token)
+		}
+	}
+	return candidates
+}
+
+// learnedTargets returns the candidates of a word expanded with the
+// extensions, without duplicates. Only the joins of two learned tokens
+// come back with another word of the wordlist, they alone are
+// remembered in pairs for the whole pass.
+func (g *Gobuster) learnedTargets(tokens []string, word string, pairs Set[string]) []string {
+	isToken := false
+	for _, token := range tokens {
+		if token == word {
+			isToken = true
+			break
+		}
+	}
+	seen := NewSet[string]()
+	var targets []string
+	for _, candidate := range learnedCandidates(tokens, word) {
+		for _, expanded := range expandExtToken(candidate, g.Opts.ExtToken, g.Opts.ExtensionsParsed, g.Opts.BlankExtension) {
+			if !seen.Add(expanded) || (isToken && !pairs.Add(expanded)) {
+				continue
+			}
+			targets = append(targets, expanded)
+		}
+	}
+	return targets
+}
+
+// sendLearned runs the second pass of -learn, the wordlist recombined
+// with the tokens of the paths found by the first one
+func (g *Gobuster) sendLearned(wordChan chan<- *BusterTarget) {
+	if g.learned == nil || g.context.Err() != nil {
+		return
+	}
+	g.waitInFlight()
+	g.learned.mu.Lock()
+	tokens := make([]string, len(g.learned.tokens))
+	copy(tokens, g.learned.tokens)
+	g.learned.mu.Unlock()
+	if len(tokens) == 0 {
+		return
+	}
+
+	wordlist, err := openWordlist(g.Opts.Wordlist)
+	if err != nil {
+		g.Logf("[!] failed to open wordlist: %v", err)
+		return
+	}
+	defer wordlist.Close()
+	g.Logf("Requesting the wordlist recombined with %d learned tokens: %s", len(tokens), strings.Join(tokens, ", "))
+
+	pairs := NewSet[string]()
+	scanner := g.newScanner(wordlist)
+	for scanner.Scan() {
+		if g.context.Err() != nil {
+			return
+		}
+		word := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(word, "#") || len(word) == 0 {
+			continue
+		}
+		targets := g.learnedTargets(tokens, word, pairs)
+		g.mu.Lock()
+		g.requestsExpected += len(targets)
+		g.mu.Unlock()
+		for _, t := range targets {
+			g.sendTarget(wordChan, &BusterTarget{Target: t})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		g.Logf("[!] failed to read the word list: %v", err)
+	}
+}
@@ -0,0 +1,88 @@
+package libgobuster
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLearnTokens(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		path     string
+		expected []string
+	}{
+		{"/admin", []string{"admin"}},
+		{"/acme-portal/backup_old.php", []string{"acme-portal", "acme", "portal", "backup_old", "backup", "old"}},
+		{"/UserAdmin.aspx", []string{"UserAdmin", "User", "Admin"}},
+		{"/v1/2021/", nil},
+		{"/api/v2/orders.json", []string{"api", "orders"}},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.path, func(t *testing.T) {
+			t.Parallel()
+
+			if got := LearnTokens(x.path); !reflect.DeepEqual(got, x.expected) {
+				t.Fatalf("expected %q, got %q", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestLearnedCandidates(t *testing.T) {
+	t.Parallel()
+
+	expected := []string{"acmeadmin", "adminacme", "acme-admin", "admin-acme", "acme_admin", "admin_acme"}
+	if got := learnedCandidates([]string{"acme", "Admin"}, "admin"); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestLearnedTargets(t *testing.T) {
+	t.Parallel()
+
+	o := NewOptions()
+	o.ExtToken = "%EXT%"
+	o.ExtensionsParsed = NewSet("php")
+	g := &Gobuster{Opts: o}
+	tokens := []string{"acme", "portal"}
+	pairs := NewSet[string]()
+
+	got := g.learnedTargets(tokens, "admin.%EXT%", pairs)
+	if len(got) != 12 || got[0] != "acmeadmin.php" || got[1] != "admin.phpacme" {
+		t.Fatalf("expected 12 targets, got %q", got)
+	}
+	if pairs.Len() != 0 {
+		t.Fatalf("expected no pair remembered for a word, got %d", pairs.Len())
+	}
+	// the joins of acme and portal are sent once, with the first of them
+	if got := g.learnedTargets(tokens, "acme", pairs); len(got) != 6 {
+		t.Fatalf("expected 6 targets, got %q", got)
+	}
+	if got := g.learnedTargets(tokens, "portal", pairs); len(got) != 0 {
+		t.Fatalf("expected the pairs sent already, got %q", got)
+	}
+}
+
+func TestLearnLimit(t *testing.T) {
+	t.Parallel()
+
+	g := &Gobuster{learned: newLearnedTokens()}
+	if added := g.Learn("/acme-portal"); added != 3 {
+		t.Fatalf("expected 3 tokens, got %d", added)
+	}
+	if added := g.Learn("/acme/portal"); added != 0 {
+		t.Fatalf("expected no new token, got %d", added)
+	}
+	for i := 0; i < learnTokenLimit; i++ {
+		g.Learn("/word" + string(rune('a'+i%26)) + string(rune('a'+i/26)))
+	}
+	if len(g.learned.tokens) != learnTokenLimit {
+		t.Fatalf("expected %d tokens, got %d", learnTokenLimit, len(g.learned.tokens))
+	}
+	if (&Gobuster{}).Learn("/admin") != 0 {
+		t.Fatal("expected nothing learned without -learn")
+	}
+}
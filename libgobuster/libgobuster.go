@@ -1,650 +1,1542 @@
-package libgobuster
-
-import (
-	"bufio"
-	"bytes"
-	"context"
-	"fmt"
-	"log"
-	"net"
-	"net/url"
-	"os"
-	"regexp"
-	"sort"
-	"strings"
-	"sync"
-	"time"
-)
-
-const (
-	// VERSION contains the current gobuster version
-	VERSION = "2.0.1"
-)
-
-// SetupFunc is the "setup" function prototype for implementations
-type SetupFunc func(*Gobuster) error
-
-// ProcessFunc is the "process" function prototype for implementations
-type ProcessFunc func(*Gobuster, string) ([]Result, error)
-
-// ResultToStringFunc is the "to string" function prototype for implementations
-type ResultToStringFunc func(*Gobuster, *Result) (*string, *string, int, error)
-
-// Gobuster is the main object when creating a new run
-type Gobuster struct {
-	Opts                          *Options
-	HTTP                          *httpClient
-	WildcardIps                   stringSet
-	context                       context.Context
-	requestsExpected              int
-	requestsIssued                int
-	mu                            *sync.RWMutex
-	plugin                        GobusterPlugin
-	IsWildcard                    bool
-	IsWildcardFileByContentLength bool
-	IsWildcardDirByContentLength  bool
-	WildcardFileContentLength     int
-	WildcardDirContentLength      int
-	IsWildcardFileByTitle         bool
-	IsWildcardDirByTitle          bool
-	WildcardFileTitle             string
-	WildcardDirTitle              string
-	WildcardStatusCode            *int
-	resultChan                    chan Result
-	errorChan                     chan error
-	errorCount                    int
-	waybackParsed                 string
-}
-
-// BusterTarget is target is the entity to be processed
-type BusterTarget struct {
-	IsURL  bool
-	Target string
-}
-
-// ParsedURL is used to store parsed urls
-type ParsedURL struct {
-	Host  string
-	Path  string
-	Query url.Values
-	URL   string
-}
-
-// GobusterPlugin is an interface which plugins must implement
-type GobusterPlugin interface {
-	Setup(*Gobuster) error
-	Process(*Gobuster, *BusterTarget) ([]Result, error)
-	ResultToString(*Gobuster, *Result) (*string, *string, int, error)
-}
-
-// NewGobuster returns a new Gobuster object
-func NewGobuster(c context.Context, opts *Options, plugin GobusterPlugin) (*Gobuster, error) {
-	// validate given options
-	multiErr := opts.validate()
-	if multiErr != nil {
-		return nil, multiErr
-	}
-
-	var g Gobuster
-	g.WildcardIps = newStringSet()
-	g.context = c
-	g.Opts = opts
-	h, err := newHTTPClient(c, opts)
-	if err != nil {
-		return nil, err
-	}
-	g.HTTP = h
-
-	g.plugin = plugin
-	g.mu = new(sync.RWMutex)
-
-	g.resultChan = make(chan Result)
-	g.errorChan = make(chan error)
-
-	return &g, nil
-}
-
-// Results returns a channel of Results
-func (g *Gobuster) Results() <-chan Result {
-	return g.resultChan
-}
-
-// Errors returns a channel of errors
-func (g *Gobuster) Errors() <-chan error {
-	return g.errorChan
-}
-
-func (g *Gobuster) incrementRequests() {
-	g.mu.Lock()
-	g.requestsIssued++
-	g.mu.Unlock()
-}
-
-// DecrementRequests decrements the requests issued
-func (g *Gobuster) DecrementRequests() {
-	g.mu.Lock()
-	if g.requestsIssued > 0 {
-		g.requestsIssued--
-	}
-	g.mu.Unlock()
-}
-
-// IncrementErrorCount increments the error count
-func (g *Gobuster) IncrementErrorCount() {
-	g.mu.Lock()
-	g.errorCount++
-	g.mu.Unlock()
-}
-
-// PrintProgress outputs the current wordlist progress to stderr
-func (g *Gobuster) PrintProgress() {
-	if !g.Opts.Quiet && !g.Opts.NoProgress {
-		g.mu.RLock()
-		if g.Opts.Wordlist == "-" {
-			fmt.Fprintf(os.Stderr, "\rProgress: %d", g.requestsIssued)
-			// only print status if we already read in the wordlist
-		} else if g.requestsExpected > 0 {
-			if !g.Opts.Verbose {
-				fmt.Fprintf(os.Stderr, "\rProgress: %d / %d (%3.2f%%)  |  Errors:  %d / %d (%3.2f%%)\r", g.requestsIssued, g.requestsExpected, float32(g.requestsIssued)*100.0/float32(g.requestsExpected), g.errorCount, g.requestsExpected, float32(g.errorCount)*100.0/float32(g.requestsExpected))
-			} else {
-				fmt.Fprintf(os.Stderr, "\rProgress: %d / %d (%3.2f%%)\r", g.requestsIssued, g.requestsExpected, float32(g.requestsIssued)*100.0/float32(g.requestsExpected))
-			}
-		}
-		g.mu.RUnlock()
-	}
-}
-
-// ClearProgress removes the last status line from stderr
-func (g *Gobuster) ClearProgress() {
-	fmt.Fprint(os.Stderr, resetTerminal())
-}
-
-// GetRequest issues a GET request to the target and returns
-// the status code, length and an error
-func (g *Gobuster) GetRequest(url string) (*int, *int64, *string, *string, error) {
-	return g.HTTP.makeRequest(url, g.Opts.Cookies)
-}
-
-// DNSLookup looks up a domain via system default DNS servers
-func (g *Gobuster) DNSLookup(domain string) ([]string, error) {
-	return net.LookupHost(domain)
-}
-
-// DNSLookupCname looks up a CNAME record via system default DNS servers
-func (g *Gobuster) DNSLookupCname(domain string) (string, error) {
-	return net.LookupCNAME(domain)
-}
-
-func (g *Gobuster) worker(wordChan <-chan *BusterTarget, wg *sync.WaitGroup) {
-	defer wg.Done()
-	for {
-		select {
-		case <-g.context.Done():
-			return
-		case busterTarget, ok := <-wordChan:
-			// worker finished
-			if !ok {
-				return
-			}
-			g.incrementRequests()
-			// Mode-specific processing
-			res, err := g.plugin.Process(g, busterTarget)
-			if err != nil {
-				// do not exit and continue
-				g.errorChan <- err
-				continue
-			} else {
-				for _, r := range res {
-					g.resultChan <- r
-				}
-			}
-		}
-	}
-}
-
-func (g *Gobuster) getWordlist() (*bufio.Scanner, error) {
-	if g.Opts.Wordlist == "-" {
-		// Read directly from stdin
-		return bufio.NewScanner(os.Stdin), nil
-	}
-	// Pull content from the wordlist
-	wordlist, err := os.Open(g.Opts.Wordlist)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open wordlist: %v", err)
-	}
-
-	wordExtensionScanner := bufio.NewScanner(wordlist)
-	wordExtensionCount := 0
-	lines := 0
-	for wordExtensionScanner.Scan() {
-		word := strings.TrimSpace(wordExtensionScanner.Text())
-		if word == "" {
-			continue
-		}
-		lines++
-		if strings.Contains(word, "%EXT%") {
-			wordExtensionCount++
-		}
-	}
-	if serr := wordExtensionScanner.Err(); serr != nil {
-		return nil, fmt.Errorf("failed to scan word list for extensions: %v", serr)
-	}
-
-	g.requestsIssued = 0
-	if g.Opts.BlankExtension {
-		g.requestsExpected = lines + wordExtensionCount*len(g.Opts.ExtensionsParsed.Set)
-	} else {
-		g.requestsExpected = lines + wordExtensionCount*len(g.Opts.ExtensionsParsed.Set) - wordExtensionCount
-	}
-
-	// rewind wordlist
-	_, err = wordlist.Seek(0, 0)
-	if err != nil {
-		return nil, fmt.Errorf("failed to rewind wordlist: %v", err)
-	}
-	return bufio.NewScanner(wordlist), nil
-}
-
-func (g *Gobuster) getWaybackUrls() (*bufio.Scanner, error) {
-	err := g.parseWaybackUrls()
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse wayback urls: %v", err)
-	}
-
-	waybackUrls, err := os.Open(g.waybackParsed)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open parsed wayback: %v", err)
-	}
-
-	scanner := bufio.NewScanner(waybackUrls)
-	lines := 0
-	for scanner.Scan() {
-		word := strings.TrimSpace(scanner.Text())
-		if word == "" {
-			continue
-		}
-		lines++
-	}
-	if serr := scanner.Err(); serr != nil {
-		return nil, fmt.Errorf("failed to scan parsed way back: %v", serr)
-	}
-
-	g.requestsExpected = lines
-	g.requestsIssued = 0
-
-	// rewind waybackurls
-	_, err = waybackUrls.Seek(0, 0)
-	if err != nil {
-		return nil, fmt.Errorf("failed to rewind parsed wayback: %v", err)
-	}
-	return bufio.NewScanner(waybackUrls), nil
-}
-
-func (g *Gobuster) parseWaybackUrls() error {
-
-	// log.Printf("fucken %s",g.Opts.OutputFolder)
-
-	waybackUrls, err := os.Open(g.Opts.WaybackUrls)
-	if err != nil {
-		return fmt.Errorf("failed to open wayback urls: %v", err)
-	}
-
-	// rewind waybackurls
-	_, err = waybackUrls.Seek(0, 0)
-	if err != nil {
-		return fmt.Errorf("failed to rewind wayback urls: %v", err)
-	}
-
-	scanner := bufio.NewScanner(waybackUrls)
-	var waybackLines []string
-	for scanner.Scan() {
-		waybackLines = append(waybackLines, scanner.Text())
-	}
-
-	log.Printf("Loading waybackurls file -> %s - Loaded %d", g.Opts.WaybackUrls, len(waybackLines))
-
-	if err := scanner.Err(); err != nil {
-		return fmt.Errorf("failed to scan wayback urls: %v", err)
-	}
-
-	sort.Strings(waybackLines)
-
-	
-
-	var parsedUrls []ParsedURL
-	for _, line := range waybackLines {
-		u, err := url.Parse(line)
-		host := ""
-		path := ""
-		query := new(url.Values)
-		if err != nil {
-			host = line
-		} else {
-			host = u.Host
-			path = u.Path
-			*query = u.Query()
-		}
-		// log.Printf("hostz: %d", host)BusterTarget
-
-
-
-		rgx := regexp.MustCompile(`(?i)[^/]+\.(jpg|jpeg|woff|woff2|ico|css|eot|pdf|ttf|gif|doc|docx|xls|xlsx|svg|csv|mp3|mp4|wma|ppt|png|pptx|swf).*`)
-		cleanPath := rgx.ReplaceAllString(path, "")
-		cleanURL := rgx.ReplaceAllString(line, "")
-
-		parsedUrls = append(parsedUrls, ParsedURL{
-			Host:  host,
-			Path:  cleanPath,
-			Query: *query,
-			URL:   cleanURL,
-		})
-	}
-
-	var uniqueParsedUrls []ParsedURL
-	for _, parsedURL := range parsedUrls {
-		if len(uniqueParsedUrls) == 0 {
-			uniqueParsedUrls = append(uniqueParsedUrls, parsedURL)
-			continue
-		}
-		isURLMatching := false
-		for _, value := range uniqueParsedUrls {
-			isQueryMatching := false
-			if value.Host == parsedURL.Host && value.Path == parsedURL.Path {
-				if len(parsedURL.Query) > 0 && len(value.Query) == len(parsedURL.Query) {
-					for parsedURLQueryKey := range parsedURL.Query {
-						if _, ok := value.Query[parsedURLQueryKey]; ok {
-							isQueryMatching = true
-						} else {
-							isQueryMatching = false
-							break
-						}
-					}
-				} else if len(value.Query) == 0 && len(parsedURL.Query) == 0 {
-					isQueryMatching = true
-				}
-			}
-
-			if isQueryMatching {
-				isURLMatching = true
-				break
-			}
-		}
-		if !isURLMatching {
-			uniqueParsedUrls = append(uniqueParsedUrls, parsedURL)
-		}
-	}
-
-	var uniqueUrls []string
-	for _, value := range uniqueParsedUrls {
-		uniqueUrls = append(uniqueUrls, value.URL)
-	}
-
-	log.Printf("Total unique URLs from wayback file parsed: %d", len(uniqueUrls))
-
-	filenameTimeStamp := int32(time.Now().Unix())
-	parsedMainURL, _ := url.Parse(g.Opts.URL)
-	sanitizedHost := strings.ReplaceAll(parsedMainURL.Host, ".", "_")
-	sanitizedHost = strings.ReplaceAll(sanitizedHost, ":", "_")
-	sanitizedPath := ""
-	if parsedMainURL.Path != "/" {
-		sanitizedPath = strings.TrimSuffix(parsedMainURL.Path, "/")
-		sanitizedPath = strings.ReplaceAll(sanitizedPath, "/", "_")
-	}
-
-	g.waybackParsed = fmt.Sprintf(g.Opts.OutputFolder + "/output_waybackurls/waybackurls_parsed_%d_%s_%s%s.txt", filenameTimeStamp, parsedMainURL.Scheme, sanitizedHost, sanitizedPath)
-	waybackUrlsParsed, err := os.Create(g.waybackParsed)
-	if err != nil {
-		return fmt.Errorf("failed to create wayback parsed: %v", err)
-	}
-	defer waybackUrlsParsed.Close()
-
-	writer := bufio.NewWriter(waybackUrlsParsed)
-	for _, line := range uniqueUrls {
-		fmt.Fprintln(writer, line)
-	}
-
-	if err := writer.Flush(); err != nil {
-		return fmt.Errorf("failed to write wayback urls: %v", err)
-	}
-
-	return nil
-}
-
-// Start the busting of the website with the given
-// set of settings from the command line.
-func (g *Gobuster) Start() error {
-	if err := g.plugin.Setup(g); err != nil {
-		return err
-	}
-
-	var workerGroup sync.WaitGroup
-	workerGroup.Add(g.Opts.Threads)
-
-	wordChan := make(chan *BusterTarget, g.Opts.Threads)
-
-	// Create goroutines for each of the number of threads
-	// specified.
-	for i := 0; i < g.Opts.Threads; i++ {
-		go g.worker(wordChan, &workerGroup)
-	}
-
-	if g.Opts.WaybackUrls != "" {
-		waybackScanner, err := g.getWaybackUrls()
-		if err != nil {
-			return err
-		}
-
-		log.Printf("Starting requesting waybackurls..")
-
-	WaybackScan:
-		for waybackScanner.Scan() {
-			select {
-			case <-g.context.Done():
-				break WaybackScan
-			default:
-				url := strings.TrimSpace(waybackScanner.Text())
-				// Skip "comment" (starts with #), as well as empty lines
-				if !strings.HasPrefix(url, "#") && len(url) > 0 {
-					busterTarget := &BusterTarget{
-						IsURL:  true,
-						Target: url,
-					}
-					wordChan <- busterTarget
-				}
-			}
-		}
-
-		time.Sleep(5 * time.Second)
-		log.Printf("waybackurls parsing and requesting done.")
-	}
-
-	log.Printf("Starting dictionary based brute-force..")
-
-	wordScanner, err := g.getWordlist()
-	if err != nil {
-		return err
-	}
-
-WordScan:
-	for wordScanner.Scan() {
-		select {
-		case <-g.context.Done():
-			break WordScan
-		default:
-			word := strings.TrimSpace(wordScanner.Text())
-			// Skip "comment" (starts with #), as well as empty lines
-			if !strings.HasPrefix(word, "#") && len(word) > 0 {
-				if strings.Contains(word, "%EXT%") {
-					if g.Opts.BlankExtension {
-						sanitizedWord := strings.ReplaceAll(word, ".%EXT%", "")
-						busterTarget := &BusterTarget{
-							IsURL:  false,
-							Target: sanitizedWord,
-						}
-						wordChan <- busterTarget
-					}
-					for ext := range g.Opts.ExtensionsParsed.Set {
-						wordWithExt := strings.ReplaceAll(word, "%EXT%", ext)
-						busterTarget := &BusterTarget{
-							IsURL:  false,
-							Target: wordWithExt,
-						}
-						wordChan <- busterTarget
-					}
-				} else {
-					busterTarget := &BusterTarget{
-						IsURL:  false,
-						Target: word,
-					}
-					wordChan <- busterTarget
-				}
-			}
-		}
-	}
-
-	close(wordChan)
-	workerGroup.Wait()
-	close(g.resultChan)
-	close(g.errorChan)
-	return nil
-}
-
-// GetConfigString returns the current config as a printable string
-func (g *Gobuster) GetConfigString() (string, error) {
-	buf := &bytes.Buffer{}
-	o := g.Opts
-	if _, err := fmt.Fprintf(buf, "[+] Mode                  : %s\n", o.Mode); err != nil {
-		return "", err
-	}
-	if _, err := fmt.Fprintf(buf, "[+] Url/Domain            : %s\n", o.URL); err != nil {
-		return "", err
-	}
-	if _, err := fmt.Fprintf(buf, "[+] Threads               : %d\n", o.Threads); err != nil {
-		return "", err
-	}
-
-	wordlist := "stdin (pipe)"
-	if o.Wordlist != "-" {
-		wordlist = o.Wordlist
-	}
-	if _, err := fmt.Fprintf(buf, "[+] Wordlist              : %s\n", wordlist); err != nil {
-		return "", err
-	}
-
-	if o.Mode == ModeDir {
-		if o.ExcludedStatusCodes != "" {
-			if _, err := fmt.Fprintf(buf, "[+] Excluded status codes : %s\n", o.ExcludedStatusCodesParsed.Stringify()); err != nil {
-				return "", err
-			}
-		}
-
-		if o.Proxy != "" {
-			if _, err := fmt.Fprintf(buf, "[+] Proxy                 : %s\n", o.Proxy); err != nil {
-				return "", err
-			}
-		}
-
-		if o.Cookies != "" {
-			if _, err := fmt.Fprintf(buf, "[+] Cookies               : %s\n", o.Cookies); err != nil {
-				return "", err
-			}
-		}
-
-		if o.UserAgent != "" {
-			if _, err := fmt.Fprintf(buf, "[+] User Agent            : %s\n", o.UserAgent); err != nil {
-				return "", err
-			}
-		}
-
-		if o.IncludeLength {
-			if _, err := fmt.Fprintf(buf, "[+] Show length           : true\n"); err != nil {
-				return "", err
-			}
-		}
-
-		if o.Username != "" {
-			if _, err := fmt.Fprintf(buf, "[+] Auth User             : %s\n", o.Username); err != nil {
-				return "", err
-			}
-		}
-
-		if len(o.Extensions) > 0 {
-			if _, err := fmt.Fprintf(buf, "[+] Extensions            : %s\n", o.ExtensionsParsed.Stringify()); err != nil {
-				return "", err
-			}
-		}
-
-		if o.UseSlash {
-			if _, err := fmt.Fprintf(buf, "[+] Add Slash             : true\n"); err != nil {
-				return "", err
-			}
-		}
-
-		if o.FollowRedirect {
-			if _, err := fmt.Fprintf(buf, "[+] Follow Redir          : true\n"); err != nil {
-				return "", err
-			}
-		}
-
-		if o.Expanded {
-			if _, err := fmt.Fprintf(buf, "[+] Expanded              : true\n"); err != nil {
-				return "", err
-			}
-		}
-
-		if o.NoStatus {
-			if _, err := fmt.Fprintf(buf, "[+] No status             : true\n"); err != nil {
-				return "", err
-			}
-		}
-
-		if o.Verbose {
-			if _, err := fmt.Fprintf(buf, "[+] Verbose               : true\n"); err != nil {
-				return "", err
-			}
-		}
-
-		if _, err := fmt.Fprintf(buf, "[+] Timeout               : %s\n", o.Timeout.String()); err != nil {
-			return "", err
-		}
-
-		if o.WaybackUrls != "" {
-			if _, err := fmt.Fprintf(buf, "[+] Wayback urls          : %s\n", o.WaybackUrls); err != nil {
-				return "", err
-			}
-		}
-
-		if o.RandomAgent != "" {
-			if _, err := fmt.Fprintf(buf, "[+] Random agent          : %s\n", o.RandomAgent); err != nil {
-				return "", err
-			}
-		}
-
-		if o.TargetUrls != "" {
-			if _, err := fmt.Fprintf(buf, "[+] Target urls           : %s\n", o.TargetUrls); err != nil {
-				return "", err
-			}
-		}
-
-		if o.ExcludeString != "" {
-			if _, err := fmt.Fprintf(buf, "[+] Exclude string         : %s\n", o.ExcludeString); err != nil {
-				return "", err
-			}
-		}
-
-		if o.BlankExtension {
-			if _, err := fmt.Fprintf(buf, "[+] Blank extension       : true\n"); err != nil {
-				return "", err
-			}
-		}
-
-
-		if o.OutputFolder != "" {
-			if _, err := fmt.Fprintf(buf, "[+] Output folder         : %s\n", o.OutputFolder); err != nil {
-				return "", err
-			}
-		}
-	}
-
-	return strings.TrimSpace(buf.String()), nil
-}
+package libgobuster
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gosirys/gobuster/libgobuster/urlnorm"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// VERSION contains the current gobuster version
+	VERSION = "2.0.1"
+)
+
+// SetupFunc is the "setup" function prototype for implementations
+type SetupFunc func(*Gobuster) error
+
+// ProcessFunc is the "process" function prototype for implementations
+type ProcessFunc func(*Gobuster, string) ([]Result, error)
+
+// ResultToStringFunc is the "to string" function prototype for implementations
+type ResultToStringFunc func(*Gobuster, *Result) (*string, *string, int, error)
+
+// Gobuster is the main object when creating a new run
+type Gobuster struct {
+	Opts                          *Options
+	HTTP                          *httpClient
+	WildcardIps                   Set[string]
+	context                       context.Context
+	cancel                        context.CancelFunc
+	requestsExpected              int
+	requestsIssued                int
+	mu                            *sync.RWMutex
+	plugin                        GobusterPlugin
+	IsWildcard                    bool
+	IsWildcardFileByContentLength bool
+	IsWildcardDirByContentLength  bool
+	WildcardFileContentLength     int
+	WildcardDirContentLength      int
+	IsWildcardFileByTitle         bool
+	IsWildcardDirByTitle          bool
+	WildcardFileTitle             string
+	WildcardDirTitle              string
+	WildcardStatusCode            *int
+	WildcardExtensions            map[string]*WildcardBaseline
+	// WildcardFileBaseline and WildcardDirBaseline are the baselines of
+	// the paths without an extension
+	WildcardFileBaseline          *WildcardBaseline
+	WildcardDirBaseline           *WildcardBaseline
+	// HostBaseline is the response to unknown hosts of -host-fuzz
+	HostBaseline                  *WildcardBaseline
+	// FaviconHash is the Shodan favicon hash of the target, if any
+	FaviconHash                   *int32
+	// Technologies are fingerprinted from the target's base page
+	Technologies                  []Technology
+	// WAF is the CDN or WAF detected in front of the target
+	WAF                           string
+	// wafBlocks counts the block pages of the WAF answered to words
+	wafBlocks                     int
+	resultChan                    chan Result
+	errorChan                     chan error
+	errorCount                    int
+	// serverErrors are the 5xx responses counted in errorCount, unlike
+	// the other errors they answered a request
+	serverErrors int
+	// errorCategories counts the errors by ClassifyError category
+	errorCategories               map[string]int
+	matchCount                    int
+	waybackParsed                 string
+	hook                          *resultHook
+	budget                        *errorBudget
+	aborted                       bool
+	startTime                     time.Time
+	groups                        resultGroups
+	requestIDs                    uint32
+	countingWordlist              bool
+	resolver                      *dnsResolver
+	extensionHits                 map[string]int
+	foundHosts                    []string
+	discovered                    *targetQueue
+	// priorityDirs are the directories answering -prioritize-codes
+	priorityDirs                  Set[string]
+	// learned holds the tokens of the matches recombined by -learn
+	learned                       *learnedTokens
+	// words feeds the workers once the scan started
+	words                         chan *BusterTarget
+	// inFlight counts the targets sent to the workers and not processed
+	inFlight                      sync.WaitGroup
+	jsEndpoints                   *jsEndpointFile
+	agents                        *agentPicker
+	sink                          *logSink
+	stream                        *resultStream
+	warmup                        *warmup
+	backoff                       *backoff
+	extensions                    []*Extension
+	openAPI                       *apiInventory
+	robots                        *RobotsRules
+	oob                           *oobClient
+	oobCallbacks                  []OOBCallback
+	// uploads are the directories accepting the PUT of -upload-check,
+	// uploadsChecked the directories checked
+	uploads                       []Upload
+	uploadsChecked                Set[string]
+	// artifactBytes are the bytes saved by -fetch-artifacts
+	artifactBytes                 int64
+	// verifier holds the matches back for -verify-hits
+	verifier                      *hitVerifier
+	// pruner stops the extensions without hits for -prune-exts
+	pruner                        *extPruner
+	robotsSkipped                 int
+	safeModeSkipped               int
+	tor                           *torController
+	timedOut                      bool
+	// fingerprint caches the ProfileFingerprint of the options, the
+	// provenance of every all time matches line carries it
+	fingerprintOnce sync.Once
+	fingerprint     string
+	// setupOnce runs the setup of the mode once, from Setup or Start
+	setupOnce sync.Once
+	setupErr  error
+	// Progress draws the progress of the scan, along with the other
+	// targets of the run
+	Progress                      *ProgressRenderer
+	// RunID identifies the scan in the file names, the all time
+	// matches, the summary and the streamed results
+	RunID                         string
+}
+
+// BusterTarget is target is the entity to be processed
+type BusterTarget struct {
+	IsURL   bool
+	Target  string
+	retries int
+	// ext is the extension the word was expanded with, for -prune-exts
+	ext string
+}
+
+// GobusterPlugin is an interface which plugins must implement
+type GobusterPlugin interface {
+	Setup(*Gobuster) error
+	Process(*Gobuster, *BusterTarget) ([]Result, error)
+	ResultToString(*Gobuster, *Result) (*string, *string, int, error)
+}
+
+// NewGobuster returns a new Gobuster object
+func NewGobuster(c context.Context, opts *Options, plugin GobusterPlugin) (*Gobuster, error) {
+	// validate given options
+	multiErr := opts.validate()
+	if multiErr != nil {
+		return nil, multiErr
+	}
+
+	var g Gobuster
+	g.WildcardIps = NewSet[string]()
+	g.WildcardExtensions = make(map[string]*WildcardBaseline)
+	if opts.PerTargetTimeout > 0 {
+		// the clock of the timeout stops while the scan is paused
+		ctx, cancel := context.WithCancelCause(c)
+		g.context, g.cancel = ctx, func() { cancel(nil) }
+		go expireAfter(ctx, cancel, opts.PerTargetTimeout, opts.Pause)
+	} else {
+		g.context, g.cancel = context.WithCancel(c)
+	}
+	if opts.Rand == nil {
+		opts.Rand = NewRand(time.Now().UnixNano())
+	}
+	g.Opts = opts
+	g.startTime = g.Now()
+	g.RunID = uuid.New().String()
+	g.groups = newResultGroups()
+	g.extensionHits = make(map[string]int)
+	g.discovered = newTargetQueue()
+	if opts.PrioritizeCodesParsed.Len() > 0 && opts.Mode == ModeDir {
+		g.priorityDirs = NewSet[string]()
+	}
+	if opts.Learn && opts.Mode == ModeDir {
+		g.learned = newLearnedTokens()
+	}
+	if opts.OpenAPI && opts.Mode == ModeDir {
+		g.openAPI = newAPIInventory()
+	}
+	if opts.VerifyHits > 0 && opts.Mode == ModeDir {
+		g.verifier = &hitVerifier{}
+	}
+	if opts.PruneExts && opts.Mode == ModeDir && opts.ExtensionsParsed.Len() > 0 {
+		g.pruner = newExtPruner()
+	}
+	if opts.JSEndpoints {
+		g.jsEndpoints = &jsEndpointFile{path: fmt.Sprintf("%s/output_jsendpoints/jsendpoints_%s.txt", opts.OutputFolder, g.OutputFileSuffix())}
+	}
+	h, err := newHTTPClient(g.context, opts)
+	if err != nil {
+		return nil, err
+	}
+	g.HTTP = h
+	g.resolver = newDNSResolver(opts)
+
+	g.plugin = plugin
+	g.mu = new(sync.RWMutex)
+
+	if opts.Plugins != "" {
+		exts, err := loadExtensions(opts.Plugins)
+		if err != nil {
+			return nil, err
+		}
+		g.extensions = exts
+	}
+
+	if opts.OnResult != "" {
+		hook, err := newResultHook(opts.OnResult, opts.OnResultConcurrency)
+		if err != nil {
+			return nil, err
+		}
+		g.hook = hook
+		g.hook.logger = opts.logger()
+	}
+
+	if opts.Syslog != "" {
+		sink, err := newLogSink(opts.Syslog, opts.clock())
+		if err != nil {
+			return nil, err
+		}
+		g.sink = sink
+	}
+
+	if opts.ResultsSocket != "" {
+		stream, err := openResultStream(opts.ResultsSocket)
+		if err != nil {
+			return nil, err
+		}
+		g.stream = stream
+	}
+
+	if len(opts.RandomAgentParsed) > 0 {
+		g.agents = newAgentPicker(opts.AgentStrategy, opts.RandomAgentParsed, opts.Rand)
+	}
+
+	if opts.Tor && (opts.TorRenewRequests > 0 || opts.TorRenewEvery > 0) {
+		g.tor = newTorController(opts)
+		if opts.TorRenewEvery > 0 {
+			go g.tor.renewEvery(g.context, opts.TorRenewEvery)
+		}
+	}
+
+	if opts.ConnRecycle > 0 {
+		go g.HTTP.recycleEvery(g.context, opts.ConnRecycle)
+	}
+
+	if opts.Warmup > 0 {
+		g.warmup = newWarmup(opts.Warmup, opts.WarmupThreads, opts.Threads)
+		g.warmup.logger = opts.logger()
+	}
+
+	if opts.Mode == ModeDir && !opts.NoBackoff {
+		g.backoff = &backoff{logger: opts.logger()}
+	}
+
+	if opts.ErrorThreshold != "" {
+		g.budget = newErrorBudget(opts.ErrorWindow, opts.ErrorThresholdParsed)
+	}
+
+	// a single -debug-http file per scan, closed once Start returns
+	if opts.DebugHTTP != "" {
+		debugLog, err := newHTTPDebugLog(opts.DebugHTTP, opts.clock())
+		if err != nil {
+			return nil, err
+		}
+		h.debugLog = debugLog
+	}
+
+	// a worker hands over its results without waiting for a slow consumer
+	g.resultChan = make(chan Result, opts.Threads)
+	g.errorChan = make(chan error, opts.Threads)
+
+	// the sockets are opened last so no error leaves them open, they
+	// are closed when Start returns or the scan is cancelled without
+	// having started
+	if opts.FastDNS {
+		fast, err := newFastResolver(opts.ResolversParsed, opts.Rand)
+		if err != nil {
+			return nil, err
+		}
+		g.resolver.fast = fast
+		go func() {
+			<-g.context.Done()
+			fast.close()
+		}()
+	}
+
+	return &g, nil
+}
+
+// Results returns a channel of Results. It has to be read until Start
+// closes it, the last results of a cancelled scan included.
+func (g *Gobuster) Results() <-chan Result {
+	return g.resultChan
+}
+
+// Errors returns a channel of errors. It has to be read until Start
+// closes it.
+func (g *Gobuster) Errors() <-chan error {
+	return g.errorChan
+}
+
+func (g *Gobuster) incrementRequests() {
+	g.mu.Lock()
+	g.requestsIssued++
+	g.mu.Unlock()
+}
+
+// DecrementRequests decrements the requests issued
+func (g *Gobuster) DecrementRequests() {
+	g.mu.Lock()
+	if g.requestsIssued > 0 {
+		g.requestsIssued--
+	}
+	g.mu.Unlock()
+}
+
+// IncrementErrorCount increments the error count
+func (g *Gobuster) IncrementErrorCount() {
+	g.mu.Lock()
+	g.errorCount++
+	g.countError(ErrorOther)
+	g.mu.Unlock()
+}
+
+// RecordError increments the error count and the count of the category
+// of the error
+func (g *Gobuster) RecordError(err error) {
+	g.mu.Lock()
+	g.errorCount++
+	g.countError(ClassifyError(err))
+	g.mu.Unlock()
+}
+
+// RecordMatch counts the match and records it for the summary
+func (g *Gobuster) RecordMatch(r *Result) {
+	g.mu.Lock()
+	g.matchCount++
+	g.groups.add(r)
+	if g.Opts.Mode == ModeDir {
+		ext := r.Extension()
+		if ext == "" {
+			ext = blankExtension
+		}
+		g.extensionHits[ext]++
+		if g.openAPI != nil {
+			g.openAPI.add(r.AppPath(g), r)
+		}
+	}
+	if g.Opts.Mode == ModeDNS {
+		g.foundHosts = append(g.foundHosts, r.Entity)
+	}
+	g.mu.Unlock()
+	if g.Opts.Mode == ModeDNS && g.Opts.FoundHost != nil {
+		g.Opts.FoundHost(r.Entity)
+	}
+}
+
+// FoundHosts returns the subdomains found by a dns mode scan
+func (g *Gobuster) FoundHosts() []string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	hosts := make([]string, len(g.foundHosts))
+	copy(hosts, g.foundHosts)
+	return hosts
+}
+
+// MatchCount returns the number of matches found so far
+func (g *Gobuster) MatchCount() int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.matchCount
+}
+
+// progressLine returns the wordlist progress of the scan, empty until
+// the number of requests is known
+func (g *Gobuster) progressLine() string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	categories := ""
+	if len(g.errorCategories) > 0 {
+		categories = fmt.Sprintf(" [%s]", formatErrorCategories(g.errorCategories))
+	}
+	if g.Opts.Wordlist == "-" {
+		return fmt.Sprintf("Progress: %d", g.requestsIssued)
+	} else if g.countingWordlist {
+		// the wordlist is still being counted
+		return fmt.Sprintf("Progress: %d / %d+  |  Errors:  %d%s", g.requestsIssued, g.requestsExpected, g.errorCount, categories)
+	} else if g.requestsExpected > 0 {
+		// only print status if we already read in the wordlist
+		if !g.Opts.Verbose {
+			return fmt.Sprintf("Progress: %d / %d (%3.2f%%)  |  Errors:  %d / %d (%3.2f%%)%s", g.requestsIssued, g.requestsExpected, float32(g.requestsIssued)*100.0/float32(g.requestsExpected), g.errorCount, g.requestsExpected, float32(g.errorCount)*100.0/float32(g.requestsExpected), categories)
+		}
+		return fmt.Sprintf("Progress: %d / %d (%3.2f%%)", g.requestsIssued, g.requestsExpected, float32(g.requestsIssued)*100.0/float32(g.requestsExpected))
+	}
+	return ""
+}
+
+// PrintProgress outputs the current wordlist progress to stderr, through
+// the progress renderer of the run if there is one
+func (g *Gobuster) PrintProgress() {
+	if g.Opts.Quiet || g.Opts.NoProgress {
+		return
+	}
+	if g.Progress != nil {
+		g.Progress.Render()
+		return
+	}
+	if line := g.progressLine(); line != "" {
+		fmt.Fprintf(os.Stderr, "\r%s\r", line)
+	}
+}
+
+// ClearProgress removes the last status lines from stderr
+func (g *Gobuster) ClearProgress() {
+	if g.Progress != nil {
+		g.Progress.Clear()
+		return
+	}
+	fmt.Fprint(os.Stderr, resetTerminal())
+}
+
+// NewRequestOptions returns the options of a new request to the url,
+// with a fresh request id and the random agent picked for it
+func (g *Gobuster) NewRequestOptions(url string) RequestOptions {
+	return RequestOptions{
+		RequestID: g.NewRequestID(),
+		UserAgent: g.RandomAgent(url),
+		Cookies:   g.Opts.Cookies,
+	}
+}
+
+// GetRequest issues a GET request to the target and returns
+// the response and an error
+func (g *Gobuster) GetRequest(url string, ro RequestOptions) (*Response, error) {
+	return g.HTTP.makeRequest(http.MethodGet, url, ro)
+}
+
+// HeadRequest issues a HEAD request to the target and returns
+// the response and an error
+func (g *Gobuster) HeadRequest(url string, ro RequestOptions) (*Response, error) {
+	return g.HTTP.makeRequest(http.MethodHead, url, ro)
+}
+
+// DNSLookup looks up a domain via system default DNS servers, errors
+// are of type *DNSLookupError
+func (g *Gobuster) DNSLookup(domain string) ([]string, error) {
+	return g.resolver.lookupHost(g.context, domain)
+}
+
+// DNSLookupCname looks up a CNAME record via system default DNS servers
+func (g *Gobuster) DNSLookupCname(domain string) (string, error) {
+	return g.resolver.lookupCNAME(g.context, domain)
+}
+
+// worker processes the targets until the queue is closed. Once the scan
+// is cancelled the remaining targets are drained without a request, so
+// the queue never fills up with nobody reading it.
+func (g *Gobuster) worker(wordChan chan *BusterTarget, wg *sync.WaitGroup) {
+	defer wg.Done()
+	for busterTarget := range wordChan {
+		if g.context.Err() != nil {
+			g.inFlight.Done()
+			continue
+		}
+		if g.Opts.Pause != nil {
+			g.Opts.Pause.wait(g.context)
+		}
+		// the target asked to retry later
+		if g.backoff != nil {
+			g.backoff.wait(g.context, g.Opts.clock())
+		}
+		// the first requests are sent over few threads only
+		warmingUp := g.warmup != nil && g.warmup.acquire(g.context)
+		start := time.Now()
+		g.incrementRequests()
+		if g.tor != nil {
+			g.tor.request()
+		}
+		// Mode-specific processing
+		res, err := g.process(busterTarget)
+		if warmingUp {
+			g.warmup.release(time.Since(start), err != nil)
+		}
+		if err == nil && g.retryLater(wordChan, busterTarget, res) {
+			g.inFlight.Done()
+			continue
+		}
+		if g.budget != nil {
+			if rate, exceeded := g.budget.record(err != nil); exceeded {
+				g.abort(rate)
+			}
+		}
+		if err != nil {
+			// the errors of the requests cut short by the
+			// cancellation are not reported
+			if g.context.Err() == nil {
+				g.errorChan <- err
+			}
+		} else {
+			g.countServerErrors(res)
+			res = g.filter(res)
+			g.recordExtension(busterTarget, res)
+			g.recordPriorityDirs(res)
+			for _, r := range res {
+				if !g.holdHit(r) {
+					g.resultChan <- r
+				}
+			}
+		}
+		g.inFlight.Done()
+	}
+}
+
+// abort cancels the scan once the error budget has been exceeded
+func (g *Gobuster) abort(rate float64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.aborted {
+		return
+	}
+	g.aborted = true
+	g.Logf("[!] Error rate %3.2f%% over the last %d requests exceeds the threshold of %3.2f%%, aborting", rate, g.Opts.ErrorWindow, g.Opts.ErrorThresholdParsed)
+	g.cancel()
+}
+
+// sendTarget queues the target unless the scan has been cancelled
+func (g *Gobuster) sendTarget(wordChan chan<- *BusterTarget, busterTarget *BusterTarget) {
+	g.inFlight.Add(1)
+	select {
+	case <-g.context.Done():
+		g.inFlight.Done()
+	case wordChan <- busterTarget:
+	}
+}
+
+// newScanner returns a line scanner accepting lines up to the
+// configured wordlist buffer size
+func (g *Gobuster) newScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), g.Opts.WordlistBufferSize)
+	return scanner
+}
+
+// wordWeight returns the number of requests a word expands to
+func (g *Gobuster) wordWeight(word string) int {
+	if !strings.Contains(word, g.Opts.ExtToken) {
+		return 1
+	}
+	weight := g.Opts.ExtensionsParsed.Len()
+	if g.Opts.BlankExtension {
+		weight++
+	}
+	return weight
+}
+
+func (g *Gobuster) getWordlist(list scanWordlist) (*bufio.Scanner, error) {
+	if list.path == "-" {
+		// Read directly from stdin
+		return g.newScanner(os.Stdin), nil
+	}
+	// Pull content from the wordlist
+	wordlist, err := openWordlist(list.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %v", list.name, err)
+	}
+	return g.newScanner(wordlist), nil
+}
+
+// countWordlists counts the requests of the wordlists in the background
+// through second handles so huge wordlists start right away, progress
+// is updated as we go
+func (g *Gobuster) countWordlists(lists []scanWordlist) error {
+	if g.Opts.Wordlist == "-" {
+		return nil
+	}
+	var counters []io.ReadCloser
+	for _, list := range lists {
+		counter, err := openWordlist(list.path)
+		if err != nil {
+			for _, c := range counters {
+				c.Close()
+			}
+			return fmt.Errorf("failed to open %s: %v", list.name, err)
+		}
+		counters = append(counters, counter)
+	}
+
+	g.mu.Lock()
+	g.requestsIssued = 0
+	g.requestsExpected = 0
+	g.countingWordlist = true
+	g.mu.Unlock()
+
+	go g.countWordlist(lists, counters)
+	return nil
+}
+
+// countWordlistBatch is the number of lines counted between updates
+// of the expected requests
+const countWordlistBatch = 10000
+
+func (g *Gobuster) countWordlist(lists []scanWordlist, counters []io.ReadCloser) {
+	defer func() {
+		for _, c := range counters {
+			c.Close()
+		}
+	}()
+	expected := 0
+	lines := 0
+	for i, list := range lists {
+		listExpected := 0
+		scanner := g.newScanner(counters[i])
+		for scanner.Scan() {
+			word := strings.TrimSpace(scanner.Text())
+			if !isWord(word) {
+				continue
+			}
+			weight := list.weight(word)
+			listExpected += weight
+			expected += weight
+			lines++
+			if lines%countWordlistBatch == 0 {
+				if g.context.Err() != nil {
+					return
+				}
+				g.mu.Lock()
+				g.requestsExpected = expected
+				g.mu.Unlock()
+			}
+		}
+		if serr := scanner.Err(); serr != nil {
+			g.Logf("[!] failed to count the %s: %v", list.name, serr)
+		}
+		// the directories and files are counted apart
+		if len(lists) > 1 {
+			g.Logf("[+] Counted %d requests of the %s %s", listExpected, list.name, list.path)
+		}
+	}
+
+	g.mu.Lock()
+	g.requestsExpected = expected
+	g.countingWordlist = false
+	g.mu.Unlock()
+}
+
+func (g *Gobuster) getWaybackUrls() (*bufio.Scanner, error) {
+	lines, err := g.parseWaybackUrls()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse wayback urls: %v", err)
+	}
+
+	waybackUrls, err := os.Open(g.waybackParsed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open parsed wayback: %v", err)
+	}
+
+	g.requestsExpected = lines
+	g.requestsIssued = 0
+	return g.newScanner(waybackUrls), nil
+}
+
+// parseWaybackUrls writes the unique urls of the wayback file and of
+// Common Crawl, sorted, to the parsed file and returns their number. The
+// files are streamed, only one url of every endpoint is kept in memory.
+func (g *Gobuster) parseWaybackUrls() (int, error) {
+	normalizer, err := urlnorm.New(urlnorm.Options{
+		StripExtensions: g.Opts.WaybackStripExtsParsed,
+		Query:           urlnorm.QueryStrategy(g.Opts.WaybackQuery),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var sources []string
+	if g.Opts.WaybackUrls != "" {
+		sources = append(sources, g.Opts.WaybackUrls)
+	}
+	if g.Opts.CommonCrawl {
+		commonCrawlUrls, err := g.fetchCommonCrawl()
+		if err != nil {
+			return 0, err
+		}
+		sources = append(sources, commonCrawlUrls)
+	}
+
+	g.waybackParsed = fmt.Sprintf("%s/output_waybackurls/waybackurls_parsed_%s.txt", g.Opts.OutputFolder, g.OutputFileSuffix())
+	waybackUrlsParsed, err := os.Create(g.waybackParsed)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create wayback parsed: %v", err)
+	}
+	defer waybackUrlsParsed.Close()
+	writer := bufio.NewWriter(waybackUrlsParsed)
+
+	seen := normalizer.NewSeen()
+	outside := 0
+	for _, source := range sources {
+		loaded, err := g.parseUrlsFile(source, seen, &outside)
+		if err != nil {
+			return 0, err
+		}
+		g.Logf("Loading waybackurls file -> %s - Loaded %d", source, loaded)
+	}
+	// the urls are requested in the order of their lines
+	for _, u := range seen.Sorted() {
+		fmt.Fprintln(writer, u)
+	}
+	if err := writer.Flush(); err != nil {
+		return 0, fmt.Errorf("failed to write wayback urls: %v", err)
+	}
+
+	if outside > 0 {
+		g.Logf("Skipped %d wayback urls outside of the base path %s", outside, g.Opts.BasePath)
+	}
+	g.Logf("Total unique URLs from wayback file parsed: %d", seen.Len())
+	return seen.Len(), nil
+}
+
+// parseUrlsFile adds the urls of a file to seen and returns the number
+// of urls loaded, the urls outside of the base path are counted in
+// outside
+func (g *Gobuster) parseUrlsFile(path string, seen *urlnorm.Seen, outside *int) (int, error) {
+	urls, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open wayback urls: %v", err)
+	}
+	defer urls.Close()
+
+	loaded := 0
+	scanner := g.newScanner(urls)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 {
+			continue
+		}
+		loaded++
+		// the urls of other applications of the host are not mapped
+		if !g.InBasePath(line) {
+			*outside++
+			continue
+		}
+		seen.Add(line)
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("failed to scan wayback urls: %v", err)
+	}
+	return loaded, nil
+}
+
+// BaseURL returns the url of the application root, the target url
+// followed by the base path
+func (g *Gobuster) BaseURL() string {
+	if g.Opts.BasePath == "" {
+		return g.Opts.URL
+	}
+	return JoinURL(g.Opts.URL, g.Opts.BasePath)
+}
+
+// InBasePath reports whether an absolute url is below the base path
+func (g *Gobuster) InBasePath(rawURL string) bool {
+	if g.Opts.BasePath == "" {
+		return true
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(u.Path+"/", g.Opts.BasePath)
+}
+
+// OutputFileSuffix returns the run timestamp, the sanitized target and
+// the run ID used to name the files created in the output folder, so
+// concurrent scans of the same target never write to the same file
+func (g *Gobuster) OutputFileSuffix() string {
+	if g.Opts.Mode == ModeDNS {
+		return fmt.Sprintf("%d_%s_%s", g.startTime.Unix(), strings.ReplaceAll(g.Opts.URL, ".", "_"), g.RunID)
+	}
+
+	parsedMainURL, _ := url.Parse(g.BaseURL())
+	sanitizedHost := sanitizedHost(parsedMainURL)
+	sanitizedPath := ""
+	if parsedMainURL.Path != "/" {
+		sanitizedPath = strings.TrimSuffix(parsedMainURL.Path, "/")
+		sanitizedPath = strings.ReplaceAll(sanitizedPath, "/", "_")
+	}
+	return fmt.Sprintf("%d_%s_%s%s_%s", g.startTime.Unix(), parsedMainURL.Scheme, sanitizedHost, sanitizedPath, g.RunID)
+}
+
+// ErrTargetTimeout is returned by Start when the scan of the target
+// did not finish within the per target timeout
+var ErrTargetTimeout = errors.New("per target timeout exceeded")
+
+// Setup runs the setup of the mode, the wildcard calibration and the
+// fingerprinting of the target. Start runs it unless it was called
+// before, which shows its findings in the config.
+func (g *Gobuster) Setup() error {
+	g.setupOnce.Do(func() {
+		g.setupErr = g.plugin.Setup(g)
+	})
+	return g.setupErr
+}
+
+// Start the busting of the website with the given
+// set of settings from the command line.
+func (g *Gobuster) Start() error {
+	// the background work of the scan, such as the circuit renewal and
+	// the connection recycling, ends with it
+	defer g.cancel()
+	defer g.resolver.close()
+	if g.jsEndpoints != nil {
+		defer g.jsEndpoints.close()
+	}
+	if g.HTTP.debugLog != nil {
+		defer g.HTTP.debugLog.close()
+	}
+	// the consumers of the output channels always see them closed, the
+	// scan failed or not
+	defer close(g.errorChan)
+	defer close(g.resultChan)
+	if err := g.Setup(); err != nil {
+		if context.Cause(g.context) == context.DeadlineExceeded {
+			return g.targetTimedOut()
+		}
+		return err
+	}
+	if g.Opts.OOBServer != "" && g.Opts.Mode == ModeDir {
+		if err := g.startOOB(); err != nil {
+			return err
+		}
+	}
+
+	var workerGroup sync.WaitGroup
+	workerGroup.Add(g.Opts.Threads)
+
+	wordChan := make(chan *BusterTarget, g.Opts.Threads)
+	g.mu.Lock()
+	g.words = wordChan
+	g.mu.Unlock()
+	if g.Opts.DebugStats > 0 {
+		go g.logStatsEvery(g.context, wordChan, g.Opts.DebugStats)
+	}
+
+	// Create goroutines for each of the number of threads
+	// specified.
+	for i := 0; i < g.Opts.Threads; i++ {
+		go g.worker(wordChan, &workerGroup)
+	}
+
+	err := g.sendTargets(wordChan)
+	// the retries of the workers are sent to the queue until every target
+	// is processed or drained, it is closed after them
+	g.inFlight.Wait()
+	close(wordChan)
+	workerGroup.Wait()
+	// the held hits are sent whatever ended the scan
+	g.verifyHits()
+	g.stopOOB()
+	if err != nil {
+		return err
+	}
+
+	if context.Cause(g.context) == context.DeadlineExceeded {
+		return g.targetTimedOut()
+	}
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	if g.robots != nil {
+		g.Logf("[+] Skipped %d paths disallowed by robots.txt", g.robotsSkipped)
+	}
+	if g.Opts.SafeMode {
+		g.Logf("[+] Skipped %d dangerous paths in safe mode", g.safeModeSkipped)
+	}
+	if g.aborted {
+		return ErrErrorThreshold
+	}
+	return nil
+}
+
+// sendTargets sends the wayback urls, the words of the wordlists and the
+// targets discovered to the workers, until the scan is cancelled
+func (g *Gobuster) sendTargets(wordChan chan<- *BusterTarget) error {
+	if g.Opts.WaybackUrls != "" || g.Opts.CommonCrawl {
+		waybackScanner, err := g.getWaybackUrls()
+		if err != nil {
+			return err
+		}
+
+		g.Logf("Starting requesting waybackurls..")
+
+	WaybackScan:
+		for waybackScanner.Scan() {
+			select {
+			case <-g.context.Done():
+				break WaybackScan
+			default:
+				url := strings.TrimSpace(waybackScanner.Text())
+				// Skip "comment" (starts with #), as well as empty lines
+				if !strings.HasPrefix(url, "#") && len(url) > 0 {
+					busterTarget := &BusterTarget{
+						IsURL:  true,
+						Target: url,
+					}
+					g.sendTarget(wordChan, busterTarget)
+				}
+			}
+		}
+
+		select {
+		case <-time.After(5 * time.Second):
+		case <-g.context.Done():
+		}
+		g.Logf("waybackurls parsing and requesting done.")
+	}
+
+	g.Logf("Starting dictionary based brute-force..")
+
+	lists := g.scanWordlists()
+	if err := g.countWordlists(lists); err != nil {
+		return err
+	}
+
+	for _, list := range lists {
+		wordScanner, err := g.getWordlist(list)
+		if err != nil {
+			return err
+		}
+
+	WordScan:
+		for wordScanner.Scan() {
+			select {
+			case <-g.context.Done():
+				break WordScan
+			default:
+				word := strings.TrimSpace(wordScanner.Text())
+				if isWord(word) {
+					for _, expanded := range list.expand(word) {
+						ext := g.expandedExt(word, expanded)
+						if g.skipPruned(ext) {
+							continue
+						}
+						busterTarget := &BusterTarget{
+							IsURL:  false,
+							Target: expanded,
+							ext:    ext,
+						}
+						g.sendTarget(wordChan, busterTarget)
+					}
+				}
+			}
+		}
+
+		if serr := wordScanner.Err(); serr != nil {
+			g.Logf("[!] failed to read the %s: %v", list.name, serr)
+		}
+	}
+
+	// the words and endpoints found on the target come after the wordlist
+	g.sendDiscovered(wordChan)
+	// then the second pass of -learn, and what its matches discovered
+	g.sendLearned(wordChan)
+	g.sendDiscovered(wordChan)
+	return nil
+}
+
+// targetTimedOut records that the per target timeout cut the scan short
+func (g *Gobuster) targetTimedOut() error {
+	g.mu.Lock()
+	g.timedOut = true
+	g.mu.Unlock()
+	return ErrTargetTimeout
+}
+
+// GetConfigString returns the current config as a printable string
+func (g *Gobuster) GetConfigString() (string, error) {
+	buf := &bytes.Buffer{}
+	o := g.Opts
+	if _, err := fmt.Fprintf(buf, "[+] Mode                  : %s\n", o.Mode); err != nil {
+		return "", err
+	}
+	if _, err := fmt.Fprintf(buf, "[+] Url/Domain            : %s\n", o.URL); err != nil {
+		return "", err
+	}
+	if _, err := fmt.Fprintf(buf, "[+] Threads               : %d\n", o.Threads); err != nil {
+		return "", err
+	}
+	if _, err := fmt.Fprintf(buf, "[+] Run                   : %s (profile %s)\n", g.RunID, g.profileFingerprint()); err != nil {
+		return "", err
+	}
+	if g.FaviconHash != nil {
+		if _, err := fmt.Fprintf(buf, "[+] Favicon hash          : %d (shodan: http.favicon.hash:%d)\n", *g.FaviconHash, *g.FaviconHash); err != nil {
+			return "", err
+		}
+	}
+	if len(g.Technologies) > 0 {
+		if _, err := fmt.Fprintf(buf, "[+] Technologies          : %s\n", g.technologyConfig()); err != nil {
+			return "", err
+		}
+	}
+
+	if o.SafeMode {
+		if _, err := fmt.Fprintf(buf, "[+] Safe mode             : GET/HEAD only, %d requests/s, dangerous paths skipped\n", SafeModeRate); err != nil {
+			return "", err
+		}
+	}
+
+	if o.MethodEnum {
+		if _, err := fmt.Fprintf(buf, "[+] Method enumeration    : OPTIONS,%s\n", strings.Join(o.MethodsParsed, ",")); err != nil {
+			return "", err
+		}
+	}
+
+	if o.ShowDiff {
+		if _, err := fmt.Fprintf(buf, "[+] Show diff             : %s/output_diffs\n", o.OutputFolder); err != nil {
+			return "", err
+		}
+	}
+
+	if len(o.EvasionParsed) > 0 {
+		if _, err := fmt.Fprintf(buf, "[+] Evasion               : %s\n", o.Evasion); err != nil {
+			return "", err
+		}
+	}
+
+	if o.PruneExts {
+		if _, err := fmt.Fprintf(buf, "[+] Prune extensions      : after %d requests without a hit\n", PruneExtsSample); err != nil {
+			return "", err
+		}
+	}
+
+	if o.VerifyHits > 0 {
+		if _, err := fmt.Fprintf(buf, "[+] Verify hits           : %d requests each\n", o.VerifyHits); err != nil {
+			return "", err
+		}
+	}
+
+	if o.FetchArtifacts {
+		if _, err := fmt.Fprintf(buf, "[+] Fetch artifacts       : %s/%s, up to %d bytes each\n", o.OutputFolder, artifactsFolder, o.ArtifactMaxSize); err != nil {
+			return "", err
+		}
+	}
+
+	if o.UploadCheck {
+		if _, err := fmt.Fprintf(buf, "[+] Upload check          : PUT, GET and DELETE of writable sounding directories\n"); err != nil {
+			return "", err
+		}
+	}
+
+	if o.WordlistFromTarget {
+		words := "baseline page"
+		if o.WordlistFromMatches {
+			words = "baseline page and matches"
+		}
+		if _, err := fmt.Fprintf(buf, "[+] Words from target     : %s\n", words); err != nil {
+			return "", err
+		}
+	}
+
+	if o.OOBServer != "" {
+		if _, err := fmt.Fprintf(buf, "[+] OOB server            : %s\n", o.OOBServer); err != nil {
+			return "", err
+		}
+	}
+
+	if o.Learn {
+		if _, err := fmt.Fprintf(buf, "[+] Learn                 : wordlist recombined with the tokens of the matches\n"); err != nil {
+			return "", err
+		}
+	}
+
+	if o.JSEndpoints {
+		if _, err := fmt.Fprintf(buf, "[+] JS endpoints          : %s/output_jsendpoints\n", o.OutputFolder); err != nil {
+			return "", err
+		}
+	}
+
+	if o.BasePath != "" {
+		if _, err := fmt.Fprintf(buf, "[+] Base path             : %s\n", o.BasePath); err != nil {
+			return "", err
+		}
+	}
+
+	wordlist := "stdin (pipe)"
+	if o.Wordlist != "-" {
+		wordlist = o.Wordlist
+	}
+	if o.Wordlist != "" {
+		if _, err := fmt.Fprintf(buf, "[+] Wordlist              : %s\n", wordlist); err != nil {
+			return "", err
+		}
+	}
+
+	if o.WordlistDirs != "" {
+		if _, err := fmt.Fprintf(buf, "[+] Directory wordlist    : %s\n", o.WordlistDirs); err != nil {
+			return "", err
+		}
+	}
+
+	if o.WordlistFiles != "" {
+		if _, err := fmt.Fprintf(buf, "[+] File wordlist         : %s\n", o.WordlistFiles); err != nil {
+			return "", err
+		}
+	}
+
+	if o.Mode == ModeDNS {
+		if _, err := fmt.Fprintf(buf, "[+] DNS timeout           : %s (%d retries)\n", o.DNSTimeout.String(), o.DNSRetries); err != nil {
+			return "", err
+		}
+		if o.FastDNS {
+			resolvers := "system nameservers"
+			if o.Resolvers != "" {
+				resolvers = strings.Join(o.ResolversParsed, ", ")
+			}
+			if _, err := fmt.Fprintf(buf, "[+] Fast DNS              : %s\n", resolvers); err != nil {
+				return "", err
+			}
+		}
+		if o.WildcardAllow != "" {
+			if _, err := fmt.Fprintf(buf, "[+] Wildcard allow        : %s\n", o.WildcardAllow); err != nil {
+				return "", err
+			}
+		}
+		if o.Takeover {
+			fingerprints := "embedded"
+			if o.TakeoverFingerprints != "" {
+				fingerprints = o.TakeoverFingerprints
+			}
+			if _, err := fmt.Fprintf(buf, "[+] Takeover check        : %d fingerprints (%s)\n", len(o.TakeoverFingerprintsParsed), fingerprints); err != nil {
+				return "", err
+			}
+		}
+
+		if o.WebProbePorts != "" {
+			if _, err := fmt.Fprintf(buf, "[+] Web probe ports       : %s\n", o.WebProbePorts); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	if o.Warmup > 0 {
+		if _, err := fmt.Fprintf(buf, "[+] Warm-up               : %d requests over %d threads\n", o.Warmup, o.WarmupThreads); err != nil {
+			return "", err
+		}
+	}
+
+	if o.PerTargetTimeout > 0 {
+		if _, err := fmt.Fprintf(buf, "[+] Per target timeout    : %s\n", o.PerTargetTimeout.String()); err != nil {
+			return "", err
+		}
+	}
+
+	if len(g.extensions) > 0 {
+		if _, err := fmt.Fprintf(buf, "[+] Plugins               : %s\n", g.extensionNames()); err != nil {
+			return "", err
+		}
+	}
+
+	if o.AllTimeDedup {
+		if _, err := fmt.Fprintf(buf, "[+] All time dedup        : true\n"); err != nil {
+			return "", err
+		}
+	}
+
+	if o.DebugStats > 0 {
+		if _, err := fmt.Fprintf(buf, "[+] Debug stats           : every %s\n", o.DebugStats.String()); err != nil {
+			return "", err
+		}
+	}
+
+	if o.Syslog != "" {
+		if _, err := fmt.Fprintf(buf, "[+] Syslog                : %s\n", o.Syslog); err != nil {
+			return "", err
+		}
+	}
+
+	if o.ResultsSocket != "" {
+		if _, err := fmt.Fprintf(buf, "[+] Results socket        : %s\n", o.ResultsSocket); err != nil {
+			return "", err
+		}
+	}
+
+	if o.ScanProfile != "" {
+		if _, err := fmt.Fprintf(buf, "[+] Profile               : %s\n", o.ScanProfile); err != nil {
+			return "", err
+		}
+	}
+
+	if o.RotateSize != "" {
+		if _, err := fmt.Fprintf(buf, "[+] Rotate size           : %d bytes\n", o.RotateSizeParsed); err != nil {
+			return "", err
+		}
+	}
+
+	if o.DoHURL != "" {
+		if _, err := fmt.Fprintf(buf, "[+] DNS over HTTPS        : %s\n", o.DoHURL); err != nil {
+			return "", err
+		}
+	}
+
+	if o.DoTServer != "" {
+		if _, err := fmt.Fprintf(buf, "[+] DNS over TLS          : %s\n", o.DoTServer); err != nil {
+			return "", err
+		}
+	}
+
+	if o.Mode == ModeDir {
+		if o.ExcludedStatusCodes != "" {
+			if _, err := fmt.Fprintf(buf, "[+] Excluded status codes : %s\n", o.ExcludedStatusCodesParsed.Stringify()); err != nil {
+				return "", err
+			}
+		}
+		if _, err := fmt.Fprintf(buf, "[+] Follow-up codes       : %s\n", o.FollowUpStatusCodes); err != nil {
+			return "", err
+		}
+		if o.LevelDelay > 0 {
+			if _, err := fmt.Fprintf(buf, "[+] Level delay           : %s\n", o.LevelDelay.String()); err != nil {
+				return "", err
+			}
+		}
+		if o.PrioritizeCodes != "" {
+			if _, err := fmt.Fprintf(buf, "[+] Prioritized codes     : %s\n", o.PrioritizeCodes); err != nil {
+				return "", err
+			}
+		}
+
+		if o.MaxResponseSize > 0 {
+			if _, err := fmt.Fprintf(buf, "[+] Max response size     : %d bytes\n", o.MaxResponseSize); err != nil {
+				return "", err
+			}
+		}
+
+		if o.StreamTimeout > 0 {
+			if _, err := fmt.Fprintf(buf, "[+] Stream timeout        : %s\n", o.StreamTimeout); err != nil {
+				return "", err
+			}
+		}
+
+		if o.Match != "" {
+			if _, err := fmt.Fprintf(buf, "[+] Match                 : %s\n", o.Match); err != nil {
+				return "", err
+			}
+		}
+
+		if o.Proxy != "" {
+			if _, err := fmt.Fprintf(buf, "[+] Proxy                 : %s\n", o.Proxy); err != nil {
+				return "", err
+			}
+		}
+
+		if o.PACURL != "" || o.PACFile != "" {
+			pac := o.PACURL
+			if pac == "" {
+				pac = o.PACFile
+			}
+			if _, err := fmt.Fprintf(buf, "[+] Proxy auto-config     : %s\n", pac); err != nil {
+				return "", err
+			}
+		}
+
+		if o.Tor {
+			renewal := "never"
+			if o.TorRenewRequests > 0 && o.TorRenewEvery > 0 {
+				renewal = fmt.Sprintf("every %d requests and %s", o.TorRenewRequests, o.TorRenewEvery)
+			} else if o.TorRenewRequests > 0 {
+				renewal = fmt.Sprintf("every %d requests", o.TorRenewRequests)
+			} else if o.TorRenewEvery > 0 {
+				renewal = fmt.Sprintf("every %s", o.TorRenewEvery)
+			}
+			if _, err := fmt.Fprintf(buf, "[+] Tor                   : %s (circuit renewal %s)\n", o.TorProxy, renewal); err != nil {
+				return "", err
+			}
+		}
+
+		if o.Cookies != "" {
+			cookies := o.Cookies
+			if o.cookiesFromSecret {
+				cookies = "(from secrets)"
+			}
+			if _, err := fmt.Fprintf(buf, "[+] Cookies               : %s\n", cookies); err != nil {
+				return "", err
+			}
+		}
+
+		if o.UserAgent != "" {
+			if _, err := fmt.Fprintf(buf, "[+] User Agent            : %s\n", o.UserAgent); err != nil {
+				return "", err
+			}
+		}
+
+		if o.IncludeLength {
+			if _, err := fmt.Fprintf(buf, "[+] Show length           : true\n"); err != nil {
+				return "", err
+			}
+		}
+
+		if o.Username != "" {
+			if _, err := fmt.Fprintf(buf, "[+] Auth User             : %s\n", o.Username); err != nil {
+				return "", err
+			}
+		}
+
+		if len(o.Extensions) > 0 {
+			if _, err := fmt.Fprintf(buf, "[+] Extensions            : %s\n", o.ExtensionsParsed.Stringify()); err != nil {
+				return "", err
+			}
+		}
+
+		if o.BearerToken != "" {
+			if _, err := fmt.Fprintf(buf, "[+] Bearer token          : (set)\n"); err != nil {
+				return "", err
+			}
+		}
+
+		if o.UseSlash {
+			if _, err := fmt.Fprintf(buf, "[+] Add Slash             : true\n"); err != nil {
+				return "", err
+			}
+		}
+
+		if o.FollowRedirect {
+			if _, err := fmt.Fprintf(buf, "[+] Follow Redir          : true\n"); err != nil {
+				return "", err
+			}
+		}
+
+		if o.FollowRedirect && o.ShowRedirectChain {
+			if _, err := fmt.Fprintf(buf, "[+] Redirect chain        : true\n"); err != nil {
+				return "", err
+			}
+		}
+
+		if o.Expanded {
+			if _, err := fmt.Fprintf(buf, "[+] Expanded              : true\n"); err != nil {
+				return "", err
+			}
+		}
+
+		if o.NoStatus {
+			if _, err := fmt.Fprintf(buf, "[+] No status             : true\n"); err != nil {
+				return "", err
+			}
+		}
+
+		if o.Verbose {
+			if _, err := fmt.Fprintf(buf, "[+] Verbose               : true\n"); err != nil {
+				return "", err
+			}
+		}
+
+		if _, err := fmt.Fprintf(buf, "[+] Timeout               : %s\n", o.Timeout.String()); err != nil {
+			return "", err
+		}
+
+		if o.WaybackUrls != "" {
+			if _, err := fmt.Fprintf(buf, "[+] Wayback urls          : %s\n", o.WaybackUrls); err != nil {
+				return "", err
+			}
+		}
+
+		if o.CommonCrawl {
+			if _, err := fmt.Fprintf(buf, "[+] Common Crawl          : true\n"); err != nil {
+				return "", err
+			}
+		}
+
+		if o.RandomAgent != "" {
+			if _, err := fmt.Fprintf(buf, "[+] Random agent          : %s (%s)\n", o.RandomAgent, o.AgentStrategy); err != nil {
+				return "", err
+			}
+		}
+
+		if o.TargetUrls != "" {
+			if _, err := fmt.Fprintf(buf, "[+] Target urls           : %s\n", o.TargetUrls); err != nil {
+				return "", err
+			}
+		}
+
+		if o.CIDR != "" {
+			if _, err := fmt.Fprintf(buf, "[+] CIDR                  : %s (ports %s)\n", o.CIDR, o.Ports); err != nil {
+				return "", err
+			}
+		}
+
+		if o.ExcludeString != "" {
+			if _, err := fmt.Fprintf(buf, "[+] Exclude string         : %s\n", o.ExcludeString); err != nil {
+				return "", err
+			}
+		}
+
+		if o.IncludeContentType != "" {
+			if _, err := fmt.Fprintf(buf, "[+] Include content type  : %s\n", o.IncludeContentType); err != nil {
+				return "", err
+			}
+		}
+
+		if o.ExcludeContentType != "" {
+			if _, err := fmt.Fprintf(buf, "[+] Exclude content type  : %s\n", o.ExcludeContentType); err != nil {
+				return "", err
+			}
+		}
+
+		if o.TreatAsMissing != "" {
+			if _, err := fmt.Fprintf(buf, "[+] Treat as missing      : %s\n", o.TreatAsMissing); err != nil {
+				return "", err
+			}
+		}
+
+		if o.Heuristic404 {
+			if _, err := fmt.Fprintf(buf, "[+] Heuristic 404         : confidence %.2f\n", NotFoundThreshold); err != nil {
+				return "", err
+			}
+		}
+
+		if o.BlankExtension {
+			if _, err := fmt.Fprintf(buf, "[+] Blank extension       : true\n"); err != nil {
+				return "", err
+			}
+		}
+
+		if o.ExtToken != DefaultExtToken {
+			if _, err := fmt.Fprintf(buf, "[+] Extension token       : %s\n", o.ExtToken); err != nil {
+				return "", err
+			}
+		}
+
+		if o.API {
+			if _, err := fmt.Fprintf(buf, "[+] API mode              : true\n"); err != nil {
+				return "", err
+			}
+		}
+
+		if o.HeadFirst {
+			if _, err := fmt.Fprintf(buf, "[+] Head first            : true\n"); err != nil {
+				return "", err
+			}
+		}
+
+		if o.ErrorThreshold != "" {
+			if _, err := fmt.Fprintf(buf, "[+] Error threshold       : %3.2f%% of %d requests\n", o.ErrorThresholdParsed, o.ErrorWindow); err != nil {
+				return "", err
+			}
+		}
+
+		if o.DNSCacheTTL > 0 {
+			if _, err := fmt.Fprintf(buf, "[+] DNS cache ttl         : %s\n", o.DNSCacheTTL.String()); err != nil {
+				return "", err
+			}
+		}
+
+		if o.StaticResolve != "" {
+			if _, err := fmt.Fprintf(buf, "[+] Static resolve        : %s\n", o.StaticResolve); err != nil {
+				return "", err
+			}
+		}
+
+		if o.ConnRecycle > 0 {
+			if _, err := fmt.Fprintf(buf, "[+] Connection recycling  : every %s\n", o.ConnRecycle.String()); err != nil {
+				return "", err
+			}
+		}
+
+		if o.HostFuzz != "" {
+			if _, err := fmt.Fprintf(buf, "[+] Host fuzzing          : %s\n", o.HostFuzz); err != nil {
+				return "", err
+			}
+		}
+
+		if o.IfModifiedSince != "" {
+			if _, err := fmt.Fprintf(buf, "[+] If modified since     : %s\n", o.IfModifiedSinceParsed.UTC().Format(http.TimeFormat)); err != nil {
+				return "", err
+			}
+		}
+
+		if o.IfNoneMatch != "" {
+			if _, err := fmt.Fprintf(buf, "[+] If none match         : %s\n", o.IfNoneMatch); err != nil {
+				return "", err
+			}
+		}
+
+		if o.RespectRobots {
+			if _, err := fmt.Fprintf(buf, "[+] Respect robots.txt    : true\n"); err != nil {
+				return "", err
+			}
+		}
+
+		if o.OpenAPI {
+			if _, err := fmt.Fprintf(buf, "[+] OpenAPI inventory     : true\n"); err != nil {
+				return "", err
+			}
+		}
+
+		if o.NoBackoff {
+			if _, err := fmt.Fprintf(buf, "[+] Backoff               : false\n"); err != nil {
+				return "", err
+			}
+		}
+
+		if o.SourceIPs != "" {
+			if _, err := fmt.Fprintf(buf, "[+] Source IPs            : %s\n", o.SourceIPs); err != nil {
+				return "", err
+			}
+		}
+
+		if _, err := fmt.Fprintf(buf, "[+] Wildcard probes       : %d (lengths %s, charset %s)\n", o.WildcardProbes, o.WildcardLengths, o.WildcardCharset); err != nil {
+			return "", err
+		}
+
+		if o.DebugHTTP != "" {
+			if _, err := fmt.Fprintf(buf, "[+] Debug HTTP log        : %s\n", o.DebugHTTP); err != nil {
+				return "", err
+			}
+		}
+
+		if o.OnResult != "" {
+			if _, err := fmt.Fprintf(buf, "[+] On result             : %s\n", o.OnResult); err != nil {
+				return "", err
+			}
+		}
+
+		if o.OutputFolder != "" {
+			if _, err := fmt.Fprintf(buf, "[+] Output folder         : %s\n", o.OutputFolder); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	return strings.TrimSpace(buf.String()), nil
+}
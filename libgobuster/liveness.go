@@ -0,0 +1,61 @@
+package libgobuster
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// CheckLiveness sends one request to a target of a run over many
+// targets, within -liveness-timeout. Any response means the target is
+// alive, the error otherwise tells why it is skipped. Invalid targets
+// are left to the validation of their scan.
+func CheckLiveness(c context.Context, opt *Options, target string) error {
+	target, err := withScheme(target)
+	if err != nil {
+		return nil
+	}
+	// the probe is not part of any scan, keep it out of the debug log
+	probeOpt := *opt
+	probeOpt.DebugHTTP = ""
+	probeOpt.Timeout = opt.LivenessTimeout
+	// the options of the run are not validated yet, the errors of
+	// -static-resolve are left to the scans
+	if probeOpt.StaticResolve != "" && probeOpt.StaticResolveParsed == nil {
+		_ = probeOpt.parseStaticResolve()
+	}
+	client, err := newHTTPClient(c, &probeOpt)
+	if err != nil {
+		return err
+	}
+	_, err = client.makeRequest(http.MethodGet, target, RequestOptions{Cookies: opt.Cookies})
+	return err
+}
+
+// SkipReason describes the liveness error of a skipped target
+func SkipReason(err error) string {
+	return fmt.Sprintf("%s: %v", ClassifyError(err), err)
+}
+
+// WriteSkippedSummary records a target skipped by the liveness check
+// in the summaries of the output folder, with the reason
+func WriteSkippedSummary(opt *Options, target, reason string) error {
+	targetOpt := *opt
+	targetOpt.Mode = ModeDir
+	targetOpt.URL, _ = withScheme(target)
+	if !strings.HasSuffix(targetOpt.URL, "/") {
+		targetOpt.URL += "/"
+	}
+	g := &Gobuster{Opts: &targetOpt, startTime: opt.clock().Now(), RunID: uuid.New().String()}
+	return writeSummaryJSON(opt.OutputFolder, g.OutputFileSuffix(), Summary{
+		RunID:   g.RunID,
+		Target:  g.BaseURL(),
+		Mode:    ModeDir,
+		Profile: opt.ScanProfile,
+		Skipped: reason,
+		Tags:    opt.Tags,
+	})
+}
@@ -0,0 +1,51 @@
+package libgobuster
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckLiveness(t *testing.T) {
+	t.Parallel()
+
+	h := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer h.Close()
+	dead := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	dead.Close()
+
+	o := NewOptions()
+	o.LivenessTimeout = time.Second
+	if err := CheckLiveness(context.Background(), o, h.URL); err != nil {
+		t.Fatalf("expected a 404 to be alive, got error: %v", err)
+	}
+	err := CheckLiveness(context.Background(), o, dead.URL)
+	if err == nil {
+		t.Fatal("expected an error for a closed server")
+	}
+	if reason := SkipReason(err); !strings.HasPrefix(reason, ErrorRefused+": ") {
+		t.Fatalf("unexpected reason %q", reason)
+	}
+}
+
+func TestWriteSkippedSummary(t *testing.T) {
+	t.Parallel()
+
+	o := NewOptions()
+	o.OutputFolder = t.TempDir()
+	if err := WriteSkippedSummary(o, "dead.example.com", "timeout: deadline exceeded"); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	summaries, err := ReadSummaries(o.OutputFolder)
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if len(summaries) != 1 || summaries[0].Target != "http://dead.example.com/" || summaries[0].Skipped != "timeout: deadline exceeded" {
+		t.Fatalf("unexpected summaries %+v", summaries)
+	}
+}
@@ -0,0 +1,26 @@
+package libgobuster
+
+import (
+	"log"
+)
+
+// logger returns the configured logger, the standard logger by default
+func (opt *Options) logger() *log.Logger {
+	if opt == nil || opt.Logger == nil {
+		return log.Default()
+	}
+	return opt.Logger
+}
+
+// orDefault returns the logger, the standard logger when nil
+func orDefault(l *log.Logger) *log.Logger {
+	if l == nil {
+		return log.Default()
+	}
+	return l
+}
+
+// Logf logs a message of the scan to the logger of the options
+func (g *Gobuster) Logf(format string, v ...interface{}) {
+	g.Opts.logger().Printf(format, v...)
+}
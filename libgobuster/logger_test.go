@@ -0,0 +1,33 @@
+package libgobuster
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"strings"
+	"testing"
+)
+
+func TestLogf(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	g := &Gobuster{Opts: &Options{Logger: log.New(&buf, "", 0)}}
+	g.Logf("[+] Counted %d requests", 42)
+	if got := buf.String(); got != "[+] Counted 42 requests\n" {
+		t.Fatalf("got %q", got)
+	}
+
+	w := newWarmup(1, 1, 4)
+	w.logger = g.Opts.logger()
+	buf.Reset()
+	w.acquire(context.Background())
+	w.release(0, false)
+	if !strings.HasPrefix(buf.String(), "[+] Warm-up done") {
+		t.Fatalf("warm-up not logged to the logger of the options: %q", buf.String())
+	}
+
+	if (&Options{}).logger() != log.Default() {
+		t.Fatal("the standard logger is not the default")
+	}
+}
@@ -0,0 +1,425 @@
+package libgobuster
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// matchType is the static type of a match expression node
+type matchType int
+
+const (
+	matchInt matchType = iota
+	matchString
+	matchBool
+)
+
+func (t matchType) String() string {
+	switch t {
+	case matchInt:
+		return "number"
+	case matchString:
+		return "string"
+	}
+	return "bool"
+}
+
+// matchVariables lists the result fields usable in a match expression
+var matchVariables = map[string]matchType{
+	"status":   matchInt,
+	"size":     matchInt,
+	"words":    matchInt,
+	"lines":    matchInt,
+	"title":    matchString,
+	"body":     matchString,
+	"url":      matchString,
+	"path":     matchString,
+	"ext":      matchString,
+	"redirect": matchString,
+	"type":     matchString,
+	"dir":      matchBool,
+}
+
+// matchNode is a type checked node of a match expression
+type matchNode struct {
+	typ  matchType
+	eval func(vars map[string]interface{}) interface{}
+}
+
+// MatchExpr is a compiled -match expression like
+// status == 200 && size > 1024 && !title.contains("Error")
+type MatchExpr struct {
+	source string
+	root   matchNode
+}
+
+// ParseMatchExpr compiles a match expression. Types are checked while
+// parsing so a compiled expression can not fail on evaluation.
+func ParseMatchExpr(source string) (*MatchExpr, error) {
+	tokens, err := tokenizeMatchExpr(source)
+	if err != nil {
+		return nil, err
+	}
+	p := &matchParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos].text)
+	}
+	if root.typ != matchBool {
+		return nil, fmt.Errorf("expression must be a condition, got a %s", root.typ)
+	}
+	return &MatchExpr{source: source, root: root}, nil
+}
+
+// String returns the source of the expression
+func (e *MatchExpr) String() string {
+	return e.source
+}
+
+// Eval evaluates the expression against the variables of a result
+func (e *MatchExpr) Eval(vars map[string]interface{}) bool {
+	return e.root.eval(vars).(bool)
+}
+
+// MatchVars returns the match expression variables of a dir mode result
+func (r *Result) MatchVars(g *Gobuster) map[string]interface{} {
+	content := ""
+	if r.Content != nil {
+		content = *r.Content
+	}
+	var size int64
+	if r.Size != nil {
+		size = *r.Size
+	}
+	redirect := ""
+	if r.RedirectURL != nil {
+		redirect = *r.RedirectURL
+	}
+	return map[string]interface{}{
+		"status":   int64(r.Status),
+		"size":     size,
+		"words":    int64(len(strings.Fields(content))),
+		"lines":    int64(strings.Count(content, "\n")),
+		"title":    ExtractTitle(content),
+		"body":     content,
+		"url":      r.FullURL(g),
+		"path":     r.Entity,
+		"ext":      r.Extension(),
+		"redirect": redirect,
+		"dir":      strings.HasSuffix(r.Entity, "/"),
+		"type":     r.ContentType,
+	}
+}
+
+type matchTokenKind int
+
+const (
+	tokIdent matchTokenKind = iota
+	tokNumber
+	tokString
+	tokOp
+)
+
+type matchToken struct {
+	kind matchTokenKind
+	text string
+}
+
+// tokenizeMatchExpr splits the expression into identifiers, numbers,
+// double quoted strings and operators
+func tokenizeMatchExpr(s string) ([]matchToken, error) {
+	var tokens []matchToken
+	for i := 0; i < len(s); {
+		c := rune(s[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case unicode.IsLetter(c) || c == '_':
+			j := i
+			for j < len(s) && (unicode.IsLetter(rune(s[j])) || unicode.IsDigit(rune(s[j])) || s[j] == '_') {
+				j++
+			}
+			tokens = append(tokens, matchToken{tokIdent, s[i:j]})
+			i = j
+		case unicode.IsDigit(c):
+			j := i
+			for j < len(s) && unicode.IsDigit(rune(s[j])) {
+				j++
+			}
+			tokens = append(tokens, matchToken{tokNumber, s[i:j]})
+			i = j
+		case c == '"':
+			j := i + 1
+			for j < len(s) && s[j] != '"' {
+				if s[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(s) {
+				return nil, fmt.Errorf("unterminated string at position %d", i)
+			}
+			text, err := strconv.Unquote(s[i : j+1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid string at position %d", i)
+			}
+			tokens = append(tokens, matchToken{tokString, text})
+			i = j + 1
+		default:
+			op := ""
+			for _, o := range []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "!", "(", ")", ".", ","} {
+				if strings.HasPrefix(s[i:], o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected character %q at position %d", c, i)
+			}
+			tokens = append(tokens, matchToken{tokOp, op})
+			i += len(op)
+		}
+	}
+	return tokens, nil
+}
+
+type matchParser struct {
+	tokens []matchToken
+	pos    int
+}
+
+func (p *matchParser) peekOp(op string) bool {
+	return p.pos < len(p.tokens) && p.tokens[p.pos].kind == tokOp && p.tokens[p.pos].text == op
+}
+
+func (p *matchParser) expectOp(op string) error {
+	if !p.peekOp(op) {
+		return fmt.Errorf("expected %q", op)
+	}
+	p.pos++
+	return nil
+}
+
+func (p *matchParser) parseOr() (matchNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return left, err
+	}
+	for p.peekOp("||") {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return right, err
+		}
+		if left.typ != matchBool || right.typ != matchBool {
+			return left, fmt.Errorf("|| needs conditions on both sides")
+		}
+		l, r := left, right
+		left = matchNode{matchBool, func(v map[string]interface{}) interface{} {
+			return l.eval(v).(bool) || r.eval(v).(bool)
+		}}
+	}
+	return left, nil
+}
+
+func (p *matchParser) parseAnd() (matchNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return left, err
+	}
+	for p.peekOp("&&") {
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return right, err
+		}
+		if left.typ != matchBool || right.typ != matchBool {
+			return left, fmt.Errorf("&& needs conditions on both sides")
+		}
+		l, r := left, right
+		left = matchNode{matchBool, func(v map[string]interface{}) interface{} {
+			return l.eval(v).(bool) && r.eval(v).(bool)
+		}}
+	}
+	return left, nil
+}
+
+func (p *matchParser) parseUnary() (matchNode, error) {
+	if p.peekOp("!") {
+		p.pos++
+		n, err := p.parseUnary()
+		if err != nil {
+			return n, err
+		}
+		if n.typ != matchBool {
+			return n, fmt.Errorf("! needs a condition, got a %s", n.typ)
+		}
+		return matchNode{matchBool, func(v map[string]interface{}) interface{} {
+			return !n.eval(v).(bool)
+		}}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *matchParser) parseComparison() (matchNode, error) {
+	left, err := p.parsePostfix()
+	if err != nil {
+		return left, err
+	}
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if !p.peekOp(op) {
+			continue
+		}
+		p.pos++
+		right, err := p.parsePostfix()
+		if err != nil {
+			return right, err
+		}
+		if left.typ != right.typ {
+			return left, fmt.Errorf("can not compare a %s with a %s", left.typ, right.typ)
+		}
+		if left.typ != matchInt && op != "==" && op != "!=" {
+			return left, fmt.Errorf("%s needs numbers, got a %s", op, left.typ)
+		}
+		return compareNodes(op, left, right), nil
+	}
+	return left, nil
+}
+
+func compareNodes(op string, l, r matchNode) matchNode {
+	return matchNode{matchBool, func(v map[string]interface{}) interface{} {
+		a, b := l.eval(v), r.eval(v)
+		switch op {
+		case "==":
+			return a == b
+		case "!=":
+			return a != b
+		}
+		x, y := a.(int64), b.(int64)
+		switch op {
+		case "<":
+			return x < y
+		case "<=":
+			return x <= y
+		case ">":
+			return x > y
+		}
+		return x >= y
+	}}
+}
+
+func (p *matchParser) parsePostfix() (matchNode, error) {
+	n, err := p.parsePrimary()
+	if err != nil {
+		return n, err
+	}
+	for p.peekOp(".") {
+		p.pos++
+		if p.pos >= len(p.tokens) || p.tokens[p.pos].kind != tokIdent {
+			return n, fmt.Errorf("expected a method name after '.'")
+		}
+		method := p.tokens[p.pos].text
+		p.pos++
+		if err := p.expectOp("("); err != nil {
+			return n, err
+		}
+		var arg *matchToken
+		if !p.peekOp(")") {
+			if p.pos >= len(p.tokens) || p.tokens[p.pos].kind != tokString {
+				return n, fmt.Errorf("%s() takes a string literal", method)
+			}
+			arg = &p.tokens[p.pos]
+			p.pos++
+		}
+		if err := p.expectOp(")"); err != nil {
+			return n, err
+		}
+		if n, err = stringMethod(n, method, arg); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// stringMethod applies contains, startsWith, endsWith, matches or lower
+// to a string node
+func stringMethod(n matchNode, method string, arg *matchToken) (matchNode, error) {
+	if n.typ != matchString {
+		return n, fmt.Errorf("%s() needs a string, got a %s", method, n.typ)
+	}
+	if method == "lower" {
+		if arg != nil {
+			return n, fmt.Errorf("lower() takes no argument")
+		}
+		return matchNode{matchString, func(v map[string]interface{}) interface{} {
+			return strings.ToLower(n.eval(v).(string))
+		}}, nil
+	}
+	if arg == nil {
+		return n, fmt.Errorf("%s() takes a string literal", method)
+	}
+
+	var test func(s string) bool
+	switch method {
+	case "contains":
+		test = func(s string) bool { return strings.Contains(s, arg.text) }
+	case "startsWith":
+		test = func(s string) bool { return strings.HasPrefix(s, arg.text) }
+	case "endsWith":
+		test = func(s string) bool { return strings.HasSuffix(s, arg.text) }
+	case "matches":
+		re, err := regexp.Compile(arg.text)
+		if err != nil {
+			return n, fmt.Errorf("invalid regular expression %q: %v", arg.text, err)
+		}
+		test = re.MatchString
+	default:
+		return n, fmt.Errorf("unknown method %s()", method)
+	}
+	return matchNode{matchBool, func(v map[string]interface{}) interface{} {
+		return test(n.eval(v).(string))
+	}}, nil
+}
+
+func (p *matchParser) parsePrimary() (matchNode, error) {
+	if p.pos >= len(p.tokens) {
+		return matchNode{}, fmt.Errorf("unexpected end of expression")
+	}
+	t := p.tokens[p.pos]
+	p.pos++
+	switch t.kind {
+	case tokNumber:
+		i, err := strconv.ParseInt(t.text, 10, 64)
+		if err != nil {
+			return matchNode{}, fmt.Errorf("invalid number %s", t.text)
+		}
+		return matchNode{matchInt, func(map[string]interface{}) interface{} { return i }}, nil
+	case tokString:
+		return matchNode{matchString, func(map[string]interface{}) interface{} { return t.text }}, nil
+	case tokIdent:
+		if t.text == "true" || t.text == "false" {
+			b := t.text == "true"
+			return matchNode{matchBool, func(map[string]interface{}) interface{} { return b }}, nil
+		}
+		typ, ok := matchVariables[t.text]
+		if !ok {
+			return matchNode{}, fmt.Errorf("unknown variable %s", t.text)
+		}
+		name := t.text
+		return matchNode{typ, func(v map[string]interface{}) interface{} { return v[name] }}, nil
+	}
+	if t.text == "(" {
+		n, err := p.parseOr()
+		if err != nil {
+			return n, err
+		}
+		return n, p.expectOp(")")
+	}
+	return matchNode{}, fmt.Errorf("unexpected %q", t.text)
+}
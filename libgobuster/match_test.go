@@ -0,0 +1,91 @@
+package libgobuster
+
+import (
+	"testing"
+)
+
+func TestMatchExpr(t *testing.T) {
+	t.Parallel()
+
+	vars := map[string]interface{}{
+		"status":   int64(200),
+		"size":     int64(2048),
+		"words":    int64(10),
+		"lines":    int64(3),
+		"title":    "Admin Panel",
+		"body":     "<html>welcome</html>",
+		"url":      "http://example.com/admin",
+		"path":     "admin",
+		"ext":      "",
+		"redirect": "",
+		"type":     "application/json",
+		"dir":      false,
+	}
+
+	var tt = []struct {
+		testName      string
+		expr          string
+		expected      bool
+		expectedError string
+	}{
+		{"Status", "status == 200", true, ""},
+		{"Combined", `status == 200 && size > 1024 && !title.contains("Error")`, true, ""},
+		{"Or", "status == 404 || size >= 2048", true, ""},
+		{"Parentheses", "!(status == 200 || dir)", false, ""},
+		{"Lower", `title.lower().startsWith("admin")`, true, ""},
+		{"Regex", `body.matches("wel+come")`, true, ""},
+		{"String compare", `ext != "php"`, true, ""},
+		{"Content type", `type == "application/json" && !type.startsWith("text/")`, true, ""},
+		{"Unknown variable", "foo == 1", false, "unknown variable foo"},
+		{"Type mismatch", `status == "200"`, false, "can not compare a number with a string"},
+		{"Not a condition", "size", false, "expression must be a condition, got a number"},
+		{"Unknown method", `title.foo("x")`, false, "unknown method foo()"},
+		{"Trailing token", "status == 200 )", false, `unexpected ")"`},
+		{"Unterminated string", `title == "abc`, false, "unterminated string at position 9"},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+			e, err := ParseMatchExpr(x.expr)
+			if x.expectedError != "" {
+				if err == nil || err.Error() != x.expectedError {
+					t.Fatalf("expected error %q, got %v", x.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got error: %v", err)
+			}
+			if got := e.Eval(vars); got != x.expected {
+				t.Fatalf("expected %t, got %t", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestMatchVarsDeclared(t *testing.T) {
+	t.Parallel()
+
+	o := NewOptions()
+	o.URL = "http://example.com/"
+	g := &Gobuster{Opts: o}
+	r := &Result{Entity: "admin", Status: 200, ContentType: "application/json"}
+	vars := r.MatchVars(g)
+	if len(vars) != len(matchVariables) {
+		t.Fatalf("expected %d variables, got %d", len(matchVariables), len(vars))
+	}
+	for name := range vars {
+		if _, ok := matchVariables[name]; !ok {
+			t.Fatalf("variable %s can not be used in an expression", name)
+		}
+	}
+	e, err := ParseMatchExpr(`type == "application/json"`)
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if !e.Eval(vars) {
+		t.Fatal("expected the content type to match")
+	}
+}
@@ -0,0 +1,412 @@
+package libgobuster
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// allTimeFiles are the files every run of an output folder appends to
+var allTimeFiles = []string{"all_time_matches.txt", "all_time_subdomains.txt"}
+
+// appendedFiles are the files shared by the runs of an output folder,
+// their lines are merged instead of copied
+var appendedFiles = map[string]bool{"listable_dirs.txt": true, TriageFilename: true}
+
+// matchesFolders are the folders of the per-run matches files by mode,
+// with the prefix of their names
+var matchesFolders = []struct{ mode, folder, prefix string }{
+	{ModeDir, "output_matches", "matches"},
+	{ModeDNS, "output_subdomains", "subdomains"},
+}
+
+// mergedSummaryFile holds the summaries of the runs of a merged folder
+// combined by target, kept apart from output_summaries so the report
+// does not count the runs twice
+const mergedSummaryFile = "merged_summary.json"
+
+// MergeStats counts what a merge brought into the destination folder
+type MergeStats struct {
+	// Lines are the all time and shared lines added
+	Lines int
+	// Files are the files of the runs copied
+	Files int
+	// Conflicts are the files differing from the destination's, kept
+	// as they were
+	Conflicts []string
+}
+
+// isAllTimeFile reports whether the path, relative to the output folder,
+// is an all time file or one of its rotated archives
+func isAllTimeFile(rel string) bool {
+	for _, name := range allTimeFiles {
+		ext := filepath.Ext(name)
+		if rel == name || (strings.HasPrefix(rel, strings.TrimSuffix(name, ext)+".") && strings.HasSuffix(rel, ext+".gz")) {
+			return true
+		}
+	}
+	return false
+}
+
+// readLines returns the non empty lines of a reader
+func readLines(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if line := strings.TrimRight(scanner.Text(), "\r"); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// readRotatedLines returns the lines of a file and its rotated archives,
+// none when it does not exist
+func readRotatedLines(path string) ([]string, error) {
+	f, err := OpenRotated(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return readLines(f)
+}
+
+// lineDate returns the "[2006-01-02 15:04:05]" prefix of an all time
+// line, which sorts by date as a string
+func lineDate(line string) string {
+	if i := strings.Index(line, "]"); i >= 0 {
+		return line[:i]
+	}
+	return line
+}
+
+// newLines returns the lines of the sources missing from existing, the
+// profile lines first and the matches in the order of their date
+func newLines(existing []string, sources [][]string) []string {
+	seen := NewSet[string]()
+	seen.AddRange(existing)
+	var profiles, matches []string
+	for _, lines := range sources {
+		for _, line := range lines {
+			if !seen.Add(line) {
+				continue
+			}
+			if strings.HasPrefix(line, profilePrefix) {
+				profiles = append(profiles, line)
+			} else {
+				matches = append(matches, line)
+			}
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return lineDate(matches[i]) < lineDate(matches[j])
+	})
+	return append(profiles, matches...)
+}
+
+// appendLines appends lines to the file, creating it if needed
+func appendLines(path string, lines []string) error {
+	if len(lines) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(strings.Join(lines, "\n") + "\n"); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// mergeAllTime adds the all time lines of the sources missing from the
+// destination, the lines already there are left untouched
+func mergeAllTime(dest string, sources []string, stats *MergeStats) error {
+	for _, name := range allTimeFiles {
+		existing, err := readRotatedLines(filepath.Join(dest, name))
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %v", filepath.Join(dest, name), err)
+		}
+		var sourceLines [][]string
+		for _, source := range sources {
+			lines, err := readRotatedLines(filepath.Join(source, name))
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %v", filepath.Join(source, name), err)
+			}
+			sourceLines = append(sourceLines, lines)
+		}
+		added := newLines(existing, sourceLines)
+		if err := appendLines(filepath.Join(dest, name), added); err != nil {
+			return fmt.Errorf("failed to write %s: %v", filepath.Join(dest, name), err)
+		}
+		stats.Lines += len(added)
+	}
+	return nil
+}
+
+// mergeFile copies a file of a run to the destination. Shared files get
+// the lines they miss, other files already there are left untouched.
+func mergeFile(src, dst string, stats *MergeStats) error {
+	data, err := ioutil.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	existing, err := ioutil.ReadFile(dst)
+	if os.IsNotExist(err) {
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return err
+		}
+		stats.Files++
+		return ioutil.WriteFile(dst, data, 0644)
+	}
+	if err != nil || bytes.Equal(data, existing) {
+		return err
+	}
+	// the matches of a tag are shared by the runs too
+	if appendedFiles[filepath.Base(dst)] || filepath.Base(filepath.Dir(dst)) == tagsFolder {
+		existingLines, err := readLines(bytes.NewReader(existing))
+		if err != nil {
+			return err
+		}
+		lines, err := readLines(bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		added := newLines(existingLines, [][]string{lines})
+		stats.Lines += len(added)
+		return appendLines(dst, added)
+	}
+	stats.Conflicts = append(stats.Conflicts, dst)
+	return nil
+}
+
+// isMergedFile reports whether the path, relative to the output folder,
+// is one of the files a merge regenerates
+func isMergedFile(rel string) bool {
+	if rel == mergedSummaryFile {
+		return true
+	}
+	for _, m := range matchesFolders {
+		if rel == "merged_"+m.prefix+".txt" {
+			return true
+		}
+	}
+	return false
+}
+
+// matchKey returns a matches line without the time it was found at, the
+// same match of two runs sharing its key
+func matchKey(line string) string {
+	if strings.HasPrefix(line, "[") {
+		if i := strings.Index(line, "]"); i >= 0 {
+			line = line[i+1:]
+		}
+	}
+	return strings.TrimSpace(line)
+}
+
+// writeMergedMatches combines the per-run matches files of the folder
+// into merged_<prefix>.txt, every match once in the order of the runs
+func writeMergedMatches(dest string) error {
+	for _, m := range matchesFolders {
+		files, err := filepath.Glob(filepath.Join(dest, m.folder, m.prefix+"_*.txt"))
+		if err != nil {
+			return err
+		}
+		// the file names start with the unix time of the run
+		sort.Strings(files)
+		seen := NewSet[string]()
+		var lines []string
+		for _, file := range files {
+			runLines, err := readRotatedLines(file)
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %v", file, err)
+			}
+			for _, line := range runLines {
+				if seen.Add(matchKey(line)) {
+					lines = append(lines, line)
+				}
+			}
+		}
+		path := filepath.Join(dest, "merged_"+m.prefix+".txt")
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if err := appendLines(path, lines); err != nil {
+			return fmt.Errorf("failed to write %s: %v", path, err)
+		}
+	}
+	return nil
+}
+
+// addSummary adds the counters of a run to the combined summary of its
+// target
+func addSummary(total *Summary, s Summary) {
+	total.Requests += s.Requests
+	total.Errors += s.Errors
+	total.Aborted = total.Aborted || s.Aborted
+	total.TimedOut = total.TimedOut || s.TimedOut
+	total.RobotsSkipped += s.RobotsSkipped
+	total.SafeModeSkipped += s.SafeModeSkipped
+	total.WAFBlocks += s.WAFBlocks
+	total.DroppedHits += s.DroppedHits
+	for ext, hits := range s.Extensions {
+		if total.Extensions == nil {
+			total.Extensions = make(map[string]int)
+		}
+		total.Extensions[ext] += hits
+	}
+	for category, count := range s.ErrorCategories {
+		if total.ErrorCategories == nil {
+			total.ErrorCategories = make(map[string]int)
+		}
+		total.ErrorCategories[category] += count
+	}
+	if total.WAF == "" {
+		total.WAF = s.WAF
+	}
+	if total.FaviconHash == nil {
+		total.FaviconHash = s.FaviconHash
+	}
+}
+
+// writeMergedSummary combines the summaries of the runs of the folder by
+// mode and target into mergedSummaryFile. The matches of a target are
+// counted once however many runs found them.
+func writeMergedSummary(dest string) error {
+	files, err := filepath.Glob(filepath.Join(dest, "output_summaries", "summary_*.json"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(files)
+
+	var merged []*Summary
+	byTarget := make(map[string]*Summary)
+	matches := make(map[string]*Set[string])
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read summary: %v", err)
+		}
+		var s Summary
+		if err := json.Unmarshal(data, &s); err != nil {
+			return fmt.Errorf("failed to decode summary %s: %v", file, err)
+		}
+		key := s.Mode + " " + s.Target
+		total, ok := byTarget[key]
+		if !ok {
+			total = &Summary{RunID: "merged", Target: s.Target, Mode: s.Mode}
+			byTarget[key] = total
+			set := NewSet[string]()
+			matches[key] = &set
+			merged = append(merged, total)
+		}
+		addSummary(total, s)
+
+		// the matches file of a run shares the suffix of its summary
+		suffix := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(file), "summary_"), ".json")
+		var lines []string
+		for _, m := range matchesFolders {
+			if m.mode != s.Mode {
+				continue
+			}
+			if lines, err = readRotatedLines(filepath.Join(dest, m.folder, m.prefix+"_"+suffix+".txt")); err != nil {
+				return err
+			}
+		}
+		// a run written to a named output file is counted as it is
+		if lines == nil {
+			total.Matches += s.Matches
+		}
+		for _, line := range lines {
+			if matches[key].Add(matchKey(line)) {
+				total.Matches++
+			}
+		}
+	}
+
+	if len(merged) == 0 {
+		return nil
+	}
+	data, err := json.MarshalIndent(merged, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode summary: %v", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dest, mergedSummaryFile), data, 0644); err != nil {
+		return fmt.Errorf("failed to write summary: %v", err)
+	}
+	return nil
+}
+
+// MergeOutputFolders merges the output folders of several runs, from
+// agents or split wordlists, into dest. The all time files are merged
+// without duplicates and the files of the runs, summaries included, are
+// copied. The matches of the runs are then combined, every match once,
+// in merged_matches.txt and merged_subdomains.txt, and their summaries
+// by target in merged_summary.json.
+func MergeOutputFolders(dest string, sources []string) (*MergeStats, error) {
+	stats := &MergeStats{}
+	destAbs, err := filepath.Abs(dest)
+	if err != nil {
+		return nil, err
+	}
+	for _, source := range sources {
+		info, err := os.Stat(source)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("%s is not an output folder", source)
+		}
+		if abs, _ := filepath.Abs(source); abs == destAbs {
+			return nil, fmt.Errorf("%s is the destination folder", source)
+		}
+	}
+	if err := os.MkdirAll(dest, 0755); err != nil {
+		return nil, err
+	}
+
+	if err := mergeAllTime(dest, sources, stats); err != nil {
+		return nil, err
+	}
+	for _, source := range sources {
+		err := filepath.Walk(source, func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() {
+				return err
+			}
+			rel, err := filepath.Rel(source, path)
+			if err != nil || isAllTimeFile(rel) || isMergedFile(rel) {
+				return err
+			}
+			if err := mergeFile(path, filepath.Join(dest, rel), stats); err != nil {
+				return fmt.Errorf("failed to merge %s: %v", path, err)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := writeMergedMatches(dest); err != nil {
+		return nil, err
+	}
+	if err := writeMergedSummary(dest); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
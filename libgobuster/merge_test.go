@@ -0,0 +1,131 @@
+package libgobuster
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewLines(t *testing.T) {
+	t.Parallel()
+
+	existing := []string{"# profile aaa mode=dir", "[2020-01-02 10:00:00] - /admin - 200 - run 1 - profile aaa"}
+	sources := [][]string{
+		{"# profile aaa mode=dir", "[2020-01-03 10:00:00] - /login - 200 - run 2 - profile aaa", "[2020-01-02 10:00:00] - /admin - 200 - run 1 - profile aaa"},
+		{"# profile bbb mode=dir", "[2020-01-01 10:00:00] - /old - 301 - run 3 - profile bbb"},
+	}
+	expected := []string{
+		"# profile bbb mode=dir",
+		"[2020-01-01 10:00:00] - /old - 301 - run 3 - profile bbb",
+		"[2020-01-03 10:00:00] - /login - 200 - run 2 - profile aaa",
+	}
+	if got := newLines(existing, sources); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestIsAllTimeFile(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		rel      string
+		expected bool
+	}{
+		{"all_time_matches.txt", true},
+		{"all_time_subdomains.2.txt.gz", true},
+		{"output_matches/matches_1_http_a_run.txt", false},
+		{"output_matches/all_time_matches.txt", false},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.rel, func(t *testing.T) {
+			t.Parallel()
+
+			if got := isAllTimeFile(x.rel); got != x.expected {
+				t.Fatalf("expected %t, got %t", x.expected, got)
+			}
+		})
+	}
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+}
+
+func TestMergeOutputFolders(t *testing.T) {
+	t.Parallel()
+
+	a, b, dest := t.TempDir(), t.TempDir(), filepath.Join(t.TempDir(), "merged")
+	writeTestFile(t, filepath.Join(a, "all_time_matches.txt"), "# profile p mode=dir\n[2020-01-02 10:00:00] - /admin - 200 - run 1 - profile p\n")
+	writeTestFile(t, filepath.Join(a, "output_summaries", "summary_1_run1.json"), `{"target":"http://a/","mode":"dir","requests":10,"matches":2,"errors":1,"extensions":{"php":1}}`)
+	writeTestFile(t, filepath.Join(a, "output_matches", "matches_1_run1.txt"), "[10:00:00]     200     -     http://a/admin\n[10:00:01]     301     -     http://a/x\n")
+	writeTestFile(t, filepath.Join(a, "output_matches", "listable_dirs.txt"), "http://a/x/\n")
+	writeTestFile(t, filepath.Join(a, "profiles", "fast.json"), "{\"name\":\"fast\"}")
+	writeTestFile(t, filepath.Join(b, "all_time_matches.txt"), "# profile p mode=dir\n[2020-01-01 10:00:00] - /login - 200 - run 2 - profile p\n")
+	writeTestFile(t, filepath.Join(b, "output_summaries", "summary_2_run2.json"), `{"target":"http://a/","mode":"dir","requests":20,"matches":2,"errors":0,"extensions":{"php":2}}`)
+	writeTestFile(t, filepath.Join(b, "output_matches", "matches_2_run2.txt"), "[11:00:00]     200     -     http://a/login\n[11:00:01]     200     -     http://a/admin\n")
+	writeTestFile(t, filepath.Join(b, "output_matches", "listable_dirs.txt"), "http://a/x/\nhttp://a/y/\n")
+	writeTestFile(t, filepath.Join(b, "profiles", "fast.json"), "{\"name\":\"fast\",\"mode\":\"dir\"}")
+
+	stats, err := MergeOutputFolders(dest, []string{a, b})
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if stats.Lines != 4 || stats.Files != 6 || len(stats.Conflicts) != 1 || !strings.HasSuffix(stats.Conflicts[0], "fast.json") {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+
+	allTime, _ := ioutil.ReadFile(filepath.Join(dest, "all_time_matches.txt"))
+	expected := "# profile p mode=dir\n[2020-01-01 10:00:00] - /login - 200 - run 2 - profile p\n[2020-01-02 10:00:00] - /admin - 200 - run 1 - profile p\n"
+	if string(allTime) != expected {
+		t.Fatalf("unexpected all time matches %q", allTime)
+	}
+	listings, _ := ioutil.ReadFile(filepath.Join(dest, "output_matches", "listable_dirs.txt"))
+	if string(listings) != "http://a/x/\nhttp://a/y/\n" {
+		t.Fatalf("unexpected listable dirs %q", listings)
+	}
+	matches, _ := ioutil.ReadFile(filepath.Join(dest, "merged_matches.txt"))
+	if string(matches) != "[10:00:00]     200     -     http://a/admin\n[10:00:01]     301     -     http://a/x\n[11:00:00]     200     -     http://a/login\n" {
+		t.Fatalf("unexpected merged matches %q", matches)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(dest, mergedSummaryFile))
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	var summaries []Summary
+	if err := json.Unmarshal(data, &summaries); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	expectedSummary := Summary{RunID: "merged", Target: "http://a/", Mode: ModeDir, Requests: 30, Matches: 3, Errors: 1, Extensions: map[string]int{"php": 3}}
+	if len(summaries) != 1 || !reflect.DeepEqual(summaries[0], expectedSummary) {
+		t.Fatalf("expected %+v, got %+v", expectedSummary, summaries)
+	}
+	// the report still counts every run once
+	if runs, err := ReadSummaries(dest); err != nil || len(runs) != 2 {
+		t.Fatalf("expected the 2 runs, got %d: %v", len(runs), err)
+	}
+
+	// merging again adds nothing
+	stats, err = MergeOutputFolders(dest, []string{a, b})
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if stats.Lines != 0 || stats.Files != 0 {
+		t.Fatalf("expected nothing new, got %+v", stats)
+	}
+
+	if _, err := MergeOutputFolders(a, []string{a}); err == nil {
+		t.Fatal("expected an error merging a folder into itself")
+	}
+}
@@ -0,0 +1,110 @@
+package libgobuster
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// DefaultEnumMethods are the methods -method-enum tries after OPTIONS
+const DefaultEnumMethods = "PUT,DELETE,PATCH"
+
+var methodRegex = regexp.MustCompile(`^[A-Z]+$`)
+
+// parseMethods parses the comma separated methods tried by -method-enum
+func (opt *Options) parseMethods() error {
+	opt.MethodsParsed = nil
+	for _, m := range strings.Split(opt.Methods, ",") {
+		m = strings.ToUpper(strings.TrimSpace(m))
+		if m == "" {
+			continue
+		}
+		if !methodRegex.MatchString(m) {
+			return fmt.Errorf("invalid method given: %s", m)
+		}
+		opt.MethodsParsed = append(opt.MethodsParsed, m)
+	}
+	return nil
+}
+
+// allowHeaderMethods returns the methods of the Allow header, in order
+// and without duplicates
+func allowHeaderMethods(header http.Header) []string {
+	var methods []string
+	seen := NewSet[string]()
+	for _, value := range header.Values("Allow") {
+		for _, m := range strings.Split(value, ",") {
+			m = strings.ToUpper(strings.TrimSpace(m))
+			if m != "" && seen.Add(m) {
+				methods = append(methods, m)
+			}
+		}
+	}
+	return methods
+}
+
+// methodAllowed reports whether the status of a probed method shows the
+// server accepted it
+func methodAllowed(status int) bool {
+	return status < http.StatusBadRequest
+}
+
+// methodSummary returns the methods metadata of a found endpoint: the methods of the
+// Allow header followed by the probed methods the server accepted, with
+// their status
+func methodSummary(allow []string, probed []string, statuses map[string]int) string {
+	var parts []string
+	listed := NewSet[string]()
+	for _, m := range allow {
+		listed.Add(m)
+		if status, ok := statuses[m]; ok && methodAllowed(status) {
+			parts = append(parts, fmt.Sprintf("%s %d", m, status))
+			continue
+		}
+		parts = append(parts, m)
+	}
+	for _, m := range probed {
+		if status, ok := statuses[m]; ok && methodAllowed(status) && !listed.Contains(m) {
+			parts = append(parts, fmt.Sprintf("%s %d", m, status))
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return strings.Join(parts, ", ")
+}
+
+// allowedMethods returns the methods of the Allow header, with no status,
+// and the probed methods the server accepted, with their status
+func allowedMethods(allow []string, probed []string, statuses map[string]int) map[string]int {
+	methods := make(map[string]int)
+	for _, m := range allow {
+		methods[m] = 0
+	}
+	for _, m := range probed {
+		if status, ok := statuses[m]; ok && methodAllowed(status) {
+			methods[m] = status
+		}
+	}
+	return methods
+}
+
+// EnumerateMethods sends OPTIONS and the -methods to the url of a found
+// endpoint and returns the summary of the methods it allows, or an empty
+// string, along with the methods themselves
+func (g *Gobuster) EnumerateMethods(url string, ro RequestOptions) (string, map[string]int) {
+	var allow []string
+	if resp, err := g.HTTP.makeRequest(http.MethodOptions, url, ro); err == nil {
+		allow = allowHeaderMethods(resp.Header)
+	}
+	statuses := make(map[string]int)
+	for _, m := range g.Opts.MethodsParsed {
+		resp, err := g.HTTP.makeRequest(m, url, ro)
+		if err != nil {
+			continue
+		}
+		statuses[m] = resp.StatusCode
+	}
+	return methodSummary(allow, g.Opts.MethodsParsed, statuses), allowedMethods(allow, g.Opts.MethodsParsed, statuses)
+}
@@ -0,0 +1,109 @@
+package libgobuster
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestParseMethods(t *testing.T) {
+	t.Parallel()
+
+	var tt = []struct {
+		testName      string
+		methods       string
+		expected      []string
+		expectedError string
+	}{
+		{"Default", DefaultEnumMethods, []string{"PUT", "DELETE", "PATCH"}, ""},
+		{"Lowercase and spaces", "put, propfind", []string{"PUT", "PROPFIND"}, ""},
+		{"Empty", "", nil, ""},
+		{"Invalid", "PUT,GET /", nil, "invalid method given: GET /"},
+	}
+
+	for _, x := range tt {
+		t.Run(x.testName, func(t *testing.T) {
+			o := NewOptions()
+			o.Methods = x.methods
+			err := o.parseMethods()
+			if x.expectedError != "" {
+				if err == nil || err.Error() != x.expectedError {
+					t.Fatalf("Expected error %q but got %v", x.expectedError, err)
+				}
+				return
+			}
+			if err != nil || !reflect.DeepEqual(o.MethodsParsed, x.expected) {
+				t.Fatalf("Expected %v but got %v (%v)", x.expected, o.MethodsParsed, err)
+			}
+		})
+	}
+}
+
+func TestMethodSummary(t *testing.T) {
+	t.Parallel()
+
+	probed := []string{"PUT", "DELETE", "PATCH"}
+	tt := []struct {
+		testName string
+		allow    string
+		statuses map[string]int
+		expected string
+	}{
+		{"Nothing allowed", "", map[string]int{"PUT": 405, "DELETE": 405, "PATCH": 501}, ""},
+		{"Allow header", "GET, HEAD,options", map[string]int{"PUT": 405}, "GET, HEAD, OPTIONS"},
+		{"Accepted upload", "GET, PUT", map[string]int{"PUT": 201, "DELETE": 403}, "GET, PUT 201"},
+		{"Accepted but not listed", "GET", map[string]int{"DELETE": 204}, "GET, DELETE 204"},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			header := http.Header{}
+			if x.allow != "" {
+				header.Set("Allow", x.allow)
+			}
+			if got := methodSummary(allowHeaderMethods(header), probed, x.statuses); got != x.expected {
+				t.Fatalf("expected %q, got %q", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestEnumerateMethods(t *testing.T) {
+	t.Parallel()
+
+	h := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodOptions:
+			w.Header().Set("Allow", "GET, HEAD, OPTIONS, PUT")
+		case http.MethodPut:
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	defer h.Close()
+
+	o := NewOptions()
+	if err := o.parseMethods(); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	c, err := newHTTPClient(context.Background(), o)
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	g := &Gobuster{Opts: o, HTTP: c}
+	expected := "GET, HEAD, OPTIONS, PUT 201"
+	got, methods := g.EnumerateMethods(h.URL, RequestOptions{})
+	if got != expected {
+		t.Fatalf("expected %q, got %q", expected, got)
+	}
+	expectedMethods := map[string]int{"GET": 0, "HEAD": 0, "OPTIONS": 0, "PUT": 201}
+	if !reflect.DeepEqual(methods, expectedMethods) {
+		t.Fatalf("expected %v, got %v", expectedMethods, methods)
+	}
+}
@@ -0,0 +1,75 @@
+package libgobuster
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// MissingRule declares the responses of a status as missing paths, for
+// the paths of a shape only: * every path, .ext the paths with the
+// extension, / the directories and any other pattern a path.Match glob
+// of the path
+type MissingRule struct {
+	Status  int
+	Pattern string
+}
+
+// matches reports whether the rule covers a response to the path
+func (rule MissingRule) matches(status int, p string) bool {
+	if status != rule.Status {
+		return false
+	}
+	switch {
+	case rule.Pattern == "*":
+		return true
+	case rule.Pattern == "/":
+		return strings.HasSuffix(p, "/")
+	case strings.HasPrefix(rule.Pattern, ".") && !strings.ContainsAny(rule.Pattern, "*?[/"):
+		return strings.EqualFold(path.Ext(strings.TrimSuffix(p, "/")), rule.Pattern)
+	}
+	matched, _ := path.Match(rule.Pattern, strings.TrimPrefix(p, "/"))
+	return matched
+}
+
+// parseMissingRules parses the comma separated status:pattern rules of
+// -treat-as-missing
+func parseMissingRules(value string) ([]MissingRule, error) {
+	var rules []MissingRule
+	for _, r := range strings.Split(value, ",") {
+		r = strings.TrimSpace(r)
+		if r == "" {
+			continue
+		}
+		parts := strings.SplitN(r, ":", 2)
+		if len(parts) != 2 || parts[1] == "" {
+			return nil, fmt.Errorf("invalid rule given: %s", r)
+		}
+		status, err := strconv.Atoi(parts[0])
+		if err != nil || status < 100 || status > 599 {
+			return nil, fmt.Errorf("invalid status code given: %s", parts[0])
+		}
+		if _, err := path.Match(parts[1], ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern given: %s", parts[1])
+		}
+		rules = append(rules, MissingRule{Status: status, Pattern: parts[1]})
+	}
+	return rules, nil
+}
+
+// TreatedAsMissing reports whether a -treat-as-missing rule declares the
+// response of the status to the path, or url, a missing path
+func (opt *Options) TreatedAsMissing(status int, entity string) bool {
+	p := entity
+	if u, err := url.Parse(entity); err == nil && u.Path != "" {
+		p = u.Path
+	}
+	for _, rule := range opt.TreatAsMissingParsed {
+		if rule.matches(status, p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package libgobuster
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseMissingRules(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		value    string
+		expected []MissingRule
+		wantErr  bool
+	}{
+		{"404:*,403:.php", []MissingRule{{404, "*"}, {403, ".php"}}, false},
+		{" 401:/ , 500:backup*", []MissingRule{{401, "/"}, {500, "backup*"}}, false},
+		{"403", nil, true},
+		{"403:", nil, true},
+		{"abc:*", nil, true},
+		{"42:*", nil, true},
+		{"403:[a", nil, true},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.value, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseMissingRules(x.value)
+			if x.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error parsing %q", x.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got error: %v", err)
+			}
+			if !reflect.DeepEqual(got, x.expected) {
+				t.Fatalf("expected %v, got %v", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestTreatedAsMissing(t *testing.T) {
+	t.Parallel()
+
+	o := NewOptions()
+	o.TreatAsMissingParsed, _ = parseMissingRules("404:*,403:.php,401:/,500:backup/*")
+
+	tt := []struct {
+		testName string
+		status   int
+		entity   string
+		expected bool
+	}{
+		{"Any path", 404, "admin", true},
+		{"Extension", 403, "index.php", true},
+		{"Extension case", 403, "INDEX.PHP", true},
+		{"Other extension", 403, "index.html", false},
+		{"Directory", 401, "admin/", true},
+		{"File", 401, "admin", false},
+		{"Glob", 500, "backup/db.sql", true},
+		{"Glob other path", 500, "db.sql", false},
+		{"Url", 403, "http://example.com/login.php?a=b", true},
+		{"Other status", 200, "index.php", false},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			if got := o.TreatedAsMissing(x.status, x.entity); got != x.expected {
+				t.Fatalf("expected %t, got %t", x.expected, got)
+			}
+		})
+	}
+}
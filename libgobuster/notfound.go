@@ -0,0 +1,117 @@
+package libgobuster
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	// NotFoundThreshold is the confidence from which -heuristic-404
+	// takes a page for a not found page
+	NotFoundThreshold = 0.5
+	// maxNotFoundContent caps the bytes of a body searched for the
+	// phrases of a not found page
+	maxNotFoundContent = 64 * 1024
+)
+
+var (
+	// notFoundPhraseRegex matches "not found" in the languages of the
+	// common error pages
+	notFoundPhraseRegex = regexp.MustCompile(`(?i)(` + strings.Join([]string{
+		// english
+		`not found`, `could not be found`, `cannot be found`, `can't be found`, `does not exist`, `doesn't exist`, `no longer (exists|available)`,
+		// french
+		`introuvable`, `non trouv[ée]e?`, `n'existe pas`,
+		// spanish
+		`no encontrad[ao]`, `no se (ha podido encontrar|encuentra)`, `no existe`,
+		// german
+		`nicht gefunden`, `existiert nicht`, `nicht gefunden werden`,
+		// italian
+		`non trovat[ao]`, `non esiste`,
+		// portuguese
+		`não encontrad[ao]`, `não existe`,
+		// dutch
+		`niet gevonden`, `bestaat niet`,
+		// russian, japanese and chinese
+		`не найдена`, `не найден`, `見つかりません`, `找不到`, `页面不存在`,
+	}, "|") + `)`)
+	// notFoundCodeRegex matches the status code in a title or a heading
+	notFoundCodeRegex = regexp.MustCompile(`\b404\b`)
+	headingRegex      = regexp.MustCompile(`(?is)<h[1-3][^>]*>(.*?)</h[1-3]>`)
+	// invisibleRegex matches the scripts, styles and comments left out
+	// of the text of a page
+	invisibleRegex = regexp.MustCompile(`(?is)<script.*?</script>|<style.*?</style>|<!--.*?-->`)
+	tagRegex       = regexp.MustCompile(`<[^>]*>`)
+)
+
+// notFoundSignal is a place of a page telling it is a not found page,
+// the weight is the confidence it gives alone
+type notFoundSignal struct {
+	name   string
+	weight float64
+	found  func(title string, headings []string, text string) bool
+}
+
+// notFoundSignals are the signals of a not found page, from the strongest:
+// a phrase in the title or a heading beats one in the text, where it may
+// be a link or a search form
+var notFoundSignals = []notFoundSignal{
+	{"title", 0.6, func(title string, _ []string, _ string) bool {
+		return notFoundPhraseRegex.MatchString(title)
+	}},
+	{"heading", 0.5, func(_ string, headings []string, _ string) bool {
+		for _, h := range headings {
+			if notFoundPhraseRegex.MatchString(h) {
+				return true
+			}
+		}
+		return false
+	}},
+	{"404", 0.4, func(title string, headings []string, _ string) bool {
+		if notFoundCodeRegex.MatchString(title) {
+			return true
+		}
+		for _, h := range headings {
+			if notFoundCodeRegex.MatchString(h) {
+				return true
+			}
+		}
+		return false
+	}},
+	{"text", 0.25, func(_ string, _ []string, text string) bool {
+		return notFoundPhraseRegex.MatchString(text)
+	}},
+}
+
+// NotFoundScore returns the confidence, from 0 to 1, that a page is a not
+// found page whatever its status, along with the signals found
+func NotFoundScore(content string) (float64, []string) {
+	if len(content) > maxNotFoundContent {
+		content = content[:maxNotFoundContent]
+	}
+	content = invisibleRegex.ReplaceAllString(content, " ")
+	title := ExtractTitle(content)
+	var headings []string
+	for _, m := range headingRegex.FindAllStringSubmatch(content, -1) {
+		headings = append(headings, tagRegex.ReplaceAllString(m[1], " "))
+	}
+	text := tagRegex.ReplaceAllString(titleRegex.ReplaceAllString(content, " "), " ")
+
+	// every signal removes its share of the remaining doubt
+	doubt := 1.0
+	var signals []string
+	for _, s := range notFoundSignals {
+		if s.found(title, headings, text) {
+			doubt *= 1 - s.weight
+			signals = append(signals, s.name)
+		}
+	}
+	return 1 - doubt, signals
+}
+
+// IsNotFoundPage reports whether a page reads as a not found page, the
+// soft 404s the wildcard title and length detection misses
+func IsNotFoundPage(content string) bool {
+	score, _ := NotFoundScore(content)
+	return score >= NotFoundThreshold
+}
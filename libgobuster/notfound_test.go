@@ -0,0 +1,41 @@
+package libgobuster
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNotFoundScore(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName string
+		content  string
+		signals  []string
+		notFound bool
+	}{
+		{"English title", "<title>Page Not Found</title><p>Sorry</p>", []string{"title"}, true},
+		{"French heading", "<h1>Page introuvable</h1>", []string{"heading", "text"}, true},
+		{"Spanish title", "<title>Error 404 - Página no encontrada</title>", []string{"title", "404"}, true},
+		{"German heading with markup", "<h2><span>Seite nicht gefunden</span></h2>", []string{"heading", "text"}, true},
+		{"Code and text", "<h1>Error 404</h1><p>Le fichier n'existe pas.</p>", []string{"404", "text"}, true},
+		{"Text only", "<title>Shop</title><p>Item not found? Contact us</p>", []string{"text"}, false},
+		{"Script only", "<title>App</title><script>throw 'not found'</script>", nil, false},
+		{"Code only", "<title>404 ways to cook</title>", []string{"404"}, false},
+		{"Normal page", "<title>Admin</title><h1>Login</h1>", nil, false},
+	}
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			score, signals := NotFoundScore(x.content)
+			if !reflect.DeepEqual(signals, x.signals) {
+				t.Fatalf("expected signals %v, got %v", x.signals, signals)
+			}
+			if got := IsNotFoundPage(x.content); got != x.notFound {
+				t.Fatalf("expected not found %t, got %t (score %.2f)", x.notFound, got, score)
+			}
+		})
+	}
+}
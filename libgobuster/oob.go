@@ -0,0 +1,354 @@
+package libgobuster
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// oobCorrelationIDLength and oobNonceLength are the lengths of the
+	// parts of an interactsh subdomain, the server correlates on the first
+	oobCorrelationIDLength = 20
+	oobNonceLength         = 13
+	// oobPollInterval is the interval the interactions are polled at
+	oobPollInterval = 5 * time.Second
+	// oobGrace is how long the callbacks of the last probes are awaited
+	oobGrace = 10 * time.Second
+	// oobExpiry is how long the url of a callback hostname is kept, the
+	// oldest ones are dropped first once oobMaxHostnames are kept
+	oobExpiry       = time.Hour
+	oobMaxHostnames = 100000
+)
+
+// oobIDChars are the characters of the interactsh subdomains
+const oobIDChars = "0123456789abcdefghijklmnopqrstuv"
+
+// OOBCallback is an interaction with the -oob-server triggered by the
+// callback hostname sent to a path
+type OOBCallback struct {
+	URL           string    `json:"url"`
+	Protocol      string    `json:"protocol"`
+	RemoteAddress string    `json:"remote_address"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+// oobInteraction is an interaction as polled from an interactsh server
+type oobInteraction struct {
+	Protocol      string    `json:"protocol"`
+	UniqueID      string    `json:"unique-id"`
+	RemoteAddress string    `json:"remote-address"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+// oobClient registers with an interactsh server and correlates the
+// interactions it polls with the urls their hostnames were sent to
+type oobClient struct {
+	server        *url.URL
+	token         string
+	client        *http.Client
+	key           *rsa.PrivateKey
+	correlationID string
+	secret        string
+	rand          Rand
+	clock         Clock
+
+	mu   sync.Mutex
+	urls map[string]string
+	// sent holds the callback hostnames in the order they were sent
+	sent     []oobHostname
+	lastSent time.Time
+}
+
+// oobHostname is a callback hostname sent to a url
+type oobHostname struct {
+	id   string
+	time time.Time
+}
+
+// parseOOBServer returns the url of an -oob-server, https unless given
+func parseOOBServer(server string) (*url.URL, error) {
+	if !strings.Contains(server, "://") {
+		server = "https://" + server
+	}
+	u, err := url.Parse(server)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid oob server given: %s", server)
+	}
+	return u, nil
+}
+
+// randomID returns n random characters of the interactsh subdomains
+func randomID(r Rand, n int) string {
+	id := make([]byte, n)
+	for i := range id {
+		id[i] = oobIDChars[r.Intn(len(oobIDChars))]
+	}
+	return string(id)
+}
+
+// newOOBClient returns a client of the -oob-server sending its requests
+// over the transport of the scan, so they go through its proxy
+func newOOBClient(opt *Options, r Rand, transport http.RoundTripper) (*oobClient, error) {
+	server, err := parseOOBServer(opt.OOBServer)
+	if err != nil {
+		return nil, err
+	}
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	return &oobClient{
+		server:        server,
+		token:         opt.OOBToken,
+		client:        &http.Client{Timeout: opt.Timeout, Transport: transport},
+		key:           key,
+		correlationID: randomID(r, oobCorrelationIDLength),
+		secret:        randomID(r, oobCorrelationIDLength),
+		rand:          r,
+		clock:         opt.clock(),
+		urls:          make(map[string]string),
+	}, nil
+}
+
+// call sends a request to the interactsh server and decodes its answer
+// into out, when given
+func (o *oobClient) call(c context.Context, method, path string, query url.Values, body interface{}, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	endpoint := o.server.ResolveReference(&url.URL{Path: path, RawQuery: query.Encode()})
+	req, err := http.NewRequest(method, endpoint.String(), reader)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(c)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if o.token != "" {
+		req.Header.Set("Authorization", o.token)
+	}
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("oob server %s answered %d to %s", o.server.Host, resp.StatusCode, path)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// register sends the public key the interactions are encrypted with
+func (o *oobClient) register(c context.Context) error {
+	der, err := x509.MarshalPKIXPublicKey(&o.key.PublicKey)
+	if err != nil {
+		return err
+	}
+	public := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	return o.call(c, http.MethodPost, "/register", nil, map[string]string{
+		"public-key":     base64.StdEncoding.EncodeToString(public),
+		"secret-key":     o.secret,
+		"correlation-id": o.correlationID,
+	}, nil)
+}
+
+// deregister lets the server forget the correlation id
+func (o *oobClient) deregister(c context.Context) error {
+	return o.call(c, http.MethodPost, "/deregister", nil, map[string]string{
+		"secret-key":     o.secret,
+		"correlation-id": o.correlationID,
+	}, nil)
+}
+
+// hostname returns a new callback hostname for the url, the expired
+// ones are forgotten
+func (o *oobClient) hostname(targetURL string) string {
+	id := o.correlationID + randomID(o.rand, oobNonceLength)
+	now := o.clock.Now()
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for len(o.sent) > 0 && (len(o.sent) >= oobMaxHostnames || now.Sub(o.sent[0].time) > oobExpiry) {
+		delete(o.urls, o.sent[0].id)
+		o.sent = o.sent[1:]
+	}
+	o.urls[id] = targetURL
+	o.sent = append(o.sent, oobHostname{id: id, time: now})
+	o.lastSent = now
+	return id + "." + o.server.Hostname()
+}
+
+// grace returns how long the callbacks of the last hostname sent are
+// still awaited, false if none was sent
+func (o *oobClient) grace() (time.Duration, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.lastSent.IsZero() {
+		return 0, false
+	}
+	return oobGrace - o.clock.Now().Sub(o.lastSent), true
+}
+
+// decryptInteraction returns the interaction of an AES-256-CFB
+// encrypted message, the IV first
+func decryptInteraction(key []byte, message string) (*oobInteraction, error) {
+	data, err := base64.StdEncoding.DecodeString(message)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < aes.BlockSize {
+		return nil, fmt.Errorf("interaction too short")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	plain := make([]byte, len(data)-aes.BlockSize)
+	cipher.NewCFBDecrypter(block, data[:aes.BlockSize]).XORKeyStream(plain, data[aes.BlockSize:])
+	var i oobInteraction
+	if err := json.Unmarshal(plain, &i); err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
+
+// poll returns the callbacks of the interactions since the last poll,
+// the interactions of unknown hostnames are dropped
+func (o *oobClient) poll(c context.Context) ([]OOBCallback, error) {
+	var answer struct {
+		Data   []string `json:"data"`
+		AESKey string   `json:"aes_key"`
+	}
+	query := url.Values{"id": {o.correlationID}, "secret": {o.secret}}
+	if err := o.call(c, http.MethodGet, "/poll", query, nil, &answer); err != nil {
+		return nil, err
+	}
+	if len(answer.Data) == 0 {
+		return nil, nil
+	}
+	encryptedKey, err := base64.StdEncoding.DecodeString(answer.AESKey)
+	if err != nil {
+		return nil, err
+	}
+	key, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, o.key, encryptedKey, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var callbacks []OOBCallback
+	for _, message := range answer.Data {
+		i, err := decryptInteraction(key, message)
+		if err != nil {
+			return callbacks, err
+		}
+		o.mu.Lock()
+		targetURL, ok := o.urls[strings.ToLower(i.UniqueID)]
+		o.mu.Unlock()
+		if !ok {
+			continue
+		}
+		callbacks = append(callbacks, OOBCallback{URL: targetURL, Protocol: i.Protocol, RemoteAddress: i.RemoteAddress, Timestamp: i.Timestamp})
+	}
+	return callbacks, nil
+}
+
+// startOOB registers with the -oob-server and polls it until the scan
+// ends
+func (g *Gobuster) startOOB() error {
+	o, err := newOOBClient(g.Opts, g.Random(), g.HTTP.client().Transport)
+	if err != nil {
+		return err
+	}
+	if err := o.register(g.context); err != nil {
+		return fmt.Errorf("failed to register with the oob server: %v", err)
+	}
+	g.oob = o
+	g.Logf("[+] Registered with the oob server %s", o.server.Host)
+	go func() {
+		ticker := time.NewTicker(oobPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-g.context.Done():
+				return
+			case <-ticker.C:
+				g.pollOOB()
+			}
+		}
+	}()
+	return nil
+}
+
+// pollOOB records and prints the callbacks polled from the -oob-server
+func (g *Gobuster) pollOOB() {
+	callbacks, err := g.oob.poll(g.context)
+	if err != nil && g.context.Err() == nil {
+		g.Logf("[!] Unable to poll the oob server: %v", err)
+	}
+	for _, cb := range callbacks {
+		g.Logf("[+] OOB %s callback from %s triggered by %s", cb.Protocol, cb.RemoteAddress, cb.URL)
+	}
+	g.mu.Lock()
+	g.oobCallbacks = append(g.oobCallbacks, callbacks...)
+	g.mu.Unlock()
+}
+
+// stopOOB awaits the callbacks of the last probes and deregisters from
+// the -oob-server. Nothing is awaited when no hostname was sent.
+func (g *Gobuster) stopOOB() {
+	if g.oob == nil {
+		return
+	}
+	if wait, sent := g.oob.grace(); sent {
+		select {
+		case <-time.After(wait):
+			g.pollOOB()
+		case <-g.context.Done():
+		}
+	}
+	if err := g.oob.deregister(context.Background()); err != nil {
+		g.Logf("[!] Unable to deregister from the oob server: %v", err)
+	}
+}
+
+// OOBRequestOptions sends a new callback hostname of the -oob-server in
+// the Referer and X-Forwarded-For headers of the request, the
+// applications processing them later call back
+func (g *Gobuster) OOBRequestOptions(ro RequestOptions, targetURL string) RequestOptions {
+	if g.oob == nil {
+		return ro
+	}
+	host := g.oob.hostname(targetURL)
+	headers := http.Header{}
+	for name, values := range ro.Headers {
+		headers[name] = values
+	}
+	headers.Set("Referer", "http://"+host+"/")
+	headers.Set("X-Forwarded-For", host)
+	ro.Headers = headers
+	return ro
+}
@@ -0,0 +1,155 @@
+package libgobuster
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// interactshStub registers one client and answers its polls with the
+// interactions of the given unique ids
+type interactshStub struct {
+	mu        sync.Mutex
+	publicKey *rsa.PublicKey
+	uniqueIDs []string
+}
+
+func (s *interactshStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch r.URL.Path {
+	case "/register":
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		data, _ := base64.StdEncoding.DecodeString(body["public-key"])
+		block, _ := pem.Decode(data)
+		key, _ := x509.ParsePKIXPublicKey(block.Bytes)
+		s.publicKey = key.(*rsa.PublicKey)
+	case "/poll":
+		aesKey := make([]byte, 32)
+		rand.Read(aesKey)
+		encryptedKey, _ := rsa.EncryptOAEP(sha256.New(), rand.Reader, s.publicKey, aesKey, nil)
+		var messages []string
+		for _, id := range s.uniqueIDs {
+			plain, _ := json.Marshal(map[string]string{"protocol": "dns", "unique-id": id, "remote-address": "10.0.0.1"})
+			block, _ := aes.NewCipher(aesKey)
+			data := make([]byte, aes.BlockSize+len(plain))
+			rand.Read(data[:aes.BlockSize])
+			cipher.NewCFBEncrypter(block, data[:aes.BlockSize]).XORKeyStream(data[aes.BlockSize:], plain)
+			messages = append(messages, base64.StdEncoding.EncodeToString(data))
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": messages, "aes_key": base64.StdEncoding.EncodeToString(encryptedKey)})
+	case "/deregister":
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestOOBCallbacks(t *testing.T) {
+	t.Parallel()
+
+	stub := &interactshStub{}
+	h := httptest.NewServer(stub)
+	defer h.Close()
+
+	o := NewOptions()
+	o.OOBServer = h.URL
+	client, err := newOOBClient(o, NewRand(1), nil)
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if err := client.register(context.Background()); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	g := &Gobuster{Opts: o, oob: client, mu: new(sync.RWMutex), context: context.Background()}
+
+	ro := g.OOBRequestOptions(RequestOptions{}, "http://target/admin")
+	host := ro.Headers.Get("X-Forwarded-For")
+	id := strings.TrimSuffix(host, "."+client.server.Hostname())
+	if len(id) != oobCorrelationIDLength+oobNonceLength || !strings.HasPrefix(id, client.correlationID) {
+		t.Fatalf("unexpected callback hostname %q", host)
+	}
+	if ro.Headers.Get("Referer") != "http://"+host+"/" {
+		t.Fatalf("unexpected referer %q", ro.Headers.Get("Referer"))
+	}
+
+	stub.mu.Lock()
+	stub.uniqueIDs = []string{strings.ToUpper(id), client.correlationID + "unknownnonce1"}
+	stub.mu.Unlock()
+	g.pollOOB()
+	if len(g.oobCallbacks) != 1 || g.oobCallbacks[0].URL != "http://target/admin" || g.oobCallbacks[0].Protocol != "dns" || g.oobCallbacks[0].RemoteAddress != "10.0.0.1" {
+		t.Fatalf("unexpected callbacks %+v", g.oobCallbacks)
+	}
+}
+
+func TestOOBHostnameExpiry(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	o := &oobClient{server: &url.URL{Host: "oob.example.com"}, rand: NewRand(1), clock: FixedClock(now), urls: make(map[string]string)}
+	if _, sent := o.grace(); sent {
+		t.Fatal("expected no grace before a hostname is sent")
+	}
+	o.hostname("http://target/a")
+	now = now.Add(oobExpiry / 2)
+	o.clock = FixedClock(now)
+	o.hostname("http://target/b")
+	if wait, sent := o.grace(); !sent || wait != oobGrace {
+		t.Fatalf("expected a grace of %s, got %s", oobGrace, wait)
+	}
+	now = now.Add(oobExpiry + time.Second)
+	o.clock = FixedClock(now)
+	o.hostname("http://target/c")
+	if len(o.urls) != 1 || len(o.sent) != 1 {
+		t.Fatalf("expected the expired hostnames forgotten, got %d", len(o.urls))
+	}
+	for i := 0; i < oobMaxHostnames+10; i++ {
+		o.hostname("http://target/d")
+	}
+	if len(o.urls) != oobMaxHostnames || len(o.sent) != oobMaxHostnames {
+		t.Fatalf("expected %d hostnames, got %d", oobMaxHostnames, len(o.urls))
+	}
+}
+
+func TestParseOOBServer(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		server   string
+		expected string
+		err      bool
+	}{
+		{"oast.pro", "https://oast.pro", false},
+		{"http://127.0.0.1:8080", "http://127.0.0.1:8080", false},
+		{"https://", "", true},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.server, func(t *testing.T) {
+			t.Parallel()
+
+			u, err := parseOOBServer(x.server)
+			if (err != nil) != x.err {
+				t.Fatalf("expected error %t, got %v", x.err, err)
+			}
+			if err == nil && u.String() != x.expected {
+				t.Fatalf("expected %q, got %q", x.expected, u.String())
+			}
+		})
+	}
+}
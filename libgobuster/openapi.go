@@ -0,0 +1,180 @@
+package libgobuster
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// apiOperation is a method of an endpoint of the OpenAPI inventory, the
+// content types are the ones of its responses
+type apiOperation struct {
+	statuses     Set[int]
+	contentTypes map[int]Set[string]
+}
+
+// apiEndpoint is a path of the OpenAPI inventory
+type apiEndpoint struct {
+	parameters Set[string]
+	operations map[string]*apiOperation
+}
+
+// apiInventory collects the matches of a dir scan, with -openapi, into
+// the skeleton of an OpenAPI 3 spec
+type apiInventory struct {
+	mu        sync.Mutex
+	endpoints map[string]*apiEndpoint
+}
+
+func newAPIInventory() *apiInventory {
+	return &apiInventory{endpoints: make(map[string]*apiEndpoint)}
+}
+
+// add records a match under its path relative to the application root,
+// the names of its query become parameters
+func (a *apiInventory) add(appPath string, r *Result) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	p, query := appPath, ""
+	if i := strings.Index(appPath, "?"); i >= 0 {
+		p, query = appPath[:i], appPath[i+1:]
+	}
+	e, ok := a.endpoints[p]
+	if !ok {
+		e = &apiEndpoint{parameters: NewSet[string](), operations: make(map[string]*apiOperation)}
+		a.endpoints[p] = e
+	}
+	if values, err := url.ParseQuery(query); err == nil {
+		for name := range values {
+			e.parameters.Add(name)
+		}
+	}
+
+	e.operation(http.MethodGet).addResponse(r.Status, r.ContentType)
+	for m, status := range r.Methods {
+		if m == http.MethodGet || m == http.MethodHead || m == http.MethodOptions {
+			continue
+		}
+		e.operation(m).addResponse(status, "")
+	}
+}
+
+func (e *apiEndpoint) operation(method string) *apiOperation {
+	o, ok := e.operations[method]
+	if !ok {
+		o = &apiOperation{statuses: NewSet[int](), contentTypes: make(map[int]Set[string])}
+		e.operations[method] = o
+	}
+	return o
+}
+
+// addResponse records a status, 0 when the method was only listed by the
+// Allow header, and the media type of the response
+func (o *apiOperation) addResponse(status int, contentType string) {
+	o.statuses.Add(status)
+	mediaType := strings.TrimSpace(strings.Split(contentType, ";")[0])
+	if mediaType == "" {
+		return
+	}
+	types, ok := o.contentTypes[status]
+	if !ok {
+		types = NewSet[string]()
+		o.contentTypes[status] = types
+	}
+	types.Add(strings.ToLower(mediaType))
+}
+
+// yamlString quotes a YAML scalar, a JSON string is a valid one
+func yamlString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+// openAPIMethodOrder orders the operations of a path, they are the
+// methods an OpenAPI path item may hold
+var openAPIMethodOrder = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// yaml renders the inventory as an OpenAPI 3 document, only the methods
+// OpenAPI knows are kept
+func (a *apiInventory) yaml(title, server, version string) []byte {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "openapi: 3.0.3\n")
+	fmt.Fprintf(&buf, "info:\n  title: %s\n  version: %s\n", yamlString(title), yamlString(version))
+	fmt.Fprintf(&buf, "  description: %s\n", yamlString("Endpoints discovered by yBuster, a starting point for manual exploration"))
+	fmt.Fprintf(&buf, "servers:\n  - url: %s\n", yamlString(server))
+	if len(a.endpoints) == 0 {
+		fmt.Fprintf(&buf, "paths: {}\n")
+		return buf.Bytes()
+	}
+
+	paths := make([]string, 0, len(a.endpoints))
+	for p := range a.endpoints {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	fmt.Fprintf(&buf, "paths:\n")
+	for _, p := range paths {
+		e := a.endpoints[p]
+		fmt.Fprintf(&buf, "  %s:\n", yamlString(p))
+		if e.parameters.Len() > 0 {
+			fmt.Fprintf(&buf, "    parameters:\n")
+			for _, name := range e.parameters.Values() {
+				fmt.Fprintf(&buf, "      - name: %s\n        in: query\n        schema:\n          type: string\n", yamlString(name))
+			}
+		}
+		for _, method := range openAPIMethodOrder {
+			o, ok := e.operations[strings.ToUpper(method)]
+			if !ok {
+				continue
+			}
+			fmt.Fprintf(&buf, "    %s:\n      responses:\n", method)
+			for _, status := range o.statuses.Values() {
+				if status == 0 {
+					fmt.Fprintf(&buf, "        default:\n          description: %s\n", yamlString("Listed in the Allow header"))
+					continue
+				}
+				fmt.Fprintf(&buf, "        %s:\n          description: %s\n", yamlString(strconv.Itoa(status)), yamlString(http.StatusText(status)))
+				if types := o.contentTypes[status]; types.Len() > 0 {
+					fmt.Fprintf(&buf, "          content:\n")
+					for _, t := range types.Values() {
+						fmt.Fprintf(&buf, "            %s: {}\n", yamlString(t))
+					}
+				}
+			}
+		}
+	}
+	return buf.Bytes()
+}
+
+// WriteOpenAPI writes the matches of a dir scan, with -openapi, as the
+// skeleton of an OpenAPI 3 spec of the target to the output_openapi
+// folder
+func (g *Gobuster) WriteOpenAPI() error {
+	if g.openAPI == nil {
+		return nil
+	}
+	folder := g.Opts.OutputFolder + "/output_openapi"
+	if err := os.MkdirAll(folder, 0755); err != nil {
+		return fmt.Errorf("failed to create openapi folder: %v", err)
+	}
+
+	data := g.openAPI.yaml(g.BaseURL(), g.BaseURL(), g.RunID)
+	filename := fmt.Sprintf("%s/openapi_%s.yaml", folder, g.OutputFileSuffix())
+	if err := ioutil.WriteFile(filename, data, 0644); err != nil {
+		return fmt.Errorf("failed to write openapi spec: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package libgobuster
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAPIInventory(t *testing.T) {
+	t.Parallel()
+
+	a := newAPIInventory()
+	a.add("/api/users?id=1&sort=name", &Result{Status: 200, ContentType: "application/json; charset=utf-8", Methods: map[string]int{"GET": 0, "OPTIONS": 0, "PUT": 201, "DELETE": 0}})
+	a.add("/admin", &Result{Status: 301})
+	a.add("/api/users", &Result{Status: 401, ContentType: "text/html"})
+
+	expected := `openapi: 3.0.3
+info:
+  title: "http://example.com/"
+  version: "run"
+  description: "Endpoints discovered by yBuster, a starting point for manual exploration"
+servers:
+  - url: "http://example.com/"
+paths:
+  "/admin":
+    get:
+      responses:
+        "301":
+          description: "Moved Permanently"
+  "/api/users":
+    parameters:
+      - name: "id"
+        in: query
+        schema:
+          type: string
+      - name: "sort"
+        in: query
+        schema:
+          type: string
+    get:
+      responses:
+        "200":
+          description: "OK"
+          content:
+            "application/json": {}
+        "401":
+          description: "Unauthorized"
+          content:
+            "text/html": {}
+    put:
+      responses:
+        "201":
+          description: "Created"
+    delete:
+      responses:
+        default:
+          description: "Listed in the Allow header"
+`
+	if got := string(a.yaml("http://example.com/", "http://example.com/", "run")); got != expected {
+		t.Fatalf("unexpected spec:\n%s", got)
+	}
+}
+
+func TestAPIInventoryEmpty(t *testing.T) {
+	t.Parallel()
+
+	got := string(newAPIInventory().yaml("t", "http://example.com/", "run"))
+	if !strings.HasSuffix(got, "paths: {}\n") {
+		t.Fatalf("expected empty paths, got:\n%s", got)
+	}
+}
@@ -1,232 +1,1111 @@
-package libgobuster
-
-import (
-	"bufio"
-	"fmt"
-	"os"
-	"regexp"
-	"strconv"
-	"strings"
-	"time"
-
-	multierror "github.com/hashicorp/go-multierror"
-)
-
-const (
-	// ModeDir represents -m dir
-	ModeDir = "dir"
-	// ModeDNS represents -m dns
-	ModeDNS = "dns"
-)
-
-// Options helds all options that can be passed to libgobuster
-type Options struct {
-	Extensions                string
-	ExtensionsParsed          stringSet
-	Mode                      string
-	OutputFilename			  string
-	OutputFolder			  string
-	Password                  string
-	ExcludedStatusCodes       string
-	ExcludedStatusCodesParsed intSet
-	Threads                   int
-	URL                       string
-	UserAgent                 string
-	Username                  string
-	Wordlist                  string
-	Proxy                     string
-	Cookies                   string
-	Timeout                   time.Duration
-	FollowRedirect            bool
-	IncludeLength             bool
-	NoStatus                  bool
-	NoProgress                bool
-	Expanded                  bool
-	Quiet                     bool
-	ShowIPs                   bool
-	ShowCNAME                 bool
-	InsecureSSL               bool
-	WildcardForced            bool
-	Verbose                   bool
-	UseSlash                  bool
-	WaybackUrls               string
-	TargetUrls                string
-	RandomAgent               string
-	RandomAgentParsed         []string
-	ExcludeString             string
-	BlankExtension            bool
-}
-
-// NewOptions returns a new initialized Options object
-func NewOptions() *Options {
-	return &Options{
-		ExcludedStatusCodesParsed: newIntSet(),
-		ExtensionsParsed:          newStringSet(),
-	}
-}
-
-// Validate validates the given options
-func (opt *Options) validate() *multierror.Error {
-	var errorList *multierror.Error
-
-	if strings.ToLower(opt.Mode) != ModeDir && strings.ToLower(opt.Mode) != ModeDNS {
-		errorList = multierror.Append(errorList, fmt.Errorf("Mode (-m): Invalid value: %s", opt.Mode))
-	}
-
-	if opt.Threads < 0 {
-		errorList = multierror.Append(errorList, fmt.Errorf("Threads (-t): Invalid value: %d", opt.Threads))
-	}
-
-	if opt.Wordlist == "" {
-		errorList = multierror.Append(errorList, fmt.Errorf("WordList (-w): Must be specified (use `-w -` for stdin)"))
-	} else if opt.Wordlist == "-" {
-		// STDIN
-	} else if _, err := os.Stat(opt.Wordlist); os.IsNotExist(err) {
-		errorList = multierror.Append(errorList, fmt.Errorf("Wordlist (-w): File does not exist: %s", opt.Wordlist))
-	}
-
-	if opt.URL == "" {
-		errorList = multierror.Append(errorList, fmt.Errorf("Url/Domain (-u): Must be specified: %s",opt.URL))
-	}
-
-	if opt.OutputFolder == "" {
-		errorList = multierror.Append(errorList, fmt.Errorf("Output folder (-of): Must be specified: %s",opt.OutputFolder))
-	}
-
-
-	if opt.ExcludedStatusCodes != "" {
-		if err := opt.parseStatusCodes(); err != nil {
-			errorList = multierror.Append(errorList, err)
-		}
-	}
-
-	if opt.Extensions != "" {
-		if err := opt.parseExtensions(); err != nil {
-			errorList = multierror.Append(errorList, err)
-		}
-	}
-
-	if opt.Mode == ModeDir {
-		if !strings.HasSuffix(opt.URL, "/") {
-			opt.URL = fmt.Sprintf("%s/", opt.URL)
-		}
-
-		if err := opt.validateDirMode(); err != nil {
-			errorList = multierror.Append(errorList, err)
-		}
-	}
-
-	if opt.WaybackUrls != "" {
-		if _, err := os.Stat(opt.WaybackUrls); os.IsNotExist(err) {
-			errorList = multierror.Append(errorList, fmt.Errorf("Wayback urls (-waybackurls): File does not exist: %s", opt.WaybackUrls))
-		}
-	}
-
-	if opt.RandomAgent != "" {
-		if _, err := os.Stat(opt.RandomAgent); os.IsNotExist(err) {
-			errorList = multierror.Append(errorList, fmt.Errorf("Random agent (-random-agent): File does not exist: %s", opt.RandomAgent))
-		} else {
-			if err := opt.parseRandomAgents(); err != nil {
-				errorList = multierror.Append(errorList, err)
-			}
-		}
-	}
-
-	if opt.TargetUrls != "" {
-		if _, err := os.Stat(opt.TargetUrls); os.IsNotExist(err) {
-			errorList = multierror.Append(errorList, fmt.Errorf("Target urls (-target-urls): File does not exist: %s", opt.TargetUrls))
-		}
-	}
-
-	return errorList
-}
-
-// ParseExtensions parses the extensions provided as a comma seperated list
-func (opt *Options) parseExtensions() error {
-	if opt.Extensions == "" {
-		return fmt.Errorf("invalid extension string provided")
-	}
-
-	exts := strings.Split(opt.Extensions, ",")
-	for _, e := range exts {
-		e = strings.TrimSpace(e)
-		// remove leading . from extensions
-		opt.ExtensionsParsed.Add(strings.TrimPrefix(e, "."))
-	}
-	return nil
-}
-
-// ParseStatusCodes parses the status codes provided as a comma seperated list
-func (opt *Options) parseStatusCodes() error {
-	if opt.ExcludedStatusCodes == "" {
-		return fmt.Errorf("invalid status code string provided")
-	}
-
-	for _, c := range strings.Split(opt.ExcludedStatusCodes, ",") {
-		c = strings.TrimSpace(c)
-		i, err := strconv.Atoi(c)
-		if err != nil {
-			return fmt.Errorf("invalid status code given: %s", c)
-		}
-		opt.ExcludedStatusCodesParsed.Add(i)
-	}
-	return nil
-}
-
-func (opt *Options) parseRandomAgents() error {
-	randomAgents, err := os.Open(opt.RandomAgent)
-	if err != nil {
-		return fmt.Errorf("failed to open random agents: %v", err)
-	}
-
-	// rewind random agents
-	_, err = randomAgents.Seek(0, 0)
-	if err != nil {
-		return fmt.Errorf("failed to rewind random agents: %v", err)
-	}
-
-	scanner := bufio.NewScanner(randomAgents)
-	for scanner.Scan() {
-		if !strings.HasPrefix(scanner.Text(), "#") && len(scanner.Text()) > 0 {
-			opt.RandomAgentParsed = append(opt.RandomAgentParsed, scanner.Text())
-		}
-	}
-
-	if err := scanner.Err(); err != nil {
-		return fmt.Errorf("failed to scan random agents: %v", err)
-	}
-
-	return nil
-}
-
-func (opt *Options) validateDirMode() error {
-	// bail out if we are not in dir mode
-	if opt.Mode != ModeDir {
-		return nil
-	}
-	if !strings.HasPrefix(opt.URL, "http") {
-		// check to see if a port was specified
-		re := regexp.MustCompile(`^[^/]+:(\d+)`)
-		match := re.FindStringSubmatch(opt.URL)
-
-		if len(match) < 2 {
-			// no port, default to http on 80
-			opt.URL = fmt.Sprintf("http://%s", opt.URL)
-		} else {
-			port, err := strconv.Atoi(match[1])
-			if err != nil || (port != 80 && port != 443) {
-				return fmt.Errorf("url scheme not specified")
-			} else if port == 80 {
-				opt.URL = fmt.Sprintf("http://%s", opt.URL)
-			} else {
-				opt.URL = fmt.Sprintf("https://%s", opt.URL)
-			}
-		}
-	}
-
-	if opt.Username != "" && opt.Password == "" {
-		return fmt.Errorf("username was provided but password is missing")
-	}
-
-	return nil
-}
+package libgobuster
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gosirys/gobuster/libgobuster/urlnorm"
+
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+const (
+	// ModeDir represents -m dir
+	ModeDir = "dir"
+	// ModeDNS represents -m dns
+	ModeDNS = "dns"
+	// ModeDNSDir represents -m dns+dir, a dns scan feeding dir scans
+	ModeDNSDir = "dns+dir"
+)
+
+const (
+	// FormatPlain is the default output of the matches
+	FormatPlain = "plain"
+	// FormatHTTPX prints one url per line, ready to pipe into other tools
+	FormatHTTPX = "httpx"
+	// FormatHTTPXAnnotated adds the status and length to the httpx urls
+	FormatHTTPXAnnotated = "httpx-annotated"
+)
+
+const (
+	// HostFuzzHost sends the words as the Host header
+	HostFuzzHost = "host"
+	// HostFuzzForwarded sends the words as the X-Forwarded-Host header
+	HostFuzzForwarded = "x-forwarded-host"
+	// HostFuzzBoth sends the words as both headers
+	HostFuzzBoth = "both"
+)
+
+// DefaultExtToken is the wordlist placeholder replaced by the extensions
+const DefaultExtToken = "%EXT%"
+
+const (
+	// EnvPassword is the environment variable holding the Basic Auth password
+	EnvPassword = "YBUSTER_PASSWORD"
+	// EnvBearerToken is the environment variable holding the bearer token
+	EnvBearerToken = "YBUSTER_BEARER_TOKEN"
+	// EnvCookies is the environment variable holding the request cookies
+	EnvCookies = "YBUSTER_COOKIES"
+)
+
+// Options helds all options that can be passed to libgobuster
+type Options struct {
+	Extensions                string
+	ExtensionsParsed          Set[string]
+	Mode                      string
+	OutputFilename			  string
+	OutputFolder			  string
+	Password                  string
+	ExcludedStatusCodes       string
+	ExcludedStatusCodesParsed Set[int]
+	Threads                   int
+	URL                       string
+	// BasePath is the path of the application root on the target,
+	// normalized to /app/v2/
+	BasePath                  string
+	UserAgent                 string
+	Username                  string
+	Wordlist                  string
+	// WordlistDirs are requested with a trailing slash, WordlistFiles
+	// with every extension
+	WordlistDirs              string
+	WordlistFiles             string
+	Proxy                     string
+	// PACURL and PACFile are the proxy auto-config file picking the
+	// proxy of every host, PAC is the file once loaded
+	PACURL                    string
+	PACFile                   string
+	PAC                       *PAC
+	Tor                       bool
+	TorProxy                  string
+	TorControl                string
+	TorPassword               string
+	TorRenewRequests          int
+	TorRenewEvery             time.Duration
+	Cookies                   string
+	Timeout                   time.Duration
+	FollowRedirect            bool
+	IncludeLength             bool
+	NoStatus                  bool
+	NoProgress                bool
+	Expanded                  bool
+	Quiet                     bool
+	ShowIPs                   bool
+	ShowCNAME                 bool
+	ShowSource                bool
+	WildcardAllow             string
+	WildcardAllowParsed       Set[string]
+	Takeover                  bool
+	TakeoverFingerprints      string
+	TakeoverFingerprintsParsed []TakeoverFingerprint
+	InsecureSSL               bool
+	WildcardForced            bool
+	Verbose                   bool
+	UseSlash                  bool
+	WaybackUrls               string
+	WaybackQuery              string
+	WaybackStripExts          string
+	WaybackStripExtsParsed    []string
+	CommonCrawl               bool
+	TargetUrls                string
+	// Tags are the tags of the target given by the target urls file,
+	// its matches are recorded per tag
+	Tags                      []string
+	CIDR                      string
+	Ports                     string
+	PortsParsed               []int
+	TCPCheck                  time.Duration
+	// LivenessTimeout bounds the request checking every target of a run
+	// over many targets is alive before its scan, 0 disables the check
+	LivenessTimeout           time.Duration
+	// DNSCacheTTL caches the addresses of the target for this long,
+	// bypassing the caches of the system
+	DNSCacheTTL               time.Duration
+	// DNSCache holds the addresses of the hosts of the scans of a run,
+	// nil gives every scan a cache of its own
+	DNSCache                  *DNSCache
+	// StaticResolve overrides the addresses of hosts, host=ip pairs
+	StaticResolve             string
+	StaticResolveParsed       map[string][]string
+	// ConnRecycle closes the idle connections at this interval
+	ConnRecycle               time.Duration
+	// FoundHost is called with every subdomain found by dns mode, dns+dir
+	// probes and scans them as they come
+	FoundHost func(host string)
+	// WebProbePorts are the ports of the subdomains found checked for a
+	// web server once a dns scan is over, as they are found by dns+dir
+	WebProbePorts             string
+	WebProbePortsParsed       []int
+	RandomAgent               string
+	RandomAgentParsed         []string
+	AgentStrategy             string
+	NoCharset                 bool
+	MaxResponseSize           int64
+	// StreamTimeout bounds the reading of streaming bodies and of the
+	// bodies stalling that long, 0 reads them until the request timeout
+	StreamTimeout             time.Duration
+	Syslog                    string
+	ResultsSocket             string
+	Format                    string
+	PerTargetTimeout          time.Duration
+	Warmup                    int
+	WarmupThreads             int
+	ExcludeString             string
+	ShowContentType           bool
+	// Columns are the comma separated columns of the console output
+	Columns                   string
+	ColumnsParsed             []string
+	ExcludeContentType        string
+	ExcludeContentTypeParsed  []string
+	IncludeContentType        string
+	IncludeContentTypeParsed  []string
+	BlankExtension            bool
+	// ExtToken is replaced by every extension in the wordlist lines
+	ExtToken                  string
+	HeadFirst                 bool
+	// NoBackoff records the 429 and 503 responses instead of pausing
+	// and retrying the words
+	NoBackoff                 bool
+	// WordlistFromTarget requests the words of the baseline page after
+	// the wordlist, WordlistFromMatches the words of the matches too
+	WordlistFromTarget        bool
+	WordlistFromMatches       bool
+	// Learn recombines the wordlist with the tokens of the paths found,
+	// in a second pass
+	Learn                     bool
+	// JSEndpoints requests the endpoints of the scripts found
+	JSEndpoints               bool
+	// HostFuzz sends the words as the Host or X-Forwarded-Host of the
+	// requests to the url instead of paths
+	HostFuzz                  string
+	// IfModifiedSince and IfNoneMatch make the requests conditional, the
+	// paths that exist answer 304 Not Modified
+	IfModifiedSince           string
+	IfModifiedSinceParsed     time.Time
+	IfNoneMatch               string
+	// RespectRobots skips the paths disallowed by robots.txt
+	RespectRobots             bool
+	// OOBServer is the interactsh server whose callback hostnames are
+	// sent in the headers of the probes, OOBToken its token if any
+	OOBServer                 string
+	OOBToken                  string
+	// SafeMode sends GET and HEAD requests only, at SafeModeRate, and
+	// skips the dangerous paths
+	SafeMode                  bool
+	// OpenAPI writes the matches as the skeleton of an OpenAPI spec
+	OpenAPI                   bool
+	// MethodEnum sends OPTIONS and the Methods to every found endpoint
+	MethodEnum                bool
+	Methods                   string
+	MethodsParsed             []string
+	// ShowDiff writes the diff of the matches sharing the status of the
+	// wildcard baseline against its body
+	ShowDiff                  bool
+	// Evasion encodes the 401 and 403 paths to find ACL and WAF bypasses
+	Evasion                   string
+	EvasionParsed             [][]string
+	// UploadCheck puts, gets and deletes a file in the writable sounding
+	// directories found
+	UploadCheck               bool
+	// FetchArtifacts saves the matches with the extension of a dump,
+	// backup or archive into a quarantine folder, ArtifactMaxSize bytes
+	// of each at most
+	FetchArtifacts            bool
+	ArtifactMaxSize           int64
+	// PruneExts stops requesting the extensions without a hit in their
+	// first PruneExtsSample requests
+	PruneExts                 bool
+	// VerifyHits requests the matches again this many times once the
+	// scan is over, the ones never reproduced are dropped
+	VerifyHits                int
+	BearerToken               string
+	SecretsFile               string
+	// Plugins are the comma separated paths of the Go plugins
+	// exporting an Extension
+	Plugins                   string
+	// AllTimeDedup appends the endpoint and status combinations missing
+	// from the all time matches only
+	AllTimeDedup              bool
+	// DebugStats logs the memory, goroutines and queues at this interval
+	DebugStats                time.Duration
+	OnResult                  string
+	OnResultConcurrency       int
+	ErrorThreshold            string
+	ErrorThresholdParsed      float64
+	// RotateSize is the size the output files are rotated at (eg. 100MB)
+	RotateSize                string
+	RotateSizeParsed          int64
+	// ScanProfile is the profile of the output folder the options were
+	// loaded from, SaveScanProfile the one they are saved as
+	ScanProfile               string
+	SaveScanProfile           string
+	// PrintCmd prints the command line equivalent to the run and stores
+	// it in the output folder
+	PrintCmd                  bool
+	ErrorWindow               int
+	SourceIPs                 string
+	SourceIPsParsed           []net.IP
+	DebugHTTP                 string
+	WildcardProbes            int
+	WildcardLengths           string
+	WildcardLengthsParsed     []int
+	WildcardCharset           string
+	WordlistBufferSize        int
+	DNSTimeout                time.Duration
+	DNSRetries                int
+	// FastDNS sends raw UDP queries to the resolvers instead of going
+	// through the system resolver
+	FastDNS                   bool
+	Resolvers                 string
+	ResolversParsed           []string
+	API                       bool
+	ShowRedirectChain         bool
+	DoHURL                    string
+	DoTServer                 string
+	FollowUpStatusCodes       string
+	FollowUpStatusCodesParsed Set[int]
+	// LevelDelay pauses the scan before every level of discovered
+	// targets, the wordlist being the first level
+	LevelDelay                time.Duration
+	// PrioritizeCodes are the status codes of the directories whose
+	// discovered targets are requested first in their level
+	PrioritizeCodes           string
+	PrioritizeCodesParsed     Set[int]
+	Match                     string
+	MatchParsed               *MatchExpr
+	// TreatAsMissing declares the responses of a status missing paths,
+	// for every path or the paths of a shape
+	TreatAsMissing            string
+	TreatAsMissingParsed      []MissingRule
+	// Heuristic404 takes the pages reading as not found pages, in any
+	// language, for false positives
+	Heuristic404              bool
+	// Clock and Rand default to the wall clock and a time seeded source,
+	// replace them to make a run deterministic
+	Clock                     Clock
+	Rand                      Rand
+	// Logger receives the progress and warnings of the scans, the
+	// standard logger by default. Programs embedding the engine may
+	// silence it with a logger writing to ioutil.Discard.
+	Logger                    *log.Logger
+	// Pause holds the workers while paused, nil never pauses
+	Pause                     *PauseGate
+	cookiesFromSecret         bool
+	secretsResolved   bool
+}
+
+// NewOptions returns a new initialized Options object
+func NewOptions() *Options {
+	return &Options{
+		ExcludedStatusCodesParsed: NewSet[int](),
+		FollowUpStatusCodes:       "2xx,3xx",
+		AgentStrategy:             AgentPerRequest,
+		Ports:                     "80,443",
+		Format:                    FormatPlain,
+		WarmupThreads:             1,
+		ExtToken:                  DefaultExtToken,
+		Clock:                     SystemClock,
+		Rand:                      NewRand(time.Now().UnixNano()),
+		TorProxy:                  "127.0.0.1:9050",
+		WaybackQuery:              string(urlnorm.QueryKeys),
+		WaybackStripExts:          strings.Join(urlnorm.DefaultStaticExtensions, ","),
+		Methods:                   DefaultEnumMethods,
+		TorControl:                "127.0.0.1:9051",
+		FollowUpStatusCodesParsed: NewSet[int](),
+		PrioritizeCodesParsed:     NewSet[int](),
+		ExtensionsParsed:          NewSet[string](),
+		WildcardAllowParsed:       NewSet[string](),
+		WildcardProbes:            2,
+		WildcardLengths:           "16,8",
+		WildcardCharset:           "hex",
+		WordlistBufferSize:        1024 * 1024,
+		DNSTimeout:                5 * time.Second,
+		DNSRetries:                2,
+		LivenessTimeout:           5 * time.Second,
+		StreamTimeout:             3 * time.Second,
+		ArtifactMaxSize:           DefaultArtifactMaxSize,
+		MaxResponseSize:           DefaultMaxResponseSize,
+	}
+}
+
+// Validate validates the given options
+func (opt *Options) validate() *multierror.Error {
+	var errorList *multierror.Error
+
+	if strings.ToLower(opt.Mode) != ModeDir && strings.ToLower(opt.Mode) != ModeDNS {
+		errorList = multierror.Append(errorList, fmt.Errorf("Mode (-m): Invalid value: %s", opt.Mode))
+	}
+
+	switch opt.Format {
+	case FormatPlain, FormatHTTPX, FormatHTTPXAnnotated:
+	default:
+		errorList = multierror.Append(errorList, fmt.Errorf("Format (-format): Invalid value: %s", opt.Format))
+	}
+
+	if opt.Threads < 0 {
+		errorList = multierror.Append(errorList, fmt.Errorf("Threads (-t): Invalid value: %d", opt.Threads))
+	}
+
+	if opt.Wordlist == "" {
+		if opt.WordlistDirs == "" && opt.WordlistFiles == "" {
+			errorList = multierror.Append(errorList, fmt.Errorf("WordList (-w): Must be specified (use `-w -` for stdin)"))
+		}
+	} else if opt.Wordlist == "-" {
+		// STDIN
+	} else if err := checkWordlist(opt.Wordlist); err != nil {
+		errorList = multierror.Append(errorList, fmt.Errorf("Wordlist (-w): %v", err))
+	}
+	if opt.WordlistDirs != "" {
+		if err := checkWordlist(opt.WordlistDirs); err != nil {
+			errorList = multierror.Append(errorList, fmt.Errorf("Directory wordlist (-wordlist-dirs): %v", err))
+		}
+	}
+	if opt.WordlistFiles != "" {
+		if err := checkWordlist(opt.WordlistFiles); err != nil {
+			errorList = multierror.Append(errorList, fmt.Errorf("File wordlist (-wordlist-files): %v", err))
+		}
+	}
+
+	if opt.Warmup < 0 {
+		errorList = multierror.Append(errorList, fmt.Errorf("Warm-up (-warmup): Invalid value: %d", opt.Warmup))
+	} else if opt.Warmup > 0 && opt.WarmupThreads < 1 {
+		errorList = multierror.Append(errorList, fmt.Errorf("Warm-up threads (-warmup-threads): Invalid value: %d", opt.WarmupThreads))
+	}
+
+	if opt.DNSCacheTTL < 0 {
+		errorList = multierror.Append(errorList, fmt.Errorf("DNS cache ttl (-dns-cache-ttl): Invalid value: %s", opt.DNSCacheTTL))
+	}
+
+	if opt.Columns != "" {
+		if err := opt.parseColumns(); err != nil {
+			errorList = multierror.Append(errorList, fmt.Errorf("Columns (-columns): Invalid value: %v", err))
+		}
+	}
+
+	if opt.StaticResolve != "" {
+		if err := opt.parseStaticResolve(); err != nil {
+			errorList = multierror.Append(errorList, fmt.Errorf("Static resolve (-static-resolve): Invalid value: %v", err))
+		}
+	}
+
+	if opt.ConnRecycle < 0 {
+		errorList = multierror.Append(errorList, fmt.Errorf("Connection recycling (-conn-recycle): Invalid value: %s", opt.ConnRecycle))
+	}
+
+	if opt.DebugStats < 0 {
+		errorList = multierror.Append(errorList, fmt.Errorf("Debug stats (-debug-stats): Invalid value: %s", opt.DebugStats))
+	}
+
+	if opt.LivenessTimeout < 0 {
+		errorList = multierror.Append(errorList, fmt.Errorf("Liveness timeout (-liveness-timeout): Invalid value: %s", opt.LivenessTimeout))
+	}
+	if opt.LevelDelay < 0 {
+		errorList = multierror.Append(errorList, fmt.Errorf("Level delay (-level-delay): Invalid value: %s", opt.LevelDelay))
+	}
+
+	if opt.PerTargetTimeout < 0 {
+		errorList = multierror.Append(errorList, fmt.Errorf("Per target timeout (-per-target-timeout): Invalid value: %s", opt.PerTargetTimeout))
+	}
+
+	if opt.MaxResponseSize < 0 {
+		errorList = multierror.Append(errorList, fmt.Errorf("Max response size (-max-response-size): Invalid value: %d", opt.MaxResponseSize))
+	}
+
+	if opt.StreamTimeout < 0 {
+		errorList = multierror.Append(errorList, fmt.Errorf("Stream timeout (-stream-timeout): Invalid value: %s", opt.StreamTimeout))
+	}
+
+	if opt.WordlistBufferSize < 64*1024 {
+		errorList = multierror.Append(errorList, fmt.Errorf("Wordlist buffer (-wordlist-buffer): Must be at least 65536: %d", opt.WordlistBufferSize))
+	}
+
+	if opt.Mode == ModeDNS {
+		if opt.DNSTimeout <= 0 {
+			errorList = multierror.Append(errorList, fmt.Errorf("DNS timeout (-dns-timeout): Invalid value: %s", opt.DNSTimeout))
+		}
+		if opt.DNSRetries < 0 {
+			errorList = multierror.Append(errorList, fmt.Errorf("DNS retries (-dns-retries): Invalid value: %d", opt.DNSRetries))
+		}
+		if opt.WildcardAllow != "" {
+			if err := opt.parseWildcardAllow(); err != nil {
+				errorList = multierror.Append(errorList, err)
+			}
+		}
+		if opt.Takeover {
+			if err := opt.parseTakeoverFingerprints(); err != nil {
+				errorList = multierror.Append(errorList, err)
+			}
+		}
+		if opt.Resolvers != "" {
+			if err := opt.parseResolvers(); err != nil {
+				errorList = multierror.Append(errorList, err)
+			}
+		}
+		if opt.WebProbePorts != "" {
+			ports, err := parsePortList(opt.WebProbePorts)
+			if err != nil {
+				errorList = multierror.Append(errorList, fmt.Errorf("Web probe ports (-web-probe-ports): %v", err))
+			}
+			opt.WebProbePortsParsed = ports
+		}
+		if opt.FastDNS && (opt.DoHURL != "" || opt.DoTServer != "") {
+			errorList = multierror.Append(errorList, fmt.Errorf("Fast DNS (-fast-dns) can not be used with DoH (-doh-url) or DoT (-dot-server)"))
+		}
+	}
+
+	if opt.DoHURL != "" && opt.DoTServer != "" {
+		errorList = multierror.Append(errorList, fmt.Errorf("DoH url (-doh-url) and DoT server (-dot-server) can not be used together"))
+	} else if opt.DoHURL != "" && !strings.HasPrefix(opt.DoHURL, "https://") {
+		errorList = multierror.Append(errorList, fmt.Errorf("DoH url (-doh-url): Must be an https url: %s", opt.DoHURL))
+	}
+
+	if opt.URL == "" {
+		errorList = multierror.Append(errorList, fmt.Errorf("Url/Domain (-u): Must be specified: %s",opt.URL))
+	}
+
+	if opt.OutputFolder == "" {
+		errorList = multierror.Append(errorList, fmt.Errorf("Output folder (-of): Must be specified: %s",opt.OutputFolder))
+	}
+
+	if err := opt.ResolveSecrets(); err != nil {
+		errorList = multierror.Append(errorList, err)
+	}
+
+	if opt.ExcludedStatusCodes != "" {
+		if err := opt.parseStatusCodes(); err != nil {
+			errorList = multierror.Append(errorList, err)
+		}
+	}
+
+	if opt.Extensions != "" {
+		if err := opt.parseExtensions(); err != nil {
+			errorList = multierror.Append(errorList, err)
+		}
+	}
+
+	if opt.ExtToken == "" || strings.ContainsAny(opt.ExtToken, " \t") {
+		errorList = multierror.Append(errorList, fmt.Errorf("Extension token (-ext-token): Invalid value: %q", opt.ExtToken))
+	}
+
+	if opt.Mode == ModeDir {
+		if err := opt.parseWildcardProbes(); err != nil {
+			errorList = multierror.Append(errorList, err)
+		}
+
+		if err := opt.parseFollowUpStatusCodes(); err != nil {
+			errorList = multierror.Append(errorList, fmt.Errorf("Follow-up codes (-followup-codes): Invalid value: %v", err))
+		}
+
+		if opt.PrioritizeCodes != "" {
+			if err := parseStatusCodeList(opt.PrioritizeCodes, "prioritize", opt.PrioritizeCodesParsed); err != nil {
+				errorList = multierror.Append(errorList, fmt.Errorf("Prioritize codes (-prioritize-codes): Invalid value: %v", err))
+			}
+		}
+
+		if opt.ExcludeContentType != "" {
+			opt.ExcludeContentTypeParsed = parseContentTypes(opt.ExcludeContentType)
+		}
+		if opt.IncludeContentType != "" {
+			opt.IncludeContentTypeParsed = parseContentTypes(opt.IncludeContentType)
+		}
+
+		if opt.Match != "" {
+			expr, err := ParseMatchExpr(opt.Match)
+			if err != nil {
+				errorList = multierror.Append(errorList, fmt.Errorf("Match (-match): Invalid expression: %v", err))
+			}
+			opt.MatchParsed = expr
+		}
+
+		if opt.TreatAsMissing != "" {
+			rules, err := parseMissingRules(opt.TreatAsMissing)
+			if err != nil {
+				errorList = multierror.Append(errorList, fmt.Errorf("Treat as missing (-treat-as-missing): Invalid value: %v", err))
+			}
+			opt.TreatAsMissingParsed = rules
+		}
+
+		if opt.WordlistFromMatches && !opt.WordlistFromTarget {
+			errorList = multierror.Append(errorList, fmt.Errorf("Wordlist from matches (-wordlist-from-matches): Requires -wordlist-from-target"))
+		}
+		if opt.Learn && opt.Wordlist == "-" {
+			errorList = multierror.Append(errorList, fmt.Errorf("Learn (-learn): Can not be used with a wordlist read from stdin, the second pass reads it again"))
+		} else if opt.Learn && opt.Wordlist == "" {
+			errorList = multierror.Append(errorList, fmt.Errorf("Learn (-learn): Requires a wordlist (-w) to recombine"))
+		}
+
+		switch opt.HostFuzz {
+		case "", HostFuzzHost, HostFuzzForwarded, HostFuzzBoth:
+		default:
+			errorList = multierror.Append(errorList, fmt.Errorf("Host fuzz (-host-fuzz): Invalid value: %s", opt.HostFuzz))
+		}
+		if opt.HostFuzz != "" && opt.WaybackUrls != "" {
+			errorList = multierror.Append(errorList, fmt.Errorf("Host fuzz (-host-fuzz) can not be used with wayback urls (-waybackurls)"))
+		}
+		if opt.HostFuzz != "" && (opt.WordlistDirs != "" || opt.WordlistFiles != "") {
+			errorList = multierror.Append(errorList, fmt.Errorf("Host fuzz (-host-fuzz) can not be used with the directory and file wordlists (-wordlist-dirs, -wordlist-files)"))
+		}
+		if opt.HostFuzz != "" && opt.CommonCrawl {
+			errorList = multierror.Append(errorList, fmt.Errorf("Host fuzz (-host-fuzz) can not be used with common crawl urls (-commoncrawl)"))
+		}
+
+		if opt.IfModifiedSince != "" {
+			t, err := parseIfModifiedSince(opt.IfModifiedSince, opt.clock().Now())
+			if err != nil {
+				errorList = multierror.Append(errorList, fmt.Errorf("If modified since (-if-modified-since): Invalid value: %v", err))
+			}
+			opt.IfModifiedSinceParsed = t
+		}
+
+		if opt.MethodEnum {
+			if err := opt.parseMethods(); err != nil {
+				errorList = multierror.Append(errorList, err)
+			}
+		}
+		if opt.Evasion != "" {
+			if err := opt.parseEvasion(); err != nil {
+				errorList = multierror.Append(errorList, fmt.Errorf("Evasion (-evasion): Invalid value: %v", err))
+			}
+		}
+		if opt.OOBServer != "" {
+			if _, err := parseOOBServer(opt.OOBServer); err != nil {
+				errorList = multierror.Append(errorList, fmt.Errorf("OOB server (-oob-server): Invalid value: %v", err))
+			}
+		}
+		if opt.SafeMode && opt.MethodEnum {
+			errorList = multierror.Append(errorList, fmt.Errorf("Safe mode (-safe-mode) can not be used with method enumeration (-method-enum), it sends other methods than GET and HEAD"))
+		}
+		if opt.VerifyHits < 0 {
+			errorList = multierror.Append(errorList, fmt.Errorf("Verify hits (-verify-hits): Invalid value: %d", opt.VerifyHits))
+		}
+		if opt.FetchArtifacts && opt.ArtifactMaxSize <= 0 {
+			errorList = multierror.Append(errorList, fmt.Errorf("Artifact max size (-artifact-max-size): Invalid value: %d", opt.ArtifactMaxSize))
+		}
+		if opt.SafeMode && opt.UploadCheck {
+			errorList = multierror.Append(errorList, fmt.Errorf("Safe mode (-safe-mode) can not be used with upload check (-upload-check), it sends PUT and DELETE requests"))
+		}
+
+		if !strings.HasSuffix(opt.URL, "/") {
+			opt.URL = fmt.Sprintf("%s/", opt.URL)
+		}
+
+		if err := opt.validateDirMode(); err != nil {
+			errorList = multierror.Append(errorList, err)
+		}
+	}
+
+	if opt.WaybackUrls != "" || opt.CommonCrawl {
+		if _, err := os.Stat(opt.WaybackUrls); opt.WaybackUrls != "" && os.IsNotExist(err) {
+			errorList = multierror.Append(errorList, fmt.Errorf("Wayback urls (-waybackurls): File does not exist: %s", opt.WaybackUrls))
+		}
+		switch urlnorm.QueryStrategy(opt.WaybackQuery) {
+		case urlnorm.QueryKeys, urlnorm.QueryFull, urlnorm.QueryIgnore:
+		default:
+			errorList = multierror.Append(errorList, fmt.Errorf("Wayback query (-wayback-query): Invalid value: %s", opt.WaybackQuery))
+		}
+		opt.WaybackStripExtsParsed = nil
+		for _, e := range strings.Split(opt.WaybackStripExts, ",") {
+			if e = strings.TrimSpace(e); e != "" {
+				opt.WaybackStripExtsParsed = append(opt.WaybackStripExtsParsed, e)
+			}
+		}
+	}
+
+	if opt.RandomAgent != "" {
+		if _, err := os.Stat(opt.RandomAgent); os.IsNotExist(err) {
+			errorList = multierror.Append(errorList, fmt.Errorf("Random agent (-random-agent): File does not exist: %s", opt.RandomAgent))
+		} else {
+			if err := opt.parseRandomAgents(); err != nil {
+				errorList = multierror.Append(errorList, err)
+			}
+		}
+	}
+
+	switch opt.AgentStrategy {
+	case AgentPerRequest, AgentPerHost, AgentPerRun:
+	default:
+		errorList = multierror.Append(errorList, fmt.Errorf("Agent strategy (-agent-strategy): Invalid value: %s", opt.AgentStrategy))
+	}
+
+	if opt.RotateSize != "" {
+		if err := opt.parseRotateSize(); err != nil {
+			errorList = multierror.Append(errorList, err)
+		}
+	}
+
+	if opt.ScanProfile != "" && !profileNameRegex.MatchString(opt.ScanProfile) {
+		errorList = multierror.Append(errorList, fmt.Errorf("Profile (-profile): Invalid value: %s", opt.ScanProfile))
+	}
+	if opt.SaveScanProfile != "" && !profileNameRegex.MatchString(opt.SaveScanProfile) {
+		errorList = multierror.Append(errorList, fmt.Errorf("Save profile (-save-profile): Invalid value: %s", opt.SaveScanProfile))
+	}
+
+	if opt.ErrorThreshold != "" {
+		if err := opt.parseErrorThreshold(); err != nil {
+			errorList = multierror.Append(errorList, err)
+		}
+		if opt.ErrorWindow < 1 {
+			errorList = multierror.Append(errorList, fmt.Errorf("Error window (-error-window): Invalid value: %d", opt.ErrorWindow))
+		}
+	}
+
+	if opt.SourceIPs != "" {
+		if err := opt.parseSourceIPs(); err != nil {
+			errorList = multierror.Append(errorList, err)
+		}
+	}
+
+	if opt.OnResult != "" {
+		if _, err := splitCommand(opt.OnResult); err != nil {
+			errorList = multierror.Append(errorList, fmt.Errorf("On result (-on-result): Invalid value: %v", err))
+		}
+	}
+
+	if opt.OnResult != "" && opt.OnResultConcurrency < 1 {
+		errorList = multierror.Append(errorList, fmt.Errorf("On result concurrency (-on-result-concurrency): Invalid value: %d", opt.OnResultConcurrency))
+	}
+
+	if opt.TargetUrls != "" {
+		if _, err := os.Stat(opt.TargetUrls); os.IsNotExist(err) {
+			errorList = multierror.Append(errorList, fmt.Errorf("Target urls (-target-urls): File does not exist: %s", opt.TargetUrls))
+		}
+	}
+
+	if opt.CIDR != "" {
+		if err := opt.parseCIDR(); err != nil {
+			errorList = multierror.Append(errorList, err)
+		}
+	}
+
+	return errorList
+}
+
+// checkWordlist reports why a wordlist can not be read
+func checkWordlist(path string) error {
+	if _, err := os.Stat(wordlistFile(path)); os.IsNotExist(err) {
+		return fmt.Errorf("File does not exist: %s", wordlistFile(path))
+	}
+	wordlist, err := openWordlist(path)
+	if err != nil {
+		return fmt.Errorf("Unable to read: %v", err)
+	}
+	return wordlist.Close()
+}
+
+// ParseExtensions parses the extensions provided as a comma seperated list
+func (opt *Options) parseExtensions() error {
+	if opt.Extensions == "" {
+		return fmt.Errorf("invalid extension string provided")
+	}
+
+	exts := strings.Split(opt.Extensions, ",")
+	for _, e := range exts {
+		e = strings.TrimSpace(e)
+		// remove leading . from extensions
+		opt.ExtensionsParsed.Add(strings.TrimPrefix(e, "."))
+	}
+	return nil
+}
+
+// ParseStatusCodes parses the status codes provided as a comma seperated list
+func (opt *Options) parseStatusCodes() error {
+	if opt.ExcludedStatusCodes == "" {
+		return fmt.Errorf("invalid status code string provided")
+	}
+
+	for _, c := range strings.Split(opt.ExcludedStatusCodes, ",") {
+		c = strings.TrimSpace(c)
+		i, err := strconv.Atoi(c)
+		if err != nil {
+			return fmt.Errorf("invalid status code given: %s", c)
+		}
+		opt.ExcludedStatusCodesParsed.Add(i)
+	}
+	return nil
+}
+
+// parseFollowUpStatusCodes parses the comma seperated status codes that
+// trigger follow-up actions like -method-enum, a whole class can be given as 4xx
+func (opt *Options) parseFollowUpStatusCodes() error {
+	if strings.TrimSpace(opt.FollowUpStatusCodes) == "" {
+		return fmt.Errorf("invalid follow-up status code string provided")
+	}
+	return parseStatusCodeList(opt.FollowUpStatusCodes, "follow-up", opt.FollowUpStatusCodesParsed)
+}
+
+// parseStatusCodeList adds the comma seperated status codes or classes
+// like 4xx of a list to the set, what names the list in the errors
+func parseStatusCodeList(list, what string, parsed Set[int]) error {
+	for _, c := range strings.Split(list, ",") {
+		c = strings.TrimSpace(c)
+		if len(c) == 3 && strings.HasSuffix(strings.ToLower(c), "xx") && c[0] >= '1' && c[0] <= '5' {
+			class := int(c[0]-'0') * 100
+			for i := class; i < class+100; i++ {
+				parsed.Add(i)
+			}
+			continue
+		}
+		i, err := strconv.Atoi(c)
+		if err != nil {
+			return fmt.Errorf("invalid %s status code given: %s", what, c)
+		}
+		parsed.Add(i)
+	}
+	return nil
+}
+
+// IsFollowUpStatus reports whether a hit with the given status is
+// positive enough to trigger follow-up actions
+func (opt *Options) IsFollowUpStatus(status int) bool {
+	return opt.FollowUpStatusCodesParsed.Contains(status)
+}
+
+// parseErrorThreshold parses a percentage like 25% or 25
+func (opt *Options) parseErrorThreshold() error {
+	t := strings.TrimSuffix(strings.TrimSpace(opt.ErrorThreshold), "%")
+	f, err := strconv.ParseFloat(t, 64)
+	if err != nil || f <= 0 || f > 100 {
+		return fmt.Errorf("invalid error threshold given: %s", opt.ErrorThreshold)
+	}
+	opt.ErrorThresholdParsed = f
+	return nil
+}
+
+// byteSizeUnits are the suffixes accepted by parseByteSize
+var byteSizeUnits = []struct {
+	suffix string
+	size   int64
+}{
+	{"GB", 1 << 30},
+	{"MB", 1 << 20},
+	{"KB", 1 << 10},
+	{"G", 1 << 30},
+	{"M", 1 << 20},
+	{"K", 1 << 10},
+	{"B", 1},
+}
+
+// parseByteSize parses a size like 100MB, 512K or 1048576
+func parseByteSize(s string) (int64, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	multiplier := int64(1)
+	for _, unit := range byteSizeUnits {
+		if strings.HasSuffix(s, unit.suffix) {
+			s = strings.TrimSpace(strings.TrimSuffix(s, unit.suffix))
+			multiplier = unit.size
+			break
+		}
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("not a positive size")
+	}
+	return n * multiplier, nil
+}
+
+// parseBasePath normalizes the base path to a leading and a trailing
+// slash, the path of the application root is given by the base path
+// alone and not by the url
+func (opt *Options) parseBasePath() error {
+	u, err := url.Parse(opt.URL)
+	if err != nil {
+		return fmt.Errorf("invalid url given: %s", opt.URL)
+	}
+	if u.Path != "/" {
+		return fmt.Errorf("base path (-base-path) can not be used with a path in the url (-u): %s", u.Path)
+	}
+	p := strings.Trim(opt.BasePath, "/")
+	if p == "" || strings.ContainsAny(p, "?#") {
+		return fmt.Errorf("invalid base path given: %s", opt.BasePath)
+	}
+	opt.BasePath = "/" + p + "/"
+	return nil
+}
+
+// parseRotateSize parses the size the output files are rotated at
+func (opt *Options) parseRotateSize() error {
+	size, err := parseByteSize(opt.RotateSize)
+	if err != nil {
+		return fmt.Errorf("invalid rotate size given: %s", opt.RotateSize)
+	}
+	opt.RotateSizeParsed = size
+	return nil
+}
+
+// parseWildcardProbes validates the wildcard calibration settings
+func (opt *Options) parseWildcardProbes() error {
+	if opt.WildcardProbes < 2 {
+		return fmt.Errorf("Wildcard probes (-wildcard-probes): Must be at least 2: %d", opt.WildcardProbes)
+	}
+	if opt.WildcardCharset == "" {
+		return fmt.Errorf("Wildcard charset (-wildcard-charset): Must be specified")
+	}
+
+	opt.WildcardLengthsParsed = nil
+	for _, l := range strings.Split(opt.WildcardLengths, ",") {
+		l = strings.TrimSpace(l)
+		i, err := strconv.Atoi(l)
+		// directory probes need room for the trailing slash
+		if err != nil || i < 2 {
+			return fmt.Errorf("invalid wildcard length given: %s", l)
+		}
+		opt.WildcardLengthsParsed = append(opt.WildcardLengthsParsed, i)
+	}
+	return nil
+}
+
+// parseSourceIPs parses the local source addresses provided as a comma seperated list
+func (opt *Options) parseSourceIPs() error {
+	for _, a := range strings.Split(opt.SourceIPs, ",") {
+		a = strings.TrimSpace(a)
+		ip := net.ParseIP(a)
+		if ip == nil {
+			return fmt.Errorf("invalid source ip given: %s", a)
+		}
+		opt.SourceIPsParsed = append(opt.SourceIPsParsed, ip)
+	}
+	return nil
+}
+
+// parseContentTypes parses the media types provided as a comma seperated list
+func parseContentTypes(list string) []string {
+	var types []string
+	for _, t := range strings.Split(list, ",") {
+		if t = strings.ToLower(strings.TrimSpace(t)); t != "" {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// ContentTypeAllowed reports whether a response of the media type passes
+// the -include-content-type and -exclude-content-type filters
+func (opt *Options) ContentTypeAllowed(mediaType string) bool {
+	if len(opt.IncludeContentTypeParsed) > 0 && !matchesMediaType(mediaType, opt.IncludeContentTypeParsed) {
+		return false
+	}
+	return !matchesMediaType(mediaType, opt.ExcludeContentTypeParsed)
+}
+
+// parseWildcardAllow parses the known wildcard addresses provided as a comma seperated list
+func (opt *Options) parseWildcardAllow() error {
+	for _, a := range strings.Split(opt.WildcardAllow, ",") {
+		a = strings.TrimSpace(a)
+		ip := net.ParseIP(a)
+		if ip == nil {
+			return fmt.Errorf("invalid wildcard ip given: %s", a)
+		}
+		opt.WildcardAllowParsed.Add(ip.String())
+	}
+	return nil
+}
+
+// parseStaticResolve parses the host=ip overrides of -static-resolve
+// provided as a comma seperated list, a host given twice gets both
+// addresses
+func (opt *Options) parseStaticResolve() error {
+	opt.StaticResolveParsed = make(map[string][]string)
+	for _, pair := range strings.Split(opt.StaticResolve, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return fmt.Errorf("invalid host=ip pair given: %s", pair)
+		}
+		ip := net.ParseIP(strings.TrimSpace(parts[1]))
+		if ip == nil {
+			return fmt.Errorf("invalid ip given: %s", parts[1])
+		}
+		host := strings.ToLower(strings.TrimSpace(parts[0]))
+		opt.StaticResolveParsed[host] = append(opt.StaticResolveParsed[host], ip.String())
+	}
+	return nil
+}
+
+// parseResolvers parses the resolvers of -fast-dns given as a comma
+// separated list of ip(:port)
+func (opt *Options) parseResolvers() error {
+	opt.ResolversParsed = nil
+	for _, r := range strings.Split(opt.Resolvers, ",") {
+		r = strings.TrimSpace(r)
+		host, port, err := net.SplitHostPort(r)
+		if err != nil {
+			host, port = strings.Trim(r, "[]"), "53"
+		}
+		if net.ParseIP(host) == nil {
+			return fmt.Errorf("invalid resolver given: %s", r)
+		}
+		opt.ResolversParsed = append(opt.ResolversParsed, net.JoinHostPort(host, port))
+	}
+	return nil
+}
+
+func (opt *Options) parseRandomAgents() error {
+	randomAgents, err := os.Open(opt.RandomAgent)
+	if err != nil {
+		return fmt.Errorf("failed to open random agents: %v", err)
+	}
+
+	// rewind random agents
+	_, err = randomAgents.Seek(0, 0)
+	if err != nil {
+		return fmt.Errorf("failed to rewind random agents: %v", err)
+	}
+
+	scanner := bufio.NewScanner(randomAgents)
+	for scanner.Scan() {
+		if !strings.HasPrefix(scanner.Text(), "#") && len(scanner.Text()) > 0 {
+			opt.RandomAgentParsed = append(opt.RandomAgentParsed, scanner.Text())
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to scan random agents: %v", err)
+	}
+
+	return nil
+}
+
+// ResolveSecrets fills the password, bearer token and cookies from the
+// secrets file, "-" reading it from stdin, and the environment when they
+// were not given on the command line. Values already set are never
+// overwritten and the secrets are only resolved once.
+func (opt *Options) ResolveSecrets() error {
+	if opt.secretsResolved {
+		return nil
+	}
+	opt.secretsResolved = true
+	if opt.SecretsFile != "" {
+		secrets, err := parseSecretsFile(opt.SecretsFile)
+		if err != nil {
+			return err
+		}
+		if opt.Password == "" {
+			opt.Password = secrets["password"]
+		}
+		if opt.BearerToken == "" {
+			opt.BearerToken = secrets["bearer"]
+		}
+		if opt.Cookies == "" && secrets["cookies"] != "" {
+			opt.Cookies = secrets["cookies"]
+			opt.cookiesFromSecret = true
+		}
+	}
+
+	if opt.Password == "" {
+		opt.Password = os.Getenv(EnvPassword)
+	}
+	if opt.BearerToken == "" {
+		opt.BearerToken = os.Getenv(EnvBearerToken)
+	}
+	if opt.Cookies == "" && os.Getenv(EnvCookies) != "" {
+		opt.Cookies = os.Getenv(EnvCookies)
+		opt.cookiesFromSecret = true
+	}
+	return nil
+}
+
+// parseSecretsFile reads key=value pairs, the supported keys being
+// password, bearer and cookies, from the file or stdin with "-"
+func parseSecretsFile(path string) (map[string]string, error) {
+	if path == "-" {
+		return parseSecrets(os.Stdin)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open secrets file: %v", err)
+	}
+	defer f.Close()
+	return parseSecrets(f)
+}
+
+// parseSecrets reads the key=value pairs of a secrets file
+func parseSecrets(r io.Reader) (map[string]string, error) {
+	secrets := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") || len(line) == 0 {
+			continue
+		}
+		parts := strings.SplitN(line, "=", 2)
+		// the line is likely the secret itself, only its start is shown
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid line %d in secrets file: %q is no key=value pair", lineNumber, secretPrefix(line))
+		}
+		key := strings.ToLower(strings.TrimSpace(parts[0]))
+		switch key {
+		case "password", "bearer", "cookies":
+			secrets[key] = strings.TrimSpace(parts[1])
+		default:
+			return nil, fmt.Errorf("unknown key in secrets file: %s", key)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to scan secrets file: %v", err)
+	}
+
+	return secrets, nil
+}
+
+// secretPrefix returns the first characters of a line of the secrets
+// file, enough to find it without printing a whole secret
+func secretPrefix(line string) string {
+	runes := []rune(line)
+	if len(runes) <= 3 {
+		return line
+	}
+	return string(runes[:3]) + "..."
+}
+
+func (opt *Options) validateDirMode() error {
+	// bail out if we are not in dir mode
+	if opt.Mode != ModeDir {
+		return nil
+	}
+	u, err := withScheme(opt.URL)
+	if err != nil {
+		return err
+	}
+	if opt.URL, err = normalizeBaseURL(u); err != nil {
+		return err
+	}
+
+	if opt.Username != "" && opt.Password == "" {
+		return fmt.Errorf("username was provided but password is missing")
+	}
+
+	if opt.BasePath != "" {
+		if err := opt.parseBasePath(); err != nil {
+			return err
+		}
+	}
+
+	if opt.Tor && opt.Proxy != "" {
+		return fmt.Errorf("tor (-tor) and proxy (-p) can not be used together")
+	}
+	if opt.PACURL != "" && opt.PACFile != "" {
+		return fmt.Errorf("PAC url (-pac-url) and PAC file (-pac-file) can not be used together")
+	}
+	if (opt.PACURL != "" || opt.PACFile != "") && (opt.Proxy != "" || opt.Tor) {
+		return fmt.Errorf("a PAC file (-pac-url, -pac-file) can not be used with a proxy (-p, -tor)")
+	}
+	if opt.TorRenewRequests < 0 || opt.TorRenewEvery < 0 {
+		return fmt.Errorf("tor circuit renewal (-tor-renew-requests, -tor-renew-every) can not be negative")
+	}
+
+	return nil
+}
@@ -1,84 +1,328 @@
-package libgobuster
-
-import (
-	"reflect"
-	"testing"
-)
-
-func TestNewOptions(t *testing.T) {
-	t.Parallel()
-
-	o := NewOptions()
-	if o.StatusCodesParsed.Set == nil {
-		t.Fatal("StatusCodesParsed not initialized")
-	}
-
-	if o.ExtensionsParsed.Set == nil {
-		t.Fatal("ExtensionsParsed not initialized")
-	}
-}
-
-func TestParseExtensions(t *testing.T) {
-	t.Parallel()
-
-	var tt = []struct {
-		testName           string
-		Extensions         string
-		expectedExtensions stringSet
-		expectedError      string
-	}{
-		{"Valid extensions", "php,asp,txt", stringSet{Set: map[string]bool{"php": true, "asp": true, "txt": true}}, ""},
-		{"Spaces", "php, asp , txt", stringSet{Set: map[string]bool{"php": true, "asp": true, "txt": true}}, ""},
-		{"Double extensions", "php,asp,txt,php,asp,txt", stringSet{Set: map[string]bool{"php": true, "asp": true, "txt": true}}, ""},
-		{"Leading dot", ".php,asp,.txt", stringSet{Set: map[string]bool{"php": true, "asp": true, "txt": true}}, ""},
-		{"Empty string", "", newStringSet(), "invalid extension string provided"},
-	}
-
-	for _, x := range tt {
-		t.Run(x.testName, func(t *testing.T) {
-			o := NewOptions()
-			o.Extensions = x.Extensions
-			err := o.parseExtensions()
-			if x.expectedError != "" {
-				if err.Error() != x.expectedError {
-					t.Fatalf("Expected error %q but got %q", x.expectedError, err.Error())
-				}
-			} else if !reflect.DeepEqual(x.expectedExtensions, o.ExtensionsParsed) {
-				t.Fatalf("Expected %v but got %v", x.expectedExtensions, o.ExtensionsParsed)
-			}
-		})
-	}
-}
-
-func TestParseStatusCodes(t *testing.T) {
-	t.Parallel()
-
-	var tt = []struct {
-		testName      string
-		stringCodes   string
-		expectedCodes intSet
-		expectedError string
-	}{
-		{"Valid codes", "200,100,202", intSet{Set: map[int]bool{100: true, 200: true, 202: true}}, ""},
-		{"Spaces", "200, 100 , 202", intSet{Set: map[int]bool{100: true, 200: true, 202: true}}, ""},
-		{"Double codes", "200, 100, 202, 100", intSet{Set: map[int]bool{100: true, 200: true, 202: true}}, ""},
-		{"Invalid code", "200,AAA", newIntSet(), "invalid status code given: AAA"},
-		{"Invalid integer", "2000000000000000000000000000000", newIntSet(), "invalid status code given: 2000000000000000000000000000000"},
-		{"Empty string", "", newIntSet(), "invalid status code string provided"},
-	}
-
-	for _, x := range tt {
-		t.Run(x.testName, func(t *testing.T) {
-			o := NewOptions()
-			o.StatusCodes = x.stringCodes
-			err := o.parseStatusCodes()
-			if x.expectedError != "" {
-				if err.Error() != x.expectedError {
-					t.Fatalf("Expected error %q but got %q", x.expectedError, err.Error())
-				}
-			} else if !reflect.DeepEqual(x.expectedCodes, o.StatusCodesParsed) {
-				t.Fatalf("Expected %v but got %v", x.expectedCodes, o.StatusCodesParsed)
-			}
-		})
-	}
-}
+package libgobuster
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestNewOptions(t *testing.T) {
+	t.Parallel()
+
+	o := NewOptions()
+	if o.ExcludedStatusCodesParsed.Set == nil {
+		t.Fatal("ExcludedStatusCodesParsed not initialized")
+	}
+
+	if o.ExtensionsParsed.Set == nil {
+		t.Fatal("ExtensionsParsed not initialized")
+	}
+}
+
+func TestParseExtensions(t *testing.T) {
+	t.Parallel()
+
+	var tt = []struct {
+		testName           string
+		Extensions         string
+		expectedExtensions Set[string]
+		expectedError      string
+	}{
+		{"Valid extensions", "php,asp,txt", Set[string]{Set: map[string]bool{"php": true, "asp": true, "txt": true}}, ""},
+		{"Spaces", "php, asp , txt", Set[string]{Set: map[string]bool{"php": true, "asp": true, "txt": true}}, ""},
+		{"Double extensions", "php,asp,txt,php,asp,txt", Set[string]{Set: map[string]bool{"php": true, "asp": true, "txt": true}}, ""},
+		{"Leading dot", ".php,asp,.txt", Set[string]{Set: map[string]bool{"php": true, "asp": true, "txt": true}}, ""},
+		{"Empty string", "", NewSet[string](), "invalid extension string provided"},
+	}
+
+	for _, x := range tt {
+		t.Run(x.testName, func(t *testing.T) {
+			o := NewOptions()
+			o.Extensions = x.Extensions
+			err := o.parseExtensions()
+			if x.expectedError != "" {
+				if err.Error() != x.expectedError {
+					t.Fatalf("Expected error %q but got %q", x.expectedError, err.Error())
+				}
+			} else if !reflect.DeepEqual(x.expectedExtensions, o.ExtensionsParsed) {
+				t.Fatalf("Expected %v but got %v", x.expectedExtensions, o.ExtensionsParsed)
+			}
+		})
+	}
+}
+
+func TestParseStatusCodes(t *testing.T) {
+	t.Parallel()
+
+	var tt = []struct {
+		testName      string
+		stringCodes   string
+		expectedCodes Set[int]
+		expectedError string
+	}{
+		{"Valid codes", "200,100,202", Set[int]{Set: map[int]bool{100: true, 200: true, 202: true}}, ""},
+		{"Spaces", "200, 100 , 202", Set[int]{Set: map[int]bool{100: true, 200: true, 202: true}}, ""},
+		{"Double codes", "200, 100, 202, 100", Set[int]{Set: map[int]bool{100: true, 200: true, 202: true}}, ""},
+		{"Invalid code", "200,AAA", NewSet[int](), "invalid status code given: AAA"},
+		{"Invalid integer", "2000000000000000000000000000000", NewSet[int](), "invalid status code given: 2000000000000000000000000000000"},
+		{"Empty string", "", NewSet[int](), "invalid status code string provided"},
+	}
+
+	for _, x := range tt {
+		t.Run(x.testName, func(t *testing.T) {
+			o := NewOptions()
+			o.ExcludedStatusCodes = x.stringCodes
+			err := o.parseStatusCodes()
+			if x.expectedError != "" {
+				if err.Error() != x.expectedError {
+					t.Fatalf("Expected error %q but got %q", x.expectedError, err.Error())
+				}
+			} else if !reflect.DeepEqual(x.expectedCodes, o.ExcludedStatusCodesParsed) {
+				t.Fatalf("Expected %v but got %v", x.expectedCodes, o.ExcludedStatusCodesParsed)
+			}
+		})
+	}
+}
+
+func TestParseFollowUpStatusCodes(t *testing.T) {
+	t.Parallel()
+
+	var tt = []struct {
+		testName      string
+		stringCodes   string
+		contains      []int
+		missing       []int
+		expectedError string
+	}{
+		{"Classes", "2xx,3xx", []int{200, 204, 301, 399}, []int{401, 403, 500}, ""},
+		{"Mixed", "2xx, 401,403", []int{200, 401, 403}, []int{301, 404}, ""},
+		{"Upper case class", "4XX", []int{400, 403, 499}, []int{200}, ""},
+		{"Invalid class", "9xx", nil, nil, "invalid follow-up status code given: 9xx"},
+		{"Invalid code", "200,AAA", nil, nil, "invalid follow-up status code given: AAA"},
+		{"Empty string", "", nil, nil, "invalid follow-up status code string provided"},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+			o := NewOptions()
+			o.FollowUpStatusCodes = x.stringCodes
+			err := o.parseFollowUpStatusCodes()
+			if x.expectedError != "" {
+				if err == nil || err.Error() != x.expectedError {
+					t.Fatalf("Expected error %q but got %v", x.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			for _, c := range x.contains {
+				if !o.IsFollowUpStatus(c) {
+					t.Fatalf("Expected %d to be a follow-up status", c)
+				}
+			}
+			for _, c := range x.missing {
+				if o.IsFollowUpStatus(c) {
+					t.Fatalf("Expected %d not to be a follow-up status", c)
+				}
+			}
+		})
+	}
+}
+
+func TestParseSecretsFile(t *testing.T) {
+	t.Parallel()
+
+	var tt = []struct {
+		testName        string
+		content         string
+		expectedSecrets map[string]string
+		expectedError   string
+	}{
+		{"Valid secrets", "password=secret\nbearer = token\ncookies=a=b; c=d", map[string]string{"password": "secret", "bearer": "token", "cookies": "a=b; c=d"}, ""},
+		{"Comments", "# comment\n\npassword=secret", map[string]string{"password": "secret"}, ""},
+		{"Unknown key", "user=admin", nil, "unknown key in secrets file: user"},
+		{"Invalid line", "# comment\np4ssw0rd", nil, "invalid line 2 in secrets file: \"p4s...\" is no key=value pair"},
+	}
+
+	for _, x := range tt {
+		t.Run(x.testName, func(t *testing.T) {
+			f, err := ioutil.TempFile("", "secrets")
+			if err != nil {
+				t.Fatalf("Got Error: %v", err)
+			}
+			defer os.Remove(f.Name())
+			if _, err := f.WriteString(x.content); err != nil {
+				t.Fatalf("Got Error: %v", err)
+			}
+			f.Close()
+
+			secrets, err := parseSecretsFile(f.Name())
+			if x.expectedError != "" {
+				if err == nil || err.Error() != x.expectedError {
+					t.Fatalf("Expected error %q but got %v", x.expectedError, err)
+				}
+			} else if !reflect.DeepEqual(x.expectedSecrets, secrets) {
+				t.Fatalf("Expected %v but got %v", x.expectedSecrets, secrets)
+			}
+		})
+	}
+}
+
+func TestResolveSecretsOnce(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "secrets")
+	if err := ioutil.WriteFile(path, []byte("bearer=token\n"), 0600); err != nil {
+		t.Fatalf("Got Error: %v", err)
+	}
+	o := NewOptions()
+	o.SecretsFile = path
+	if err := o.ResolveSecrets(); err != nil {
+		t.Fatalf("Got Error: %v", err)
+	}
+	// a second call, as Validate makes, does not read the file again
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("Got Error: %v", err)
+	}
+	if err := o.ResolveSecrets(); err != nil || o.BearerToken != "token" {
+		t.Fatalf("Expected the token resolved once but got %q: %v", o.BearerToken, err)
+	}
+}
+
+func TestParseSourceIPs(t *testing.T) {
+	t.Parallel()
+
+	var tt = []struct {
+		testName      string
+		sourceIPs     string
+		expectedCount int
+		expectedError string
+	}{
+		{"Valid addresses", "10.0.0.1, 10.0.0.2,::1", 3, ""},
+		{"Invalid address", "10.0.0.1,example.com", 0, "invalid source ip given: example.com"},
+	}
+
+	for _, x := range tt {
+		t.Run(x.testName, func(t *testing.T) {
+			o := NewOptions()
+			o.SourceIPs = x.sourceIPs
+			err := o.parseSourceIPs()
+			if x.expectedError != "" {
+				if err == nil || err.Error() != x.expectedError {
+					t.Fatalf("Expected error %q but got %v", x.expectedError, err)
+				}
+			} else if len(o.SourceIPsParsed) != x.expectedCount {
+				t.Fatalf("Expected %d addresses but got %v", x.expectedCount, o.SourceIPsParsed)
+			}
+		})
+	}
+}
+
+func TestParseWildcardAllow(t *testing.T) {
+	t.Parallel()
+
+	var tt = []struct {
+		testName      string
+		wildcardAllow string
+		expected      []string
+		expectedError string
+	}{
+		{"Valid addresses", "10.0.0.1, 2001:db8::0001", []string{"10.0.0.1", "2001:db8::1"}, ""},
+		{"Invalid address", "10.0.0.1,example.com", nil, "invalid wildcard ip given: example.com"},
+	}
+
+	for _, x := range tt {
+		t.Run(x.testName, func(t *testing.T) {
+			o := NewOptions()
+			o.WildcardAllow = x.wildcardAllow
+			err := o.parseWildcardAllow()
+			if x.expectedError != "" {
+				if err == nil || err.Error() != x.expectedError {
+					t.Fatalf("Expected error %q but got %v", x.expectedError, err)
+				}
+				return
+			}
+			for _, ip := range x.expected {
+				if !o.WildcardAllowParsed.Contains(ip) {
+					t.Fatalf("Expected %s in %v", ip, o.WildcardAllowParsed.Set)
+				}
+			}
+		})
+	}
+}
+
+func TestParseRotateSize(t *testing.T) {
+	t.Parallel()
+
+	var tt = []struct {
+		testName      string
+		rotateSize    string
+		expected      int64
+		expectedError string
+	}{
+		{"Bytes", "4096", 4096, ""},
+		{"Kilobytes", "512K", 512 * 1024, ""},
+		{"Megabytes", "100MB", 100 * 1024 * 1024, ""},
+		{"Lowercase", "1 gb", 1024 * 1024 * 1024, ""},
+		{"Zero", "0", 0, "invalid rotate size given: 0"},
+		{"Invalid", "lots", 0, "invalid rotate size given: lots"},
+	}
+
+	for _, x := range tt {
+		t.Run(x.testName, func(t *testing.T) {
+			o := NewOptions()
+			o.RotateSize = x.rotateSize
+			err := o.parseRotateSize()
+			if x.expectedError != "" {
+				if err == nil || err.Error() != x.expectedError {
+					t.Fatalf("Expected error %q but got %v", x.expectedError, err)
+				}
+				return
+			}
+			if err != nil || o.RotateSizeParsed != x.expected {
+				t.Fatalf("Expected %d but got %d (%v)", x.expected, o.RotateSizeParsed, err)
+			}
+		})
+	}
+}
+
+func TestParseBasePath(t *testing.T) {
+	t.Parallel()
+
+	var tt = []struct {
+		testName      string
+		url           string
+		basePath      string
+		expected      string
+		expectedError string
+	}{
+		{"Normalized", "http://example.com/", "app/v2", "/app/v2/", ""},
+		{"Slashes", "http://example.com/", "//app/", "/app/", ""},
+		{"Root", "http://example.com/", "/", "", "invalid base path given: /"},
+		{"Query", "http://example.com/", "/app?x=1", "", "invalid base path given: /app?x=1"},
+		{"Path in url", "http://example.com/app/", "/v2/", "", "base path (-base-path) can not be used with a path in the url (-u): /app/"},
+	}
+
+	for _, x := range tt {
+		t.Run(x.testName, func(t *testing.T) {
+			o := NewOptions()
+			o.URL = x.url
+			o.BasePath = x.basePath
+			err := o.parseBasePath()
+			if x.expectedError != "" {
+				if err == nil || err.Error() != x.expectedError {
+					t.Fatalf("Expected error %q but got %v", x.expectedError, err)
+				}
+				return
+			}
+			if err != nil || o.BasePath != x.expected {
+				t.Fatalf("Expected %q but got %q (%v)", x.expected, o.BasePath, err)
+			}
+		})
+	}
+}
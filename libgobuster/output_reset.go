@@ -1,7 +1,32 @@
-// +build !windows
-
-package libgobuster
-
-func resetTerminal() string {
-	return "\r\x1b[2K"
-}
+// +build !windows
+
+package libgobuster
+
+import "strings"
+
+// SetupConsole prepares the terminal for the progress and the colors, it
+// reports whether the colors can be printed
+func SetupConsole() bool {
+	return true
+}
+
+// multiLineProgress reports whether the terminal can move the cursor up
+// to redraw a progress frame of many lines
+func multiLineProgress() bool {
+	return true
+}
+
+func resetTerminal() string {
+	return "\r\x1b[2K"
+}
+
+// fitWidth returns the progress line as drawn on the terminal
+func fitWidth(line string) string {
+	return line
+}
+
+// clearLines erases the lines of a progress frame, the cursor being on
+// the last one
+func clearLines(n int) string {
+	return "\r\x1b[2K" + strings.Repeat("\x1b[1A\x1b[2K", n-1)
+}
@@ -1,7 +1,62 @@
-// +build windows
-
-package libgobuster
-
-func resetTerminal() string {
-	return "\r\r"
-}
+// +build windows
+
+package libgobuster
+
+import (
+	"os"
+	"strings"
+	"syscall"
+
+	"github.com/gookit/color"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// vtEnabled is set once the console of stderr interprets the escape
+// sequences, which Windows 10 consoles do once asked to
+var vtEnabled bool
+
+// SetupConsole enables the escape sequences on the console, it reports
+// whether the colors can be printed. Older consoles print the sequences
+// of the colors as is.
+func SetupConsole() bool {
+	colors := color.EnableVirtualTerminalProcessing(syscall.Handle(os.Stdout.Fd()), true) == nil
+	vtEnabled = color.EnableVirtualTerminalProcessing(syscall.Handle(os.Stderr.Fd()), true) == nil
+	return colors
+}
+
+// multiLineProgress reports whether the console can move the cursor up,
+// otherwise the progress is drawn on a single line
+func multiLineProgress() bool {
+	return vtEnabled
+}
+
+// consoleWidth returns the number of columns of the console
+func consoleWidth() int {
+	width, _, err := terminal.GetSize(int(os.Stderr.Fd()))
+	if err != nil || width <= 0 {
+		return 80
+	}
+	return width
+}
+
+func resetTerminal() string {
+	if vtEnabled {
+		return "\r\x1b[2K"
+	}
+	// the console wraps a line filling the last column, the cursor would
+	// end up on the next one
+	return "\r" + strings.Repeat(" ", consoleWidth()-1) + "\r"
+}
+
+// fitWidth cuts the progress line to the console, a wrapped line can not
+// be erased with a carriage return
+func fitWidth(line string) string {
+	return truncateLine(line, consoleWidth()-1)
+}
+
+func clearLines(n int) string {
+	if vtEnabled {
+		return "\r\x1b[2K" + strings.Repeat("\x1b[1A\x1b[2K", n-1)
+	}
+	return resetTerminal()
+}
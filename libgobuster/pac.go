@@ -0,0 +1,233 @@
+package libgobuster
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dop251/goja"
+)
+
+// maxPACSize caps the bytes of a PAC file read
+const maxPACSize = 1024 * 1024
+
+// PAC is a proxy auto-config file, its FindProxyForURL function picks
+// the proxy of every host. The proxies are looked up once per scheme and
+// host, the path of the urls is never given to the script, like the
+// browsers do for https.
+type PAC struct {
+	script *pacScript
+	// mu guards the proxies only, the script runs without it
+	mu      sync.Mutex
+	proxies map[string]*url.URL
+	// lookupIP resolves the hosts for dnsResolve, isInNet and
+	// isResolvable
+	lookupIP func(host string) ([]net.IP, error)
+	// now is the time of weekdayRange, dateRange and timeRange
+	now func() time.Time
+}
+
+// LoadPAC reads the PAC file of -pac-file or downloads the one of -pac-url
+func LoadPAC(file, pacURL string, timeout time.Duration) (*PAC, error) {
+	if file != "" {
+		src, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the PAC file: %v", err)
+		}
+		return ParsePAC(string(src))
+	}
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Get(pacURL)
+	if err != nil {
+		return nil, fmt.Errorf("unable to download the PAC file: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to download the PAC file: status %d", resp.StatusCode)
+	}
+	src, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxPACSize))
+	if err != nil {
+		return nil, fmt.Errorf("unable to download the PAC file: %v", err)
+	}
+	return ParsePAC(string(src))
+}
+
+// ParsePAC parses the script of a PAC file
+func ParsePAC(src string) (*PAC, error) {
+	p := &PAC{proxies: make(map[string]*url.URL), lookupIP: net.LookupIP, now: time.Now}
+	script, err := newPACScript(src, p.builtins())
+	if err != nil {
+		return nil, fmt.Errorf("invalid PAC file: %v", err)
+	}
+	p.script = script
+	return p, nil
+}
+
+// FindProxy returns the answer of FindProxyForURL for an url, like
+// "PROXY proxy.example.com:8080; DIRECT"
+func (p *PAC) FindProxy(rawURL, host string) (string, error) {
+	result, err := p.script.findProxy(rawURL, host)
+	if err != nil {
+		return "", fmt.Errorf("PAC file: %v", err)
+	}
+	return result, nil
+}
+
+// Proxy is the proxy function of the http transport
+func (p *PAC) Proxy(req *http.Request) (*url.URL, error) {
+	key := req.URL.Scheme + "://" + req.URL.Host
+	p.mu.Lock()
+	proxy, ok := p.proxies[key]
+	p.mu.Unlock()
+	if ok {
+		return proxy, nil
+	}
+	result, err := p.FindProxy(key+"/", req.URL.Hostname())
+	if err != nil {
+		return nil, err
+	}
+	proxy, err = ParsePACResult(result)
+	if err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	p.proxies[key] = proxy
+	p.mu.Unlock()
+	return proxy, nil
+}
+
+// pacSchemes are the schemes of the proxy types of a PAC answer
+var pacSchemes = map[string]string{
+	"PROXY":  "http",
+	"HTTP":   "http",
+	"HTTPS":  "https",
+	"SOCKS5": "socks5",
+}
+
+// ParsePACResult returns the proxy of the first usable entry of a PAC
+// answer, nil for DIRECT. There is no failover to the next entries, the
+// SOCKS4 ones the transport does not support are skipped, SOCKS included
+// since it means SOCKS4 in the PAC files.
+func ParsePACResult(result string) (*url.URL, error) {
+	if strings.TrimSpace(result) == "" {
+		return nil, nil
+	}
+	for _, entry := range strings.Split(result, ";") {
+		fields := strings.Fields(entry)
+		if len(fields) == 0 {
+			continue
+		}
+		kind := strings.ToUpper(fields[0])
+		if kind == "DIRECT" {
+			return nil, nil
+		}
+		scheme, ok := pacSchemes[kind]
+		if !ok || len(fields) != 2 {
+			continue
+		}
+		return &url.URL{Scheme: scheme, Host: fields[1]}, nil
+	}
+	return nil, fmt.Errorf("no usable proxy in the PAC answer %q", result)
+}
+
+// builtins are the functions of the PAC environment
+func (p *PAC) builtins() map[string]interface{} {
+	return map[string]interface{}{
+		"isPlainHostName": func(host string) bool {
+			return !strings.Contains(host, ".")
+		},
+		"dnsDomainIs": func(host, domain string) bool {
+			return strings.HasSuffix(strings.ToLower(host), strings.ToLower(domain))
+		},
+		"localHostOrDomainIs": func(host, hostdom string) bool {
+			host, hostdom = strings.ToLower(host), strings.ToLower(hostdom)
+			return host == hostdom || !strings.Contains(host, ".") && strings.HasPrefix(hostdom, host+".")
+		},
+		"dnsDomainLevels": func(host string) int {
+			return strings.Count(host, ".")
+		},
+		"shExpMatch": shExpMatch,
+		"isResolvable": func(host string) bool {
+			return p.resolve(host) != nil
+		},
+		"dnsResolve": func(host string) interface{} {
+			if ip := p.resolve(host); ip != nil {
+				return ip.String()
+			}
+			return nil
+		},
+		"isInNet": func(host, pattern, mask string) bool {
+			ip := p.resolve(host)
+			patternIP := net.ParseIP(pattern).To4()
+			maskIP := net.ParseIP(mask).To4()
+			if ip == nil || patternIP == nil || maskIP == nil {
+				return false
+			}
+			m := net.IPMask(maskIP)
+			return ip.Mask(m).Equal(patternIP.Mask(m))
+		},
+		"myIpAddress":  myIPAddress,
+		"weekdayRange": p.timeFunction(weekdayRange),
+		"dateRange":    p.timeFunction(dateRange),
+		"timeRange":    p.timeFunction(timeRange),
+		"alert":        func(string) {},
+	}
+}
+
+// timeFunction returns a date or time function of the PAC environment,
+// their arguments vary too much to be converted by goja
+func (p *PAC) timeFunction(fn func(goja.FunctionCall, time.Time) bool) func(goja.FunctionCall, *goja.Runtime) goja.Value {
+	return func(call goja.FunctionCall, vm *goja.Runtime) goja.Value {
+		return vm.ToValue(fn(call, p.now()))
+	}
+}
+
+// resolve returns the IPv4 address of a host, nil when it does not resolve
+func (p *PAC) resolve(host string) net.IP {
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.To4()
+	}
+	ips, err := p.lookupIP(host)
+	if err != nil {
+		return nil
+	}
+	for _, ip := range ips {
+		if v4 := ip.To4(); v4 != nil {
+			return v4
+		}
+	}
+	return nil
+}
+
+// shExpMatch matches a string against a shell expression, * and ?
+func shExpMatch(str, shexp string) bool {
+	expr := regexp.QuoteMeta(shexp)
+	expr = strings.ReplaceAll(expr, `\*`, ".*")
+	expr = strings.ReplaceAll(expr, `\?`, ".")
+	re, err := regexp.Compile("^" + expr + "$")
+	if err != nil {
+		return false
+	}
+	return re.MatchString(str)
+}
+
+// myIPAddress returns the first IPv4 address of the interfaces that is
+// not a loopback one
+func myIPAddress() string {
+	addrs, err := net.InterfaceAddrs()
+	if err == nil {
+		for _, addr := range addrs {
+			if ipNet, ok := addr.(*net.IPNet); ok && !ipNet.IP.IsLoopback() && ipNet.IP.To4() != nil {
+				return ipNet.IP.String()
+			}
+		}
+	}
+	return "127.0.0.1"
+}
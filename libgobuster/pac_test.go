@@ -0,0 +1,290 @@
+package libgobuster
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const testPAC = `
+// the corporate egress
+var proxy = "PROXY proxy.example.com:8080";
+
+function isInternal(host) {
+	return isPlainHostName(host) || dnsDomainIs(host, ".corp.example.com") ||
+		isInNet(host, "10.0.0.0", "255.0.0.0");
+}
+
+function FindProxyForURL(url, host) {
+	host = host.toLowerCase();
+	if (isInternal(host))
+		return "DIRECT";
+	else if (shExpMatch(host, "*.lab.example.com") && url.substring(0, 6) == "https:") {
+		return "SOCKS5 socks.example.com:1080; DIRECT";
+	}
+	/* everything else */
+	return dnsDomainLevels(host) > 4 ? "SOCKS4 old.example.com:1080; " + proxy : proxy;
+}
+`
+
+func TestPACFindProxy(t *testing.T) {
+	t.Parallel()
+
+	pac, err := ParsePAC(testPAC)
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	pac.lookupIP = func(host string) ([]net.IP, error) {
+		if host == "app.internal.example.com" {
+			return []net.IP{net.ParseIP("10.1.2.3")}, nil
+		}
+		return nil, fmt.Errorf("no such host")
+	}
+
+	tt := []struct {
+		url      string
+		expected string
+	}{
+		{"http://intranet/", ""},
+		{"http://WIKI.corp.example.com/", ""},
+		{"http://app.internal.example.com/", ""},
+		{"https://a.lab.example.com/", "socks5://socks.example.com:1080"},
+		{"http://a.lab.example.com/", "http://proxy.example.com:8080"},
+		{"https://www.example.com:8443/", "http://proxy.example.com:8080"},
+		{"http://a.b.c.d.example.com/", "http://proxy.example.com:8080"},
+	}
+	for _, x := range tt {
+		u, err := url.Parse(x.url)
+		if err != nil {
+			t.Fatalf("got error: %v", err)
+		}
+		proxy, err := pac.Proxy(&http.Request{URL: u})
+		if err != nil {
+			t.Fatalf("%s: got error: %v", x.url, err)
+		}
+		got := ""
+		if proxy != nil {
+			got = proxy.String()
+		}
+		if got != x.expected {
+			t.Fatalf("%s: expected %q, got %q", x.url, x.expected, got)
+		}
+	}
+}
+
+func TestPACFullJavaScript(t *testing.T) {
+	t.Parallel()
+
+	pac, err := ParsePAC(`
+var direct = ["intranet", "localhost"];
+var lab = /^[a-z]+\.lab\.example\.com$/;
+
+function FindProxyForURL(url, host) {
+	for (var i = 0; i < direct.length; i++) {
+		if (host === direct[i])
+			return "DIRECT";
+	}
+	if (lab.test(host))
+		return "PROXY lab.example.com:3128";
+	switch (true) {
+	case weekdayRange("SAT", "SUN"):
+		return "PROXY weekend.example.com:3128";
+	case dateRange(24, "DEC", 26, "DEC") && timeRange(9, 17, "GMT"):
+		return "PROXY holiday.example.com:3128";
+	}
+	return "PROXY proxy.example.com:8080";
+}`)
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+
+	tt := []struct {
+		host     string
+		now      time.Time
+		expected string
+	}{
+		{"intranet", time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC), "DIRECT"},
+		{"a.lab.example.com", time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC), "PROXY lab.example.com:3128"},
+		{"www.example.com", time.Date(2024, 3, 9, 10, 0, 0, 0, time.UTC), "PROXY weekend.example.com:3128"},
+		{"www.example.com", time.Date(2024, 12, 25, 16, 59, 0, 0, time.UTC), "PROXY holiday.example.com:3128"},
+		{"www.example.com", time.Date(2024, 12, 25, 17, 0, 0, 0, time.UTC), "PROXY proxy.example.com:8080"},
+		{"www.example.com", time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC), "PROXY proxy.example.com:8080"},
+	}
+	for _, x := range tt {
+		now := x.now
+		pac.now = func() time.Time { return now }
+		got, err := pac.FindProxy("http://"+x.host+"/", x.host)
+		if err != nil {
+			t.Fatalf("%s: got error: %v", x.host, err)
+		}
+		if got != x.expected {
+			t.Fatalf("%s at %s: expected %q, got %q", x.host, x.now, x.expected, got)
+		}
+	}
+}
+
+func TestPACDateFunctions(t *testing.T) {
+	t.Parallel()
+
+	// a Wednesday
+	now := time.Date(2024, 6, 12, 14, 30, 15, 0, time.UTC)
+	tt := []struct {
+		call     string
+		expected bool
+	}{
+		{`weekdayRange("WED")`, true},
+		{`weekdayRange("MON", "FRI")`, true},
+		{`weekdayRange("FRI", "MON")`, false},
+		{`weekdayRange("SAT", "WED", "GMT")`, true},
+		{`dateRange(12)`, true},
+		{`dateRange("JUN")`, true},
+		{`dateRange(2024)`, true},
+		{`dateRange(1, 15)`, true},
+		{`dateRange("JUL", "MAY")`, false},
+		{`dateRange("NOV", "JUN")`, true},
+		{`dateRange(1, "JUN", 11, "JUN")`, false},
+		{`dateRange("MAY", 2024, "JAN", 2025)`, true},
+		{`dateRange(1, "JAN", 2023, 31, "DEC", 2023)`, false},
+		{`dateRange(1, "JUN")`, false},
+		{`timeRange(14)`, true},
+		{`timeRange(9, 14)`, false},
+		{`timeRange(22, 15)`, true},
+		{`timeRange(14, 30, 14, 31)`, true},
+		{`timeRange(14, 30, 16, 14, 30, 20, "GMT")`, false},
+	}
+	for _, x := range tt {
+		pac, err := ParsePAC(fmt.Sprintf(`function FindProxyForURL(url, host) { return %s ? "DIRECT" : "PROXY p:1"; }`, x.call))
+		if err != nil {
+			t.Fatalf("%s: got error: %v", x.call, err)
+		}
+		pac.now = func() time.Time { return now }
+		got, err := pac.FindProxy("http://h/", "h")
+		if err != nil {
+			t.Fatalf("%s: got error: %v", x.call, err)
+		}
+		if (got == "DIRECT") != x.expected {
+			t.Fatalf("%s: expected %v, got %q", x.call, x.expected, got)
+		}
+	}
+}
+
+func TestPACConcurrentLookups(t *testing.T) {
+	t.Parallel()
+
+	pac, err := ParsePAC(`function FindProxyForURL(url, host) { return isResolvable(host) ? "DIRECT" : "PROXY p:1"; }`)
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	release := make(chan struct{})
+	pac.lookupIP = func(host string) ([]net.IP, error) {
+		if host == "slow.example.com" {
+			<-release
+		}
+		return []net.IP{net.ParseIP("10.0.0.1")}, nil
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		pac.FindProxy("http://slow.example.com/", "slow.example.com")
+	}()
+	// the slow lookup of one host never holds up another one
+	done := make(chan struct{})
+	go func() {
+		pac.FindProxy("http://fast.example.com/", "fast.example.com")
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the lookup waited on the slow host")
+	}
+	close(release)
+	wg.Wait()
+}
+
+func TestPACTimeout(t *testing.T) {
+	t.Parallel()
+
+	pac, err := ParsePAC(`function FindProxyForURL(url, host) { while (true) {} }`)
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	pac.script.timeout = 10 * time.Millisecond
+	if _, err := pac.FindProxy("http://h/", "h"); err == nil || !strings.Contains(err.Error(), "did not return") {
+		t.Fatalf("expected a timeout, got %v", err)
+	}
+}
+
+func TestParsePACResult(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		result      string
+		expected    string
+		expectedErr bool
+	}{
+		{"DIRECT", "", false},
+		{"", "", false},
+		{"PROXY p:3128", "http://p:3128", false},
+		{"HTTPS p:443; DIRECT", "https://p:443", false},
+		{"SOCKS4 s:1080; SOCKS s:1081; SOCKS5 s:1082", "socks5://s:1082", false},
+		{"SOCKS s:1081", "", true},
+		{"SOCKS4 s:1080", "", true},
+	}
+	for _, x := range tt {
+		x := x
+		t.Run(x.result, func(t *testing.T) {
+			t.Parallel()
+
+			proxy, err := ParsePACResult(x.result)
+			if x.expectedErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", proxy)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got error: %v", err)
+			}
+			got := ""
+			if proxy != nil {
+				got = proxy.String()
+			}
+			if got != x.expected {
+				t.Fatalf("expected %q, got %q", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestParsePACInvalid(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName string
+		src      string
+	}{
+		{"no function", `var proxy = "DIRECT";`},
+		{"unterminated string", `function FindProxyForURL(url, host) { return "DIRECT; }`},
+		{"missing brace", `function FindProxyForURL(url, host) { return "DIRECT";`},
+		{"not a function", `var FindProxyForURL = "DIRECT";`},
+		{"failing global", `var proxy = undefinedFunction();`},
+	}
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := ParsePAC(x.src); err == nil {
+				t.Fatalf("expected an error")
+			}
+		})
+	}
+}
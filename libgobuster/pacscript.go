@@ -0,0 +1,219 @@
+package libgobuster
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dop251/goja"
+)
+
+// pacTimeout caps how long a call of FindProxyForURL may run, a script
+// looping forever is interrupted
+const pacTimeout = 5 * time.Second
+
+// pacScript runs a proxy auto-config file with the goja JavaScript
+// engine. A runtime is not safe for concurrent use, every call takes an
+// idle one from the pool so the lookups of a script never wait on
+// another host's.
+type pacScript struct {
+	program  *goja.Program
+	builtins map[string]interface{}
+	pool     sync.Pool
+	timeout  time.Duration
+}
+
+// newPACScript compiles a script and checks it defines FindProxyForURL
+func newPACScript(src string, builtins map[string]interface{}) (*pacScript, error) {
+	program, err := goja.Compile("pac", src, false)
+	if err != nil {
+		return nil, err
+	}
+	s := &pacScript{program: program, builtins: builtins, timeout: pacTimeout}
+	vm, err := s.newRuntime()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := goja.AssertFunction(vm.Get("FindProxyForURL")); !ok {
+		return nil, fmt.Errorf("FindProxyForURL is not defined")
+	}
+	s.pool.Put(vm)
+	return s, nil
+}
+
+// newRuntime returns a runtime with the PAC functions that ran the
+// global statements of the script
+func (s *pacScript) newRuntime() (*goja.Runtime, error) {
+	vm := goja.New()
+	for name, fn := range s.builtins {
+		if err := vm.Set(name, fn); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := vm.RunProgram(s.program); err != nil {
+		return nil, err
+	}
+	return vm, nil
+}
+
+// findProxy calls FindProxyForURL, an undefined or null answer is empty
+func (s *pacScript) findProxy(rawURL, host string) (string, error) {
+	vm, ok := s.pool.Get().(*goja.Runtime)
+	if !ok {
+		var err error
+		if vm, err = s.newRuntime(); err != nil {
+			return "", err
+		}
+	}
+	fn, ok := goja.AssertFunction(vm.Get("FindProxyForURL"))
+	if !ok {
+		return "", fmt.Errorf("FindProxyForURL is not defined")
+	}
+	timer := time.AfterFunc(s.timeout, func() {
+		vm.Interrupt(fmt.Sprintf("FindProxyForURL did not return within %s", s.timeout))
+	})
+	result, err := fn(goja.Undefined(), vm.ToValue(rawURL), vm.ToValue(host))
+	timer.Stop()
+	// an interrupted runtime is left in an undefined state
+	if err != nil {
+		return "", err
+	}
+	vm.ClearInterrupt()
+	s.pool.Put(vm)
+	if goja.IsUndefined(result) || goja.IsNull(result) {
+		return "", nil
+	}
+	return result.String(), nil
+}
+
+// pacWeekdays are the day names of weekdayRange, in the order of
+// time.Weekday
+var pacWeekdays = []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}
+
+// pacMonths are the month names of dateRange
+var pacMonths = []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}
+
+// indexOf returns the position of a name in the list, -1 if missing
+func indexOf(names []string, name string) int {
+	for i, n := range names {
+		if strings.EqualFold(n, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// pacArgs returns the arguments of a date or time function and the
+// time they are compared with, in UTC with a trailing "GMT" argument
+func pacArgs(call goja.FunctionCall, now time.Time) ([]goja.Value, time.Time) {
+	args := call.Arguments
+	if n := len(args); n > 0 && strings.EqualFold(args[n-1].String(), "GMT") {
+		return args[:n-1], now.UTC()
+	}
+	return args, now
+}
+
+// inRange reports whether the value is between the bounds, the range
+// wrapping around when the first bound is after the second one
+func inRange(value, first, last int) bool {
+	if first <= last {
+		return first <= value && value <= last
+	}
+	return value >= first || value <= last
+}
+
+// weekdayRange is the PAC function weekdayRange(wd1 [, wd2] [, "GMT"])
+func weekdayRange(call goja.FunctionCall, now time.Time) bool {
+	args, now := pacArgs(call, now)
+	if len(args) == 0 {
+		return false
+	}
+	first := indexOf(pacWeekdays, args[0].String())
+	last := first
+	if len(args) > 1 {
+		last = indexOf(pacWeekdays, args[1].String())
+	}
+	if first < 0 || last < 0 {
+		return false
+	}
+	return inRange(int(now.Weekday()), first, last)
+}
+
+// pacDateField returns the field of a dateRange argument: days are
+// numbers up to 31, years the larger ones and months their three letter
+// names. The values are scaled so a date compares as a single number.
+func pacDateField(arg goja.Value) (kind, value int, ok bool) {
+	if month := indexOf(pacMonths, arg.String()); month >= 0 {
+		return 1, (month + 1) * 100, true
+	}
+	n := int(arg.ToInteger())
+	switch {
+	case n > 31:
+		return 2, n * 10000, true
+	case n >= 1:
+		return 0, n, true
+	}
+	return 0, 0, false
+}
+
+// dateRange is the PAC function dateRange, taking a day, a month, a year
+// or the two bounds of a range of them, optionally followed by "GMT"
+func dateRange(call goja.FunctionCall, now time.Time) bool {
+	args, now := pacArgs(call, now)
+	if len(args) == 0 || len(args) > 1 && len(args)%2 != 0 || len(args) > 6 {
+		return false
+	}
+	half := (len(args) + 1) / 2
+	current := []int{now.Day(), int(now.Month()) * 100, now.Year() * 10000}
+	var first, last, value int
+	for i, arg := range args {
+		kind, v, ok := pacDateField(arg)
+		if !ok {
+			return false
+		}
+		if i < half {
+			first += v
+			value += current[kind]
+			// a single value is its own range
+			if len(args) == 1 {
+				last = v
+			}
+			continue
+		}
+		// the second bound names the same fields as the first one
+		if firstKind, _, _ := pacDateField(args[i-half]); firstKind != kind {
+			return false
+		}
+		last += v
+	}
+	return inRange(value, first, last)
+}
+
+// timeRange is the PAC function timeRange, taking an hour, the hours or
+// the minutes or the seconds bounding a range, optionally followed by
+// "GMT". An hour range ends before its last hour starts.
+func timeRange(call goja.FunctionCall, now time.Time) bool {
+	args, now := pacArgs(call, now)
+	values := make([]int, len(args))
+	for i, arg := range args {
+		values[i] = int(arg.ToInteger())
+	}
+	hour, minute, second := now.Clock()
+	switch len(values) {
+	case 1:
+		return hour == values[0]
+	case 2:
+		if values[0] == values[1] {
+			return hour == values[0]
+		}
+		return inRange(hour, values[0], (values[1]+23)%24)
+	case 4:
+		value := hour*60 + minute
+		return inRange(value, values[0]*60+values[1], values[2]*60+values[3])
+	case 6:
+		value := hour*3600 + minute*60 + second
+		return inRange(value, values[0]*3600+values[1]*60+values[2], values[3]*3600+values[4]*60+values[5])
+	}
+	return false
+}
@@ -0,0 +1,116 @@
+package libgobuster
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// PauseGate holds the workers of the scans sharing it while the run is
+// paused, from SIGUSR2 or an embedding application
+type PauseGate struct {
+	mu sync.Mutex
+	// resume is closed on resume, nil while running
+	resume chan struct{}
+	// pausedAt is when the current pause started, paused the time the
+	// earlier pauses lasted
+	pausedAt time.Time
+	paused   time.Duration
+}
+
+// NewPauseGate returns a gate letting the workers through
+func NewPauseGate() *PauseGate {
+	return &PauseGate{}
+}
+
+// Toggle pauses a running scan and resumes a paused one, it reports
+// whether the scan is paused now
+func (p *PauseGate) Toggle() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.resume == nil {
+		p.resume = make(chan struct{})
+		p.pausedAt = time.Now()
+		return true
+	}
+	close(p.resume)
+	p.resume = nil
+	p.paused += time.Since(p.pausedAt)
+	return false
+}
+
+// pausedTime returns how long the scans were paused, the current pause
+// included
+func (p *PauseGate) pausedTime() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.resume != nil {
+		return p.paused + time.Since(p.pausedAt)
+	}
+	return p.paused
+}
+
+// Paused reports whether the scan is paused
+func (p *PauseGate) Paused() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.resume != nil
+}
+
+// wait blocks while the scan is paused or until the context is done
+func (p *PauseGate) wait(c context.Context) {
+	p.mu.Lock()
+	resume := p.resume
+	p.mu.Unlock()
+	if resume == nil {
+		return
+	}
+	select {
+	case <-resume:
+	case <-c.Done():
+	}
+}
+
+// expireAfter cancels the scan with context.DeadlineExceeded once it ran
+// for the timeout, the time it was paused not counting
+func expireAfter(c context.Context, cancel context.CancelCauseFunc, timeout time.Duration, pause *PauseGate) {
+	start := time.Now()
+	var pausedAtStart time.Duration
+	if pause != nil {
+		pausedAtStart = pause.pausedTime()
+	}
+	for {
+		elapsed := time.Since(start)
+		if pause != nil {
+			elapsed -= pause.pausedTime() - pausedAtStart
+		}
+		if elapsed >= timeout {
+			cancel(context.DeadlineExceeded)
+			return
+		}
+		// a pause meanwhile moves the deadline, it is checked again
+		timer := time.NewTimer(timeout - elapsed)
+		select {
+		case <-c.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// State returns the state of the scan, logged on SIGUSR1
+func (g *Gobuster) State() string {
+	g.mu.RLock()
+	words, errorCount, expected := g.words, g.errorCount, g.requestsExpected
+	categories := formatErrorCategories(g.errorCategories)
+	g.mu.RUnlock()
+	s := g.runtimeStats(words)
+	if categories != "" {
+		categories = " (" + categories + ")"
+	}
+	paused := g.Opts.Pause != nil && g.Opts.Pause.Paused()
+	return fmt.Sprintf("target %s, run %s, running for %s, paused %t, requests %d/%d, matches %d, errors %d%s, %s",
+		g.BaseURL(), g.RunID, g.Now().Sub(g.startTime).Round(time.Second), paused, s.Requests, expected, s.Matches, errorCount, categories, s)
+}
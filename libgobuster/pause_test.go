@@ -0,0 +1,88 @@
+package libgobuster
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPauseGate(t *testing.T) {
+	t.Parallel()
+
+	p := NewPauseGate()
+	// a running scan is let through
+	p.wait(context.Background())
+
+	if !p.Toggle() || !p.Paused() {
+		t.Fatal("expected the scan paused")
+	}
+	done := make(chan struct{})
+	go func() {
+		p.wait(context.Background())
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Fatal("expected the worker held while paused")
+	case <-time.After(50 * time.Millisecond):
+	}
+	if p.Toggle() || p.Paused() {
+		t.Fatal("expected the scan resumed")
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected the worker released on resume")
+	}
+
+	// a cancelled scan is not held
+	p.Toggle()
+	c, cancel := context.WithCancel(context.Background())
+	cancel()
+	p.wait(c)
+}
+
+func TestExpireAfter(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName string
+		paused   bool
+		expired  bool
+	}{
+		{"Running", false, true},
+		{"Paused", true, false},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			p := NewPauseGate()
+			c, cancel := context.WithCancelCause(context.Background())
+			defer cancel(nil)
+			go expireAfter(c, cancel, 100*time.Millisecond, p)
+			if x.paused {
+				p.Toggle()
+			}
+			select {
+			case <-c.Done():
+			case <-time.After(300 * time.Millisecond):
+			}
+			if expired := context.Cause(c) == context.DeadlineExceeded; expired != x.expired {
+				t.Fatalf("expected expired %t, got %t", x.expired, expired)
+			}
+			if !x.paused {
+				return
+			}
+			// the clock runs again once resumed
+			p.Toggle()
+			select {
+			case <-c.Done():
+			case <-time.After(time.Second):
+				t.Fatal("expected the scan to expire once resumed")
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package libgobuster
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"sync"
+)
+
+// WebProber checks hosts for a web server over one client, the probes
+// of a run share its connections. They are not part of any scan and
+// stay out of the debug log.
+type WebProber struct {
+	opt    *Options
+	client *httpClient
+}
+
+// NewWebProber returns the prober of the hosts of a run
+func NewWebProber(c context.Context, opt *Options) (*WebProber, error) {
+	probeOpt := *opt
+	probeOpt.DebugHTTP = ""
+	client, err := newHTTPClient(c, &probeOpt)
+	if err != nil {
+		return nil, err
+	}
+	return &WebProber{opt: opt, client: client}, nil
+}
+
+// probe requests the url, any response whatever its status means a
+// server is listening
+func (p *WebProber) probe(base string) error {
+	_, err := p.client.makeRequest(http.MethodGet, base, RequestOptions{Cookies: p.opt.Cookies})
+	return err
+}
+
+// WebServer checks whether the host serves a website, trying https
+// before http, and returns the base url to run dir mode against
+func (p *WebProber) WebServer(host string) (string, error) {
+	var err error
+	for _, scheme := range []string{"https", "http"} {
+		base := hostURL(scheme, host, 0)
+		if err = p.probe(base); err == nil {
+			return base, nil
+		}
+	}
+	return "", fmt.Errorf("no web server found on %s: %v", host, err)
+}
+
+// webProbeSchemes returns the schemes a port is tried with, https first
+// on 443 and 8443
+func webProbeSchemes(port int) []string {
+	if port == 443 || port == 8443 {
+		return []string{"https", "http"}
+	}
+	return []string{"http", "https"}
+}
+
+// WebPorts returns the urls of the web servers on the -web-probe-ports
+// of the host
+func (p *WebProber) WebPorts(host string) []string {
+	var urls []string
+	for _, port := range p.opt.WebProbePortsParsed {
+		for _, scheme := range webProbeSchemes(port) {
+			if p.client.context.Err() != nil {
+				return urls
+			}
+			base := hostURL(scheme, host, port)
+			if p.probe(base) == nil {
+				urls = append(urls, base)
+				break
+			}
+		}
+	}
+	return urls
+}
+
+// ProbeWebPorts checks the -web-probe-ports of every subdomain found for
+// a web server. The urls of the web servers are written to the
+// output_webprobe folder and returned in the order of the subdomains.
+func (g *Gobuster) ProbeWebPorts(c context.Context) ([]string, error) {
+	prober, err := NewWebProber(c, g.Opts)
+	if err != nil {
+		return nil, err
+	}
+
+	hosts := g.FoundHosts()
+	live := make([][]string, len(hosts))
+	threads := g.Opts.Threads
+	if threads < 1 {
+		threads = 1
+	}
+	sem := make(chan struct{}, threads)
+	var wg sync.WaitGroup
+	for i, host := range hosts {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, host string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			live[i] = prober.WebPorts(host)
+		}(i, host)
+	}
+	wg.Wait()
+
+	var urls []string
+	for _, l := range live {
+		urls = append(urls, l...)
+	}
+	return urls, g.WriteWebProbes(urls)
+}
+
+// WriteWebProbes writes the urls of the web servers found on the
+// -web-probe-ports to the output_webprobe folder, nothing when none
+func (g *Gobuster) WriteWebProbes(urls []string) error {
+	if len(urls) == 0 {
+		return nil
+	}
+	file := fmt.Sprintf("%s/output_webprobe/webprobe_%s.txt", g.Opts.OutputFolder, g.OutputFileSuffix())
+	if err := os.MkdirAll(path.Dir(file), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, []byte(strings.Join(urls, "\n")+"\n"), 0644)
+}
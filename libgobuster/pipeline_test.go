@@ -0,0 +1,57 @@
+package libgobuster
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestProbeWebPorts(t *testing.T) {
+	t.Parallel()
+
+	h := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer h.Close()
+	u, _ := url.Parse(h.URL)
+	open, _ := strconv.Atoi(u.Port())
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	closed := l.Addr().(*net.TCPAddr).Port
+	l.Close()
+
+	o := NewOptions()
+	o.Mode = ModeDNS
+	o.URL = "example.com"
+	o.OutputFolder = t.TempDir()
+	o.WebProbePortsParsed = []int{closed, open}
+	g := &Gobuster{Opts: o, mu: new(sync.RWMutex), foundHosts: []string{"127.0.0.1"}}
+
+	urls, err := g.ProbeWebPorts(context.Background())
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	expected := []string{"http://127.0.0.1:" + u.Port() + "/"}
+	if !reflect.DeepEqual(urls, expected) {
+		t.Fatalf("expected %v, got %v", expected, urls)
+	}
+
+	written, err := ioutil.ReadFile(filepath.Join(o.OutputFolder, "output_webprobe", "webprobe_"+g.OutputFileSuffix()+".txt"))
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if string(written) != expected[0]+"\n" {
+		t.Fatalf("unexpected web probe file %q", written)
+	}
+}
@@ -0,0 +1,72 @@
+package libgobuster
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"time"
+)
+
+// profilesFolder is the folder of the output folder holding the profiles
+const profilesFolder = "profiles"
+
+var profileNameRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// ScanProfile is a named set of options stored in the output folder,
+// reused exactly by later runs
+type ScanProfile struct {
+	Name    string    `json:"name"`
+	Mode    string    `json:"mode"`
+	Created time.Time `json:"created"`
+	// Flags are the flags given on the command line by name
+	Flags map[string]string `json:"flags"`
+}
+
+// profilePath returns the file of a named profile
+func profilePath(outputFolder, name string) (string, error) {
+	if !profileNameRegex.MatchString(name) {
+		return "", fmt.Errorf("invalid profile name given: %s", name)
+	}
+	return fmt.Sprintf("%s/%s/%s.json", outputFolder, profilesFolder, name), nil
+}
+
+// LoadProfile reads a named profile of the output folder
+func LoadProfile(outputFolder, name string) (*ScanProfile, error) {
+	path, err := profilePath(outputFolder, name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no profile %s in %s", name, outputFolder)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read profile: %v", err)
+	}
+	var p ScanProfile
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to decode profile %s: %v", path, err)
+	}
+	return &p, nil
+}
+
+// Save writes the profile to the output folder, replacing a profile of
+// the same name
+func (p *ScanProfile) Save(outputFolder string) error {
+	path, err := profilePath(outputFolder, p.Name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(outputFolder+"/"+profilesFolder, 0755); err != nil {
+		return fmt.Errorf("failed to create profiles folder: %v", err)
+	}
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode profile: %v", err)
+	}
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write profile: %v", err)
+	}
+	return nil
+}
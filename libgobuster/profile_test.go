@@ -0,0 +1,65 @@
+package libgobuster
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestScanProfile(t *testing.T) {
+	t.Parallel()
+
+	folder := t.TempDir()
+	p := &ScanProfile{
+		Name:    "api-v2",
+		Mode:    ModeDir,
+		Created: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		Flags:   map[string]string{"t": "20", "ext": "php,json", "k": "true"},
+	}
+	if err := p.Save(folder); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	loaded, err := LoadProfile(folder, "api-v2")
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if !reflect.DeepEqual(loaded, p) {
+		t.Fatalf("loaded %+v, saved %+v", loaded, p)
+	}
+
+	if _, err := LoadProfile(folder, "missing"); err == nil {
+		t.Fatalf("expected an error for a missing profile")
+	}
+}
+
+func TestScanProfileName(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName string
+		name     string
+		valid    bool
+	}{
+		{"Plain", "quick", true},
+		{"Dots and dashes", "api_v2.full-scan", true},
+		{"Empty", "", false},
+		{"Path", "../quick", false},
+		{"Separator", "a/b", false},
+		{"Hidden", ".quick", false},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := profilePath("out", x.name)
+			if x.valid && err != nil {
+				t.Fatalf("got error: %v", err)
+			}
+			if !x.valid && err == nil {
+				t.Fatalf("expected an error for %q", x.name)
+			}
+		})
+	}
+}
@@ -0,0 +1,309 @@
+package libgobuster
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// progressLogInterval is the interval of the progress log lines written
+// when stderr is not a terminal
+const progressLogInterval = 10 * time.Second
+
+// progressRecentTargets is the number of finished targets kept on screen
+const progressRecentTargets = 3
+
+// progressBarWidth is the number of cells of a progress bar
+const progressBarWidth = 20
+
+// ProgressRenderer draws the progress of a run over one or many targets
+// on stderr. On a terminal a single target keeps the classic progress
+// line, many targets get a bar each for the current and the last
+// finished targets, followed by the totals of the run. Elsewhere the
+// progress is logged every progressLogInterval.
+type ProgressRenderer struct {
+	mu  sync.Mutex
+	out io.Writer
+	tty bool
+	// total is the number of targets of the run, 0 when not known yet
+	total int
+	// active are the scans running, the last one tracked is drawn
+	active  []*Gobuster
+	started int
+	// done sums up the finished targets
+	done    progressStats
+	recent  []string
+	lines   int
+	lastLog time.Time
+}
+
+// progressStats are the counters of one or many scans
+type progressStats struct {
+	issued   int
+	expected int
+	errors   int
+	matches  int
+}
+
+// NewProgressRenderer returns the renderer of a run over total targets,
+// drawing on f when it is a terminal
+func NewProgressRenderer(f *os.File, total int) *ProgressRenderer {
+	return &ProgressRenderer{
+		out:   f,
+		tty:   terminal.IsTerminal(int(f.Fd())),
+		total: total,
+	}
+}
+
+// SetTotal sets the number of targets of the run once it is known
+func (p *ProgressRenderer) SetTotal(total int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.total = total
+}
+
+// Skip removes a target which is not scanned after all from the total
+func (p *ProgressRenderer) Skip() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.total > 0 {
+		p.total--
+	}
+}
+
+// Track makes the scan the current target of the run, the scans still
+// running, like the dns scan of dns+dir, are counted in the totals
+func (p *ProgressRenderer) Track(g *Gobuster) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.active = append(p.active, g)
+	p.started++
+}
+
+// Finish adds a scan which returned to the finished targets
+func (p *ProgressRenderer) Finish(g *Gobuster) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i, a := range p.active {
+		if a != g {
+			continue
+		}
+		p.active = append(p.active[:i], p.active[i+1:]...)
+		s := g.progressStats()
+		p.done.add(s)
+		line := fmt.Sprintf("%-*s  %s  %d requests, %d matches, %d errors", progressBarWidth+2, "done", g.BaseURL(), s.issued, s.matches, s.errors)
+		p.recent = append(p.recent, line)
+		if len(p.recent) > progressRecentTargets {
+			p.recent = p.recent[1:]
+		}
+		return
+	}
+}
+
+// current returns the scan drawn, the last one tracked still running
+func (p *ProgressRenderer) current() *Gobuster {
+	if len(p.active) == 0 {
+		return nil
+	}
+	return p.active[len(p.active)-1]
+}
+
+// totals returns the counters of the finished and the running scans
+func (p *ProgressRenderer) totals() progressStats {
+	totals := p.done
+	for _, g := range p.active {
+		totals.add(g.progressStats())
+	}
+	return totals
+}
+
+// add adds the counters of a scan
+func (s *progressStats) add(o progressStats) {
+	s.issued += o.issued
+	s.expected += o.expected
+	s.errors += o.errors
+	s.matches += o.matches
+}
+
+// progressStats returns the counters of the scan
+func (g *Gobuster) progressStats() progressStats {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return progressStats{
+		issued:   g.requestsIssued,
+		expected: g.requestsExpected,
+		errors:   g.errorCount,
+		matches:  g.matchCount,
+	}
+}
+
+// progressBar draws the share of the issued requests
+func progressBar(issued, expected int) string {
+	filled := 0
+	if expected > 0 {
+		filled = issued * progressBarWidth / expected
+	}
+	if filled > progressBarWidth {
+		filled = progressBarWidth
+	}
+	return "[" + strings.Repeat("#", filled) + strings.Repeat(".", progressBarWidth-filled) + "]"
+}
+
+// truncateLine cuts a line to width characters on the screen. The escape
+// sequences of the colors take no room and are kept whole, a line cut
+// after one of them resets the colors.
+func truncateLine(line string, width int) string {
+	visible := 0
+	escaped := false
+	for i := 0; i < len(line); {
+		if line[i] == '\x1b' {
+			i += escapeLen(line[i:])
+			escaped = true
+			continue
+		}
+		if visible == width {
+			if escaped {
+				return line[:i] + "\x1b[0m"
+			}
+			return line[:i]
+		}
+		_, size := utf8.DecodeRuneInString(line[i:])
+		i += size
+		visible++
+	}
+	return line
+}
+
+// escapeLen returns the length of the escape sequence starting s, a CSI
+// sequence ends with a byte between @ and ~
+func escapeLen(s string) int {
+	if len(s) < 2 {
+		return len(s)
+	}
+	if s[1] != '[' {
+		return 2
+	}
+	for i := 2; i < len(s); i++ {
+		if s[i] >= '@' && s[i] <= '~' {
+			return i + 1
+		}
+	}
+	return len(s)
+}
+
+// targetsLabel returns the number of the current target of the run
+func (p *ProgressRenderer) targetsLabel() string {
+	if p.total > 0 {
+		return fmt.Sprintf("%d/%d", p.started, p.total)
+	}
+	return fmt.Sprintf("%d", p.started)
+}
+
+// frame returns the lines drawn on a terminal
+func (p *ProgressRenderer) frame() []string {
+	current := p.current()
+	if p.total <= 1 && p.started <= 1 {
+		if line := current.progressLine(); line != "" {
+			return []string{line}
+		}
+		return nil
+	}
+
+	lines := append([]string{}, p.recent...)
+	s := current.progressStats()
+	percent := 0.0
+	if s.expected > 0 {
+		percent = float64(s.issued) * 100 / float64(s.expected)
+	}
+	lines = append(lines, fmt.Sprintf("%s  %s  %d / %d (%3.2f%%)", progressBar(s.issued, s.expected), current.BaseURL(), s.issued, s.expected, percent))
+	totals := p.totals()
+	lines = append(lines, fmt.Sprintf("Targets: %s  |  Requests: %d  |  Matches: %d  |  Errors: %d",
+		p.targetsLabel(), totals.issued, totals.matches, totals.errors))
+	return lines
+}
+
+// logLine returns the progress logged when stderr is not a terminal
+func (p *ProgressRenderer) logLine() string {
+	current := p.current()
+	line := current.progressLine()
+	if line == "" {
+		return ""
+	}
+	if p.total <= 1 && p.started <= 1 {
+		return line
+	}
+	totals := p.totals()
+	return fmt.Sprintf("Target %s %s: %s  |  Total: %d requests, %d matches, %d errors",
+		p.targetsLabel(), current.BaseURL(), line, totals.issued, totals.matches, totals.errors)
+}
+
+// Render draws the progress of the current target and the run
+func (p *ProgressRenderer) Render() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	current := p.current()
+	if current == nil {
+		return
+	}
+
+	if !p.tty {
+		now := current.Now()
+		if now.Sub(p.lastLog) < progressLogInterval {
+			return
+		}
+		if line := p.logLine(); line != "" {
+			current.Logf("[+] %s", line)
+			p.lastLog = now
+		}
+		return
+	}
+
+	lines := p.frame()
+	if !multiLineProgress() && len(lines) > 1 {
+		// the bar of the current target and the totals
+		lines = []string{strings.Join(lines[len(lines)-2:], "  |  ")}
+	}
+	for i := range lines {
+		lines[i] = fitWidth(lines[i])
+	}
+	p.clear()
+	if len(lines) == 0 {
+		return
+	}
+	// the cursor stays on the last line so results overwrite the frame
+	fmt.Fprintf(p.out, "\r%s\r", strings.Join(lines, "\n"))
+	p.lines = len(lines)
+}
+
+// State returns the state of the current target and of the run
+func (p *ProgressRenderer) State() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	current := p.current()
+	if current == nil {
+		return "no target is being scanned"
+	}
+	return fmt.Sprintf("targets %s, %d requests, %d matches and %d errors on the finished targets, %s",
+		p.targetsLabel(), p.done.issued, p.done.matches, p.done.errors, current.State())
+}
+
+// Clear removes the frame from the terminal
+func (p *ProgressRenderer) Clear() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.clear()
+}
+
+func (p *ProgressRenderer) clear() {
+	if !p.tty || p.lines == 0 {
+		return
+	}
+	fmt.Fprint(p.out, clearLines(p.lines))
+	p.lines = 0
+}
@@ -0,0 +1,141 @@
+package libgobuster
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestProgressBar(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName string
+		issued   int
+		expected int
+		bar      string
+	}{
+		{"Unknown", 5, 0, "[....................]"},
+		{"Half", 50, 100, "[##########..........]"},
+		{"Done", 100, 100, "[####################]"},
+		{"Over", 120, 100, "[####################]"},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			if got := progressBar(x.issued, x.expected); got != x.bar {
+				t.Fatalf("expected %q, got %q", x.bar, got)
+			}
+		})
+	}
+}
+
+func TestTruncateLine(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName string
+		line     string
+		width    int
+		expected string
+	}{
+		{"Short", "abc", 5, "abc"},
+		{"Cut", "abcdef", 3, "abc"},
+		{"Runes", "héllo wörld", 7, "héllo w"},
+		{"Colors", "\x1b[32mabc\x1b[0mdef", 4, "\x1b[32mabc\x1b[0md\x1b[0m"},
+		{"Colors fitting", "\x1b[32mabc\x1b[0m", 3, "\x1b[32mabc\x1b[0m"},
+		{"Cut escape", "ab\x1b[3", 2, "ab\x1b[3"},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			if got := truncateLine(x.line, x.width); got != x.expected {
+				t.Fatalf("expected %q, got %q", x.expected, got)
+			}
+		})
+	}
+}
+
+func progressTarget(url string, issued, expected, matches int) *Gobuster {
+	o := NewOptions()
+	o.URL = url
+	o.Wordlist = "words.txt"
+	return &Gobuster{Opts: o, mu: new(sync.RWMutex), requestsIssued: issued, requestsExpected: expected, matchCount: matches}
+}
+
+func TestProgressRenderer(t *testing.T) {
+	t.Parallel()
+
+	out := &bytes.Buffer{}
+	p := &ProgressRenderer{out: out, tty: true, total: 3}
+
+	a := progressTarget("http://a/", 100, 100, 2)
+	p.Track(a)
+	p.Finish(a)
+	p.Track(progressTarget("http://b/", 25, 100, 1))
+	frame := p.frame()
+	if len(frame) != 3 {
+		t.Fatalf("expected the finished target, the current one and the totals, got %q", frame)
+	}
+	if !strings.HasPrefix(frame[0], "done") || !strings.Contains(frame[0], "http://a/") {
+		t.Fatalf("unexpected finished target line %q", frame[0])
+	}
+	if !strings.HasPrefix(frame[1], "[#####...............]  http://b/") {
+		t.Fatalf("unexpected current target line %q", frame[1])
+	}
+	expected := "Targets: 2/3  |  Requests: 125  |  Matches: 3  |  Errors: 0"
+	if frame[2] != expected {
+		t.Fatalf("expected %q, got %q", expected, frame[2])
+	}
+
+	p.Render()
+	if p.lines != 3 {
+		t.Fatalf("expected 3 lines on screen, got %d", p.lines)
+	}
+	out.Reset()
+	p.Clear()
+	if out.String() != clearLines(3) || p.lines != 0 {
+		t.Fatalf("unexpected clear sequence %q", out.String())
+	}
+}
+
+func TestProgressRendererSingleTarget(t *testing.T) {
+	t.Parallel()
+
+	p := &ProgressRenderer{out: &bytes.Buffer{}, tty: true, total: 1}
+	p.Track(progressTarget("http://a/", 10, 40, 0))
+	frame := p.frame()
+	if len(frame) != 1 || !strings.HasPrefix(frame[0], "Progress: 10 / 40") {
+		t.Fatalf("expected the single progress line, got %q", frame)
+	}
+}
+
+func TestProgressRendererConcurrentScans(t *testing.T) {
+	t.Parallel()
+
+	p := &ProgressRenderer{out: &bytes.Buffer{}, tty: true}
+	// the dns scan of dns+dir keeps running along the dir scans
+	dns := progressTarget("example.com", 50, 100, 3)
+	p.Track(dns)
+	p.Track(progressTarget("http://www.example.com/", 10, 100, 1))
+	frame := p.frame()
+	if len(frame) != 2 || !strings.Contains(frame[0], "http://www.example.com/") {
+		t.Fatalf("expected the dir scan drawn, got %q", frame)
+	}
+	expected := "Targets: 2  |  Requests: 60  |  Matches: 4  |  Errors: 0"
+	if frame[1] != expected {
+		t.Fatalf("expected %q, got %q", expected, frame[1])
+	}
+
+	p.Finish(dns)
+	if frame := p.frame(); !strings.HasPrefix(frame[0], "done") || !strings.Contains(frame[0], "example.com") || frame[2] != expected {
+		t.Fatalf("expected the dns scan finished, got %q", frame)
+	}
+}
@@ -0,0 +1,102 @@
+package libgobuster
+
+import (
+	"path"
+	"strings"
+	"sync"
+)
+
+// PruneExtsSample is the number of requests of an extension without a hit
+// after which -prune-exts stops requesting it. By the rule of three its
+// hit rate is then below 1% with a 95% confidence.
+const PruneExtsSample = 300
+
+// extPruner counts the requests and hits of every extension the words
+// are expanded with, for -prune-exts
+type extPruner struct {
+	mu     sync.Mutex
+	tries  map[string]int
+	hits   map[string]int
+	pruned Set[string]
+}
+
+func newExtPruner() *extPruner {
+	return &extPruner{
+		tries:  make(map[string]int),
+		hits:   make(map[string]int),
+		pruned: NewSet[string](),
+	}
+}
+
+// record counts a request of an extension, it returns true when the
+// extension is pruned by it
+func (p *extPruner) record(ext string, hit bool) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.tries[ext]++
+	if hit {
+		p.hits[ext]++
+	}
+	if p.hits[ext] > 0 || p.tries[ext] < PruneExtsSample {
+		return false
+	}
+	return p.pruned.Add(ext)
+}
+
+func (p *extPruner) isPruned(ext string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.pruned.Contains(ext)
+}
+
+// Values returns the extensions pruned, sorted
+func (p *extPruner) Values() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.pruned.Values()
+}
+
+// expandedExt returns the extension a wordlist word was expanded with, ""
+// for the words requested as they are
+func (g *Gobuster) expandedExt(word, expanded string) string {
+	if expanded == word {
+		return ""
+	}
+	ext := strings.TrimPrefix(path.Ext(expanded), ".")
+	if !g.Opts.ExtensionsParsed.Contains(ext) {
+		return ""
+	}
+	return ext
+}
+
+// skipPruned reports whether the extension of a word is pruned, the
+// request is then no longer expected
+func (g *Gobuster) skipPruned(ext string) bool {
+	if g.pruner == nil || ext == "" || !g.pruner.isPruned(ext) {
+		return false
+	}
+	g.mu.Lock()
+	if g.requestsExpected > 0 {
+		g.requestsExpected--
+	}
+	g.mu.Unlock()
+	return true
+}
+
+// recordExtension counts the request of a word expanded with an
+// extension and whether one of its results matched
+func (g *Gobuster) recordExtension(busterTarget *BusterTarget, res []Result) {
+	if g.pruner == nil || busterTarget.ext == "" {
+		return
+	}
+	hit := false
+	for _, r := range res {
+		if r.Match {
+			hit = true
+			break
+		}
+	}
+	if g.pruner.record(busterTarget.ext, hit) && !g.Opts.Quiet {
+		g.Logf("[+] Pruned extension %s: no hit in %d requests", busterTarget.ext, PruneExtsSample)
+	}
+}
@@ -0,0 +1,63 @@
+package libgobuster
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtPruner(t *testing.T) {
+	t.Parallel()
+
+	p := newExtPruner()
+	for i := 1; i < PruneExtsSample; i++ {
+		if p.record("asp", false) {
+			t.Fatalf("pruned after %d requests", i)
+		}
+		p.record("php", i == 250)
+	}
+	if !p.record("asp", false) {
+		t.Fatalf("expected asp to be pruned after %d requests", PruneExtsSample)
+	}
+	if p.record("asp", false) {
+		t.Fatalf("expected asp to be pruned once")
+	}
+	for i := 0; i < PruneExtsSample; i++ {
+		p.record("php", false)
+	}
+	if !p.isPruned("asp") || p.isPruned("php") {
+		t.Fatalf("expected asp pruned and php kept, got %v", p.Values())
+	}
+	if got := p.Values(); !reflect.DeepEqual(got, []string{"asp"}) {
+		t.Fatalf("expected [asp], got %v", got)
+	}
+}
+
+func TestExpandedExt(t *testing.T) {
+	t.Parallel()
+
+	o := NewOptions()
+	o.ExtensionsParsed = NewSet("php", "bak")
+	g := &Gobuster{Opts: o}
+
+	tt := []struct {
+		word     string
+		expanded string
+		expected string
+	}{
+		{"admin", "admin", ""},
+		{"admin", "admin.php", "php"},
+		{"index.%EXT%", "index.bak", "bak"},
+		{"config", "config/", ""},
+		{"app", "app.js", ""},
+	}
+	for _, x := range tt {
+		x := x
+		t.Run(x.expanded, func(t *testing.T) {
+			t.Parallel()
+
+			if got := g.expandedExt(x.word, x.expanded); got != x.expected {
+				t.Fatalf("expected %q, got %q", x.expected, got)
+			}
+		})
+	}
+}
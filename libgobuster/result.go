@@ -1,21 +1,217 @@
-package libgobuster
-
-// Result represents a single gobuster result
-type Result struct {
-	Entity      string
-	Status      int
-	Extra       string
-	Size        *int64
-	Content     *string
-	IsEntityURL bool
-	RedirectURL *string
-}
-
-// ToString converts the Result to it's textual representation
-func (r *Result) ToString(g *Gobuster) (string, string, int, error) {
-	s, as, status, err := g.plugin.ResultToString(g, r)
-	if err != nil {
-		return "", "", 0, err
-	}
-	return *s, *as, status, nil
-}
+package libgobuster
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Result represents a single gobuster result
+type Result struct {
+	Entity      string
+	Status      int
+	// Metadata are the annotations of the result by key, rendered by
+	// the output of every format
+	Metadata    map[string]string
+	Size        *int64
+	Content     *string
+	IsEntityURL bool
+	RedirectURL *string
+	// RedirectChain holds every url of a followed redirect
+	RedirectChain []string
+	IPs         []string
+	CNAME       string
+	// Takeover names the service the subdomain may be taken over through
+	Takeover    string
+	RequestID   string
+	ContentType string
+	// Listable is set for directory listing pages
+	Listable    bool
+	// Methods are the methods -method-enum found the endpoint allows,
+	// with the status of the probed ones
+	Methods     map[string]int
+	// VirtualHost is the host sent by -host-fuzz
+	VirtualHost string
+	// RetryAfter is the delay a 429 or 503 response asked for
+	RetryAfter  time.Duration
+	// Match is set by the plugins classifying their results in Process
+	// for the hits
+	Match bool
+	// FalsePositive is set along with Match for the wildcard answers
+	FalsePositive bool
+}
+
+// ToString converts the Result to it's textual representation
+func (r *Result) ToString(g *Gobuster) (string, string, int, error) {
+	s, as, status, err := g.plugin.ResultToString(g, r)
+	if err != nil {
+		return "", "", 0, err
+	}
+	return *s, *as, status, nil
+}
+
+// The metadata keys of the results, the plugins and extensions are free
+// to add their own
+const (
+	MetaIPs         = "ips"
+	MetaCNAME       = "cname"
+	MetaError       = "error"
+	MetaTitle       = "title"
+	MetaContentType = "content_type"
+	MetaAPI         = "api"
+	MetaBinary      = "binary"
+	MetaStreaming   = "streaming"
+	MetaWAF         = "waf"
+	MetaAuth        = "auth"
+	MetaDiff        = "diff"
+	MetaMethods     = "methods"
+	MetaUpload      = "upload"
+	MetaArtifact    = "artifact"
+	MetaBypass      = "bypass"
+	MetaStability   = "stability"
+	MetaFavicon     = "favicon_hash"
+)
+
+// metaAnnotations are the renderings of the metadata annotating the plain
+// output, in their order, %s standing for the value. The keys left out
+// are shown by their own options (-show-ips, -show-content-type...), the
+// keys of the plugins follow sorted as key: value.
+var metaAnnotations = []struct {
+	key    string
+	format string
+}{
+	{MetaError, "%s"},
+	{MetaAPI, "%s"},
+	{MetaBinary, "binary"},
+	{MetaStreaming, "streaming"},
+	{MetaWAF, "blocked by %s"},
+	{MetaAuth, "%s"},
+	{MetaDiff, "wildcard diff %s"},
+	{MetaMethods, "methods: %s"},
+	{MetaUpload, "upload: %s"},
+	{MetaArtifact, "artifact: %s"},
+	{MetaBypass, "%s"},
+	{MetaStability, "%s"},
+}
+
+// shownMeta are the metadata keys shown by their own options, or once in
+// the config for the favicon hash of the target
+var shownMeta = NewSet(MetaIPs, MetaCNAME, MetaTitle, MetaContentType, MetaFavicon)
+
+// SetMeta sets a metadata of the result, the empty values are left out.
+// The flags are set to "true".
+func (r *Result) SetMeta(key, value string) {
+	if value == "" {
+		return
+	}
+	if r.Metadata == nil {
+		r.Metadata = make(map[string]string)
+	}
+	r.Metadata[key] = value
+}
+
+// Annotation returns the metadata of the result as shown by the plain
+// output, joined by semicolons
+func (r *Result) Annotation() string {
+	var parts []string
+	known := NewSet[string]()
+	for _, a := range metaAnnotations {
+		known.Add(a.key)
+		if value, ok := r.Metadata[a.key]; ok {
+			parts = append(parts, strings.ReplaceAll(a.format, "%s", value))
+		}
+	}
+	var others []string
+	for key := range r.Metadata {
+		if !known.Contains(key) && !shownMeta.Contains(key) {
+			others = append(others, key)
+		}
+	}
+	sort.Strings(others)
+	for _, key := range others {
+		parts = append(parts, key+": "+r.Metadata[key])
+	}
+	return strings.Join(parts, "; ")
+}
+
+// FullURL returns the absolute url (or domain in dns mode) of the result
+func (r *Result) FullURL(g *Gobuster) string {
+	if r.IsEntityURL || g.Opts.Mode == ModeDNS {
+		return r.Entity
+	}
+	return g.URLFor(r.Entity)
+}
+
+// AppPath returns the path of a dir mode result relative to the
+// application root, with a leading slash and the query of a wayback url,
+// or the host of a -host-fuzz result
+func (r *Result) AppPath(g *Gobuster) string {
+	// the fuzzed hosts share the path
+	if r.VirtualHost != "" {
+		return r.VirtualHost
+	}
+	if !r.IsEntityURL {
+		return "/" + r.Entity
+	}
+	p := r.Entity
+	if u, err := url.Parse(r.Entity); err == nil {
+		p = u.Path
+		if u.RawQuery != "" {
+			p += "?" + u.RawQuery
+		}
+	}
+	return "/" + strings.TrimPrefix(strings.TrimPrefix(p, "/"), strings.TrimPrefix(g.Opts.BasePath, "/"))
+}
+
+// DNSRecord returns the tab separated subdomain, IP addresses, CNAME,
+// source record and takeover fingerprint of a dns mode result
+func (r *Result) DNSRecord() string {
+	return fmt.Sprintf("%s\t%s\t%s\t%s\t%s", r.Entity, strings.Join(r.IPs, ","), r.CNAME, r.DNSSource(), r.Takeover)
+}
+
+// DNSSource returns whether a subdomain resolved through its own address
+// records or by following a CNAME chain
+func (r *Result) DNSSource() string {
+	if r.CNAME != "" {
+		return DNSSourceCNAME
+	}
+	return DNSSourceA
+}
+
+// Extension returns the extension of the result path without the leading dot
+func (r *Result) Extension() string {
+	entity := r.Entity
+	if u, err := url.Parse(entity); err == nil {
+		entity = u.Path
+	}
+	return strings.TrimPrefix(path.Ext(entity), ".")
+}
+
+// HTTPXLine returns the result in the httpx output style, the url alone
+// or annotated with the status and length (IP addresses in dns mode)
+func (r *Result) HTTPXLine(g *Gobuster) string {
+	if g.Opts.Format != FormatHTTPXAnnotated {
+		return r.FullURL(g)
+	}
+	return r.annotatedLine(g)
+}
+
+// annotatedLine returns the url of the result followed by its status and
+// length, or its IP addresses in dns mode
+func (r *Result) annotatedLine(g *Gobuster) string {
+	line := r.FullURL(g)
+	if g.Opts.Mode == ModeDNS {
+		if len(r.IPs) > 0 {
+			line += fmt.Sprintf(" [%s]", strings.Join(r.IPs, ","))
+		}
+		return line
+	}
+	line += fmt.Sprintf(" [%d]", r.Status)
+	if r.Size != nil {
+		line += fmt.Sprintf(" [%d]", *r.Size)
+	}
+	return line
+}
@@ -0,0 +1,113 @@
+package libgobuster
+
+import "testing"
+
+func TestHTTPXLine(t *testing.T) {
+	t.Parallel()
+
+	size := int64(42)
+	tt := []struct {
+		testName string
+		mode     string
+		format   string
+		result   Result
+		expected string
+	}{
+		{"Dir", ModeDir, FormatHTTPX, Result{Entity: "admin", Status: 200, Size: &size}, "http://example.com/admin"},
+		{"Dir annotated", ModeDir, FormatHTTPXAnnotated, Result{Entity: "admin", Status: 200, Size: &size}, "http://example.com/admin [200] [42]"},
+		{"Entity url", ModeDir, FormatHTTPXAnnotated, Result{Entity: "http://other.com/x", IsEntityURL: true, Status: 301}, "http://other.com/x [301]"},
+		{"DNS", ModeDNS, FormatHTTPX, Result{Entity: "www.example.com", IPs: []string{"10.0.0.1"}}, "www.example.com"},
+		{"DNS annotated", ModeDNS, FormatHTTPXAnnotated, Result{Entity: "www.example.com", IPs: []string{"10.0.0.1", "10.0.0.2"}}, "www.example.com [10.0.0.1,10.0.0.2]"},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			o := NewOptions()
+			o.Mode = x.mode
+			o.URL = "http://example.com/"
+			o.Format = x.format
+			g := &Gobuster{Opts: o}
+			if got := x.result.HTTPXLine(g); got != x.expected {
+				t.Fatalf("expected %q, got %q", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestDNSRecord(t *testing.T) {
+	t.Parallel()
+
+	direct := Result{Entity: "www.example.com", IPs: []string{"10.0.0.1", "10.0.0.2"}}
+	if got := direct.DNSRecord(); got != "www.example.com\t10.0.0.1,10.0.0.2\t\tA\t" {
+		t.Fatalf("unexpected record %q", got)
+	}
+	aliased := Result{Entity: "shop.example.com", IPs: []string{"10.0.0.3"}, CNAME: "shops.provider.net"}
+	if got := aliased.DNSRecord(); got != "shop.example.com\t10.0.0.3\tshops.provider.net\tCNAME\t" {
+		t.Fatalf("unexpected record %q", got)
+	}
+	dangling := Result{Entity: "docs.example.com", CNAME: "example.github.io", Takeover: "GitHub Pages"}
+	if got := dangling.DNSRecord(); got != "docs.example.com\t\texample.github.io\tCNAME\tGitHub Pages" {
+		t.Fatalf("unexpected record %q", got)
+	}
+}
+
+func TestAppPath(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName string
+		basePath string
+		result   Result
+		expected string
+	}{
+		{"Word", "", Result{Entity: "admin"}, "/admin"},
+		{"Wayback url", "", Result{Entity: "http://example.com/a/b?id=1", IsEntityURL: true}, "/a/b?id=1"},
+		{"Word below base path", "/app/v2/", Result{Entity: "admin/"}, "/admin/"},
+		{"Wayback url below base path", "/app/v2/", Result{Entity: "http://example.com/app/v2/users", IsEntityURL: true}, "/users"},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			o := NewOptions()
+			o.Mode = ModeDir
+			o.URL = "http://example.com/"
+			o.BasePath = x.basePath
+			g := &Gobuster{Opts: o}
+			if got := x.result.AppPath(g); got != x.expected {
+				t.Fatalf("expected %q, got %q", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestAnnotation(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName string
+		metadata map[string]string
+		expected string
+	}{
+		{"None", nil, ""},
+		{"Ordered", map[string]string{MetaStability: "stable 3/3", MetaBinary: "true", MetaWAF: "Sucuri"}, "binary; blocked by Sucuri; stable 3/3"},
+		{"Shown by options", map[string]string{MetaTitle: "Admin", MetaContentType: "text/html", MetaMethods: "GET, PUT 201"}, "methods: GET, PUT 201"},
+		{"Plugin keys", map[string]string{"tech": "nginx", "cve": "CVE-2021-1", MetaUpload: "PUT 201"}, "upload: PUT 201; cve: CVE-2021-1; tech: nginx"},
+	}
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			r := Result{Metadata: x.metadata}
+			if got := r.Annotation(); got != x.expected {
+				t.Fatalf("expected %q, got %q", x.expected, got)
+			}
+		})
+	}
+}
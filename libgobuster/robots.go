@@ -0,0 +1,195 @@
+package libgobuster
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// ErrRobotsDisallowAll is returned by Start when -respect-robots leaves
+// no path of the target to request
+var ErrRobotsDisallowAll = errors.New("robots.txt disallows every path")
+
+// robotsRule is an Allow or Disallow line of robots.txt
+type robotsRule struct {
+	allow   bool
+	pattern string
+	re      *regexp.Regexp
+}
+
+// RobotsRules are the rules of robots.txt applying to the scan, the
+// group of its user agent or else the * group
+type RobotsRules struct {
+	rules []robotsRule
+	// disallowAll is set when robots.txt could not be fetched, which
+	// RFC 9309 treats as a complete disallow
+	disallowAll bool
+}
+
+// robotsPattern compiles a path pattern, * matches any characters and a
+// trailing $ anchors the end of the path
+func robotsPattern(pattern string) *regexp.Regexp {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+	parts := strings.Split(pattern, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	expr := "^" + strings.Join(parts, ".*")
+	if anchored {
+		expr += "$"
+	}
+	return regexp.MustCompile(expr)
+}
+
+// ParseRobots parses robots.txt, keeping the rules of the groups naming
+// the agent, or of the * groups if none does
+func ParseRobots(r io.Reader, agent string) (*RobotsRules, error) {
+	agent = strings.ToLower(agent)
+	var own, all []robotsRule
+	var groupAgents []string
+	inRules := false
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		key, value := strings.ToLower(strings.TrimSpace(parts[0])), strings.TrimSpace(parts[1])
+		switch key {
+		case "user-agent":
+			// consecutive user agents share the rules that follow
+			if inRules {
+				groupAgents = nil
+				inRules = false
+			}
+			groupAgents = append(groupAgents, strings.ToLower(value))
+		case "allow", "disallow":
+			inRules = true
+			// an empty disallow allows everything
+			if value == "" {
+				continue
+			}
+			rule := robotsRule{allow: key == "allow", pattern: value, re: robotsPattern(value)}
+			for _, a := range groupAgents {
+				if a == "*" {
+					all = append(all, rule)
+				} else if strings.Contains(agent, a) {
+					own = append(own, rule)
+				}
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if own != nil {
+		return &RobotsRules{rules: own}, nil
+	}
+	return &RobotsRules{rules: all}, nil
+}
+
+// Allowed reports whether the path may be requested: the longest
+// matching rule decides, Allow wins a tie
+func (r *RobotsRules) Allowed(path string) bool {
+	if r.disallowAll {
+		return false
+	}
+	if path == "/robots.txt" {
+		return true
+	}
+	allowed, longest := true, -1
+	for _, rule := range r.rules {
+		if !rule.re.MatchString(path) {
+			continue
+		}
+		if len(rule.pattern) > longest || (len(rule.pattern) == longest && rule.allow) {
+			allowed, longest = rule.allow, len(rule.pattern)
+		}
+	}
+	return allowed
+}
+
+// robotsAgent returns the user agent robots.txt groups are matched with
+func (opt *Options) robotsAgent() string {
+	if opt.UserAgent != "" {
+		return opt.UserAgent
+	}
+	return "gobuster"
+}
+
+// FetchRobots fetches the robots.txt of the target for -respect-robots.
+// A missing one allows every path, an unreachable one none.
+func (g *Gobuster) FetchRobots() error {
+	u, err := url.Parse(g.BaseURL())
+	if err != nil {
+		return err
+	}
+	robotsURL := JoinURL(originURL(u), "robots.txt")
+	resp, err := g.GetRequest(robotsURL, g.NewRequestOptions(robotsURL))
+	if err != nil {
+		return fmt.Errorf("unable to fetch %s: %v", robotsURL, err)
+	}
+	switch {
+	case resp.StatusCode >= 500:
+		g.robots = &RobotsRules{disallowAll: true}
+		g.Logf("[!] %s answered %d, every path is disallowed", robotsURL, resp.StatusCode)
+		return nil
+	case resp.StatusCode != http.StatusOK:
+		g.robots = &RobotsRules{}
+		g.Logf("[+] No robots.txt, every path is allowed")
+		return nil
+	}
+	rules, err := ParseRobots(strings.NewReader(resp.Content), g.Opts.robotsAgent())
+	if err != nil {
+		return fmt.Errorf("unable to parse %s: %v", robotsURL, err)
+	}
+	g.robots = rules
+	g.Logf("[+] Respecting %s (%d rules)", robotsURL, len(rules.rules))
+	return nil
+}
+
+// RobotsDisallowAll reports whether -respect-robots disallows every path
+// of the target, robots.txt being unreachable
+func (g *Gobuster) RobotsDisallowAll() bool {
+	return g.robots != nil && g.robots.disallowAll
+}
+
+// RobotsAllowed reports whether -respect-robots lets the url be
+// requested, the skipped urls are counted. The urls of other hosts are
+// not covered by the robots.txt of the target.
+func (g *Gobuster) RobotsAllowed(rawURL string) bool {
+	if g.robots == nil {
+		return true
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return true
+	}
+	if base, err := url.Parse(g.BaseURL()); err != nil || !strings.EqualFold(base.Host, u.Host) {
+		return true
+	}
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+	if g.robots.Allowed(path) {
+		return true
+	}
+	g.mu.Lock()
+	g.robotsSkipped++
+	g.mu.Unlock()
+	return false
+}
@@ -0,0 +1,60 @@
+package libgobuster
+
+import (
+	"strings"
+	"testing"
+)
+
+const testRobots = `# comment
+User-agent: *
+Disallow: /admin
+Disallow: /*.bak$
+Allow: /admin/public
+
+User-agent: gobuster
+User-agent: other
+Disallow: /private # trailing comment
+Disallow:
+`
+
+func TestRobotsAllowed(t *testing.T) {
+	t.Parallel()
+
+	common, err := ParseRobots(strings.NewReader(testRobots), "Mozilla/5.0")
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	own, err := ParseRobots(strings.NewReader(testRobots), "gobuster 2.0.1")
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+
+	tt := []struct {
+		testName string
+		rules    *RobotsRules
+		path     string
+		expected bool
+	}{
+		{"Disallowed", common, "/admin", false},
+		{"Disallowed prefix", common, "/administrator", false},
+		{"Longest allow wins", common, "/admin/public/index.html", true},
+		{"Wildcard", common, "/db.bak", false},
+		{"Anchored", common, "/db.bak.txt", true},
+		{"Not covered", common, "/login", true},
+		{"Robots", common, "/robots.txt", true},
+		{"Own group", own, "/private/x", false},
+		{"Own group only", own, "/admin", true},
+		{"Disallow all", &RobotsRules{disallowAll: true}, "/", false},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			if got := x.rules.Allowed(x.path); got != x.expected {
+				t.Fatalf("expected %t for %s, got %t", x.expected, x.path, got)
+			}
+		})
+	}
+}
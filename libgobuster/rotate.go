@@ -0,0 +1,233 @@
+package libgobuster
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// RotatingFile appends to a file which is compressed into numbered
+// archives once it outgrows the maximum size: name.1.txt.gz is the
+// newest archive, name.2.txt.gz the one before and so on
+type RotatingFile struct {
+	mu      sync.Mutex
+	path    string
+	maxSize int64
+	f       *os.File
+	size    int64
+}
+
+// OpenRotatingFile opens the file for appending, a maximum size of 0
+// never rotates it
+func OpenRotatingFile(path string, maxSize int64) (*RotatingFile, error) {
+	r := &RotatingFile{path: path, maxSize: maxSize}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *RotatingFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	r.f = f
+	r.size = fi.Size()
+	return nil
+}
+
+// Name returns the path of the file written to
+func (r *RotatingFile) Name() string {
+	return r.path
+}
+
+// Write appends p, rotating the file first when p would not fit
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.maxSize > 0 {
+		// another run sharing the output folder may have rotated it
+		if fi, err := os.Stat(r.path); err != nil || !sameFile(fi, r.f) {
+			r.f.Close()
+			if err := r.open(); err != nil {
+				return 0, err
+			}
+		}
+		if r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+			if err := r.rotate(); err != nil {
+				return 0, fmt.Errorf("failed to rotate %s: %v", r.path, err)
+			}
+		}
+	}
+	n, err := r.f.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// WriteString appends s
+func (r *RotatingFile) WriteString(s string) (int, error) {
+	return r.Write([]byte(s))
+}
+
+// Close closes the current file
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.f.Close()
+}
+
+func sameFile(fi os.FileInfo, f *os.File) bool {
+	current, err := f.Stat()
+	return err == nil && os.SameFile(fi, current)
+}
+
+// rotatedName returns the name of the nth archive of the path
+func rotatedName(path string, n int) string {
+	ext := filepath.Ext(path)
+	return fmt.Sprintf("%s.%d%s.gz", strings.TrimSuffix(path, ext), n, ext)
+}
+
+// rotatedArchives returns the numbers of the existing archives of the
+// path, newest first
+func rotatedArchives(path string) ([]int, error) {
+	ext := filepath.Ext(path)
+	prefix := strings.TrimSuffix(path, ext) + "."
+	matches, err := filepath.Glob(prefix + "*" + ext + ".gz")
+	if err != nil {
+		return nil, err
+	}
+	var numbers []int
+	for _, m := range matches {
+		n, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(m, prefix), ext+".gz"))
+		if err != nil || n < 1 {
+			continue
+		}
+		numbers = append(numbers, n)
+	}
+	sort.Ints(numbers)
+	return numbers, nil
+}
+
+// rotate shifts the archives by one and compresses the current file
+// into the first one
+func (r *RotatingFile) rotate() error {
+	if err := r.f.Close(); err != nil {
+		return err
+	}
+
+	// the file is moved away first so the writes of other runs go
+	// to a new file while it is compressed
+	pending := fmt.Sprintf("%s.rotating-%d", r.path, os.Getpid())
+	if err := os.Rename(r.path, pending); err != nil {
+		return err
+	}
+
+	numbers, err := rotatedArchives(r.path)
+	if err != nil {
+		return err
+	}
+	for i := len(numbers) - 1; i >= 0; i-- {
+		if err := os.Rename(rotatedName(r.path, numbers[i]), rotatedName(r.path, numbers[i]+1)); err != nil {
+			return err
+		}
+	}
+	if err := compressFile(pending, rotatedName(r.path, 1)); err != nil {
+		return err
+	}
+	if err := os.Remove(pending); err != nil {
+		return err
+	}
+	return r.open()
+}
+
+func compressFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(out)
+	if _, err := io.Copy(zw, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// multiReadCloser closes every file read through a MultiReader
+type multiReadCloser struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (m *multiReadCloser) Close() error {
+	var err error
+	for _, c := range m.closers {
+		if cerr := c.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// OpenRotated reads the archives of a rotated file, oldest first,
+// followed by the current file
+func OpenRotated(path string) (io.ReadCloser, error) {
+	numbers, err := rotatedArchives(path)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &multiReadCloser{}
+	var readers []io.Reader
+	for i := len(numbers) - 1; i >= 0; i-- {
+		f, err := os.Open(rotatedName(path, numbers[i]))
+		if err != nil {
+			m.Close()
+			return nil, err
+		}
+		m.closers = append(m.closers, f)
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			m.Close()
+			return nil, fmt.Errorf("failed to read %s: %v", f.Name(), err)
+		}
+		readers = append(readers, zr)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		// everything may have been rotated away
+		if !os.IsNotExist(err) || len(readers) == 0 {
+			m.Close()
+			return nil, err
+		}
+	} else {
+		m.closers = append(m.closers, f)
+		readers = append(readers, f)
+	}
+	m.Reader = io.MultiReader(readers...)
+	return m, nil
+}
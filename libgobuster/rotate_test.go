@@ -0,0 +1,72 @@
+package libgobuster
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRotatingFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "all_time_matches.txt")
+	r, err := OpenRotatingFile(path, 10)
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	for _, line := range []string{"one\n", "two\n", "three\n", "four\n"} {
+		if _, err := r.WriteString(line); err != nil {
+			t.Fatalf("got error: %v", err)
+		}
+	}
+	if err := r.Close(); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+
+	// one+two fit, three and four each start a new file
+	for _, name := range []string{"all_time_matches.1.txt.gz", "all_time_matches.2.txt.gz"} {
+		if _, err := os.Stat(filepath.Join(filepath.Dir(path), name)); err != nil {
+			t.Fatalf("expected archive %s: %v", name, err)
+		}
+	}
+	current, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if string(current) != "four\n" {
+		t.Fatalf("unexpected current file %q", current)
+	}
+
+	all, err := OpenRotated(path)
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	defer all.Close()
+	content, err := ioutil.ReadAll(all)
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if string(content) != "one\ntwo\nthree\nfour\n" {
+		t.Fatalf("unexpected content %q", content)
+	}
+}
+
+func TestRotatingFileUnlimited(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "matches.txt")
+	r, err := OpenRotatingFile(path, 0)
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	for i := 0; i < 100; i++ {
+		if _, err := r.WriteString("line\n"); err != nil {
+			t.Fatalf("got error: %v", err)
+		}
+	}
+	r.Close()
+	if archives, _ := rotatedArchives(path); len(archives) != 0 {
+		t.Fatalf("expected no archives, got %v", archives)
+	}
+}
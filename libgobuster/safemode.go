@@ -0,0 +1,98 @@
+package libgobuster
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SafeModeRate caps the requests per second of -safe-mode
+const SafeModeRate = 10
+
+// safeModeMethods are the only methods -safe-mode sends
+var safeModeMethods = map[string]bool{http.MethodGet: true, http.MethodHead: true}
+
+// dangerousPathRegex matches the paths -safe-mode never requests, the
+// ones a GET may still delete, reset or stop something on badly written
+// applications
+var dangerousPathRegex = regexp.MustCompile(`(?i)delete|remove|destroy|drop|truncate|purge|wipe|shutdown|reboot|restart|reset|kill|uninstall|logout|logoff|signout|unsubscribe|disable|deactivate`)
+
+// IsDangerousPath reports whether -safe-mode skips the path
+func IsDangerousPath(path string) bool {
+	return dangerousPathRegex.MatchString(path)
+}
+
+// checkSafeMethod refuses the methods other than GET and HEAD in
+// -safe-mode
+func checkSafeMethod(safeMode bool, method string) error {
+	if safeMode && !safeModeMethods[method] {
+		return fmt.Errorf("safe mode refuses to send %s requests", method)
+	}
+	return nil
+}
+
+// rateLimiter spaces the requests evenly to stay under a rate
+type rateLimiter struct {
+	interval time.Duration
+	clock    Clock
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+func newRateLimiter(perSecond int, clock Clock) *rateLimiter {
+	return &rateLimiter{interval: time.Second / time.Duration(perSecond), clock: clock}
+}
+
+// wait blocks until the next request is allowed or the context is done
+func (l *rateLimiter) wait(c context.Context) {
+	l.mu.Lock()
+	now := l.clock.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	pause := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+	if pause <= 0 {
+		return
+	}
+	select {
+	case <-time.After(pause):
+	case <-c.Done():
+	}
+}
+
+// SafeModeAllowed reports whether -safe-mode requests the entity, the
+// dangerous paths skipped are counted. Only the path of a url entity is
+// checked, the host name never makes a target dangerous.
+func (g *Gobuster) SafeModeAllowed(entity string) bool {
+	if !g.Opts.SafeMode || !IsDangerousPath(g.safeModePath(entity)) {
+		return true
+	}
+	g.mu.Lock()
+	g.safeModeSkipped++
+	g.mu.Unlock()
+	return false
+}
+
+// safeModePath returns the part of the entity -safe-mode checks, urls
+// lose their base url or at least their scheme and host
+func (g *Gobuster) safeModePath(entity string) string {
+	if !strings.Contains(entity, "://") {
+		return entity
+	}
+	if base := g.BaseURL(); base != "" && strings.HasPrefix(entity, base) {
+		return strings.TrimPrefix(entity, base)
+	}
+	u, err := url.Parse(entity)
+	if err != nil {
+		return entity
+	}
+	return u.RequestURI()
+}
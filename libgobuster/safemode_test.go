@@ -0,0 +1,112 @@
+package libgobuster
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestIsDangerousPath(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		path     string
+		expected bool
+	}{
+		{"admin", false},
+		{"index.php", false},
+		{"user/delete", true},
+		{"deleteAccount.php", true},
+		{"api/v1/Shutdown", true},
+		{"password_reset", true},
+		{"logout", true},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.path, func(t *testing.T) {
+			t.Parallel()
+
+			if got := IsDangerousPath(x.path); got != x.expected {
+				t.Fatalf("expected %t, got %t", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestSafeModeMethods(t *testing.T) {
+	t.Parallel()
+
+	requests := 0
+	h := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer h.Close()
+
+	o := NewOptions()
+	o.SafeMode = true
+	c, err := newHTTPClient(context.Background(), o)
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if _, err := c.makeRequest(http.MethodGet, h.URL, RequestOptions{}); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	for _, method := range []string{http.MethodOptions, http.MethodPut, http.MethodDelete} {
+		if _, err := c.makeRequest(method, h.URL, RequestOptions{}); err == nil {
+			t.Fatalf("expected %s to be refused", method)
+		}
+	}
+	if requests != 1 {
+		t.Fatalf("expected 1 request, got %d", requests)
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	t.Parallel()
+
+	l := newRateLimiter(100, SystemClock)
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		l.wait(context.Background())
+	}
+	// the first request goes right away, the next ones 10ms apart
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Fatalf("expected at least 50ms, got %s", elapsed)
+	}
+}
+
+func TestSafeModeAllowed(t *testing.T) {
+	t.Parallel()
+
+	o := NewOptions()
+	o.SafeMode = true
+	o.URL = "https://reset.example.com/"
+	g := &Gobuster{Opts: o, mu: new(sync.RWMutex)}
+	for _, entity := range []string{"admin", "reset", "drop_table", "login"} {
+		g.SafeModeAllowed(entity)
+	}
+	if g.safeModeSkipped != 2 {
+		t.Fatalf("expected 2 skipped paths, got %d", g.safeModeSkipped)
+	}
+
+	// the urls of wayback, commoncrawl and discovered targets are only
+	// checked by their path
+	tt := []struct {
+		entity   string
+		expected bool
+	}{
+		{"https://reset.example.com/admin", true},
+		{"https://reset.example.com/user/delete", false},
+		{"https://files.dropbox.com/login", true},
+		{"https://files.dropbox.com/?action=logout", false},
+	}
+	for _, x := range tt {
+		if got := g.SafeModeAllowed(x.entity); got != x.expected {
+			t.Fatalf("%s: expected %v, got %v", x.entity, x.expected, got)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package libgobuster
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+)
+
+// SeenIndex holds the endpoint and status combinations of the all time
+// matches with the date they were first recorded, -all-time-dedup
+// appends the new combinations only
+type SeenIndex struct {
+	first map[string]string
+}
+
+// NewSeenIndex returns an empty index
+func NewSeenIndex() *SeenIndex {
+	return &SeenIndex{first: make(map[string]string)}
+}
+
+// seenKey returns the endpoint and status of an all time matches line,
+// the subdomain alone in dns mode, and the day it was recorded
+func seenKey(line string) (string, string, bool) {
+	entity, entry := parseHistoryLine(line)
+	if entry == nil {
+		return "", "", false
+	}
+	key := endpointKey(entity)
+	fields := strings.Split(line, " - ")
+	if len(fields) > 2 {
+		// dir mode lines continue with "<status>[  ->  <redirect>]"
+		if status := strings.TrimSpace(strings.SplitN(fields[2], "  ->  ", 2)[0]); isStatus(status) {
+			key += " " + status
+		}
+	}
+	return key, strings.SplitN(entry.Date, " ", 2)[0], true
+}
+
+// isStatus reports whether the field is a status code
+func isStatus(field string) bool {
+	if len(field) != 3 {
+		return false
+	}
+	for _, c := range field {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// ReadSeenIndex indexes the lines of an all time matches file
+func ReadSeenIndex(r io.Reader) (*SeenIndex, error) {
+	s := NewSeenIndex()
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		s.Seen(strings.TrimSpace(scanner.Text()))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// LoadSeenIndex indexes an all time matches file and its rotated
+// archives, a missing file gives an empty index
+func LoadSeenIndex(path string) (*SeenIndex, error) {
+	f, err := OpenRotated(path)
+	if os.IsNotExist(err) {
+		return NewSeenIndex(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadSeenIndex(f)
+}
+
+// Seen records the endpoint and status of an all time matches line. It
+// returns the day they were first recorded and true when they were
+// already in the index.
+func (s *SeenIndex) Seen(line string) (string, bool) {
+	key, date, ok := seenKey(line)
+	if !ok {
+		return "", false
+	}
+	if first, ok := s.first[key]; ok {
+		return first, true
+	}
+	s.first[key] = date
+	return "", false
+}
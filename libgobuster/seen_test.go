@@ -0,0 +1,46 @@
+package libgobuster
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSeenIndex(t *testing.T) {
+	t.Parallel()
+
+	existing := strings.Join([]string{
+		"# profile aaa mode=dir",
+		"[2020-01-02 10:00:00] - /admin - 200 - run 1 - profile aaa",
+		"[2020-01-03 10:00:00] - /login - 302  ->  /sso - run 1 - profile aaa",
+		"[2020-01-04 10:00:00] - www.example.com - 10.0.0.1 -  - run 2 - profile bbb",
+	}, "\n")
+	s, err := ReadSeenIndex(strings.NewReader(existing))
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+
+	tt := []struct {
+		testName string
+		line     string
+		date     string
+		seen     bool
+	}{
+		{"Same endpoint and status", "[2021-05-01 08:00:00] - /admin - 200", "2020-01-02", true},
+		{"Other status", "[2021-05-01 08:00:00] - /admin - 403", "", false},
+		{"Other redirect", "[2021-05-01 08:00:00] - /login - 302  ->  /other", "2020-01-03", true},
+		{"New endpoint", "[2021-05-01 08:00:00] - /backup - 200", "", false},
+		{"Subdomain", "[2021-05-01 08:00:00] - www.example.com - 10.0.0.2 - ", "2020-01-04", true},
+		{"Not a match line", "# profile ccc mode=dir", "", false},
+	}
+
+	// the subtests share the index, the new lines are recorded too
+	for _, x := range tt {
+		date, seen := s.Seen(x.line)
+		if date != x.date || seen != x.seen {
+			t.Fatalf("%s: expected %q and %t, got %q and %t", x.testName, x.date, x.seen, date, seen)
+		}
+	}
+	if date, seen := s.Seen("[2021-05-02 08:00:00] - /backup - 200"); !seen || date != "2021-05-01" {
+		t.Fatalf("expected the new endpoint recorded, got %q and %t", date, seen)
+	}
+}
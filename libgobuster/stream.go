@@ -0,0 +1,171 @@
+package libgobuster
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// streamWriteTimeout is how long a consumer may stall before it is
+// disconnected, a slow reader must never hold up the scan
+const streamWriteTimeout = time.Second
+
+// resultStream writes the matches as NDJSON to the consumers connected
+// to a unix socket, or to a named pipe
+type resultStream struct {
+	mu       sync.Mutex
+	listener net.Listener
+	pipe     io.WriteCloser
+	conns    map[net.Conn]bool
+}
+
+var (
+	streamsMu sync.Mutex
+	// streams outlive a single scan so consumers stay connected while
+	// many targets are scanned one after the other
+	streams = make(map[string]*resultStream)
+)
+
+// openResultStream returns the stream of the path, creating it on first
+// use. An existing named pipe is written to, otherwise a unix socket is
+// listened on.
+func openResultStream(path string) (*resultStream, error) {
+	streamsMu.Lock()
+	defer streamsMu.Unlock()
+	if s, ok := streams[path]; ok {
+		return s, nil
+	}
+
+	s := &resultStream{conns: make(map[net.Conn]bool)}
+	if fi, err := os.Stat(path); err == nil && fi.Mode()&os.ModeNamedPipe != 0 {
+		// read-write so opening does not block until a reader shows up
+		pipe, err := os.OpenFile(path, os.O_RDWR, 0)
+		if err != nil {
+			return nil, fmt.Errorf("unable to open results pipe %s: %v", path, err)
+		}
+		s.pipe = pipe
+	} else {
+		if err == nil && fi.Mode()&os.ModeSocket != 0 {
+			// left behind by a previous run
+			os.Remove(path)
+		}
+		l, err := net.Listen("unix", path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to listen on results socket %s: %v", path, err)
+		}
+		s.listener = l
+		go s.accept()
+	}
+	streams[path] = s
+	return s, nil
+}
+
+func (s *resultStream) accept() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns[conn] = true
+		s.mu.Unlock()
+	}
+}
+
+// write sends a single line to every consumer, dropping the ones that
+// fail or stall. It returns the error of the results pipe.
+func (s *resultStream) write(line []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.pipe != nil {
+		_, err := s.pipe.Write(line)
+		return err
+	}
+	for conn := range s.conns {
+		conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+		if _, err := conn.Write(line); err != nil {
+			conn.Close()
+			delete(s.conns, conn)
+		}
+	}
+	return nil
+}
+
+func (s *resultStream) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.pipe != nil {
+		s.pipe.Close()
+		return
+	}
+	// closing the listener removes the socket file
+	s.listener.Close()
+	for conn := range s.conns {
+		conn.Close()
+	}
+}
+
+// CloseResultStreams disconnects the consumers of every results socket
+func CloseResultStreams() {
+	streamsMu.Lock()
+	defer streamsMu.Unlock()
+	for path, s := range streams {
+		s.close()
+		delete(streams, path)
+	}
+}
+
+// streamedResult is a match as written to the results socket
+type streamedResult struct {
+	Time      time.Time `json:"time"`
+	RunID     string    `json:"run_id"`
+	Mode      string    `json:"mode"`
+	Target    string    `json:"target"`
+	URL       string    `json:"url"`
+	Status    int       `json:"status,omitempty"`
+	Size      *int64    `json:"size,omitempty"`
+	Redirect  string    `json:"redirect,omitempty"`
+	IPs       []string  `json:"ips,omitempty"`
+	CNAME     string    `json:"cname,omitempty"`
+	RequestID string    `json:"request_id,omitempty"`
+	// ContentType is the media type of dir mode responses
+	ContentType string `json:"content_type,omitempty"`
+	// Metadata are the annotations of the result
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// StreamResult writes a match to the results socket, if configured
+func (g *Gobuster) StreamResult(r *Result) {
+	if g.stream == nil {
+		return
+	}
+	sr := streamedResult{
+		Time:        g.Now(),
+		RunID:       g.RunID,
+		Mode:        g.Opts.Mode,
+		Target:      g.BaseURL(),
+		URL:         r.FullURL(g),
+		Status:      r.Status,
+		Size:        r.Size,
+		IPs:         r.IPs,
+		CNAME:       r.CNAME,
+		RequestID:   r.RequestID,
+		ContentType: r.ContentType,
+		Metadata:    r.Metadata,
+	}
+	if r.RedirectURL != nil {
+		sr.Redirect = *r.RedirectURL
+	}
+	line, err := json.Marshal(sr)
+	if err != nil {
+		g.Logf("[!] Unable to encode result: %v", err)
+		return
+	}
+	if err := g.stream.write(append(line, '\n')); err != nil {
+		g.Logf("[!] Unable to write to the results pipe: %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+package libgobuster
+
+import (
+	"bufio"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestResultStream(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "results.sock")
+	s, err := openResultStream(path)
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if again, _ := openResultStream(path); again != s {
+		t.Fatal("expected the stream of the path to be shared")
+	}
+	defer func() {
+		streamsMu.Lock()
+		delete(streams, path)
+		streamsMu.Unlock()
+		s.close()
+	}()
+
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	defer conn.Close()
+	// wait for the consumer to be registered
+	for i := 0; i < 100; i++ {
+		s.mu.Lock()
+		n := len(s.conns)
+		s.mu.Unlock()
+		if n == 1 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	s.write([]byte("{\"status\":200}\n"))
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if line != "{\"status\":200}\n" {
+		t.Fatalf("unexpected line %q", line)
+	}
+}
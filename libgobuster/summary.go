@@ -0,0 +1,281 @@
+package libgobuster
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// blankExtension is the extension key of words requested without one
+const blankExtension = "(blank)"
+
+// Summary holds the statistics of a run
+type Summary struct {
+	RunID       string         `json:"run_id"`
+	Target      string         `json:"target"`
+	Mode        string         `json:"mode"`
+	Profile     string         `json:"profile,omitempty"`
+	Requests    int            `json:"requests"`
+	Matches     int            `json:"matches"`
+	Errors      int            `json:"errors"`
+	Aborted     bool           `json:"aborted"`
+	TimedOut    bool           `json:"timed_out"`
+	Extensions  map[string]int `json:"extensions,omitempty"`
+	TitleGroups []ResultGroup  `json:"title_groups,omitempty"`
+	BodyGroups  []ResultGroup  `json:"body_groups,omitempty"`
+	// Skipped is the reason the liveness check skipped the target
+	Skipped string `json:"skipped,omitempty"`
+	// RobotsSkipped are the paths -respect-robots did not request
+	RobotsSkipped int `json:"robots_skipped,omitempty"`
+	// SafeModeSkipped are the dangerous paths -safe-mode did not request
+	SafeModeSkipped int `json:"safe_mode_skipped,omitempty"`
+	// ErrorCategories counts the errors by ClassifyError category, the
+	// 5xx responses included
+	ErrorCategories map[string]int `json:"error_categories,omitempty"`
+	// Agents holds the sticky random agents by host ("" when per-run)
+	Agents map[string]string `json:"agents,omitempty"`
+	// FaviconHash is the Shodan http.favicon.hash of the target
+	FaviconHash *int32 `json:"favicon_hash,omitempty"`
+	// Technologies are the technologies fingerprinted on the target
+	Technologies []Technology `json:"technologies,omitempty"`
+	// OOBCallbacks are the -oob-server interactions of the probes
+	OOBCallbacks []OOBCallback `json:"oob_callbacks,omitempty"`
+	// WAF is the CDN or WAF detected in front of the target
+	WAF string `json:"waf,omitempty"`
+	// WAFBlocks are the words answered with a block page of the WAF
+	WAFBlocks int `json:"waf_blocks,omitempty"`
+	// Uploads are the directories accepting the PUT of -upload-check
+	Uploads []Upload `json:"uploads,omitempty"`
+	// DroppedHits are the matches -verify-hits never reproduced
+	DroppedHits int `json:"dropped_hits,omitempty"`
+	// Tags are the tags of the target in the target urls file
+	Tags []string `json:"tags,omitempty"`
+	// PrunedExtensions are the extensions -prune-exts stopped requesting
+	PrunedExtensions []string `json:"pruned_extensions,omitempty"`
+}
+
+// Summary returns the statistics of the run so far
+func (g *Gobuster) Summary() Summary {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	s := Summary{
+		RunID:   g.RunID,
+		Target:  g.BaseURL(),
+		Mode:    g.Opts.Mode,
+		Profile: g.Opts.ScanProfile,
+		// requestsIssued is decremented for every request ending in an
+		// error, the 5xx answers are counted in both
+		Requests:     g.requestsIssued + g.errorCount - g.serverErrors,
+		Matches:      g.matchCount,
+		Errors:       g.errorCount,
+		Aborted:      g.aborted,
+		TimedOut:     g.timedOut,
+		TitleGroups:  topGroups(g.groups.titles),
+		BodyGroups:   topGroups(g.groups.hashes),
+		FaviconHash:  g.FaviconHash,
+		Technologies: g.Technologies,
+	}
+	s.RobotsSkipped = g.robotsSkipped
+	s.SafeModeSkipped = g.safeModeSkipped
+	s.WAF, s.WAFBlocks = g.WAF, g.wafBlocks
+	s.OOBCallbacks = append([]OOBCallback(nil), g.oobCallbacks...)
+	s.Uploads = append([]Upload(nil), g.uploads...)
+	s.Tags = g.Opts.Tags
+	if g.verifier != nil {
+		g.verifier.mu.Lock()
+		s.DroppedHits = g.verifier.dropped
+		g.verifier.mu.Unlock()
+	}
+	if g.pruner != nil {
+		s.PrunedExtensions = g.pruner.Values()
+	}
+	if g.agents != nil {
+		s.Agents = g.agents.sticky()
+	}
+	if len(g.errorCategories) > 0 {
+		s.ErrorCategories = make(map[string]int, len(g.errorCategories))
+		for category, count := range g.errorCategories {
+			s.ErrorCategories[category] = count
+		}
+	}
+	if len(g.extensionHits) > 0 {
+		s.Extensions = make(map[string]int, len(g.extensionHits))
+		for ext, hits := range g.extensionHits {
+			s.Extensions[ext] = hits
+		}
+	}
+	return s
+}
+
+// GetSummaryString returns the statistics of the run as a printable string
+func (g *Gobuster) GetSummaryString() (string, error) {
+	buf := &bytes.Buffer{}
+	s := g.Summary()
+
+	if _, err := fmt.Fprintf(buf, "[+] Run                   : %s\n", s.RunID); err != nil {
+		return "", err
+	}
+	if s.Profile != "" {
+		if _, err := fmt.Fprintf(buf, "[+] Profile               : %s\n", s.Profile); err != nil {
+			return "", err
+		}
+	}
+	if _, err := fmt.Fprintf(buf, "[+] Requests              : %d\n", s.Requests); err != nil {
+		return "", err
+	}
+	if _, err := fmt.Fprintf(buf, "[+] Matches               : %d\n", s.Matches); err != nil {
+		return "", err
+	}
+	if _, err := fmt.Fprintf(buf, "[+] Errors                : %d\n", s.Errors); err != nil {
+		return "", err
+	}
+	if s.RobotsSkipped > 0 {
+		if _, err := fmt.Fprintf(buf, "[+] Skipped by robots.txt : %d\n", s.RobotsSkipped); err != nil {
+			return "", err
+		}
+	}
+	if s.SafeModeSkipped > 0 {
+		if _, err := fmt.Fprintf(buf, "[+] Skipped by safe mode  : %d\n", s.SafeModeSkipped); err != nil {
+			return "", err
+		}
+	}
+	if len(s.ErrorCategories) > 0 {
+		if _, err := fmt.Fprintf(buf, "[+] Error categories      : %s\n", formatErrorCategories(s.ErrorCategories)); err != nil {
+			return "", err
+		}
+	}
+	if len(s.PrunedExtensions) > 0 {
+		if _, err := fmt.Fprintf(buf, "[+] Pruned extensions     : %s\n", strings.Join(s.PrunedExtensions, ", ")); err != nil {
+			return "", err
+		}
+	}
+	if s.DroppedHits > 0 {
+		if _, err := fmt.Fprintf(buf, "[+] Dropped hits          : %d not reproduced\n", s.DroppedHits); err != nil {
+			return "", err
+		}
+	}
+	if s.Aborted {
+		if _, err := fmt.Fprintf(buf, "[+] Aborted               : error threshold exceeded\n"); err != nil {
+			return "", err
+		}
+	}
+
+	if s.FaviconHash != nil {
+		if _, err := fmt.Fprintf(buf, "[+] Favicon hash          : %d\n", *s.FaviconHash); err != nil {
+			return "", err
+		}
+	}
+	if len(s.Technologies) > 0 {
+		if _, err := fmt.Fprintf(buf, "[+] Technologies          : %s\n", technologyList(s.Technologies)); err != nil {
+			return "", err
+		}
+	}
+	for _, cb := range s.OOBCallbacks {
+		if _, err := fmt.Fprintf(buf, "[+] OOB callback          : %s (%s from %s)\n", cb.URL, cb.Protocol, cb.RemoteAddress); err != nil {
+			return "", err
+		}
+	}
+	for _, u := range s.Uploads {
+		if _, err := fmt.Fprintf(buf, "[+] Upload                : %s (%s)\n", u.URL, u.Detail()); err != nil {
+			return "", err
+		}
+	}
+	if s.WAF != "" {
+		if _, err := fmt.Fprintf(buf, "[+] CDN/WAF               : %s\n", s.WAF); err != nil {
+			return "", err
+		}
+	}
+	if s.WAFBlocks > 0 {
+		if _, err := fmt.Fprintf(buf, "[+] WAF block pages       : %d\n", s.WAFBlocks); err != nil {
+			return "", err
+		}
+	}
+	if s.TimedOut {
+		if _, err := fmt.Fprintf(buf, "[+] Timed out             : after %s\n", g.Opts.PerTargetTimeout.String()); err != nil {
+			return "", err
+		}
+	}
+
+	if len(s.Extensions) > 0 {
+		var exts []string
+		for ext := range s.Extensions {
+			exts = append(exts, ext)
+		}
+		sort.Strings(exts)
+		var hits []string
+		for _, ext := range exts {
+			hits = append(hits, fmt.Sprintf("%s=%d", ext, s.Extensions[ext]))
+		}
+		if _, err := fmt.Fprintf(buf, "[+] Extension hits        : %s\n", strings.Join(hits, ", ")); err != nil {
+			return "", err
+		}
+	}
+
+	for _, group := range s.TitleGroups {
+		if _, err := fmt.Fprintf(buf, "[+] Title group           : %d endpoints share title '%s'\n", group.Count, group.Key); err != nil {
+			return "", err
+		}
+	}
+	for _, group := range s.BodyGroups {
+		if _, err := fmt.Fprintf(buf, "[+] Body group            : %d endpoints share body hash %s\n", group.Count, group.Key); err != nil {
+			return "", err
+		}
+	}
+
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// WriteSummaryJSON writes the summary of the run to the output folder
+func (g *Gobuster) WriteSummaryJSON() error {
+	return writeSummaryJSON(g.Opts.OutputFolder, g.OutputFileSuffix(), g.Summary())
+}
+
+// writeSummaryJSON writes a summary to the summaries of the output folder
+func writeSummaryJSON(outputFolder, suffix string, s Summary) error {
+	folder := outputFolder + "/output_summaries"
+	if err := os.MkdirAll(folder, 0755); err != nil {
+		return fmt.Errorf("failed to create summaries folder: %v", err)
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode summary: %v", err)
+	}
+
+	filename := fmt.Sprintf("%s/summary_%s.json", folder, suffix)
+	if err := ioutil.WriteFile(filename, data, 0644); err != nil {
+		return fmt.Errorf("failed to write summary: %v", err)
+	}
+	return nil
+}
+
+// ReadSummaries reads the summaries written to the output folder,
+// ordered by the time the runs started
+func ReadSummaries(outputFolder string) ([]Summary, error) {
+	files, err := filepath.Glob(outputFolder + "/output_summaries/summary_*.json")
+	if err != nil {
+		return nil, err
+	}
+	// the file names start with the unix time of the run
+	sort.Strings(files)
+
+	var summaries []Summary
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read summary: %v", err)
+		}
+		var s Summary
+		if err := json.Unmarshal(data, &s); err != nil {
+			return nil, fmt.Errorf("failed to decode summary %s: %v", file, err)
+		}
+		summaries = append(summaries, s)
+	}
+	return summaries, nil
+}
@@ -0,0 +1,186 @@
+package libgobuster
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	syslogFacilityUser = 1
+	syslogNotice       = 5
+	syslogInfo         = 6
+	// syslogSDID is the structured data id of the fields, 32473 is the
+	// private enterprise number reserved for documentation
+	syslogSDID       = "ybuster@32473"
+	journaldSocket   = "/run/systemd/journal/socket"
+	syslogAppName    = "ybuster"
+	journaldFieldPre = "YBUSTER_"
+)
+
+// logSink forwards matches and summaries to a log collector
+type logSink struct {
+	mu       sync.Mutex
+	conn     net.Conn
+	journald bool
+	stream   bool
+	hostname string
+	clock    Clock
+}
+
+// newLogSink connects to the sink given as udp://host:port,
+// tcp://host:port, unix:///dev/log or journald
+func newLogSink(target string, clock Clock) (*logSink, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "-"
+	}
+	sink := &logSink{hostname: hostname, clock: clock}
+
+	var network, address string
+	if target == "journald" {
+		network, address = "unixgram", journaldSocket
+		sink.journald = true
+	} else {
+		u, err := url.Parse(target)
+		if err != nil {
+			return nil, fmt.Errorf("invalid syslog target %q: %v", target, err)
+		}
+		switch u.Scheme {
+		case "udp", "tcp":
+			network, address = u.Scheme, u.Host
+		case "unix":
+			network, address = "unixgram", u.Path
+		default:
+			return nil, fmt.Errorf("invalid syslog target %q: scheme must be udp, tcp or unix", target)
+		}
+		sink.stream = u.Scheme == "tcp"
+	}
+
+	conn, err := net.DialTimeout(network, address, 5*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to syslog target %s: %v", target, err)
+	}
+	sink.conn = conn
+	return sink, nil
+}
+
+// send writes a single message with its structured fields
+func (s *logSink) send(severity int, msg string, fields map[string]string) error {
+	var data []byte
+	if s.journald {
+		data = formatJournald(severity, msg, fields)
+	} else {
+		data = formatRFC5424(s.clock.Now(), s.hostname, os.Getpid(), severity, msg, fields)
+		if s.stream {
+			// octet counting framing (RFC6587)
+			data = append([]byte(fmt.Sprintf("%d ", len(data))), data...)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := s.conn.Write(data)
+	return err
+}
+
+func (s *logSink) Close() error {
+	return s.conn.Close()
+}
+
+// sortedKeys returns the field names in a stable order
+func sortedKeys(fields map[string]string) []string {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// formatRFC5424 renders a syslog message with the fields as structured data
+func formatRFC5424(t time.Time, hostname string, pid int, severity int, msg string, fields map[string]string) []byte {
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "<%d>1 %s %s %s %d - ", syslogFacilityUser*8+severity, t.UTC().Format(time.RFC3339), hostname, syslogAppName, pid)
+	if len(fields) == 0 {
+		buf.WriteString("-")
+	} else {
+		fmt.Fprintf(buf, "[%s", syslogSDID)
+		escaper := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+		for _, k := range sortedKeys(fields) {
+			fmt.Fprintf(buf, " %s=\"%s\"", k, escaper.Replace(fields[k]))
+		}
+		buf.WriteString("]")
+	}
+	fmt.Fprintf(buf, " %s", msg)
+	return buf.Bytes()
+}
+
+// formatJournald renders a message in the journald native protocol,
+// newlines in values are replaced as the simple KEY=VALUE form is used
+func formatJournald(severity int, msg string, fields map[string]string) []byte {
+	buf := &bytes.Buffer{}
+	clean := strings.NewReplacer("\n", " ", "\r", " ")
+	fmt.Fprintf(buf, "MESSAGE=%s\n", clean.Replace(msg))
+	fmt.Fprintf(buf, "PRIORITY=%d\n", severity)
+	fmt.Fprintf(buf, "SYSLOG_IDENTIFIER=%s\n", syslogAppName)
+	for _, k := range sortedKeys(fields) {
+		fmt.Fprintf(buf, "%s%s=%s\n", journaldFieldPre, strings.ToUpper(k), clean.Replace(fields[k]))
+	}
+	return buf.Bytes()
+}
+
+// SyslogResult forwards a match to the syslog sink, if configured
+func (g *Gobuster) SyslogResult(r *Result, line string) {
+	if g.sink == nil {
+		return
+	}
+	fields := map[string]string{
+		"run_id": g.RunID,
+		"mode":   g.Opts.Mode,
+		"target": g.BaseURL(),
+		"url":    r.FullURL(g),
+		"status": fmt.Sprintf("%d", r.Status),
+	}
+	if r.Size != nil {
+		fields["size"] = fmt.Sprintf("%d", *r.Size)
+	}
+	if r.RedirectURL != nil && *r.RedirectURL != "" {
+		fields["redirect"] = *r.RedirectURL
+	}
+	if len(r.IPs) > 0 {
+		fields["ips"] = strings.Join(r.IPs, ",")
+	}
+	if err := g.sink.send(syslogNotice, line, fields); err != nil {
+		g.Logf("[!] Unable to write to syslog: %v", err)
+	}
+}
+
+// SyslogSummary forwards the run statistics to the syslog sink and
+// closes it
+func (g *Gobuster) SyslogSummary() {
+	if g.sink == nil {
+		return
+	}
+	defer g.sink.Close()
+	s := g.Summary()
+	fields := map[string]string{
+		"run_id":   g.RunID,
+		"mode":     s.Mode,
+		"target":   s.Target,
+		"requests": fmt.Sprintf("%d", s.Requests),
+		"matches":  fmt.Sprintf("%d", s.Matches),
+		"errors":   fmt.Sprintf("%d", s.Errors),
+		"aborted":  fmt.Sprintf("%t", s.Aborted),
+	}
+	msg := fmt.Sprintf("scan of %s finished: %d requests, %d matches, %d errors", s.Target, s.Requests, s.Matches, s.Errors)
+	if err := g.sink.send(syslogInfo, msg, fields); err != nil {
+		g.Logf("[!] Unable to write to syslog: %v", err)
+	}
+}
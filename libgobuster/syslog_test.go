@@ -0,0 +1,77 @@
+package libgobuster
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatRFC5424(t *testing.T) {
+	t.Parallel()
+
+	ts := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	var tt = []struct {
+		testName string
+		severity int
+		msg      string
+		fields   map[string]string
+		expected string
+	}{
+		{"No fields", syslogInfo, "done", nil, "<14>1 2026-01-02T03:04:05Z host ybuster 42 - - done"},
+		{"Fields", syslogNotice, "found", map[string]string{"url": "http://a/b", "status": "200"},
+			`<13>1 2026-01-02T03:04:05Z host ybuster 42 - [ybuster@32473 status="200" url="http://a/b"] found`},
+		{"Escaping", syslogNotice, "found", map[string]string{"title": `a "b" [c]`},
+			`<13>1 2026-01-02T03:04:05Z host ybuster 42 - [ybuster@32473 title="a \"b\" [c\]"] found`},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+			got := string(formatRFC5424(ts, "host", 42, x.severity, x.msg, x.fields))
+			if got != x.expected {
+				t.Fatalf("expected %q, got %q", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestFormatJournald(t *testing.T) {
+	t.Parallel()
+
+	got := string(formatJournald(syslogNotice, "found\nit", map[string]string{"url": "http://a/b"}))
+	expected := "MESSAGE=found it\nPRIORITY=5\nSYSLOG_IDENTIFIER=ybuster\nYBUSTER_URL=http://a/b\n"
+	if got != expected {
+		t.Fatalf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestLogSinkUDP(t *testing.T) {
+	t.Parallel()
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	defer pc.Close()
+
+	sink, err := newLogSink("udp://"+pc.LocalAddr().String(), SystemClock)
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	defer sink.Close()
+	if err := sink.send(syslogNotice, "found", map[string]string{"status": "200"}); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+
+	buf := make([]byte, 1024)
+	pc.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := pc.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if !strings.HasPrefix(string(buf[:n]), "<13>1 ") || !strings.HasSuffix(string(buf[:n]), `[ybuster@32473 status="200"] found`) {
+		t.Fatalf("unexpected message %q", buf[:n])
+	}
+}
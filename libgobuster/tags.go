@@ -0,0 +1,109 @@
+package libgobuster
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// tagsFolder holds the matches of the tagged targets, one file per tag
+// shared by the runs of the output folder
+const tagsFolder = "output_tags"
+
+// tagNameRegex matches the valid tags, they name the files of their matches
+var tagNameRegex = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// ParseTargetLine splits a line of the target urls file into the url and
+// the comma separated tags following it, like
+// https://a.example.com admin,prod
+func ParseTargetLine(line string) (string, []string, error) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return "", nil, nil
+	}
+	seen := NewSet[string]()
+	var tags []string
+	for _, field := range fields[1:] {
+		for _, tag := range strings.Split(field, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "" {
+				continue
+			}
+			if !tagNameRegex.MatchString(tag) {
+				return "", nil, fmt.Errorf("invalid tag given: %s", tag)
+			}
+			if seen.Add(tag) {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	return fields[0], tags, nil
+}
+
+// RecordTagMatch appends a match, with its url, status and length, to the
+// matches of every tag of the target
+func (g *Gobuster) RecordTagMatch(r *Result) error {
+	if len(g.Opts.Tags) == 0 {
+		return nil
+	}
+	line := r.annotatedLine(g)
+	for _, tag := range g.Opts.Tags {
+		path := filepath.Join(g.Opts.OutputFolder, tagsFolder, tag+".txt")
+		if err := appendLines(path, []string{line}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TagSummary adds up the summaries of the targets of a tag
+type TagSummary struct {
+	Tag     string
+	Targets int
+	Matches int
+	Errors  int
+	Skipped int
+}
+
+// TagTotals are the summaries of the tags of a run over many targets
+type TagTotals map[string]*TagSummary
+
+// Add counts the summary of a target in every one of its tags
+func (t TagTotals) Add(tags []string, s Summary) {
+	for _, tag := range tags {
+		total, ok := t[tag]
+		if !ok {
+			total = &TagSummary{Tag: tag}
+			t[tag] = total
+		}
+		total.Targets++
+		total.Matches += s.Matches
+		total.Errors += s.Errors
+		if s.Skipped != "" {
+			total.Skipped++
+		}
+	}
+}
+
+// Sorted returns the summaries of the tags ordered by tag
+func (t TagTotals) Sorted() []TagSummary {
+	summaries := make([]TagSummary, 0, len(t))
+	for _, total := range t {
+		summaries = append(summaries, *total)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Tag < summaries[j].Tag
+	})
+	return summaries
+}
+
+// SummariesByTag adds the summaries of the tagged targets up per tag
+func SummariesByTag(summaries []Summary) []TagSummary {
+	totals := TagTotals{}
+	for _, s := range summaries {
+		totals.Add(s.Tags, s)
+	}
+	return totals.Sorted()
+}
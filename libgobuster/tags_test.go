@@ -0,0 +1,82 @@
+package libgobuster
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseTargetLine(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		line     string
+		target   string
+		tags     []string
+		hasError bool
+	}{
+		{"https://a.example.com", "https://a.example.com", nil, false},
+		{"https://a.example.com admin,prod", "https://a.example.com", []string{"admin", "prod"}, false},
+		{"https://a.example.com\tadmin, prod,admin", "https://a.example.com", []string{"admin", "prod"}, false},
+		{"https://a.example.com admin/prod", "", nil, true},
+	}
+	for _, x := range tt {
+		x := x
+		t.Run(x.line, func(t *testing.T) {
+			t.Parallel()
+
+			target, tags, err := ParseTargetLine(x.line)
+			if (err != nil) != x.hasError {
+				t.Fatalf("expected error %t, got %v", x.hasError, err)
+			}
+			if target != x.target || !reflect.DeepEqual(tags, x.tags) {
+				t.Fatalf("expected %q %v, got %q %v", x.target, x.tags, target, tags)
+			}
+		})
+	}
+}
+
+func TestSummariesByTag(t *testing.T) {
+	t.Parallel()
+
+	summaries := []Summary{
+		{Target: "https://a.example.com/", Matches: 3, Errors: 1, Tags: []string{"prod", "admin"}},
+		{Target: "https://b.example.com/", Matches: 2, Tags: []string{"prod"}},
+		{Target: "https://c.example.com/", Skipped: "timeout", Tags: []string{"prod"}},
+		{Target: "https://d.example.com/", Matches: 7},
+	}
+	expected := []TagSummary{
+		{Tag: "admin", Targets: 1, Matches: 3, Errors: 1},
+		{Tag: "prod", Targets: 3, Matches: 5, Errors: 1, Skipped: 1},
+	}
+	if got := SummariesByTag(summaries); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, got)
+	}
+}
+
+func TestRecordTagMatch(t *testing.T) {
+	t.Parallel()
+
+	o := NewOptions()
+	o.OutputFolder = t.TempDir()
+	o.URL = "https://a.example.com/"
+	o.Tags = []string{"admin", "prod"}
+	g := &Gobuster{Opts: o}
+	size := int64(120)
+	for _, r := range []Result{{Entity: "login", Status: 200, Size: &size}, {Entity: "admin/", Status: 403}} {
+		r := r
+		if err := g.RecordTagMatch(&r); err != nil {
+			t.Fatalf("got error: %v", err)
+		}
+	}
+	for _, tag := range o.Tags {
+		data, err := ioutil.ReadFile(filepath.Join(o.OutputFolder, tagsFolder, tag+".txt"))
+		if err != nil {
+			t.Fatalf("got error: %v", err)
+		}
+		if string(data) != "https://a.example.com/login [200] [120]\nhttps://a.example.com/admin/ [403]\n" {
+			t.Fatalf("unexpected matches of %s: %q", tag, data)
+		}
+	}
+}
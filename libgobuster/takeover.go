@@ -0,0 +1,69 @@
+package libgobuster
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// defaultTakeoverFingerprints is the fingerprint list shipped with the
+// binary, -takeover-fingerprints replaces it with an updated copy
+//
+//go:embed takeover_fingerprints.json
+var defaultTakeoverFingerprints []byte
+
+// TakeoverDangling names the takeovers of CNAMEs pointing nowhere that
+// match no known service
+const TakeoverDangling = "dangling CNAME"
+
+// TakeoverFingerprint describes a service whose unclaimed resources can
+// be registered by anyone
+type TakeoverFingerprint struct {
+	Service string   `json:"service"`
+	CNAME   []string `json:"cname"`
+	// NXDomain restricts the fingerprint to CNAMEs that no longer resolve
+	NXDomain bool `json:"nxdomain"`
+}
+
+// parseTakeoverFingerprints loads the embedded fingerprints, or the ones
+// of the -takeover-fingerprints file
+func (opt *Options) parseTakeoverFingerprints() error {
+	data := defaultTakeoverFingerprints
+	if opt.TakeoverFingerprints != "" {
+		var err error
+		data, err = os.ReadFile(opt.TakeoverFingerprints)
+		if err != nil {
+			return fmt.Errorf("failed to open takeover fingerprints: %v", err)
+		}
+	}
+	var fingerprints []TakeoverFingerprint
+	if err := json.Unmarshal(data, &fingerprints); err != nil {
+		return fmt.Errorf("invalid takeover fingerprints: %v", err)
+	}
+	opt.TakeoverFingerprintsParsed = fingerprints
+	return nil
+}
+
+// MatchTakeover returns the service a CNAME makes the subdomain
+// takeoverable through, or an empty string. dangling tells whether the
+// CNAME target fails to resolve.
+func MatchTakeover(fingerprints []TakeoverFingerprint, cname string, dangling bool) string {
+	cname = strings.ToLower(strings.TrimSuffix(cname, "."))
+	for _, f := range fingerprints {
+		if f.NXDomain && !dangling {
+			continue
+		}
+		for _, suffix := range f.CNAME {
+			suffix = strings.ToLower(strings.TrimPrefix(suffix, "."))
+			if cname == suffix || strings.HasSuffix(cname, "."+suffix) {
+				return f.Service
+			}
+		}
+	}
+	if dangling {
+		return TakeoverDangling
+	}
+	return ""
+}
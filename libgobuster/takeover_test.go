@@ -0,0 +1,69 @@
+package libgobuster
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMatchTakeover(t *testing.T) {
+	t.Parallel()
+
+	o := NewOptions()
+	if err := o.parseTakeoverFingerprints(); err != nil {
+		t.Fatalf("embedded fingerprints: %v", err)
+	}
+
+	tt := []struct {
+		testName string
+		cname    string
+		dangling bool
+		expected string
+	}{
+		{"GitHub Pages", "example.github.io.", false, "GitHub Pages"},
+		{"Case insensitive", "Example.GitHub.IO", false, "GitHub Pages"},
+		{"Suffix only on label boundary", "example.notgithub.io", false, ""},
+		{"Azure resolving", "app.azurewebsites.net", false, ""},
+		{"Azure dangling", "app.azurewebsites.net", true, "Azure"},
+		{"Unknown dangling", "gone.example.org", true, TakeoverDangling},
+		{"Unknown resolving", "cdn.example.org", false, ""},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			if got := MatchTakeover(o.TakeoverFingerprintsParsed, x.cname, x.dangling); got != x.expected {
+				t.Fatalf("expected %q, got %q", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestParseTakeoverFingerprintsFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "fingerprints.json")
+	if err := os.WriteFile(path, []byte(`[{"service": "Example", "cname": ["example.net"]}]`), 0600); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	o := NewOptions()
+	o.TakeoverFingerprints = path
+	if err := o.parseTakeoverFingerprints(); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if got := MatchTakeover(o.TakeoverFingerprintsParsed, "a.example.net", false); got != "Example" {
+		t.Fatalf("expected the file fingerprint to match, got %q", got)
+	}
+	if got := MatchTakeover(o.TakeoverFingerprintsParsed, "a.github.io", false); got != "" {
+		t.Fatalf("expected the embedded fingerprints to be replaced, got %q", got)
+	}
+
+	if err := os.WriteFile(path, []byte(`{`), 0600); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if err := o.parseTakeoverFingerprints(); err == nil {
+		t.Fatal("expected an error for invalid JSON")
+	}
+}
@@ -0,0 +1,100 @@
+package libgobuster
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// targetWordRegex matches the words tokenized from the pages of the
+// target, identifiers of scripts included
+var targetWordRegex = regexp.MustCompile(`[A-Za-z][A-Za-z0-9_-]{2,39}`)
+
+// targetStopWords are the words of the markup and the scripts themselves,
+// not worth a request
+var targetStopWords = map[string]bool{
+	"html": true, "head": true, "body": true, "div": true, "span": true, "script": true, "style": true,
+	"class": true, "href": true, "src": true, "type": true, "text": true, "meta": true, "link": true,
+	"title": true, "content": true, "charset": true, "utf-8": true, "width": true, "height": true,
+	"input": true, "form": true, "button": true, "label": true, "value": true, "name": true, "rel": true,
+	"stylesheet": true, "javascript": true, "function": true, "return": true, "var": true, "let": true,
+	"const": true, "true": true, "false": true, "null": true, "undefined": true, "this": true, "new": true,
+	"else": true, "typeof": true, "window": true, "document": true, "the": true, "and": true, "for": true,
+	"with": true, "you": true, "your": true, "are": true, "not": true, "http": true, "https": true,
+	"www": true, "com": true, "nbsp": true, "amp": true,
+}
+
+// targetWordTypes are the content types the words are read from
+var targetWordTypes = []string{"html", "javascript", "ecmascript", "json", "xml", "text/plain"}
+
+// targetScriptLimit is the number of scripts of the baseline page
+// whose words are collected
+const targetScriptLimit = 10
+
+var scriptSrcRegex = regexp.MustCompile(`(?i)<script[^>]+src=["']([^"']+)["']`)
+
+// ScriptSources returns the absolute urls of the scripts a page loads
+// from the host of the page
+func ScriptSources(content, pageURL string) []string {
+	page, err := url.Parse(pageURL)
+	if err != nil {
+		return nil
+	}
+	var sources []string
+	seen := NewSet[string]()
+	for _, m := range scriptSrcRegex.FindAllStringSubmatch(content, -1) {
+		src, err := page.Parse(m[1])
+		if err != nil || src.Host != page.Host {
+			continue
+		}
+		src.Fragment = ""
+		if seen.Add(src.String()) {
+			sources = append(sources, src.String())
+		}
+		if len(sources) == targetScriptLimit {
+			break
+		}
+	}
+	return sources
+}
+
+// ExtractWords tokenizes the words of a page in the order they appear,
+// without duplicates and without the words of the markup
+func ExtractWords(content string) []string {
+	var words []string
+	seen := NewSet[string]()
+	for _, w := range targetWordRegex.FindAllString(content, -1) {
+		w = strings.TrimRight(w, "-_")
+		if len(w) < 3 || targetStopWords[strings.ToLower(w)] {
+			continue
+		}
+		if seen.Add(w) {
+			words = append(words, w)
+		}
+	}
+	return words
+}
+
+// AddTargetWords queues the words of a page of the target, with
+// -wordlist-from-target, and returns the number of new ones
+func (g *Gobuster) AddTargetWords(content, contentType string) int {
+	if !g.Opts.WordlistFromTarget {
+		return 0
+	}
+	textual := false
+	for _, t := range targetWordTypes {
+		if strings.Contains(strings.ToLower(contentType), t) {
+			textual = true
+		}
+	}
+	if !textual {
+		return 0
+	}
+	added := 0
+	for _, w := range ExtractWords(content) {
+		if g.discovered.add(&BusterTarget{Target: w}) {
+			added++
+		}
+	}
+	return added
+}
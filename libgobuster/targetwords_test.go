@@ -0,0 +1,69 @@
+package libgobuster
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractWords(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName string
+		content  string
+		expected []string
+	}{
+		{"Markup", `<html><head><title>Acme Portal</title></head><body><a href="/downloads">Downloads</a></body></html>`, []string{"Acme", "Portal", "downloads", "Downloads"}},
+		{"Script", `<script>var apiBase = "/internal_api/"; function loadReports() { return null; }</script>`, []string{"apiBase", "internal_api", "loadReports"}},
+		{"Short and duplicate", `ab abc abc x- staging- staging`, []string{"abc", "staging"}},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			if got := ExtractWords(x.content); !reflect.DeepEqual(got, x.expected) {
+				t.Fatalf("expected %v, got %v", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestTargetWords(t *testing.T) {
+	t.Parallel()
+
+	o := NewOptions()
+	o.WordlistFromTarget = true
+	g := &Gobuster{Opts: o, discovered: newTargetQueue()}
+	if n := g.AddTargetWords("alpha beta", "text/html; charset=utf-8"); n != 2 {
+		t.Fatalf("expected 2 words, got %d", n)
+	}
+	if n := g.AddTargetWords("gamma", "image/png"); n != 0 {
+		t.Fatalf("expected binary content to be skipped, got %d words", n)
+	}
+	if n := g.AddTargetWords("beta gamma", "application/javascript"); n != 1 {
+		t.Fatalf("expected 1 new word, got %d", n)
+	}
+	var words []string
+	for _, target := range g.discovered.take() {
+		words = append(words, target.Target)
+	}
+	if !reflect.DeepEqual(words, []string{"alpha", "beta", "gamma"}) {
+		t.Fatalf("unexpected queue %v", words)
+	}
+	if got := g.discovered.take(); len(got) != 0 {
+		t.Fatalf("expected an empty queue, got %v", got)
+	}
+}
+
+func TestScriptSources(t *testing.T) {
+	t.Parallel()
+
+	content := `<script src="/js/app.js"></script><script type="module" src='vendor.js#x'></script>
+<script src="https://cdn.example.net/lib.js"></script><script src="/js/app.js"></script>`
+	expected := []string{"http://example.com/js/app.js", "http://example.com/app/vendor.js"}
+	if got := ScriptSources(content, "http://example.com/app/"); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
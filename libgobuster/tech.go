@@ -0,0 +1,186 @@
+package libgobuster
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Technology is a server side technology detected on the target
+type Technology struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+func (t Technology) String() string {
+	if t.Version == "" {
+		return t.Name
+	}
+	return t.Name + " " + t.Version
+}
+
+// techFingerprint recognizes a technology by a header, a cookie or the
+// meta generator of a page, the first group of a pattern is the version
+type techFingerprint struct {
+	name string
+	// headers maps a header name to the pattern of its value
+	headers map[string]*regexp.Regexp
+	// cookies are cookie name prefixes
+	cookies   []string
+	generator *regexp.Regexp
+	// extensions are the file extensions the technology usually serves
+	extensions []string
+}
+
+var techFingerprints = []techFingerprint{
+	{name: "Apache", headers: map[string]*regexp.Regexp{"Server": regexp.MustCompile(`(?i)^apache(?:/([\d.]+))?(?:\s|$)`)}},
+	{name: "Nginx", headers: map[string]*regexp.Regexp{"Server": regexp.MustCompile(`(?i)nginx(?:/([\d.]+))?`)}},
+	{name: "IIS", headers: map[string]*regexp.Regexp{"Server": regexp.MustCompile(`(?i)microsoft-iis(?:/([\d.]+))?`)}, extensions: []string{"asp", "aspx"}},
+	{name: "LiteSpeed", headers: map[string]*regexp.Regexp{"Server": regexp.MustCompile(`(?i)litespeed`)}},
+	{name: "Caddy", headers: map[string]*regexp.Regexp{"Server": regexp.MustCompile(`(?i)caddy`)}},
+	{name: "Tomcat", headers: map[string]*regexp.Regexp{"Server": regexp.MustCompile(`(?i)(?:apache-coyote|tomcat)(?:/([\d.]+))?`)}, extensions: []string{"jsp", "do"}},
+	{name: "Jetty", headers: map[string]*regexp.Regexp{"Server": regexp.MustCompile(`(?i)jetty(?:\(([\d.]+)\))?`)}, extensions: []string{"jsp"}},
+	{name: "Cloudflare", headers: map[string]*regexp.Regexp{"Server": regexp.MustCompile(`(?i)^cloudflare`), "Cf-Ray": regexp.MustCompile(`.`)}},
+	{
+		name:       "PHP",
+		headers:    map[string]*regexp.Regexp{"X-Powered-By": regexp.MustCompile(`(?i)php(?:/([\d.]+))?`)},
+		cookies:    []string{"PHPSESSID"},
+		extensions: []string{"php"},
+	},
+	{
+		name:       "ASP.NET",
+		headers:    map[string]*regexp.Regexp{"X-Powered-By": regexp.MustCompile(`(?i)asp\.net`), "X-Aspnet-Version": regexp.MustCompile(`([\d.]+)`)},
+		cookies:    []string{"ASP.NET_SessionId", ".ASPXAUTH"},
+		extensions: []string{"aspx", "ashx", "asmx"},
+	},
+	{name: "Java", cookies: []string{"JSESSIONID"}, extensions: []string{"jsp", "do", "action"}},
+	{name: "Express", headers: map[string]*regexp.Regexp{"X-Powered-By": regexp.MustCompile(`(?i)express`)}, cookies: []string{"connect.sid"}},
+	{name: "Next.js", headers: map[string]*regexp.Regexp{"X-Powered-By": regexp.MustCompile(`(?i)next\.js(?: ([\d.]+))?`)}},
+	{name: "Django", cookies: []string{"csrftoken", "django_language"}, extensions: []string{"py"}},
+	{name: "Ruby on Rails", headers: map[string]*regexp.Regexp{"X-Powered-By": regexp.MustCompile(`(?i)phusion passenger`)}, cookies: []string{"_rails_session"}, extensions: []string{"rb"}},
+	{name: "Laravel", cookies: []string{"laravel_session", "XSRF-TOKEN"}, extensions: []string{"php"}},
+	{name: "ColdFusion", cookies: []string{"CFID", "CFTOKEN"}, extensions: []string{"cfm", "cfc"}},
+	{
+		name:       "WordPress",
+		headers:    map[string]*regexp.Regexp{"Link": regexp.MustCompile(`(?i)wp-json`)},
+		cookies:    []string{"wordpress_", "wp-settings-"},
+		generator:  regexp.MustCompile(`(?i)wordpress(?: ([\d.]+))?`),
+		extensions: []string{"php"},
+	},
+	{name: "Drupal", headers: map[string]*regexp.Regexp{"X-Generator": regexp.MustCompile(`(?i)drupal(?: (\d+))?`), "X-Drupal-Cache": regexp.MustCompile(`.`)}, generator: regexp.MustCompile(`(?i)drupal(?: (\d+))?`), extensions: []string{"php"}},
+	{name: "Joomla", generator: regexp.MustCompile(`(?i)joomla!?(?: ([\d.]+))?`), extensions: []string{"php"}},
+	{name: "TYPO3", generator: regexp.MustCompile(`(?i)typo3(?: cms)?(?: ([\d.]+))?`), extensions: []string{"php"}},
+	{name: "Shopify", headers: map[string]*regexp.Regexp{"X-Shopid": regexp.MustCompile(`.`)}, cookies: []string{"_shopify_"}},
+}
+
+var metaGeneratorRegex = regexp.MustCompile(`(?is)<meta[^>]+name=["']?generator["']?[^>]+content=["']([^"']+)["']|<meta[^>]+content=["']([^"']+)["'][^>]+name=["']?generator["']?`)
+
+// metaGenerator returns the content of the meta generator tag
+func metaGenerator(content string) string {
+	m := metaGeneratorRegex.FindStringSubmatch(content)
+	if m == nil {
+		return ""
+	}
+	if m[1] != "" {
+		return m[1]
+	}
+	return m[2]
+}
+
+// cookieNames returns the names of the cookies set by a response
+func cookieNames(header http.Header) []string {
+	var names []string
+	for _, c := range (&http.Response{Header: header}).Cookies() {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+// match returns whether the fingerprint matches, and the version when known
+func (fp techFingerprint) match(header http.Header, cookies []string, generator string) (bool, string) {
+	found, version := false, ""
+	check := func(re *regexp.Regexp, value string) {
+		if m := re.FindStringSubmatch(value); m != nil {
+			found = true
+			if len(m) > 1 && m[1] != "" && version == "" {
+				version = m[1]
+			}
+		}
+	}
+	for name, re := range fp.headers {
+		for _, value := range header.Values(name) {
+			check(re, value)
+		}
+	}
+	if fp.generator != nil && generator != "" {
+		check(fp.generator, generator)
+	}
+	for _, prefix := range fp.cookies {
+		for _, name := range cookies {
+			if strings.HasPrefix(name, prefix) {
+				found = true
+			}
+		}
+	}
+	return found, version
+}
+
+// DetectTechnologies fingerprints the technologies of a page by its
+// headers, cookies and meta generator, sorted by name
+func DetectTechnologies(resp *Response) []Technology {
+	if resp == nil {
+		return nil
+	}
+	cookies := cookieNames(resp.Header)
+	generator := metaGenerator(resp.Content)
+
+	var techs []Technology
+	for _, fp := range techFingerprints {
+		if ok, version := fp.match(resp.Header, cookies, generator); ok {
+			techs = append(techs, Technology{Name: fp.name, Version: version})
+		}
+	}
+	sort.Slice(techs, func(i, j int) bool { return techs[i].Name < techs[j].Name })
+	return techs
+}
+
+// technologyList returns the technologies as a comma separated list
+func technologyList(techs []Technology) string {
+	names := make([]string, len(techs))
+	for i, t := range techs {
+		names[i] = t.String()
+	}
+	return strings.Join(names, ", ")
+}
+
+// technologyConfig returns the technologies detected on the target
+// for the config, with the extensions they usually serve
+func (g *Gobuster) technologyConfig() string {
+	list := technologyList(g.Technologies)
+	if exts := g.TechnologyExtensions(); len(exts) > 0 {
+		list += fmt.Sprintf(" (extensions: %s)", strings.Join(exts, ","))
+	}
+	return list
+}
+
+// TechnologyExtensions returns the file extensions usually served by
+// the detected technologies
+func (g *Gobuster) TechnologyExtensions() []string {
+	seen := NewSet[string]()
+	var exts []string
+	for _, t := range g.Technologies {
+		for _, fp := range techFingerprints {
+			if fp.name != t.Name {
+				continue
+			}
+			for _, ext := range fp.extensions {
+				if seen.Add(ext) {
+					exts = append(exts, ext)
+				}
+			}
+		}
+	}
+	return exts
+}
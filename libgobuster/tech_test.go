@@ -0,0 +1,63 @@
+package libgobuster
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestDetectTechnologies(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName string
+		header   http.Header
+		content  string
+		expected []Technology
+	}{
+		{"Nothing", http.Header{}, "<html></html>", nil},
+		{"Server and powered by", http.Header{"Server": {"nginx/1.18.0"}, "X-Powered-By": {"PHP/8.1.2"}}, "", []Technology{{"Nginx", "1.18.0"}, {"PHP", "8.1.2"}}},
+		{"Tomcat is not Apache", http.Header{"Server": {"Apache-Coyote/1.1"}}, "", []Technology{{"Tomcat", "1.1"}}},
+		{"Apache without version", http.Header{"Server": {"Apache"}}, "", []Technology{{"Apache", ""}}},
+		{"Session cookie", http.Header{"Set-Cookie": {"JSESSIONID=abc; Path=/; HttpOnly"}}, "", []Technology{{"Java", ""}}},
+		{"Meta generator", http.Header{}, `<meta name="generator" content="WordPress 6.4.2" />`, []Technology{{"WordPress", "6.4.2"}}},
+		{"Meta generator content first", http.Header{}, `<meta content="Joomla! - Open Source Content Management" name="generator">`, []Technology{{"Joomla", ""}}},
+	}
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			got := DetectTechnologies(&Response{Header: x.header, Content: x.content})
+			if !reflect.DeepEqual(got, x.expected) {
+				t.Fatalf("expected %v, got %v", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestTechnologyExtensions(t *testing.T) {
+	t.Parallel()
+
+	g := &Gobuster{Technologies: []Technology{{Name: "PHP"}, {Name: "WordPress"}, {Name: "Nginx"}}}
+	if got := g.TechnologyExtensions(); !reflect.DeepEqual(got, []string{"php"}) {
+		t.Fatalf("expected [php], got %v", got)
+	}
+}
+
+func TestTechnologyConfig(t *testing.T) {
+	t.Parallel()
+
+	o := NewOptions()
+	o.Mode = ModeDir
+	g := &Gobuster{Opts: o, mu: new(sync.RWMutex), Technologies: []Technology{{Name: "Nginx", Version: "1.18.0"}, {Name: "PHP"}}}
+	config, err := g.GetConfigString()
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if line := "[+] Technologies          : Nginx 1.18.0, PHP (extensions: php)\n"; !strings.Contains(config, line) {
+		t.Fatalf("expected %q in the config:\n%s", line, config)
+	}
+}
@@ -0,0 +1,146 @@
+package libgobuster
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// torCookieRegex extracts the cookie file of a PROTOCOLINFO reply
+var torCookieRegex = regexp.MustCompile(`COOKIEFILE="((?:[^"\\]|\\.)*)"`)
+
+// torController asks the Tor control port for new circuits
+type torController struct {
+	address  string
+	password string
+	logger   *log.Logger
+
+	mu       sync.Mutex
+	every    int
+	requests int
+}
+
+func newTorController(opt *Options) *torController {
+	return &torController{
+		address:  opt.TorControl,
+		password: opt.TorPassword,
+		logger:   opt.logger(),
+		every:    opt.TorRenewRequests,
+	}
+}
+
+// command sends a control port command and returns the reply lines,
+// failing on any status but 250
+func torCommand(rw *bufio.ReadWriter, command string) ([]string, error) {
+	if _, err := rw.WriteString(command + "\r\n"); err != nil {
+		return nil, err
+	}
+	if err := rw.Flush(); err != nil {
+		return nil, err
+	}
+	var lines []string
+	for {
+		line, err := rw.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if len(line) < 4 {
+			return nil, fmt.Errorf("invalid control port reply %q", line)
+		}
+		if line[:3] != "250" {
+			return nil, fmt.Errorf("control port replied %q", line)
+		}
+		lines = append(lines, line[4:])
+		// "250 " ends the reply, "250-" and "250+" continue it
+		if line[3] == ' ' {
+			return lines, nil
+		}
+	}
+}
+
+// authenticate logs in with the password, the cookie file announced by
+// PROTOCOLINFO or no credentials at all
+func (t *torController) authenticate(rw *bufio.ReadWriter) error {
+	if t.password != "" {
+		_, err := torCommand(rw, fmt.Sprintf("AUTHENTICATE \"%s\"", strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(t.password)))
+		return err
+	}
+	info, err := torCommand(rw, "PROTOCOLINFO 1")
+	if err != nil {
+		return err
+	}
+	for _, line := range info {
+		if m := torCookieRegex.FindStringSubmatch(line); m != nil && strings.Contains(line, "COOKIE") {
+			cookie, err := os.ReadFile(strings.ReplaceAll(m[1], `\\`, `\`))
+			if err != nil {
+				return fmt.Errorf("unable to read the Tor cookie: %v", err)
+			}
+			_, err = torCommand(rw, "AUTHENTICATE "+hex.EncodeToString(cookie))
+			return err
+		}
+	}
+	_, err = torCommand(rw, "AUTHENTICATE")
+	return err
+}
+
+// renew signals NEWNYM so the following requests leave through new circuits
+func (t *torController) renew() error {
+	conn, err := net.DialTimeout("tcp", t.address, 5*time.Second)
+	if err != nil {
+		return fmt.Errorf("unable to connect to the Tor control port %s: %v", t.address, err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(10 * time.Second))
+
+	rw := bufio.NewReadWriter(bufio.NewReader(conn), bufio.NewWriter(conn))
+	if err := t.authenticate(rw); err != nil {
+		return fmt.Errorf("unable to authenticate to the Tor control port: %v", err)
+	}
+	if _, err := torCommand(rw, "SIGNAL NEWNYM"); err != nil {
+		return fmt.Errorf("unable to renew the Tor circuit: %v", err)
+	}
+	return nil
+}
+
+func (t *torController) renewAndLog() {
+	if err := t.renew(); err != nil {
+		orDefault(t.logger).Printf("[!] %v", err)
+	}
+}
+
+// request counts a request, renewing the circuit every N of them
+func (t *torController) request() {
+	if t.every <= 0 {
+		return
+	}
+	t.mu.Lock()
+	t.requests++
+	renew := t.requests%t.every == 0
+	t.mu.Unlock()
+	if renew {
+		go t.renewAndLog()
+	}
+}
+
+// renewEvery renews the circuit periodically until the context is done
+func (t *torController) renewEvery(c context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.Done():
+			return
+		case <-ticker.C:
+			t.renewAndLog()
+		}
+	}
+}
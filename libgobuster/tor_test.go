@@ -0,0 +1,90 @@
+package libgobuster
+
+import (
+	"bufio"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeTorControl answers a single control port connection and returns
+// the commands it received
+func fakeTorControl(t *testing.T, cookieFile string) (string, <-chan []string) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	commands := make(chan []string, 1)
+	go func() {
+		defer l.Close()
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		var received []string
+		defer func() { commands <- received }()
+		r := bufio.NewReader(conn)
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimSpace(line)
+			received = append(received, line)
+			switch {
+			case strings.HasPrefix(line, "PROTOCOLINFO"):
+				conn.Write([]byte("250-PROTOCOLINFO 1\r\n250-AUTH METHODS=COOKIE,SAFECOOKIE COOKIEFILE=\"" + cookieFile + "\"\r\n250-VERSION Tor=\"0.4.8.9\"\r\n250 OK\r\n"))
+			case line == "AUTHENTICATE 636f6f6b6965", line == `AUTHENTICATE "secret"`:
+				conn.Write([]byte("250 OK\r\n"))
+			case strings.HasPrefix(line, "AUTHENTICATE"):
+				conn.Write([]byte("515 Authentication failed\r\n"))
+				return
+			case line == "SIGNAL NEWNYM":
+				conn.Write([]byte("250 OK\r\n"))
+				return
+			}
+		}
+	}()
+	return l.Addr().String(), commands
+}
+
+func TestTorRenew(t *testing.T) {
+	t.Parallel()
+
+	cookieFile := filepath.Join(t.TempDir(), "control_auth_cookie")
+	if err := os.WriteFile(cookieFile, []byte("cookie"), 0600); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+
+	tt := []struct {
+		testName string
+		password string
+		expected []string
+		wantErr  bool
+	}{
+		{"Cookie", "", []string{"PROTOCOLINFO 1", "AUTHENTICATE 636f6f6b6965", "SIGNAL NEWNYM"}, false},
+		{"Password", "secret", []string{`AUTHENTICATE "secret"`, "SIGNAL NEWNYM"}, false},
+		{"Wrong password", "wrong", []string{`AUTHENTICATE "wrong"`}, true},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			address, commands := fakeTorControl(t, cookieFile)
+			tor := &torController{address: address, password: x.password}
+			err := tor.renew()
+			if x.wantErr != (err != nil) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got := <-commands
+			if strings.Join(got, "|") != strings.Join(x.expected, "|") {
+				t.Fatalf("expected commands %v, got %v", x.expected, got)
+			}
+		})
+	}
+}
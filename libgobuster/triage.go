@@ -0,0 +1,167 @@
+package libgobuster
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TriageFilename is the log of the triage of the findings of an output
+// folder. Its lines are appended only, so the folders of a team merge.
+const TriageFilename = "triage.jsonl"
+
+const (
+	// TriageInteresting marks a finding worth a closer look
+	TriageInteresting = "interesting"
+	// TriageFalsePositive marks a finding that is no finding
+	TriageFalsePositive = "false-positive"
+	// TriageDone marks a finding looked into
+	TriageDone = "done"
+)
+
+// TriageStatuses are the statuses a finding can be marked with
+var TriageStatuses = []string{TriageInteresting, TriageFalsePositive, TriageDone}
+
+// TriageEntry is a line of the triage log, a status or a note given to
+// a finding
+type TriageEntry struct {
+	Finding string    `json:"finding"`
+	Status  string    `json:"status,omitempty"`
+	Note    string    `json:"note,omitempty"`
+	Author  string    `json:"author,omitempty"`
+	Time    time.Time `json:"time"`
+}
+
+// Triage is the state of a finding: its last status and every note
+type Triage struct {
+	Finding string
+	Status  string
+	Updated time.Time
+	Notes   []TriageEntry
+}
+
+// ParseTriageStatus checks the status given to a finding
+func ParseTriageStatus(status string) (string, error) {
+	for _, s := range TriageStatuses {
+		if strings.EqualFold(status, s) {
+			return s, nil
+		}
+	}
+	return "", fmt.Errorf("invalid triage status given: %s, use one of %s", status, strings.Join(TriageStatuses, ", "))
+}
+
+// TriageFinding returns the form of a finding in the triage, the one of
+// the history
+func TriageFinding(finding string) string {
+	return normalizeEntity(finding)
+}
+
+// RecordedFindings checks the findings are matches recorded in the all
+// time files of the output folder and returns them in the form of the
+// history, "admin", "/admin" and "/admin/" being the same finding
+func RecordedFindings(outputFolder string, findings []string) ([]string, error) {
+	recorded := NewSet[string]()
+	for _, name := range allTimeFiles {
+		lines, err := readRotatedLines(filepath.Join(outputFolder, name))
+		if err != nil {
+			return nil, err
+		}
+		for _, line := range lines {
+			if entity, entry := parseHistoryLine(line); entry != nil {
+				recorded.Add(normalizeEntity(entity))
+			}
+		}
+	}
+	var normalized, unknown []string
+	for _, finding := range findings {
+		n := TriageFinding(finding)
+		if !recorded.Contains(n) {
+			unknown = append(unknown, finding)
+			continue
+		}
+		normalized = append(normalized, n)
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("not a match recorded in %s: %s", outputFolder, strings.Join(unknown, ", "))
+	}
+	return normalized, nil
+}
+
+// AppendTriage records entries in the triage log of the output folder
+func AppendTriage(outputFolder string, entries []TriageEntry) error {
+	var lines []string
+	for _, e := range entries {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		lines = append(lines, string(data))
+	}
+	return appendLines(filepath.Join(outputFolder, TriageFilename), lines)
+}
+
+// ReadTriage returns the state of every finding of the triage log of
+// the output folder, sorted by finding
+func ReadTriage(outputFolder string) ([]Triage, error) {
+	f, err := os.Open(filepath.Join(outputFolder, TriageFilename))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	lines, err := readLines(f)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []TriageEntry
+	for i, line := range lines {
+		var e TriageEntry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			return nil, fmt.Errorf("invalid line %d of %s: %v", i+1, TriageFilename, err)
+		}
+		entries = append(entries, e)
+	}
+	// merged logs interleave the entries of several folders
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Time.Before(entries[j].Time) })
+
+	byFinding := make(map[string]*Triage)
+	for _, e := range entries {
+		finding := TriageFinding(e.Finding)
+		t, ok := byFinding[finding]
+		if !ok {
+			t = &Triage{Finding: finding}
+			byFinding[finding] = t
+		}
+		if e.Status != "" {
+			t.Status = e.Status
+		}
+		if e.Note != "" {
+			t.Notes = append(t.Notes, e)
+		}
+		t.Updated = e.Time
+	}
+	triage := make([]Triage, 0, len(byFinding))
+	for _, t := range byFinding {
+		triage = append(triage, *t)
+	}
+	sort.Slice(triage, func(i, j int) bool { return triage[i].Finding < triage[j].Finding })
+	return triage, nil
+}
+
+// TriageCounts returns the number of findings of every status
+func TriageCounts(triage []Triage) map[string]int {
+	counts := make(map[string]int)
+	for _, t := range triage {
+		if t.Status != "" {
+			counts[t.Status]++
+		}
+	}
+	return counts
+}
@@ -0,0 +1,133 @@
+package libgobuster
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTriageStatus(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		status   string
+		expected string
+		err      bool
+	}{
+		{"interesting", TriageInteresting, false},
+		{"False-Positive", TriageFalsePositive, false},
+		{"done", TriageDone, false},
+		{"fixed", "", true},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.status, func(t *testing.T) {
+			t.Parallel()
+
+			s, err := ParseTriageStatus(x.status)
+			if (err != nil) != x.err {
+				t.Fatalf("expected error %t, got %v", x.err, err)
+			}
+			if s != x.expected {
+				t.Fatalf("expected %q, got %q", x.expected, s)
+			}
+		})
+	}
+}
+
+func TestReadTriage(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	if triage, err := ReadTriage(dir); err != nil || triage != nil {
+		t.Fatalf("expected no triage, got %v, %v", triage, err)
+	}
+
+	now := time.Now()
+	first := []TriageEntry{
+		{Finding: "/admin", Status: TriageInteresting, Note: "login form", Author: "alice", Time: now},
+		{Finding: "/backup", Status: TriageFalsePositive, Time: now},
+	}
+	// a later note without a status keeps the last status
+	second := []TriageEntry{
+		{Finding: "/admin", Status: TriageDone, Time: now.Add(time.Minute)},
+		{Finding: "/admin", Note: "default creds", Author: "bob", Time: now.Add(2 * time.Minute)},
+	}
+	if err := AppendTriage(dir, second); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if err := AppendTriage(dir, first); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+
+	triage, err := ReadTriage(dir)
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if len(triage) != 2 || triage[0].Finding != "admin" || triage[1].Finding != "backup" {
+		t.Fatalf("unexpected triage %+v", triage)
+	}
+	admin := triage[0]
+	if admin.Status != TriageDone || len(admin.Notes) != 2 || admin.Notes[0].Note != "login form" || admin.Notes[1].Author != "bob" {
+		t.Fatalf("unexpected triage of /admin %+v", admin)
+	}
+	if !admin.Updated.Equal(now.Add(2 * time.Minute)) {
+		t.Fatalf("unexpected update time %v", admin.Updated)
+	}
+	counts := TriageCounts(triage)
+	if counts[TriageDone] != 1 || counts[TriageFalsePositive] != 1 || counts[TriageInteresting] != 0 {
+		t.Fatalf("unexpected counts %v", counts)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, TriageFilename), []byte("not json\n"), 0644); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if _, err := ReadTriage(dir); err == nil {
+		t.Fatal("expected an error on an invalid line")
+	}
+}
+
+func TestRecordedFindings(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	matches := "# profile 085141f908ad mode=dir\n" +
+		"[2024-01-02 03:04:05] - /admin - 301  ->  http://example.com/admin/ - run 1 - profile 085141f908ad\n" +
+		"[2024-01-02 03:04:05] - /backup.sql - 200 - run 1 - profile 085141f908ad\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, "all_time_matches.txt"), []byte(matches), 0644); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	subdomains := "[2024-01-02 03:04:05] - www.example.com - 93.184.216.34 - run 2 - profile 1a2b3c4d5e6f\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, "all_time_subdomains.txt"), []byte(subdomains), 0644); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+
+	tt := []struct {
+		testName string
+		findings []string
+		expected []string
+		err      bool
+	}{
+		{"Recorded", []string{"/admin", "admin/", "www.example.com"}, []string{"admin", "admin", "www.example.com"}, false},
+		{"Not recorded", []string{"/admin", "/secret"}, nil, true},
+		{"Profile line", []string{"# profile 085141f908ad mode=dir"}, nil, true},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := RecordedFindings(dir, x.findings)
+			if (err != nil) != x.err {
+				t.Fatalf("expected error %t, got %v", x.err, err)
+			}
+			if strings.Join(got, ",") != strings.Join(x.expected, ",") {
+				t.Fatalf("expected %q, got %q", x.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package libgobuster
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+const (
+	// uploadNameLength is the length of the random part of the name of
+	// the file -upload-check puts
+	uploadNameLength = 16
+	// uploadCharset are the characters of the random part of the name
+	uploadCharset = "0123456789abcdef"
+)
+
+// writableDirRegex matches the names of the directories likely to accept
+// uploads, the ones -upload-check tries
+var writableDirRegex = regexp.MustCompile(`(?i)^(uploads?|upload_files|files?|tmp|temp|media|images?|img|static|assets|dav|webdav|public|share|shared|storage|attachments?|documents?)$`)
+
+// Upload is a directory accepting the PUT of -upload-check
+type Upload struct {
+	URL string `json:"url"`
+	// PutStatus is the status of the PUT of the file
+	PutStatus int `json:"put_status"`
+	// Served reports whether a GET returned the file put
+	Served bool `json:"served"`
+	// DeleteStatus is the status of the DELETE cleaning the file up, the
+	// file is left on the server unless it is a 2xx
+	DeleteStatus int `json:"delete_status"`
+}
+
+// Deleted reports whether the file put was cleaned up
+func (u Upload) Deleted() bool {
+	return isSuccess(u.DeleteStatus)
+}
+
+// String returns the annotation of the directory
+func (u Upload) String() string {
+	return "upload: " + u.Detail()
+}
+
+// Detail returns the statuses of the PUT and DELETE and whether the file
+// was served
+func (u Upload) Detail() string {
+	parts := []string{fmt.Sprintf("PUT %d", u.PutStatus)}
+	if u.Served {
+		parts = append(parts, "served")
+	} else {
+		parts = append(parts, "not served")
+	}
+	if u.Deleted() {
+		parts = append(parts, fmt.Sprintf("DELETE %d", u.DeleteStatus))
+	} else {
+		parts = append(parts, "not deleted")
+	}
+	return strings.Join(parts, ", ")
+}
+
+func isSuccess(status int) bool {
+	return status >= 200 && status < 300
+}
+
+// IsWritableSounding reports whether the last segment of a directory path
+// sounds like a directory accepting uploads
+func IsWritableSounding(p string) bool {
+	p = strings.TrimSuffix(p, "/")
+	if i := strings.LastIndex(p, "/"); i >= 0 {
+		p = p[i+1:]
+	}
+	return writableDirRegex.MatchString(p)
+}
+
+// CheckUpload puts a uniquely named text file into a directory, gets it
+// back and deletes it, it returns the upload when the PUT succeeded. Every
+// directory is checked once.
+func (g *Gobuster) CheckUpload(dirURL string, ro RequestOptions) (*Upload, error) {
+	if !strings.HasSuffix(dirURL, "/") {
+		dirURL += "/"
+	}
+	g.mu.Lock()
+	if g.uploadsChecked.Set == nil {
+		g.uploadsChecked = NewSet[string]()
+	}
+	first := g.uploadsChecked.Add(dirURL)
+	g.mu.Unlock()
+	if !first {
+		return nil, nil
+	}
+
+	name := fmt.Sprintf("gobuster-%s.txt", RandomString(g.Random(), uploadNameLength, uploadCharset))
+	fileURL := dirURL + name
+	content := fmt.Sprintf("gobuster upload check %s\n", name)
+
+	putRO := ro
+	putRO.Body = content
+	resp, err := g.HTTP.makeRequest(http.MethodPut, fileURL, putRO)
+	if err != nil {
+		return nil, err
+	}
+	if !isSuccess(resp.StatusCode) {
+		return nil, nil
+	}
+	upload := &Upload{URL: dirURL, PutStatus: resp.StatusCode}
+
+	if resp, err := g.HTTP.makeRequest(http.MethodGet, fileURL, ro); err == nil {
+		upload.Served = resp.StatusCode == http.StatusOK && strings.Contains(resp.Content, content)
+	}
+	if resp, err := g.HTTP.makeRequest(http.MethodDelete, fileURL, ro); err == nil {
+		upload.DeleteStatus = resp.StatusCode
+	}
+
+	g.Logf("[!] %s accepts uploads (%s)", dirURL, upload.Detail())
+	if !upload.Deleted() {
+		g.Logf("[!] Unable to delete %s, remove it by hand", fileURL)
+	}
+	g.mu.Lock()
+	g.uploads = append(g.uploads, *upload)
+	g.mu.Unlock()
+	return upload, nil
+}
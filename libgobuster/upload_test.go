@@ -0,0 +1,105 @@
+package libgobuster
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestIsWritableSounding(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		path     string
+		expected bool
+	}{
+		{"uploads/", true},
+		{"static/Images/", true},
+		{"webdav", true},
+		{"admin/", false},
+		{"uploads/admin/", false},
+		{"fileserver/", false},
+	}
+	for _, x := range tt {
+		x := x
+		t.Run(x.path, func(t *testing.T) {
+			t.Parallel()
+
+			if got := IsWritableSounding(x.path); got != x.expected {
+				t.Fatalf("expected %t, got %t", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestCheckUpload(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	files := make(map[string][]byte)
+	h := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if !strings.HasPrefix(r.URL.Path, "/dav/") {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		switch r.Method {
+		case http.MethodPut:
+			body, _ := ioutil.ReadAll(r.Body)
+			files[r.URL.Path] = body
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodGet:
+			body, ok := files[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(body)
+		case http.MethodDelete:
+			delete(files, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer h.Close()
+
+	o := NewOptions()
+	c, err := newHTTPClient(context.Background(), o)
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	g := &Gobuster{Opts: o, HTTP: c, mu: new(sync.RWMutex)}
+
+	upload, err := g.CheckUpload(h.URL+"/dav", RequestOptions{})
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	expected := &Upload{URL: h.URL + "/dav/", PutStatus: 201, Served: true, DeleteStatus: 204}
+	if !reflect.DeepEqual(upload, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, upload)
+	}
+	if got := upload.String(); got != "upload: PUT 201, served, DELETE 204" {
+		t.Fatalf("unexpected extra %q", got)
+	}
+	mu.Lock()
+	left := len(files)
+	mu.Unlock()
+	if left != 0 {
+		t.Fatalf("expected the file to be deleted, %d left", left)
+	}
+	// a directory is checked once
+	if upload, err := g.CheckUpload(h.URL+"/dav/", RequestOptions{}); upload != nil || err != nil {
+		t.Fatalf("expected no second check, got %+v %v", upload, err)
+	}
+	if upload, err := g.CheckUpload(h.URL+"/admin/", RequestOptions{}); upload != nil || err != nil {
+		t.Fatalf("expected no upload, got %+v %v", upload, err)
+	}
+	if s := g.Summary(); len(s.Uploads) != 1 {
+		t.Fatalf("expected an upload in the summary, got %+v", s.Uploads)
+	}
+}
@@ -0,0 +1,108 @@
+package libgobuster
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// defaultPorts are the ports the urls leave out for their scheme
+var defaultPorts = map[string]string{"http": "80", "https": "443"}
+
+// schemelessPortRegex matches the port of a url given without a scheme,
+// the host may be a bracketed IPv6 literal
+var schemelessPortRegex = regexp.MustCompile(`^(\[[^\]/]+\]|[^/:\[\]]+):(\d+)`)
+
+// withScheme returns the url with the scheme of its port, http when
+// the port does not tell. Bare IPv6 literals get their brackets.
+func withScheme(rawURL string) (string, error) {
+	if strings.HasPrefix(rawURL, "http") {
+		return rawURL, nil
+	}
+	host, rest := rawURL, ""
+	if i := strings.Index(rawURL, "/"); i >= 0 {
+		host, rest = rawURL[:i], rawURL[i:]
+	}
+	if ip := net.ParseIP(host); ip != nil && strings.Contains(host, ":") {
+		return fmt.Sprintf("http://[%s]%s", host, rest), nil
+	}
+
+	match := schemelessPortRegex.FindStringSubmatch(rawURL)
+	if match == nil {
+		// no port, default to http on 80
+		return fmt.Sprintf("http://%s", rawURL), nil
+	}
+	port, err := strconv.Atoi(match[2])
+	if err != nil || (port != 80 && port != 443) {
+		return "", fmt.Errorf("url scheme not specified")
+	} else if port == 80 {
+		return fmt.Sprintf("http://%s", rawURL), nil
+	}
+	return fmt.Sprintf("https://%s", rawURL), nil
+}
+
+// normalizeBaseURL returns the base url of a target with a lower case
+// scheme, without the default port of the scheme and ending with a slash
+func normalizeBaseURL(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("invalid url given: %s", rawURL)
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	if port := u.Port(); port != "" && defaultPorts[u.Scheme] == port {
+		u.Host = bracketHost(u.Hostname())
+	}
+	if !strings.HasSuffix(u.Path, "/") {
+		u.Path += "/"
+		if u.RawPath != "" {
+			u.RawPath += "/"
+		}
+	}
+	return u.String(), nil
+}
+
+// bracketHost puts an IPv6 literal between brackets, as urls need
+func bracketHost(host string) string {
+	if strings.Contains(host, ":") && !strings.HasPrefix(host, "[") {
+		return "[" + host + "]"
+	}
+	return host
+}
+
+// hostURL returns the base url of a port of the host, without the
+// default port of the scheme, 0 leaves the port out
+func hostURL(scheme, host string, port int) string {
+	u := url.URL{Scheme: scheme, Host: bracketHost(host), Path: "/"}
+	if port != 0 && defaultPorts[scheme] != strconv.Itoa(port) {
+		u.Host = net.JoinHostPort(host, strconv.Itoa(port))
+	}
+	return u.String()
+}
+
+// originURL returns the scheme and host of a url, with a slash
+func originURL(u *url.URL) string {
+	return (&url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/"}).String()
+}
+
+// JoinURL appends a path to a base url with exactly one slash between
+// them. The path is kept as is, the words of the wordlists may hold a
+// query or escapes of their own.
+func JoinURL(base, p string) string {
+	if !strings.HasSuffix(base, "/") {
+		base += "/"
+	}
+	return base + strings.TrimPrefix(p, "/")
+}
+
+// URLFor returns the url of a path below the base url of the target
+func (g *Gobuster) URLFor(p string) string {
+	return JoinURL(g.BaseURL(), p)
+}
+
+// sanitizedHost returns the host and port of a url for the file names
+func sanitizedHost(u *url.URL) string {
+	return strings.NewReplacer(".", "_", ":", "_", "[", "", "]", "").Replace(u.Host)
+}
@@ -0,0 +1,135 @@
+package libgobuster
+
+import (
+	"testing"
+)
+
+func TestWithScheme(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		url      string
+		expected string
+		err      bool
+	}{
+		{"https://example.com", "https://example.com", false},
+		{"example.com", "http://example.com", false},
+		{"example.com:443/app", "https://example.com:443/app", false},
+		{"example.com:8080", "", true},
+		{"::1", "http://[::1]", false},
+		{"2001:db8::1/app", "http://[2001:db8::1]/app", false},
+		{"[::1]", "http://[::1]", false},
+		{"[::1]:443", "https://[::1]:443", false},
+		{"[::1]:8080", "", true},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.url, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := withScheme(x.url)
+			if (err != nil) != x.err || got != x.expected {
+				t.Fatalf("expected %q (error %t), got %q (%v)", x.expected, x.err, got, err)
+			}
+		})
+	}
+}
+
+func TestNormalizeBaseURL(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		url      string
+		expected string
+	}{
+		{"http://example.com", "http://example.com/"},
+		{"HTTPS://example.com:443/app", "https://example.com/app/"},
+		{"http://example.com:443/", "http://example.com:443/"},
+		{"http://[::1]:80/", "http://[::1]/"},
+		{"https://[2001:db8::1]:8443/a%2Fb", "https://[2001:db8::1]:8443/a%2Fb/"},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.url, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := normalizeBaseURL(x.url)
+			if err != nil {
+				t.Fatalf("got error: %v", err)
+			}
+			if got != x.expected {
+				t.Fatalf("expected %q, got %q", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestHostURL(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName string
+		scheme   string
+		host     string
+		port     int
+		expected string
+	}{
+		{"Default http", "http", "www.example.com", 80, "http://www.example.com/"},
+		{"Default https", "https", "www.example.com", 443, "https://www.example.com/"},
+		{"Other port", "http", "www.example.com", 8080, "http://www.example.com:8080/"},
+		{"Swapped scheme", "http", "www.example.com", 443, "http://www.example.com:443/"},
+		{"No port", "https", "www.example.com", 0, "https://www.example.com/"},
+		{"IPv6", "http", "2001:db8::1", 8080, "http://[2001:db8::1]:8080/"},
+		{"IPv6 default port", "https", "::1", 443, "https://[::1]/"},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			if got := hostURL(x.scheme, x.host, x.port); got != x.expected {
+				t.Fatalf("expected %q, got %q", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestJoinURL(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		base     string
+		path     string
+		expected string
+	}{
+		{"http://example.com/", "admin", "http://example.com/admin"},
+		{"http://example.com/app", "/admin/", "http://example.com/app/admin/"},
+		{"http://[::1]/", "a?b=1", "http://[::1]/a?b=1"},
+		{"http://example.com/app/", "%2e%2e/x", "http://example.com/app/%2e%2e/x"},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.path, func(t *testing.T) {
+			t.Parallel()
+
+			if got := JoinURL(x.base, x.path); got != x.expected {
+				t.Fatalf("expected %q, got %q", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestOutputFileSuffixIPv6(t *testing.T) {
+	t.Parallel()
+
+	o := NewOptions()
+	o.URL = "http://[::1]:8080/"
+	g := &Gobuster{Opts: o, RunID: "run"}
+	if got := g.OutputFileSuffix(); got != "-62135596800_http___1_8080_run" {
+		t.Fatalf("unexpected suffix %q", got)
+	}
+}
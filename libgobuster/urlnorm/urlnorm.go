@@ -0,0 +1,190 @@
+// Package urlnorm normalizes and deduplicates url lists such as the ones
+// of the wayback machine, reducing static files to their directory and
+// treating urls that only differ in their query values as duplicates.
+package urlnorm
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// QueryStrategy decides when the queries of two urls of the same host
+// and path make them duplicates
+type QueryStrategy string
+
+const (
+	// QueryKeys treats urls with the same query parameter names as duplicates
+	QueryKeys QueryStrategy = "keys"
+	// QueryFull only treats identical queries as duplicates
+	QueryFull QueryStrategy = "full"
+	// QueryIgnore treats urls of the same path as duplicates whatever their query
+	QueryIgnore QueryStrategy = "ignore"
+)
+
+// DefaultStaticExtensions are the extensions of the files that are not
+// worth requesting, their urls are reduced to the directory
+var DefaultStaticExtensions = []string{
+	"jpg", "jpeg", "woff", "woff2", "ico", "css", "eot", "pdf", "ttf", "gif", "doc", "docx",
+	"xls", "xlsx", "svg", "csv", "mp3", "mp4", "wma", "ppt", "png", "pptx", "swf",
+}
+
+// Options configures a Normalizer
+type Options struct {
+	// StripExtensions lists the extensions of the files reduced to their
+	// directory, nil or empty keeps every file
+	StripExtensions []string
+	// Query is the strategy comparing the queries, QueryKeys when empty
+	Query QueryStrategy
+}
+
+// URL is a normalized url
+type URL struct {
+	Host  string
+	Path  string
+	Query url.Values
+	// Raw is the url as it is written out, without the stripped file
+	Raw string
+}
+
+// Normalizer normalizes and deduplicates urls
+type Normalizer struct {
+	query QueryStrategy
+	strip *regexp.Regexp
+}
+
+// New returns a Normalizer for the options
+func New(opts Options) (*Normalizer, error) {
+	n := &Normalizer{query: opts.Query}
+	switch n.query {
+	case "":
+		n.query = QueryKeys
+	case QueryKeys, QueryFull, QueryIgnore:
+	default:
+		return nil, fmt.Errorf("invalid query strategy: %s", opts.Query)
+	}
+
+	var exts []string
+	for _, e := range opts.StripExtensions {
+		if e = strings.TrimPrefix(strings.TrimSpace(e), "."); e != "" {
+			exts = append(exts, regexp.QuoteMeta(e))
+		}
+	}
+	if len(exts) > 0 {
+		n.strip = regexp.MustCompile(fmt.Sprintf(`(?i)[^/]+\.(%s).*`, strings.Join(exts, "|")))
+	}
+	return n, nil
+}
+
+// Normalize parses a url, stripping a static file and what follows it.
+// Lines that are no url are kept as the host.
+func (n *Normalizer) Normalize(line string) URL {
+	u := URL{Raw: line, Query: url.Values{}}
+	parsed, err := url.Parse(line)
+	if err != nil {
+		u.Host = line
+	} else {
+		u.Host = parsed.Host
+		u.Path = parsed.Path
+		u.Query = parsed.Query()
+	}
+	if n.strip != nil {
+		u.Path = n.strip.ReplaceAllString(u.Path, "")
+		u.Raw = n.strip.ReplaceAllString(line, "")
+	}
+	return u
+}
+
+// Key returns the identity of the endpoint a url requests, urls with the
+// same key are duplicates
+func (n *Normalizer) Key(u URL) string {
+	var query string
+	switch n.query {
+	case QueryFull:
+		query = u.Query.Encode()
+	case QueryKeys:
+		keys := make([]string, 0, len(u.Query))
+		for key := range u.Query {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		query = strings.Join(keys, "&")
+	}
+	return u.Host + "\x00" + u.Path + "\x00" + query
+}
+
+// Duplicate reports whether b requests the same endpoint as a
+func (n *Normalizer) Duplicate(a, b URL) bool {
+	return n.Key(a) == n.Key(b)
+}
+
+// Seen tracks the endpoints of a stream of urls, only the url kept of
+// every endpoint is held in memory
+type Seen struct {
+	n    *Normalizer
+	urls map[string]seenURL
+}
+
+// seenURL is the line kept of an endpoint and its normalized url
+type seenURL struct {
+	line string
+	raw  string
+}
+
+// NewSeen returns an empty Seen
+func (n *Normalizer) NewSeen() *Seen {
+	return &Seen{n: n, urls: make(map[string]seenURL)}
+}
+
+// Add normalizes the line and returns its url, reporting false when an
+// url of the same endpoint was added before. The smallest line of an
+// endpoint is the one kept.
+func (s *Seen) Add(line string) (string, bool) {
+	u := s.n.Normalize(line)
+	key := s.n.Key(u)
+	if kept, ok := s.urls[key]; ok {
+		if line < kept.line {
+			s.urls[key] = seenURL{line: line, raw: u.Raw}
+		}
+		return u.Raw, false
+	}
+	s.urls[key] = seenURL{line: line, raw: u.Raw}
+	return u.Raw, true
+}
+
+// Len returns the number of unique endpoints added
+func (s *Seen) Len() int {
+	return len(s.urls)
+}
+
+// Sorted returns the url kept of every endpoint in the order of their
+// lines, as sorting the lines before deduplicating them would
+func (s *Seen) Sorted() []string {
+	kept := make([]seenURL, 0, len(s.urls))
+	for _, u := range s.urls {
+		kept = append(kept, u)
+	}
+	sort.Slice(kept, func(i, j int) bool {
+		return kept[i].line < kept[j].line
+	})
+	urls := make([]string, len(kept))
+	for i, u := range kept {
+		urls[i] = u.raw
+	}
+	return urls
+}
+
+// Dedup returns the normalized urls of the lines without duplicates, in
+// the order they were first seen
+func (n *Normalizer) Dedup(lines []string) []string {
+	seen := n.NewSeen()
+	var urls []string
+	for _, line := range lines {
+		if u, ok := seen.Add(line); ok {
+			urls = append(urls, u)
+		}
+	}
+	return urls
+}
@@ -0,0 +1,154 @@
+package urlnorm
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	if _, err := New(Options{Query: "bogus"}); err == nil {
+		t.Fatal("expected an error for an unknown query strategy")
+	}
+	n, err := New(Options{})
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if n.query != QueryKeys {
+		t.Fatalf("expected the keys strategy by default, got %s", n.query)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	t.Parallel()
+
+	n, err := New(Options{StripExtensions: DefaultStaticExtensions})
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+
+	tt := []struct {
+		testName string
+		line     string
+		host     string
+		path     string
+		raw      string
+	}{
+		{"Page", "http://example.com/login.php?next=/", "example.com", "/login.php", "http://example.com/login.php?next=/"},
+		{"Static file", "http://example.com/img/logo.PNG?v=2", "example.com", "/img/", "http://example.com/img/"},
+		{"Directory", "https://example.com/admin/", "example.com", "/admin/", "https://example.com/admin/"},
+		{"No url", "%zz", "%zz", "", "%zz"},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			u := n.Normalize(x.line)
+			if u.Host != x.host || u.Path != x.path || u.Raw != x.raw {
+				t.Fatalf("expected %s %s %s, got %s %s %s", x.host, x.path, x.raw, u.Host, u.Path, u.Raw)
+			}
+		})
+	}
+}
+
+func TestDedup(t *testing.T) {
+	t.Parallel()
+
+	lines := []string{
+		"http://example.com/a.php?id=1",
+		"http://example.com/a.php?id=2",
+		"http://example.com/a.php?id=1&x=2",
+		"http://example.com/a.php",
+		"http://example.com/a.php",
+		"http://example.com/img/a.png",
+		"http://example.com/img/b.jpg",
+		"http://other.com/a.php?id=1",
+	}
+
+	tt := []struct {
+		testName string
+		opts     Options
+		expected []string
+	}{
+		{"Keys", Options{StripExtensions: DefaultStaticExtensions, Query: QueryKeys}, []string{
+			"http://example.com/a.php?id=1",
+			"http://example.com/a.php?id=1&x=2",
+			"http://example.com/a.php",
+			"http://example.com/img/",
+			"http://other.com/a.php?id=1",
+		}},
+		{"Full", Options{StripExtensions: DefaultStaticExtensions, Query: QueryFull}, []string{
+			"http://example.com/a.php?id=1",
+			"http://example.com/a.php?id=2",
+			"http://example.com/a.php?id=1&x=2",
+			"http://example.com/a.php",
+			"http://example.com/img/",
+			"http://other.com/a.php?id=1",
+		}},
+		{"Ignore", Options{StripExtensions: DefaultStaticExtensions, Query: QueryIgnore}, []string{
+			"http://example.com/a.php?id=1",
+			"http://example.com/img/",
+			"http://other.com/a.php?id=1",
+		}},
+		{"No stripping", Options{Query: QueryIgnore}, []string{
+			"http://example.com/a.php?id=1",
+			"http://example.com/img/a.png",
+			"http://example.com/img/b.jpg",
+			"http://other.com/a.php?id=1",
+		}},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			n, err := New(x.opts)
+			if err != nil {
+				t.Fatalf("got error: %v", err)
+			}
+			if got := n.Dedup(lines); !reflect.DeepEqual(got, x.expected) {
+				t.Fatalf("expected %v, got %v", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestSeen(t *testing.T) {
+	t.Parallel()
+
+	n, err := New(Options{})
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	seen := n.NewSeen()
+	for _, line := range []string{"http://example.com/a?b=1&a=2", "http://example.com/a?a=3&b=4"} {
+		seen.Add(line)
+	}
+	if seen.Len() != 1 {
+		t.Fatalf("expected the parameter order to be ignored, got %d endpoints", seen.Len())
+	}
+	if _, ok := seen.Add("http://example.com/a?a=1"); !ok {
+		t.Fatal("expected other parameter names to be a new endpoint")
+	}
+}
+
+func TestSeenSorted(t *testing.T) {
+	t.Parallel()
+
+	n, err := New(Options{})
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	seen := n.NewSeen()
+	for _, line := range []string{"http://example.com/b", "http://example.com/a?z=1", "http://example.com/a?z=0", "#http://example.com/c"} {
+		seen.Add(line)
+	}
+	expected := []string{"#http://example.com/c", "http://example.com/a?z=0", "http://example.com/b"}
+	if got := seen.Sorted(); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %q, got %q", expected, got)
+	}
+}
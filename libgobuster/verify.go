@@ -0,0 +1,125 @@
+package libgobuster
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// verifyHitsThreads is the concurrency of the -verify-hits pass, low so
+// that the load of the scan no longer makes the server answer at random
+const verifyHitsThreads = 2
+
+// hitVerifier holds the matches of a scan back until -verify-hits
+// requested them again
+type hitVerifier struct {
+	mu      sync.Mutex
+	hits    []Result
+	dropped int
+}
+
+// holdHit keeps a match for the verification pass, it returns false for
+// the other results and when the hits are not verified
+func (g *Gobuster) holdHit(r Result) bool {
+	if g.verifier == nil || !r.Match {
+		return false
+	}
+	g.verifier.mu.Lock()
+	g.verifier.hits = append(g.verifier.hits, r)
+	g.verifier.mu.Unlock()
+	return true
+}
+
+// Stability returns the annotation of a hit reproduced by some of the
+// tries of -verify-hits, stable when all of them reproduced it
+func Stability(reproduced, tries int) string {
+	if reproduced == tries {
+		return fmt.Sprintf("stable %d/%d", reproduced, tries)
+	}
+	return fmt.Sprintf("flaky %d/%d", reproduced, tries)
+}
+
+// reproduce requests a hit -verify-hits times and returns how many times
+// the server answered with its status again
+func (g *Gobuster) reproduce(r *Result) int {
+	url := r.FullURL(g)
+	ro := g.NewRequestOptions(url)
+	if r.VirtualHost != "" {
+		ro = g.HostRequestOptions(url, r.VirtualHost)
+	}
+	// the hits of -head-first answered to HEAD carry no body
+	method := http.MethodGet
+	if g.Opts.HeadFirst && (r.Content == nil || *r.Content == "") {
+		method = http.MethodHead
+	}
+	reproduced := 0
+	for i := 0; i < g.Opts.VerifyHits; i++ {
+		resp, err := g.HTTP.makeRequest(method, url, ro)
+		if err == nil && resp.StatusCode == r.Status {
+			reproduced++
+		}
+	}
+	return reproduced
+}
+
+// verifyHits requests the held matches again once the scan is over and
+// sends the ones reproduced at least once, annotated with their
+// stability. The hits of a scan cut short are sent unverified.
+func (g *Gobuster) verifyHits() {
+	if g.verifier == nil {
+		return
+	}
+	g.verifier.mu.Lock()
+	hits := g.verifier.hits
+	g.verifier.hits = nil
+	g.verifier.mu.Unlock()
+	if len(hits) == 0 {
+		return
+	}
+	if g.context.Err() != nil {
+		for _, r := range hits {
+			g.resultChan <- r
+		}
+		return
+	}
+
+	if !g.Opts.Quiet {
+		g.Logf("[+] Verifying %d hits with %d requests each", len(hits), g.Opts.VerifyHits)
+	}
+	reproduced := make([]int, len(hits))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < verifyHitsThreads; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				reproduced[i] = g.reproduce(&hits[i])
+			}
+		}()
+	}
+	for i := range hits {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	dropped := 0
+	for i, r := range hits {
+		if reproduced[i] == 0 {
+			if g.Opts.Verbose {
+				g.Logf("[-] Dropped %s: status %d not reproduced", r.FullURL(g), r.Status)
+			}
+			dropped++
+			continue
+		}
+		r.SetMeta(MetaStability, Stability(reproduced[i], g.Opts.VerifyHits))
+		g.resultChan <- r
+	}
+	g.verifier.mu.Lock()
+	g.verifier.dropped += dropped
+	g.verifier.mu.Unlock()
+	if dropped > 0 && !g.Opts.Quiet {
+		g.Logf("[+] Dropped %d of %d hits not reproduced", dropped, len(hits))
+	}
+}
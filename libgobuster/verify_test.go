@@ -0,0 +1,111 @@
+package libgobuster
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestStability(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		reproduced int
+		tries      int
+		expected   string
+	}{
+		{3, 3, "stable 3/3"},
+		{1, 3, "flaky 1/3"},
+	}
+	for _, x := range tt {
+		x := x
+		t.Run(x.expected, func(t *testing.T) {
+			t.Parallel()
+
+			if got := Stability(x.reproduced, x.tries); got != x.expected {
+				t.Fatalf("expected %q, got %q", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestVerifyHits(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	requests := make(map[string]int)
+	h := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.Path]++
+		n := requests[r.URL.Path]
+		mu.Unlock()
+		switch {
+		case r.URL.Path == "/stable":
+		case r.URL.Path == "/flaky" && n == 2:
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer h.Close()
+
+	o := NewOptions()
+	o.URL = h.URL + "/"
+	o.VerifyHits = 3
+	o.Quiet = true
+	c, err := newHTTPClient(context.Background(), o)
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	g := &Gobuster{Opts: o, HTTP: c, context: context.Background(), mu: new(sync.RWMutex)}
+	g.resultChan = make(chan Result, 3)
+	g.verifier = &hitVerifier{hits: []Result{
+		{Entity: "stable", Status: 200, Content: new(string)},
+		{Entity: "flaky", Status: 200, Content: new(string)},
+		{Entity: "gone", Status: 200, Content: new(string)},
+	}}
+	g.verifyHits()
+	close(g.resultChan)
+
+	got := make(map[string]string)
+	for r := range g.resultChan {
+		got[r.Entity] = r.Metadata[MetaStability]
+	}
+	expected := map[string]string{"stable": "stable 3/3", "flaky": "flaky 1/3"}
+	if len(got) != len(expected) || got["stable"] != expected["stable"] || got["flaky"] != expected["flaky"] {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	if s := g.Summary(); s.DroppedHits != 1 {
+		t.Fatalf("expected 1 dropped hit, got %d", s.DroppedHits)
+	}
+}
+
+func TestHoldHit(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName string
+		verify   bool
+		match    bool
+		expected bool
+	}{
+		{"Hit", true, true, true},
+		{"Miss", true, false, false},
+		{"Not verified", false, true, false},
+	}
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			g := &Gobuster{Opts: NewOptions()}
+			if x.verify {
+				g.verifier = &hitVerifier{}
+			}
+			if got := g.holdHit(Result{Entity: "admin", Status: 200, Match: x.match}); got != x.expected {
+				t.Fatalf("expected %t, got %t", x.expected, got)
+			}
+		})
+	}
+}
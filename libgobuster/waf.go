@@ -0,0 +1,145 @@
+package libgobuster
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// wafSignature recognizes a CDN or WAF by its headers and cookies, and
+// its block or challenge pages by their content
+type wafSignature struct {
+	name string
+	// headers maps a header name to the pattern of its value
+	headers map[string]*regexp.Regexp
+	// cookies are cookie name prefixes
+	cookies []string
+	// block matches the content of the block and challenge pages
+	block *regexp.Regexp
+}
+
+var wafSignatures = []wafSignature{
+	{
+		name:    "Cloudflare",
+		headers: map[string]*regexp.Regexp{"Server": regexp.MustCompile(`(?i)^cloudflare`), "Cf-Ray": regexp.MustCompile(`.`), "Cf-Mitigated": regexp.MustCompile(`.`)},
+		cookies: []string{"__cf_bm", "cf_clearance", "__cfduid"},
+		block:   regexp.MustCompile(`(?i)attention required! \| cloudflare|just a moment\.\.\.|cf-chl-|cf-error-details|/cdn-cgi/challenge-platform/`),
+	},
+	{
+		name:    "Akamai",
+		headers: map[string]*regexp.Regexp{"Server": regexp.MustCompile(`(?i)akamaighost`), "Akamai-Grn": regexp.MustCompile(`.`), "X-Akamai-Transformed": regexp.MustCompile(`.`)},
+		cookies: []string{"ak_bmsc", "bm_sz", "_abck"},
+		block:   regexp.MustCompile(`(?is)<title>access denied</title>.*reference&#32;(?:#|&#35;)|errors\.edgesuite\.net`),
+	},
+	{
+		name:    "Sucuri",
+		headers: map[string]*regexp.Regexp{"Server": regexp.MustCompile(`(?i)sucuri`), "X-Sucuri-Id": regexp.MustCompile(`.`), "X-Sucuri-Block": regexp.MustCompile(`.`)},
+		block:   regexp.MustCompile(`(?i)sucuri website firewall|cloudproxy@sucuri\.net`),
+	},
+	{
+		name:    "Imperva",
+		headers: map[string]*regexp.Regexp{"X-Iinfo": regexp.MustCompile(`.`), "X-Cdn": regexp.MustCompile(`(?i)incapsula|imperva`)},
+		cookies: []string{"incap_ses_", "visid_incap_"},
+		block:   regexp.MustCompile(`(?i)incapsula incident id|_incapsula_resource`),
+	},
+	{
+		name:    "AWS CloudFront",
+		headers: map[string]*regexp.Regexp{"X-Amz-Cf-Id": regexp.MustCompile(`.`), "Via": regexp.MustCompile(`(?i)cloudfront`)},
+		block:   regexp.MustCompile(`(?i)request blocked\..*cloudfront|generated by cloudfront \(cloudfront\)`),
+	},
+	{
+		name:    "AWS WAF",
+		cookies: []string{"aws-waf-token"},
+		block:   regexp.MustCompile(`(?i)awswaf|aws-waf-token`),
+	},
+	{
+		name:    "Fastly",
+		headers: map[string]*regexp.Regexp{"X-Served-By": regexp.MustCompile(`(?i)cache-`), "Fastly-Debug-Digest": regexp.MustCompile(`.`)},
+	},
+	{
+		name:    "F5 BIG-IP ASM",
+		cookies: []string{"TS01", "BIGipServer"},
+		block:   regexp.MustCompile(`(?i)the requested url was rejected\. please consult with your administrator`),
+	},
+	{
+		name:    "ModSecurity",
+		headers: map[string]*regexp.Regexp{"Server": regexp.MustCompile(`(?i)mod_security|nyob`)},
+		block:   regexp.MustCompile(`(?i)mod_security|this error was generated by mod_security`),
+	},
+}
+
+// matchHeaders returns whether a header or a cookie of the response
+// matches the signature
+func (sig wafSignature) matchHeaders(header http.Header, cookies []string) bool {
+	for name, re := range sig.headers {
+		for _, value := range header.Values(name) {
+			if re.MatchString(value) {
+				return true
+			}
+		}
+	}
+	for _, prefix := range sig.cookies {
+		for _, name := range cookies {
+			if strings.HasPrefix(name, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// DetectWAF returns the CDN or WAF serving a response, if any, and
+// whether the response is one of its block or challenge pages rather
+// than the content of the application
+func DetectWAF(resp *Response) (string, bool) {
+	if resp == nil {
+		return "", false
+	}
+	cookies := cookieNames(resp.Header)
+	// a block page names its WAF even without the headers
+	for _, sig := range wafSignatures {
+		if sig.block != nil && resp.StatusCode >= http.StatusBadRequest && sig.block.MatchString(resp.Content) {
+			return sig.name, true
+		}
+	}
+	for _, sig := range wafSignatures {
+		if sig.matchHeaders(resp.Header, cookies) {
+			// Cloudflare flags its challenges in a header of their own
+			return sig.name, resp.Header.Get("Cf-Mitigated") != ""
+		}
+	}
+	return "", false
+}
+
+// LogWAF prints the CDN or WAF detected in front of the target, with a
+// warning when its base page is already a block page
+func (g *Gobuster) LogWAF(base *Response) {
+	name, block := DetectWAF(base)
+	if name == "" {
+		return
+	}
+	g.WAF = name
+	if block {
+		g.Logf("[!] The base page is a %s block page, the results will likely be the WAF's rather than the application's", name)
+		return
+	}
+	g.Logf("[+] CDN/WAF: %s", name)
+}
+
+// AnnotateWAF marks a result whose response is a block page of a CDN or
+// WAF, warning the first time the target starts blocking
+func (g *Gobuster) AnnotateWAF(r *Result, resp *Response) {
+	name, block := DetectWAF(resp)
+	if !block {
+		return
+	}
+	r.SetMeta(MetaWAF, name)
+
+	g.mu.Lock()
+	g.wafBlocks++
+	first := g.wafBlocks == 1
+	g.mu.Unlock()
+	if first {
+		g.Logf("[!] %s answered /%s with a block page, the next results may be the WAF's rather than the application's", name, r.Entity)
+	}
+}
@@ -0,0 +1,56 @@
+package libgobuster
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+)
+
+func TestDetectWAF(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName string
+		status   int
+		header   http.Header
+		content  string
+		name     string
+		block    bool
+	}{
+		{"Nothing", 200, http.Header{"Server": {"nginx"}}, "<html></html>", "", false},
+		{"Cloudflare ray", 200, http.Header{"Cf-Ray": {"7d1c2e3f4a5b6c7d-AMS"}}, "<html>app</html>", "Cloudflare", false},
+		{"Cloudflare challenge", 403, http.Header{"Server": {"cloudflare"}}, "<title>Just a moment...</title>", "Cloudflare", true},
+		{"Cloudflare mitigated", 200, http.Header{"Cf-Ray": {"abc"}, "Cf-Mitigated": {"challenge"}}, "", "Cloudflare", true},
+		{"Akamai header", 404, http.Header{"Server": {"AkamaiGHost"}}, "<html>not found</html>", "Akamai", false},
+		{"Akamai denied", 403, http.Header{}, "<HTML><HEAD><TITLE>Access Denied</TITLE></HEAD><BODY>Reference&#32;&#35;18.abc</BODY></HTML>", "Akamai", true},
+		{"Sucuri block", 403, http.Header{"X-Sucuri-Id": {"11005"}}, "<title>Sucuri WebSite Firewall - Access Denied</title>", "Sucuri", true},
+		{"Imperva cookie", 200, http.Header{"Set-Cookie": {"incap_ses_123=abc; path=/"}}, "", "Imperva", false},
+		{"Block marker on a match", 200, http.Header{}, "<p>Sucuri WebSite Firewall</p>", "", false},
+	}
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			name, block := DetectWAF(&Response{StatusCode: x.status, Header: x.header, Content: x.content})
+			if name != x.name || block != x.block {
+				t.Fatalf("expected %q and %t, got %q and %t", x.name, x.block, name, block)
+			}
+		})
+	}
+}
+
+func TestAnnotateWAF(t *testing.T) {
+	t.Parallel()
+
+	g := &Gobuster{mu: new(sync.RWMutex)}
+	r := &Result{Entity: "admin", Metadata: map[string]string{MetaAuth: "Basic"}}
+	g.AnnotateWAF(r, &Response{StatusCode: 403, Header: http.Header{"X-Sucuri-Block": {"1"}}, Content: "Sucuri WebSite Firewall"})
+	if r.Annotation() != "blocked by Sucuri; Basic" || g.wafBlocks != 1 {
+		t.Fatalf("unexpected annotation %q and %d blocks", r.Annotation(), g.wafBlocks)
+	}
+	g.AnnotateWAF(r, &Response{StatusCode: 200, Content: "<html>app</html>"})
+	if g.wafBlocks != 1 {
+		t.Fatalf("expected 1 block, got %d", g.wafBlocks)
+	}
+}
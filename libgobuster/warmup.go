@@ -0,0 +1,79 @@
+package libgobuster
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// warmup holds back the workers until the first requests of a scan,
+// sent over a few threads, have completed
+type warmup struct {
+	sem     chan struct{}
+	over    chan struct{}
+	threads int
+	logger  *log.Logger
+
+	mu        sync.Mutex
+	remaining int
+	total     int
+	finished  int
+	errors    int
+	latency   time.Duration
+}
+
+func newWarmup(requests, warmupThreads, threads int) *warmup {
+	return &warmup{
+		sem:       make(chan struct{}, warmupThreads),
+		over:      make(chan struct{}),
+		threads:   threads,
+		remaining: requests,
+		total:     requests,
+	}
+}
+
+// acquire blocks until the worker may send a request and reports whether
+// the request is part of the warm-up and has to be released
+func (w *warmup) acquire(c context.Context) bool {
+	select {
+	case <-w.over:
+		return false
+	case <-c.Done():
+		return false
+	case w.sem <- struct{}{}:
+	}
+
+	w.mu.Lock()
+	if w.remaining > 0 {
+		w.remaining--
+		w.mu.Unlock()
+		return true
+	}
+	w.mu.Unlock()
+
+	// the last warm-up requests are still running
+	<-w.sem
+	select {
+	case <-w.over:
+	case <-c.Done():
+	}
+	return false
+}
+
+// release records the outcome of a warm-up request and ramps up to the
+// full thread count once the last one completed
+func (w *warmup) release(took time.Duration, failed bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.finished++
+	w.latency += took
+	if failed {
+		w.errors++
+	}
+	<-w.sem
+	if w.finished == w.total {
+		orDefault(w.logger).Printf("[+] Warm-up done: %d requests, %s average latency, %d errors, ramping up to %d threads", w.total, (w.latency / time.Duration(w.total)).Round(time.Millisecond), w.errors, w.threads)
+		close(w.over)
+	}
+}
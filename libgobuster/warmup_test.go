@@ -0,0 +1,52 @@
+package libgobuster
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWarmup(t *testing.T) {
+	t.Parallel()
+
+	w := newWarmup(6, 2, 8)
+	var running, maxRunning, warm int32
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 3; j++ {
+				if !w.acquire(context.Background()) {
+					continue
+				}
+				atomic.AddInt32(&warm, 1)
+				n := atomic.AddInt32(&running, 1)
+				for {
+					m := atomic.LoadInt32(&maxRunning)
+					if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+						break
+					}
+				}
+				time.Sleep(5 * time.Millisecond)
+				atomic.AddInt32(&running, -1)
+				w.release(5*time.Millisecond, false)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if warm != 6 {
+		t.Fatalf("expected 6 warm-up requests, got %d", warm)
+	}
+	if maxRunning > 2 {
+		t.Fatalf("expected at most 2 concurrent warm-up requests, got %d", maxRunning)
+	}
+	select {
+	case <-w.over:
+	default:
+		t.Fatal("expected the warm-up to be over")
+	}
+}
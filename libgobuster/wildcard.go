@@ -0,0 +1,67 @@
+package libgobuster
+
+// WildcardBaseline is the response signature shared by paths which
+// should not exist, used to filter false positives
+type WildcardBaseline struct {
+	Status   int
+	ByTitle  bool
+	Title    string
+	ByLength bool
+	Length   int
+	// ByPathLength is set for error pages reflecting the path, whose
+	// length grows by Slope for every character of the path
+	ByPathLength bool
+	Intercept    int
+	Slope        int
+	// Content is the body of the first probe, requested as ContentURL,
+	// which -show-diff compares the results to
+	Content    string
+	ContentURL string
+}
+
+// minPathLengthPoints is the number of distinct path lengths needed to
+// trust a fit, any two points are on a line
+const minPathLengthPoints = 3
+
+// FitPathLength fits the content lengths of the responses to paths of
+// the given lengths on a line, it returns false unless every response
+// is exactly on it and the length grows with the path
+func FitPathLength(pathLengths, lengths []int) (intercept, slope int, ok bool) {
+	if len(pathLengths) != len(lengths) || len(pathLengths) == 0 {
+		return 0, 0, false
+	}
+	distinct := NewSet(pathLengths...)
+	if distinct.Len() < minPathLengthPoints {
+		return 0, 0, false
+	}
+
+	// the slope is given by any two points of different path lengths
+	p0, l0 := pathLengths[0], lengths[0]
+	for i := range pathLengths {
+		if pathLengths[i] == p0 {
+			continue
+		}
+		dl, dp := lengths[i]-l0, pathLengths[i]-p0
+		if dl%dp != 0 {
+			return 0, 0, false
+		}
+		slope = dl / dp
+		break
+	}
+	if slope < 1 {
+		return 0, 0, false
+	}
+	intercept = l0 - slope*p0
+	for i := range pathLengths {
+		if lengths[i] != intercept+slope*pathLengths[i] {
+			return 0, 0, false
+		}
+	}
+	return intercept, slope, true
+}
+
+// LengthFor returns the length of the wildcard response to a path of
+// the given length, modeled by FitPathLength
+func (b *WildcardBaseline) LengthFor(pathLength int) int {
+	return b.Intercept + b.Slope*pathLength
+}
@@ -0,0 +1,45 @@
+package libgobuster
+
+import "testing"
+
+func TestFitPathLength(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName    string
+		pathLengths []int
+		lengths     []int
+		intercept   int
+		slope       int
+		ok          bool
+	}{
+		{"Reflected once", []int{16, 8, 5, 11}, []int{116, 108, 105, 111}, 100, 1, true},
+		{"Reflected twice", []int{16, 8, 5}, []int{232, 216, 210}, 200, 2, true},
+		{"Repeated lengths", []int{16, 8, 16, 8, 5}, []int{116, 108, 116, 108, 105}, 100, 1, true},
+		{"Two lengths only", []int{16, 8, 16, 8}, []int{116, 108, 116, 108}, 0, 0, false},
+		{"Off the line", []int{16, 8, 5}, []int{116, 108, 107}, 0, 0, false},
+		{"Fractional slope", []int{16, 8, 4}, []int{108, 104, 102}, 0, 0, false},
+		{"Shrinking", []int{16, 8, 5}, []int{100, 108, 111}, 0, 0, false},
+		{"Mismatched", []int{16, 8}, []int{116}, 0, 0, false},
+	}
+
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			intercept, slope, ok := FitPathLength(x.pathLengths, x.lengths)
+			if ok != x.ok || intercept != x.intercept || slope != x.slope {
+				t.Fatalf("expected %d + %d (%t), got %d + %d (%t)", x.intercept, x.slope, x.ok, intercept, slope, ok)
+			}
+			if ok {
+				b := &WildcardBaseline{ByPathLength: true, Intercept: intercept, Slope: slope}
+				for i, p := range x.pathLengths {
+					if got := b.LengthFor(p); got != x.lengths[i] {
+						t.Fatalf("expected length %d for %d, got %d", x.lengths[i], p, got)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package libgobuster
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// archiveSuffixes are the archives a wordlist can be read from, the
+// member is given after a colon: lists.zip:common.txt
+var archiveSuffixes = []string{".zip", ".tar", ".tar.gz", ".tgz"}
+
+// splitWordlistPath splits a wordlist path into the file on disk and
+// the member of the archive, if any
+func splitWordlistPath(path string) (string, string) {
+	lower := strings.ToLower(path)
+	for i := range path {
+		if path[i] != ':' {
+			continue
+		}
+		for _, suffix := range archiveSuffixes {
+			if strings.HasSuffix(lower[:i], suffix) {
+				return path[:i], path[i+1:]
+			}
+		}
+	}
+	return path, ""
+}
+
+// wordlistFile returns the file on disk holding the wordlist
+func wordlistFile(path string) string {
+	file, _ := splitWordlistPath(path)
+	return file
+}
+
+// readCloser closes the underlying file along with the reader
+type readCloser struct {
+	io.Reader
+	close func() error
+}
+
+func (r readCloser) Close() error {
+	return r.close()
+}
+
+// openWordlist opens a plain or gzip compressed wordlist, or a member
+// of a zip or tar archive. An archive holding a single file does not
+// need the member to be named.
+func openWordlist(path string) (io.ReadCloser, error) {
+	file, member := splitWordlistPath(path)
+	lower := strings.ToLower(file)
+
+	if strings.HasSuffix(lower, ".zip") {
+		return openZipMember(file, member)
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	var r io.Reader = f
+	closeAll := f.Close
+	if strings.HasSuffix(lower, ".gz") || strings.HasSuffix(lower, ".tgz") {
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("failed to decompress %s: %v", file, err)
+		}
+		r = zr
+		closeAll = func() error {
+			zr.Close()
+			return f.Close()
+		}
+	}
+
+	if strings.HasSuffix(lower, ".tar") || strings.HasSuffix(lower, ".tar.gz") || strings.HasSuffix(lower, ".tgz") {
+		tr, names, err := findTarMember(tar.NewReader(r), member)
+		if err != nil {
+			closeAll()
+			return nil, fmt.Errorf("%s: %v", file, err)
+		}
+		if tr == nil {
+			closeAll()
+			if member == "" && len(names) == 1 {
+				// tar is read sequentially, start over at the only file
+				return openWordlist(file + ":" + names[0])
+			}
+			return nil, fmt.Errorf("%s: %v", file, memberError(member, names))
+		}
+		r = tr
+	}
+	return readCloser{Reader: r, close: closeAll}, nil
+}
+
+func openZipMember(file, member string) (io.ReadCloser, error) {
+	zr, err := zip.OpenReader(file)
+	if err != nil {
+		return nil, err
+	}
+	var found *zip.File
+	var files []*zip.File
+	var names []string
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		files = append(files, f)
+		names = append(names, f.Name)
+		if f.Name == member {
+			found = f
+		}
+	}
+	if member == "" && len(files) == 1 {
+		found = files[0]
+	}
+	if found == nil {
+		zr.Close()
+		return nil, fmt.Errorf("%s: %s", file, memberError(member, names))
+	}
+	rc, err := found.Open()
+	if err != nil {
+		zr.Close()
+		return nil, err
+	}
+	return readCloser{Reader: rc, close: func() error {
+		rc.Close()
+		return zr.Close()
+	}}, nil
+}
+
+// findTarMember advances the tar reader to the member, or returns the
+// names of the regular files when it is not found
+func findTarMember(tr *tar.Reader, member string) (io.Reader, []string, error) {
+	var names []string
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			return nil, names, nil
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if h.Typeflag != tar.TypeReg {
+			continue
+		}
+		if member != "" && h.Name == member {
+			return tr, nil, nil
+		}
+		names = append(names, h.Name)
+	}
+}
+
+func memberError(member string, names []string) error {
+	if member == "" {
+		return fmt.Errorf("name the wordlist of the archive after a colon, one of: %s", strings.Join(names, ", "))
+	}
+	return fmt.Errorf("no wordlist %s in the archive, one of: %s", member, strings.Join(names, ", "))
+}
+
+// isWord reports whether a trimmed line of a wordlist is requested. The
+// comments, starting with #, and the empty lines are neither requested
+// nor counted.
+func isWord(line string) bool {
+	return line != "" && !strings.HasPrefix(line, "#")
+}
+
+// scanWordlist is a wordlist of the scan and the paths its words are
+// requested as
+type scanWordlist struct {
+	name   string
+	path   string
+	expand func(word string) []string
+	weight func(word string) int
+}
+
+// scanWordlists returns the wordlists of the scan in the order they are
+// requested: -w, then -wordlist-dirs and -wordlist-files
+func (g *Gobuster) scanWordlists() []scanWordlist {
+	var lists []scanWordlist
+	if g.Opts.Wordlist != "" {
+		lists = append(lists, scanWordlist{name: "wordlist", path: g.Opts.Wordlist, expand: g.expandWord, weight: g.wordWeight})
+	}
+	if g.Opts.WordlistDirs != "" {
+		lists = append(lists, scanWordlist{name: "directory wordlist", path: g.Opts.WordlistDirs, expand: dirWords, weight: func(string) int { return 1 }})
+	}
+	if g.Opts.WordlistFiles != "" {
+		lists = append(lists, scanWordlist{name: "file wordlist", path: g.Opts.WordlistFiles, expand: g.fileWords, weight: func(word string) int { return len(g.fileWords(word)) }})
+	}
+	return lists
+}
+
+// expandWord returns the paths of a -w word, one per extension when it
+// holds the extension token
+func (g *Gobuster) expandWord(word string) []string {
+	return expandExtToken(word, g.Opts.ExtToken, g.Opts.ExtensionsParsed, g.Opts.BlankExtension)
+}
+
+// dirWords returns the path of a -wordlist-dirs word, with a trailing
+// slash
+func dirWords(word string) []string {
+	return []string{strings.TrimSuffix(word, "/") + "/"}
+}
+
+// fileWords returns the paths of a -wordlist-files word: the word with
+// every extension, or as is when it has an extension of its own or no
+// extension is given
+func (g *Gobuster) fileWords(word string) []string {
+	if strings.Contains(word, g.Opts.ExtToken) {
+		return g.expandWord(word)
+	}
+	if path.Ext(word) != "" || g.Opts.ExtensionsParsed.Len() == 0 {
+		return []string{word}
+	}
+	var words []string
+	if g.Opts.BlankExtension {
+		words = append(words, word)
+	}
+	for _, ext := range g.Opts.ExtensionsParsed.Values() {
+		words = append(words, word+"."+ext)
+	}
+	return words
+}
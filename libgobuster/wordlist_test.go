@@ -0,0 +1,204 @@
+package libgobuster
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+)
+
+func writeTestArchives(t *testing.T, dir string) {
+	t.Helper()
+
+	gz, err := os.Create(filepath.Join(dir, "list.txt.gz"))
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	zw := gzip.NewWriter(gz)
+	zw.Write([]byte("gzipped\n"))
+	zw.Close()
+	gz.Close()
+
+	z, err := os.Create(filepath.Join(dir, "lists.zip"))
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	archive := zip.NewWriter(z)
+	for name, content := range map[string]string{"common.txt": "common\n", "big.txt": "big\n"} {
+		w, _ := archive.Create(name)
+		w.Write([]byte(content))
+	}
+	archive.Close()
+	z.Close()
+
+	tgz, err := os.Create(filepath.Join(dir, "single.tgz"))
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	tzw := gzip.NewWriter(tgz)
+	tw := tar.NewWriter(tzw)
+	tw.WriteHeader(&tar.Header{Name: "words/", Typeflag: tar.TypeDir, Mode: 0755})
+	tw.WriteHeader(&tar.Header{Name: "words/only.txt", Typeflag: tar.TypeReg, Mode: 0644, Size: 5})
+	tw.Write([]byte("only\n"))
+	tw.Close()
+	tzw.Close()
+	tgz.Close()
+}
+
+func TestOpenWordlist(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeTestArchives(t, dir)
+
+	tt := []struct {
+		testName string
+		path     string
+		expected string
+		err      bool
+	}{
+		{"Gzip", "list.txt.gz", "gzipped\n", false},
+		{"Zip member", "lists.zip:common.txt", "common\n", false},
+		{"Zip missing member", "lists.zip:missing.txt", "", true},
+		{"Zip without member", "lists.zip", "", true},
+		{"Tar member", "single.tgz:words/only.txt", "only\n", false},
+		{"Tar single file", "single.tgz", "only\n", false},
+	}
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			r, err := openWordlist(filepath.Join(dir, x.path))
+			if x.err {
+				if err == nil {
+					r.Close()
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got error: %v", err)
+			}
+			defer r.Close()
+			content, err := ioutil.ReadAll(r)
+			if err != nil {
+				t.Fatalf("got error: %v", err)
+			}
+			if string(content) != x.expected {
+				t.Fatalf("expected %q, got %q", x.expected, content)
+			}
+		})
+	}
+}
+
+func TestSplitWordlistPath(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		path   string
+		file   string
+		member string
+	}{
+		{"common.txt", "common.txt", ""},
+		{"lists.zip:common.txt", "lists.zip", "common.txt"},
+		{"lists.TAR.GZ:a:b.txt", "lists.TAR.GZ", "a:b.txt"},
+		{`C:\lists\common.txt`, `C:\lists\common.txt`, ""},
+	}
+	for _, x := range tt {
+		file, member := splitWordlistPath(x.path)
+		if file != x.file || member != x.member {
+			t.Fatalf("%s: expected %q and %q, got %q and %q", x.path, x.file, x.member, file, member)
+		}
+	}
+}
+
+func TestScanWordlists(t *testing.T) {
+	t.Parallel()
+
+	exts := NewSet[string]()
+	exts.AddRange([]string{"php", "bak"})
+
+	tt := []struct {
+		testName string
+		list     string
+		blank    bool
+		word     string
+		expected []string
+	}{
+		{"Directory", "dirs", false, "admin", []string{"admin/"}},
+		{"Directory with slash", "dirs", false, "admin/", []string{"admin/"}},
+		{"File", "files", false, "index", []string{"index.bak", "index.php"}},
+		{"File blank", "files", true, "index", []string{"index", "index.bak", "index.php"}},
+		{"File with extension", "files", true, "robots.txt", []string{"robots.txt"}},
+		{"File with token", "files", false, "config.%EXT%.old", []string{"config.bak.old", "config.php.old"}},
+	}
+	for _, x := range tt {
+		x := x
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			opt := NewOptions()
+			opt.ExtensionsParsed = exts
+			opt.BlankExtension = x.blank
+			opt.WordlistDirs = "dirs.txt"
+			opt.WordlistFiles = "files.txt"
+			g := &Gobuster{Opts: opt}
+			lists := g.scanWordlists()
+			if len(lists) != 2 {
+				t.Fatalf("expected 2 wordlists, got %d", len(lists))
+			}
+			list := lists[0]
+			if x.list == "files" {
+				list = lists[1]
+			}
+			got := list.expand(x.word)
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, x.expected) {
+				t.Fatalf("expected %v, got %v", x.expected, got)
+			}
+			if weight := list.weight(x.word); weight != len(got) {
+				t.Fatalf("expected a weight of %d, got %d", len(got), weight)
+			}
+		})
+	}
+}
+
+func TestCountWordlist(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "words.txt")
+	if err := ioutil.WriteFile(path, []byte("admin\n# comment\n\n  #indented comment\nindex.%EXT%\n"), 0600); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	opt := NewOptions()
+	opt.Wordlist = path
+	opt.ExtensionsParsed.AddRange([]string{"php", "bak"})
+	g := &Gobuster{Opts: opt, mu: new(sync.RWMutex), context: context.Background()}
+	counter, err := openWordlist(path)
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	// the comments and empty lines are no requests
+	g.countWordlist(g.scanWordlists(), []io.ReadCloser{counter})
+	if g.requestsExpected != 3 {
+		t.Fatalf("expected 3 requests, got %d", g.requestsExpected)
+	}
+}
+
+func TestFileWordsWithoutExtensions(t *testing.T) {
+	t.Parallel()
+
+	g := &Gobuster{Opts: NewOptions()}
+	if got := g.fileWords("index"); !reflect.DeepEqual(got, []string{"index"}) {
+		t.Fatalf("expected the word as is, got %v", got)
+	}
+}
@@ -0,0 +1,38 @@
+package libgobuster
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWorkerDrainsCancelled(t *testing.T) {
+	t.Parallel()
+
+	c, cancel := context.WithCancel(context.Background())
+	cancel()
+	g := &Gobuster{context: c, Opts: &Options{}, mu: new(sync.RWMutex)}
+	wordChan := make(chan *BusterTarget, 3)
+	for _, word := range []string{"a", "b", "c"} {
+		g.inFlight.Add(1)
+		wordChan <- &BusterTarget{Target: word}
+	}
+	close(wordChan)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go g.worker(wordChan, &wg)
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		g.inFlight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the targets queued before the cancellation were not drained")
+	}
+}
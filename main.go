@@ -1,315 +1,1259 @@
-package main
-
-//----------------------------------------------------
-// Gobuster -- by OJ Reeves
-//
-// A crap attempt at building something that resembles
-// dirbuster or dirb using Go. The goal was to build
-// a tool that would help learn Go and to actually do
-// something useful. The idea of having this compile
-// to native code is also appealing.
-//
-// Run: gobuster -h
-//
-// Please see THANKS file for contributors.
-// Please see LICENSE file for license details.
-//
-//----------------------------------------------------
-
-import (
-	"context"
-	"flag"
-	"fmt"
-	"log"
-	"net/url"
-	"os"
-	"os/signal"
-	"strings"
-	"sync"
-	"syscall"
-	"time"
-
-	"yBuster/gobusterdir"
-	"yBuster/gobusterdns"
-	"yBuster/libgobuster"
-
-	"github.com/gookit/color"
-	"golang.org/x/crypto/ssh/terminal"
-)
-
-func ruler() {
-	fmt.Println("===============================================================")
-}
-
-func banner() {
-	fmt.Printf("yBuster v%s              Custom by Y\n", libgobuster.VERSION)
-}
-
-func resultWorker(g *libgobuster.Gobuster, filename string, outputfolder string, wg *sync.WaitGroup) {
-	defer wg.Done()
-	var f *os.File
-	var af *os.File
-	var err error
-	var aerr error
-	var aerrz error
-
-	if len(outputfolder) == 0 {
-		log.Fatalf("Output folder cannot be null.")
-
-	} else {
-
-		if _, ferrz := os.Stat(outputfolder); os.IsNotExist(ferrz) {
-			errDir := os.MkdirAll(outputfolder, 0755)
-			if errDir != nil {
-				log.Fatalf("error on creating main output folder: %v", aerrz)
-			}
-		}
-		if _, ferrz := os.Stat(outputfolder + "/output_matches/"); os.IsNotExist(ferrz) {
-			errDir := os.MkdirAll(outputfolder + "/output_matches/", 0755)
-			if errDir != nil {
-				log.Fatalf("error on creating matches output folder: %v", aerrz)
-			}
-		}
-		if _, ferrz := os.Stat(outputfolder + "/output_waybackurls/"); os.IsNotExist(ferrz) {
-			errDir := os.MkdirAll(outputfolder + "/output_waybackurls/", 0755)
-			if errDir != nil {
-				log.Fatalf("error on creating waybackurls output folder: %v", aerrz)
-			}
-		}
-	}
-
-
-	if filename != "" {
-		f, err = os.Create(outputfolder + "/" + filename)
-		if err != nil {
-			log.Fatalf("error on creating output file: %v", err)
-		}
-	} else {
-		filenameTimeStamp := int32(time.Now().Unix())
-		parsedMainURL, _ := url.Parse(g.Opts.URL)
-		sanitizedHost := strings.ReplaceAll(parsedMainURL.Host, ".", "_")
-		sanitizedHost = strings.ReplaceAll(sanitizedHost, ":", "_")
-		sanitizedPath := ""
-		if parsedMainURL.Path != "/" {
-			sanitizedPath = strings.TrimSuffix(parsedMainURL.Path, "/")
-			sanitizedPath = strings.ReplaceAll(sanitizedPath, "/", "_")
-		}
-
-		autoFilename := fmt.Sprintf(outputfolder + "/output_matches/matches_%d_%s_%s%s.txt", filenameTimeStamp, parsedMainURL.Scheme, sanitizedHost, sanitizedPath)
-		f, err = os.Create(autoFilename)
-		if err != nil {
-			log.Fatalf("error on creating output file: %v", err)
-		}
-	}
-
-
- 
-
-	if _, ferr := os.Stat(outputfolder +"/all_time_matches.txt"); os.IsNotExist(ferr) {
-		af, aerr = os.Create(outputfolder + "/all_time_matches.txt")
-		if aerr != nil {
-			log.Fatalf("error on creating all time matches file: %v", aerr)
-		}
-	} else {
-		af, aerr = os.OpenFile(outputfolder + "/all_time_matches.txt", os.O_APPEND|os.O_WRONLY, 0600)
-		if aerr != nil {
-			log.Fatalf("error on opening all time matches file: %v", aerr)
-		}
-	}
-	defer af.Close()
-
-	for r := range g.Results() {
-		s, as, status, err := r.ToString(g)
-		if err != nil {
-			log.Fatal(err)
-		}
-		if s != "" {
-			g.ClearProgress()
-			s = strings.TrimSpace(s)
-			c := color.Style{color.White}
-			if status == 200 {
-				c = color.Style{color.FgGreen, color.OpBold}
-			} else if status == 301 || status == 302 {
-				c = color.Style{color.FgYellow, color.OpBold}
-			} else if status == 400 {
-				c = color.Style{color.FgWhite, color.OpBold}
-			} else if status == 401 {
-				c = color.Style{color.FgCyan, color.OpBold}
-			} else if status == 403 {
-				c = color.Style{color.FgMagenta, color.OpBold}
-			} else if status == 500 {
-				c = color.Style{color.FgRed, color.OpBold}
-			}
-			c.Println(s)
-			if f != nil {
-				err = writeToFile(f, s)
-				if err != nil {
-					log.Fatalf("error on writing output file: %v", err)
-				}
-			}
-		}
-		if as != "" {
-			as = strings.TrimSpace(as)
-			if af != nil {
-				werr := writeToFile(af, as)
-				if werr != nil {
-					log.Fatalf("error on writing all time matches file: %v", err)
-				}
-			}
-		}
-	}
-}
-
-func errorWorker(g *libgobuster.Gobuster, wg *sync.WaitGroup) {
-	defer wg.Done()
-	for e := range g.Errors() {
-		g.IncrementErrorCount()
-		g.DecrementRequests()
-		if !g.Opts.Quiet {
-			g.ClearProgress()
-			if g.Opts.Verbose {
-				log.Printf("[!] %v", e)
-			}
-		}
-	}
-}
-
-func progressWorker(c context.Context, g *libgobuster.Gobuster) {
-	tick := time.NewTicker(1 * time.Second)
-
-	for {
-		select {
-		case <-tick.C:
-			g.PrintProgress()
-		case <-c.Done():
-			return
-		}
-	}
-}
-
-func writeToFile(f *os.File, output string) error {
-	_, err := f.WriteString(fmt.Sprintf("%s\n", output))
-	if err != nil {
-		return fmt.Errorf("[!] Unable to write to file %v", err)
-	}
-	return nil
-}
-
-func main() {
-	// var outputFilename string
-	o := libgobuster.NewOptions()
-	flag.IntVar(&o.Threads, "t", 10, "Number of concurrent threads")
-	flag.StringVar(&o.Mode, "m", "dir", "Directory/File mode (dir)")
-	flag.StringVar(&o.Wordlist, "w", "", "Path to the wordlist")
-	flag.StringVar(&o.OutputFolder, "of", "", "Path to output folder directory")
-	flag.StringVar(&o.ExcludedStatusCodes, "x", "", "Excluded status codes (dir mode only)")
-	flag.StringVar(&o.OutputFilename, "o", "", "Output file to write results to (defaults to stdout)")
-	flag.StringVar(&o.URL, "u", "", "The target URL or Domain")
-	flag.StringVar(&o.Cookies, "c", "", "Cookies to use for the requests (dir mode only)")
-	flag.StringVar(&o.Username, "U", "", "Username for Basic Auth (dir mode only)")
-	flag.StringVar(&o.Password, "P", "", "Password for Basic Auth (dir mode only)")
-	flag.StringVar(&o.Extensions, "ext", "", "File extension(s) to search for (dir mode only)")
-	flag.StringVar(&o.UserAgent, "a", "", "Set the User-Agent string (dir mode only)")
-	flag.StringVar(&o.Proxy, "p", "", "Proxy to use for requests [http(s)://host:port] (dir mode only)")
-	flag.DurationVar(&o.Timeout, "to", 10*time.Second, "HTTP Timeout in seconds (dir mode only)")
-	flag.BoolVar(&o.Verbose, "v", false, "Verbose output (errors)")
-	flag.BoolVar(&o.ShowIPs, "i", false, "Show IP addresses (dns mode only)")
-	flag.BoolVar(&o.ShowCNAME, "cn", false, "Show CNAME records (dns mode only, cannot be used with '-i' option)")
-	flag.BoolVar(&o.FollowRedirect, "r", false, "Follow redirects")
-	flag.BoolVar(&o.Quiet, "q", false, "Don't print the banner and other noise")
-	flag.BoolVar(&o.Expanded, "e", false, "Expanded mode, print full URLs")
-	flag.BoolVar(&o.NoStatus, "n", false, "Don't print status codes")
-	flag.BoolVar(&o.IncludeLength, "l", false, "Include the length of the body in the output (dir mode only)")
-	flag.BoolVar(&o.UseSlash, "f", false, "Append a forward-slash to each directory request (dir mode only)")
-	flag.BoolVar(&o.WildcardForced, "fw", false, "Force continued operation when wildcard found")
-	flag.BoolVar(&o.InsecureSSL, "k", false, "Skip SSL certificate verification")
-	flag.BoolVar(&o.NoProgress, "np", false, "Don't display progress")
-	flag.StringVar(&o.WaybackUrls, "waybackurls", "", "Path to the wayback urls")
-	flag.StringVar(&o.TargetUrls, "targeturls", "", "Path to the target urls")
-	flag.StringVar(&o.RandomAgent, "random-agent", "", "Path to the random agent file")
-	flag.StringVar(&o.ExcludeString, "xs", "", "Response content string to exclude")
-	flag.BoolVar(&o.BlankExtension, "be", false, "Request word without extension")
-
-	flag.Parse()
-
-	// Prompt for PW if not provided
-	if o.Username != "" && o.Password == "" {
-		fmt.Printf("[?] Auth Password: ")
-		passBytes, err := terminal.ReadPassword(int(syscall.Stdin))
-		// print a newline to simulate the newline that was entered
-		// this means that formatting/printing after doesn't look bad.
-		fmt.Println("")
-		if err != nil {
-			log.Fatal("[!] Auth username given but reading of password failed")
-		}
-		o.Password = string(passBytes)
-	}
-
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	var plugin libgobuster.GobusterPlugin
-	switch o.Mode {
-	case libgobuster.ModeDir:
-		plugin = gobusterdir.GobusterDir{}
-	case libgobuster.ModeDNS:
-		plugin = gobusterdns.GobusterDNS{}
-	}
-
-	gobuster, err := libgobuster.NewGobuster(ctx, o, plugin)
-	if err != nil {
-		log.Fatalf("[!] %v", err)
-	}
-
-	if !o.Quiet {
-		fmt.Println("")
-		ruler()
-		banner()
-		ruler()
-		c, err := gobuster.GetConfigString()
-		if err != nil {
-			log.Fatalf("error on creating config string: %v", err)
-		}
-		fmt.Println(c)
-		ruler()
-		log.Println("Starting yBuster")
-		ruler()
-	}
-
-	signalChan := make(chan os.Signal, 1)
-	signal.Notify(signalChan, os.Interrupt)
-	go func() {
-		for range signalChan {
-			// caught CTRL+C
-			if !gobuster.Opts.Quiet {
-				fmt.Println("\n[!] Keyboard interrupt detected, terminating.")
-			}
-			cancel()
-		}
-	}()
-
-	var wg sync.WaitGroup
-	wg.Add(2)
-	go errorWorker(gobuster, &wg)
-	go resultWorker(gobuster, o.OutputFilename, o.OutputFolder, &wg)
-
-	if !o.Quiet && !o.NoProgress {
-		go progressWorker(ctx, gobuster)
-	}
-
-	if err := gobuster.Start(); err != nil {
-		log.Printf("[!] %v", err)
-	} else {
-		// call cancel func to free ressources and stop progressFunc
-		cancel()
-		// wait for all output funcs to finish
-		wg.Wait()
-	}
-
-	if !o.Quiet {
-		gobuster.ClearProgress()
-		ruler()
-		log.Println("Finished")
-		ruler()
-	}
-}
+package main
+
+//----------------------------------------------------
+// Gobuster -- by OJ Reeves
+//
+// A crap attempt at building something that resembles
+// dirbuster or dirb using Go. The goal was to build
+// a tool that would help learn Go and to actually do
+// something useful. The idea of having this compile
+// to native code is also appealing.
+//
+// Run: gobuster -h
+//
+// Please see THANKS file for contributors.
+// Please see LICENSE file for license details.
+//
+//----------------------------------------------------
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gosirys/gobuster/gobusterdir"
+	"github.com/gosirys/gobuster/gobusterdns"
+	"github.com/gosirys/gobuster/libgobuster"
+
+	"github.com/gookit/color"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// exit codes so automation can branch on the outcome of a run
+const (
+	exitFindings     = 0
+	exitNoFindings   = 1
+	exitOptionsError = 2
+	exitAborted      = 3
+)
+
+// exitError ends the run with its exit code, main logs its message and
+// exits once the deferred cleanups and flushes ran
+type exitError struct {
+	code    int
+	message string
+}
+
+func (e *exitError) Error() string {
+	return e.message
+}
+
+// fail returns the error ending the run with the given exit code
+func fail(code int, format string, v ...interface{}) error {
+	return &exitError{code: code, message: fmt.Sprintf(format, v...)}
+}
+
+// errUsage ends a run whose usage was printed
+var errUsage = &exitError{code: exitOptionsError}
+
+func ruler() {
+	fmt.Println("===============================================================")
+}
+
+// isTerminal reports whether the file is a terminal
+func isTerminal(f *os.File) bool {
+	return terminal.IsTerminal(int(f.Fd()))
+}
+
+func banner() {
+	fmt.Printf("yBuster v%s              Custom by Y\n", libgobuster.VERSION)
+}
+
+// resultWorker prints and records the results of a scan. An error on
+// writing them cancels the scan, the results left are drained so it
+// ends.
+func resultWorker(g *libgobuster.Gobuster, filename string, outputfolder string, cancel context.CancelFunc) error {
+	err := writeResults(g, filename, outputfolder)
+	if err != nil {
+		cancel()
+		for range g.Results() {
+		}
+	}
+	return err
+}
+
+// writeResults prints the results and writes them to the output folder
+func writeResults(g *libgobuster.Gobuster, filename string, outputfolder string) error {
+	var f *libgobuster.RotatingFile
+	var af *libgobuster.RotatingFile
+	var err error
+	var aerr error
+
+	if len(outputfolder) == 0 {
+		return fail(exitOptionsError, "Output folder cannot be null.")
+	}
+
+	// dns mode keeps its results apart from the dir mode matches
+	matchesFolder, matchesPrefix, allTimeFilename := "output_matches", "matches", "all_time_matches.txt"
+	if g.Opts.Mode == libgobuster.ModeDNS {
+		matchesFolder, matchesPrefix, allTimeFilename = "output_subdomains", "subdomains", "all_time_subdomains.txt"
+	}
+
+	if _, ferrz := os.Stat(outputfolder); os.IsNotExist(ferrz) {
+		errDir := os.MkdirAll(outputfolder, 0755)
+		if errDir != nil {
+			return fail(exitAborted, "error on creating main output folder: %v", errDir)
+		}
+	}
+	if _, ferrz := os.Stat(outputfolder + "/" + matchesFolder + "/"); os.IsNotExist(ferrz) {
+		errDir := os.MkdirAll(outputfolder+"/"+matchesFolder+"/", 0755)
+		if errDir != nil {
+			return fail(exitAborted, "error on creating matches output folder: %v", errDir)
+		}
+	}
+	if g.Opts.Mode == libgobuster.ModeDir {
+		if _, ferrz := os.Stat(outputfolder + "/output_waybackurls/"); os.IsNotExist(ferrz) {
+			errDir := os.MkdirAll(outputfolder+"/output_waybackurls/", 0755)
+			if errDir != nil {
+				return fail(exitAborted, "error on creating waybackurls output folder: %v", errDir)
+			}
+		}
+	}
+
+	// every sink rotates once it outgrows -rotate-size
+	rotateSize := g.Opts.RotateSizeParsed
+	if filename != "" {
+		// a named output file starts over on every run
+		if err = os.Truncate(outputfolder+"/"+filename, 0); err != nil && !os.IsNotExist(err) {
+			return fail(exitAborted, "error on creating output file: %v", err)
+		}
+		f, err = libgobuster.OpenRotatingFile(outputfolder+"/"+filename, rotateSize)
+		if err != nil {
+			return fail(exitAborted, "error on creating output file: %v", err)
+		}
+	} else {
+		autoFilename := fmt.Sprintf("%s/%s/%s_%s.txt", outputfolder, matchesFolder, matchesPrefix, g.OutputFileSuffix())
+		f, err = libgobuster.OpenRotatingFile(autoFilename, rotateSize)
+		if err != nil {
+			return fail(exitAborted, "error on creating output file: %v", err)
+		}
+	}
+	defer f.Close()
+
+	af, aerr = libgobuster.OpenRotatingFile(outputfolder+"/"+allTimeFilename, rotateSize)
+	if aerr != nil {
+		return fail(exitAborted, "error on opening all time matches file: %v", aerr)
+	}
+	defer af.Close()
+
+	var seen *libgobuster.SeenIndex
+	if g.Opts.AllTimeDedup {
+		if seen, err = libgobuster.LoadSeenIndex(outputfolder + "/" + allTimeFilename); err != nil {
+			return fail(exitAborted, "error on reading all time matches file: %v", err)
+		}
+	}
+
+	var listings *listingFile
+	defer func() {
+		if listings != nil {
+			listings.Close()
+		}
+	}()
+
+	profileWritten := false
+	httpx := g.Opts.Format == libgobuster.FormatHTTPX || g.Opts.Format == libgobuster.FormatHTTPXAnnotated
+	for r := range g.Results() {
+		s, as, status, err := r.ToString(g)
+		if err != nil {
+			return fail(exitAborted, "%v", err)
+		}
+		seenOn := ""
+		if seen != nil && as != "" {
+			if date, ok := seen.Seen(strings.TrimSpace(as)); ok {
+				seenOn = date
+			}
+		}
+		if httpx {
+			// only the matches are printed, as bare lines without colors
+			s = ""
+			if as != "" {
+				g.ClearProgress()
+				line := r.HTTPXLine(g)
+				fmt.Println(line)
+				if f != nil {
+					if err := writeToFile(f, line); err != nil {
+						return fail(exitAborted, "error on writing output file: %v", err)
+					}
+				}
+			}
+		}
+		if s != "" {
+			g.ClearProgress()
+			s = strings.TrimSpace(s)
+			if seenOn != "" {
+				s += fmt.Sprintf("  [seen before on %s]", seenOn)
+			}
+			c := color.Style{color.White}
+			if status == 200 {
+				c = color.Style{color.FgGreen, color.OpBold}
+			} else if status >= 300 && status < 400 {
+				c = color.Style{color.FgYellow, color.OpBold}
+			} else if status == 400 {
+				c = color.Style{color.FgWhite, color.OpBold}
+			} else if status == 401 {
+				c = color.Style{color.FgCyan, color.OpBold}
+			} else if status == 403 {
+				c = color.Style{color.FgMagenta, color.OpBold}
+			} else if status == 500 {
+				c = color.Style{color.FgRed, color.OpBold}
+			}
+			c.Println(s)
+			if f != nil {
+				// dns mode records every column in the per-run file
+				if g.Opts.Mode == libgobuster.ModeDNS && r.Status != 404 {
+					s = r.DNSRecord()
+				}
+				err = writeToFile(f, s)
+				if err != nil {
+					return fail(exitAborted, "error on writing output file: %v", err)
+				}
+			}
+		}
+		if as != "" {
+			g.RecordMatch(&r)
+			g.RunResultHook(&r)
+			as = strings.TrimSpace(as)
+			if werr := g.RecordTagMatch(&r); werr != nil {
+				return fail(exitAborted, "error on writing tag matches file: %v", werr)
+			}
+			g.SyslogResult(&r, as)
+			g.StreamResult(&r)
+			if r.Listable {
+				if listings == nil {
+					if listings, err = openListingFile(outputfolder+"/"+matchesFolder+"/listable_dirs.txt", rotateSize); err != nil {
+						return err
+					}
+				}
+				if werr := listings.Add(r.FullURL(g)); werr != nil {
+					return fail(exitAborted, "error on writing listable dirs file: %v", werr)
+				}
+			}
+			as += g.Provenance()
+			// -all-time-dedup records an endpoint and status once
+			if af != nil && seenOn == "" {
+				// describe the options once per run so the profile
+				// fingerprint of the lines can be looked up later
+				if !profileWritten {
+					if werr := writeToFile(af, g.ProfileLine()); werr != nil {
+						return fail(exitAborted, "error on writing all time matches file: %v", werr)
+					}
+					profileWritten = true
+				}
+				werr := writeToFile(af, as)
+				if werr != nil {
+					return fail(exitAborted, "error on writing all time matches file: %v", werr)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func errorWorker(g *libgobuster.Gobuster, wg *sync.WaitGroup) {
+	defer wg.Done()
+	for e := range g.Errors() {
+		g.RecordError(e)
+		g.DecrementRequests()
+		if !g.Opts.Quiet {
+			g.ClearProgress()
+			if g.Opts.Verbose {
+				log.Printf("[!] %v", e)
+			}
+		}
+	}
+}
+
+func progressWorker(c context.Context, g *libgobuster.Gobuster) {
+	tick := time.NewTicker(1 * time.Second)
+
+	for {
+		select {
+		case <-tick.C:
+			g.PrintProgress()
+		case <-c.Done():
+			return
+		}
+	}
+}
+
+func writeToFile(f io.Writer, output string) error {
+	_, err := fmt.Fprintf(f, "%s\n", output)
+	if err != nil {
+		return fmt.Errorf("[!] Unable to write to file %v", err)
+	}
+	return nil
+}
+
+// listingFile appends the urls of directory listings to a file shared
+// by every run, once per url
+type listingFile struct {
+	*libgobuster.RotatingFile
+	seen libgobuster.Set[string]
+}
+
+func openListingFile(filename string, rotateSize int64) (*listingFile, error) {
+	l := &listingFile{seen: libgobuster.NewSet[string]()}
+	if r, err := libgobuster.OpenRotated(filename); err == nil {
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			l.seen.Add(strings.TrimSpace(scanner.Text()))
+		}
+		r.Close()
+	}
+	f, err := libgobuster.OpenRotatingFile(filename, rotateSize)
+	if err != nil {
+		return nil, fail(exitAborted, "error on opening listable dirs file: %v", err)
+	}
+	l.RotatingFile = f
+	return l, nil
+}
+
+// Add records the url unless it was found before
+func (l *listingFile) Add(url string) error {
+	if !l.seen.Add(url) {
+		return nil
+	}
+	return writeToFile(l.RotatingFile, url)
+}
+
+// historyCommand prints when and under which options a path (or
+// subdomain) was first and last recorded in the all time matches
+func historyCommand(args []string) (int, error) {
+	fs := flag.NewFlagSet("history", flag.ExitOnError)
+	outputFolder := fs.String("of", "", "Path to output folder directory")
+	mode := fs.String("m", libgobuster.ModeDir, "Mode of the all time matches to search (dir, dns)")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s history [options] <path>\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return exitOptionsError, nil
+	}
+	if fs.NArg() != 1 || *outputFolder == "" {
+		fs.Usage()
+		return exitOptionsError, nil
+	}
+
+	filename := *outputFolder + "/all_time_matches.txt"
+	if *mode == libgobuster.ModeDNS {
+		filename = *outputFolder + "/all_time_subdomains.txt"
+	}
+	f, err := libgobuster.OpenRotated(filename)
+	if err != nil {
+		return 0, fail(exitOptionsError, "[!] %v", err)
+	}
+	defer f.Close()
+
+	h, err := libgobuster.ReadHistory(f, fs.Arg(0))
+	if err != nil {
+		return 0, fail(exitAborted, "[!] error on reading %s: %v", filename, err)
+	}
+	if h.Seen == 0 {
+		fmt.Printf("%s was never recorded in %s\n", h.Entity, filename)
+		return exitNoFindings, nil
+	}
+
+	fmt.Printf("[+] %-22s: %s\n", "Entity", h.Entity)
+	fmt.Printf("[+] %-22s: %d times\n", "Seen", h.Seen)
+	for _, e := range []struct {
+		label string
+		entry *libgobuster.HistoryEntry
+	}{{"First seen", h.First}, {"Last seen", h.Last}} {
+		fmt.Printf("[+] %-22s: %s\n", e.label, e.entry.Date)
+		fmt.Printf("    %-22s: %s\n", "Line", e.entry.Line)
+		if e.entry.RunID == "" {
+			fmt.Printf("    %-22s: %s\n", "Run", "unknown (recorded before provenance tracking)")
+			continue
+		}
+		fmt.Printf("    %-22s: %s\n", "Run", e.entry.RunID)
+		profile, ok := h.Profiles[e.entry.Profile]
+		if !ok {
+			profile = "unknown"
+		}
+		fmt.Printf("    %-22s: %s (%s)\n", "Profile", e.entry.Profile, profile)
+	}
+	return exitFindings, nil
+}
+
+// progress draws the progress of every scan of the run
+var progress *libgobuster.ProgressRenderer
+
+// runScan runs a single dir or dns scan to completion and prints its summary
+func runScan(ctx context.Context, o *libgobuster.Options) (*libgobuster.Gobuster, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	newScan := gobusterdir.New
+	if o.Mode == libgobuster.ModeDNS {
+		newScan = gobusterdns.New
+	}
+	gobuster, err := newScan(ctx, o)
+	if err != nil {
+		return nil, &newScanError{err: err}
+	}
+
+	gobuster.Progress = progress
+	progress.Track(gobuster)
+
+	if pendingProfile != nil {
+		// the options are validated by the first scan of the run
+		if err := pendingProfile.Save(o.OutputFolder); err != nil {
+			return gobuster, fail(exitAborted, "[!] %v", err)
+		}
+		log.Printf("[+] Saved profile %s", pendingProfile.Name)
+		pendingProfile = nil
+	}
+
+	if pendingCommand != nil {
+		path, err := pendingCommand.Write(o.OutputFolder, os.Args[0], gobuster.OutputFileSuffix())
+		if err != nil {
+			return gobuster, fail(exitAborted, "[!] %v", err)
+		}
+		fmt.Println(pendingCommand.CommandLine(os.Args[0]))
+		log.Printf("[+] Saved command to %s", path)
+		pendingCommand = nil
+	}
+
+	if !o.Quiet {
+		// the findings of the setup such as the favicon hash are part
+		// of the config, its error is returned again by Start
+		_ = gobuster.Setup()
+		c, err := gobuster.GetConfigString()
+		if err != nil {
+			return gobuster, fail(exitAborted, "error on creating config string: %v", err)
+		}
+		fmt.Println(c)
+		ruler()
+		log.Println("Starting yBuster")
+		ruler()
+	}
+
+	var wg sync.WaitGroup
+	var resultErr error
+	wg.Add(2)
+	go errorWorker(gobuster, &wg)
+	go func() {
+		defer wg.Done()
+		resultErr = resultWorker(gobuster, o.OutputFilename, o.OutputFolder, cancel)
+	}()
+
+	if !o.Quiet && !o.NoProgress {
+		go progressWorker(ctx, gobuster)
+	}
+
+	err = gobuster.Start()
+	if err != nil {
+		log.Printf("[!] %v", err)
+	}
+	progress.Finish(gobuster)
+	// call cancel func to free ressources and stop progressFunc
+	cancel()
+	// Start closes the output channels whatever its error, the last
+	// results are flushed before going on
+	wg.Wait()
+	gobuster.WaitResultHooks()
+	// the results which could not be written end the run
+	if resultErr != nil {
+		err = resultErr
+	}
+	if !stopsRun(err) {
+		if err := gobuster.WriteSummaryJSON(); err != nil {
+			log.Printf("[!] %v", err)
+		}
+		if err := gobuster.WriteOpenAPI(); err != nil {
+			log.Printf("[!] %v", err)
+		}
+		gobuster.SyslogSummary()
+	}
+
+	if !o.Quiet {
+		gobuster.ClearProgress()
+		ruler()
+		summary, err := gobuster.GetSummaryString()
+		if err != nil {
+			return gobuster, fail(exitAborted, "error on creating summary string: %v", err)
+		}
+		fmt.Println(summary)
+		ruler()
+		log.Println("Finished")
+		ruler()
+	}
+	return gobuster, err
+}
+
+// newScanError is the error of a scan which could not be created, its
+// options are invalid for the target
+type newScanError struct {
+	err error
+}
+
+func (e *newScanError) Error() string {
+	return e.err.Error()
+}
+
+// stopsRun reports whether the error of one target's scan ends a run
+// over many targets, an aborted, timed out or robots.txt disallowed
+// target does not
+func stopsRun(err error) bool {
+	return err != nil && err != libgobuster.ErrErrorThreshold && err != libgobuster.ErrTargetTimeout && err != libgobuster.ErrRobotsDisallowAll
+}
+
+// runDirScan runs dir mode against the base url and returns its matches
+func runDirScan(ctx context.Context, o *libgobuster.Options, base string) (int, error) {
+	dirOpts := *o
+	dirOpts.Mode = libgobuster.ModeDir
+	dirOpts.URL = base
+	g, err := runScan(ctx, &dirOpts)
+	if g == nil {
+		return 0, err
+	}
+	return g.MatchCount(), err
+}
+
+// probeWebPorts checks the -web-probe-ports of the subdomains found by a
+// dns scan and returns the urls of their web servers
+func probeWebPorts(ctx context.Context, g *libgobuster.Gobuster) []string {
+	urls, err := g.ProbeWebPorts(ctx)
+	if err != nil {
+		log.Printf("[!] %v", err)
+	}
+	if !g.Opts.Quiet {
+		log.Printf("[+] %d web servers found on the ports %s of %d subdomains", len(urls), g.Opts.WebProbePorts, len(g.FoundHosts()))
+	}
+	return urls
+}
+
+// hostQueue hands the hosts found by a stage of dns+dir to the next one,
+// the stage pushing them never waits
+type hostQueue struct {
+	mu     sync.Mutex
+	hosts  []string
+	closed bool
+	ready  chan struct{}
+}
+
+func newHostQueue() *hostQueue {
+	return &hostQueue{ready: make(chan struct{}, 1)}
+}
+
+func (q *hostQueue) signal() {
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
+
+// push queues a host
+func (q *hostQueue) push(host string) {
+	q.mu.Lock()
+	q.hosts = append(q.hosts, host)
+	q.mu.Unlock()
+	q.signal()
+}
+
+// close tells no more host is coming
+func (q *hostQueue) close() {
+	q.mu.Lock()
+	q.closed = true
+	q.mu.Unlock()
+	q.signal()
+}
+
+// pop returns the next host, it returns false once the queue is closed
+// and drained or the context is cancelled
+func (q *hostQueue) pop(ctx context.Context) (string, bool) {
+	for {
+		q.mu.Lock()
+		if len(q.hosts) > 0 {
+			host := q.hosts[0]
+			q.hosts = q.hosts[1:]
+			q.mu.Unlock()
+			return host, true
+		}
+		closed := q.closed
+		q.mu.Unlock()
+		if closed {
+			return "", false
+		}
+		select {
+		case <-q.ready:
+		case <-ctx.Done():
+			return "", false
+		}
+	}
+}
+
+// probeHosts probes the hosts of the queue for web servers, -t at a
+// time, and queues the base urls found. Without -web-probe-ports the
+// default website of every host is probed.
+func probeHosts(ctx context.Context, o *libgobuster.Options, prober *libgobuster.WebProber, hosts, bases *hostQueue) {
+	defer bases.close()
+	threads := o.Threads
+	if threads < 1 {
+		threads = 1
+	}
+	sem := make(chan struct{}, threads)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		host, ok := hosts.pop(ctx)
+		if !ok {
+			return
+		}
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(host string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if len(o.WebProbePortsParsed) > 0 {
+				for _, base := range prober.WebPorts(host) {
+					bases.push(base)
+				}
+				return
+			}
+			base, err := prober.WebServer(host)
+			if err != nil {
+				if o.Verbose {
+					log.Printf("[!] %v", err)
+				}
+				return
+			}
+			if !o.Quiet {
+				log.Printf("[+] %s serves a website", host)
+			}
+			bases.push(base)
+		}(host)
+	}
+}
+
+// runDNSDir enumerates subdomains and runs dir mode with the same
+// wordlist against every one of them serving a website. The subdomains
+// are probed and scanned while the dns scan goes on, the dir scans one
+// after the other. It returns the number of matches over all scans.
+func runDNSDir(ctx context.Context, o *libgobuster.Options) (int, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	// the probes of every host share one client
+	prober, err := libgobuster.NewWebProber(ctx, o)
+	if err != nil {
+		return 0, err
+	}
+
+	hosts, bases := newHostQueue(), newHostQueue()
+	dnsOpts := *o
+	dnsOpts.Mode = libgobuster.ModeDNS
+	dnsOpts.FoundHost = hosts.push
+	var dnsScan *libgobuster.Gobuster
+	var dnsErr error
+	dnsDone := make(chan struct{})
+	go func() {
+		defer close(dnsDone)
+		defer hosts.close()
+		dnsScan, dnsErr = runScan(ctx, &dnsOpts)
+		// the subdomains found before a timeout are still scanned
+		if stopsRun(dnsErr) {
+			cancel()
+		}
+	}()
+	go probeHosts(ctx, o, prober, hosts, bases)
+
+	// the number of targets is only known once the dns scan is over
+	progress.SetTotal(0)
+	matches := 0
+	var found []string
+	var dirErr error
+	for {
+		base, ok := bases.pop(ctx)
+		if !ok {
+			break
+		}
+		found = append(found, base)
+		if !o.Quiet {
+			log.Printf("[+] Starting dir mode against %s", base)
+			ruler()
+		}
+		n, err := runDirScan(ctx, o, base)
+		matches += n
+		// a host the scan could not be created for is skipped
+		if _, ok := err.(*newScanError); ok {
+			log.Printf("[!] Skipping %s: %v", base, err)
+			continue
+		}
+		if stopsRun(err) {
+			dirErr = err
+			cancel()
+			break
+		}
+	}
+	<-dnsDone
+
+	if dnsScan == nil {
+		return matches, dnsErr
+	}
+	matches += dnsScan.MatchCount()
+	if len(o.WebProbePortsParsed) > 0 {
+		if !o.Quiet {
+			log.Printf("[+] %d web servers found on the ports %s of %d subdomains", len(found), o.WebProbePorts, len(dnsScan.FoundHosts()))
+		}
+		if err := dnsScan.WriteWebProbes(found); err != nil {
+			log.Printf("[!] %v", err)
+		}
+	}
+	if stopsRun(dnsErr) {
+		return matches, dnsErr
+	}
+	return matches, dirErr
+}
+
+// runTargets runs dir mode against every url of the target urls file
+// and every host of the cidr range
+func runTargets(ctx context.Context, o *libgobuster.Options) (int, error) {
+	var targets []string
+	tags := make(map[string][]string)
+	if o.TargetUrls != "" {
+		f, err := os.Open(o.TargetUrls)
+		if err != nil {
+			return 0, fail(exitOptionsError, "[!] Target urls (-targeturls): %v", err)
+		}
+		defer f.Close()
+
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			// Skip "comment" (starts with #), as well as empty lines
+			if strings.HasPrefix(line, "#") || len(line) == 0 {
+				continue
+			}
+			target, targetTags, err := libgobuster.ParseTargetLine(line)
+			if err != nil {
+				return 0, fail(exitOptionsError, "[!] Target urls (-targeturls): %v", err)
+			}
+			targets = append(targets, target)
+			if len(targetTags) > 0 {
+				tags[target] = targetTags
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return 0, fail(exitOptionsError, "[!] Target urls (-targeturls): %v", err)
+		}
+	}
+
+	if o.CIDR != "" {
+		hosts, err := libgobuster.ExpandCIDR(o)
+		if err != nil {
+			return 0, fail(exitOptionsError, "[!] CIDR (-cidr): %v", err)
+		}
+		if o.TCPCheck > 0 {
+			live := libgobuster.ReachableTargets(ctx, hosts, o.TCPCheck, o.Threads)
+			if !o.Quiet {
+				log.Printf("[+] %d of %d hosts of %s accept connections", len(live), len(hosts), o.CIDR)
+			}
+			hosts = live
+		}
+		targets = append(targets, hosts...)
+	}
+
+	progress.SetTotal(len(targets))
+	matches, skipped := 0, 0
+	totals := libgobuster.TagTotals{}
+	defer printTagTotals(o, totals)
+	for _, target := range targets {
+		if ctx.Err() != nil {
+			break
+		}
+		targetOpts := *o
+		targetOpts.Tags = tags[target]
+		// dead targets would only add thousands of timeouts to the errors
+		if o.LivenessTimeout > 0 {
+			if err := libgobuster.CheckLiveness(ctx, &targetOpts, target); err != nil {
+				if ctx.Err() != nil {
+					break
+				}
+				reason := libgobuster.SkipReason(err)
+				log.Printf("[!] Skipping %s: %s", target, reason)
+				if err := libgobuster.WriteSkippedSummary(&targetOpts, target, reason); err != nil {
+					log.Printf("[!] %v", err)
+				}
+				totals.Add(targetOpts.Tags, libgobuster.Summary{Skipped: reason})
+				progress.Skip()
+				skipped++
+				continue
+			}
+		}
+		n, err := runDirScan(ctx, &targetOpts, target)
+		matches += n
+		totals.Add(targetOpts.Tags, libgobuster.Summary{Matches: n})
+		if stopsRun(err) {
+			return matches, err
+		}
+	}
+	if skipped > 0 && !o.Quiet {
+		log.Printf("[+] Skipped %d of %d targets failing the liveness check", skipped, len(targets))
+	}
+	return matches, nil
+}
+
+// printTagTotals prints the targets and matches of every tag of a run
+// over many targets
+func printTagTotals(o *libgobuster.Options, totals libgobuster.TagTotals) {
+	if o.Quiet {
+		return
+	}
+	for _, t := range totals.Sorted() {
+		log.Printf("[+] Tag %s: %d targets, %d matches, %d skipped", t.Tag, t.Targets, t.Matches, t.Skipped)
+	}
+}
+
+// loadRun reads the matches of a run given as a matches file or as a
+// run id recorded in the all time matches of the output folder
+func loadRun(run, outputFolder, mode string) (map[string]libgobuster.Endpoint, error) {
+	if _, err := os.Stat(run); err == nil {
+		f, err := os.Open(run)
+		if err != nil {
+			return nil, fail(exitOptionsError, "[!] %v", err)
+		}
+		defer f.Close()
+		endpoints, err := libgobuster.ReadMatchesFile(f)
+		if err != nil {
+			return nil, fail(exitAborted, "[!] error on reading %s: %v", run, err)
+		}
+		return endpoints, nil
+	}
+
+	if outputFolder == "" {
+		return nil, fail(exitOptionsError, "[!] %s is not a file, the output folder (-of) is needed to look up run ids", run)
+	}
+	filename := outputFolder + "/all_time_matches.txt"
+	if mode == libgobuster.ModeDNS {
+		filename = outputFolder + "/all_time_subdomains.txt"
+	}
+	f, err := libgobuster.OpenRotated(filename)
+	if err != nil {
+		return nil, fail(exitOptionsError, "[!] %v", err)
+	}
+	defer f.Close()
+	endpoints, err := libgobuster.ReadRunMatches(f, run)
+	if err != nil {
+		return nil, fail(exitAborted, "[!] error on reading %s: %v", filename, err)
+	}
+	if len(endpoints) == 0 {
+		return nil, fail(exitOptionsError, "[!] No matches of run %s found in %s", run, filename)
+	}
+	return endpoints, nil
+}
+
+// compareCommand prints the endpoints added, removed and changed
+// between two runs
+func compareCommand(args []string) (int, error) {
+	fs := flag.NewFlagSet("compare", flag.ExitOnError)
+	outputFolder := fs.String("of", "", "Path to output folder directory, needed when comparing run ids")
+	mode := fs.String("m", libgobuster.ModeDir, "Mode of the all time matches holding the run ids (dir, dns)")
+	format := fs.String("format", "markdown", "Report format: markdown or html")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s compare [options] <matches file or run id> <matches file or run id>\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return exitOptionsError, nil
+	}
+	if fs.NArg() != 2 || (*format != "markdown" && *format != "html") {
+		fs.Usage()
+		return exitOptionsError, nil
+	}
+
+	runA, runB := fs.Arg(0), fs.Arg(1)
+	endpointsA, err := loadRun(runA, *outputFolder, *mode)
+	if err != nil {
+		return 0, err
+	}
+	endpointsB, err := loadRun(runB, *outputFolder, *mode)
+	if err != nil {
+		return 0, err
+	}
+	diff := libgobuster.CompareRuns(endpointsA, endpointsB)
+	if *format == "html" {
+		fmt.Print(diff.HTML(runA, runB))
+	} else {
+		fmt.Print(diff.Markdown(runA, runB))
+	}
+
+	if diff.Empty() {
+		return exitNoFindings, nil
+	}
+	return exitFindings, nil
+}
+
+// reportCommand prints the summaries of the runs stored in an output folder
+func reportCommand(args []string) (int, error) {
+	fs := flag.NewFlagSet("report", flag.ExitOnError)
+	outputFolder := fs.String("of", "", "Path to output folder directory")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s report [options]\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return exitOptionsError, nil
+	}
+	if *outputFolder == "" || fs.NArg() != 0 {
+		fs.Usage()
+		return exitOptionsError, nil
+	}
+
+	matches, err := printReport(*outputFolder)
+	if err != nil {
+		return 0, err
+	}
+	if matches == 0 {
+		return exitNoFindings, nil
+	}
+	return exitFindings, nil
+}
+
+// printReport prints a table of the summaries of the runs stored in an
+// output folder and returns the number of matches over all of them
+func printReport(outputFolder string) (int, error) {
+	summaries, err := libgobuster.ReadSummaries(outputFolder)
+	if err != nil {
+		return 0, fail(exitAborted, "[!] %v", err)
+	}
+	if len(summaries) == 0 {
+		fmt.Printf("No summaries found in %s\n", outputFolder)
+		return 0, nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "MODE\tTARGET\tREQUESTS\tMATCHES\tERRORS\tSTATE")
+	matches := 0
+	for _, s := range summaries {
+		state := "finished"
+		if s.Skipped != "" {
+			state = "skipped (" + s.Skipped + ")"
+		} else if s.Aborted {
+			state = "aborted"
+		} else if s.TimedOut {
+			state = "timed out"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%s\n", s.Mode, s.Target, s.Requests, s.Matches, s.Errors, state)
+		matches += s.Matches
+	}
+	w.Flush()
+
+	if tags := libgobuster.SummariesByTag(summaries); len(tags) > 0 {
+		fmt.Println()
+		fmt.Fprintln(w, "TAG\tTARGETS\tMATCHES\tERRORS\tSKIPPED")
+		for _, t := range tags {
+			fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\n", t.Tag, t.Targets, t.Matches, t.Errors, t.Skipped)
+		}
+		w.Flush()
+	}
+
+	if triage, err := libgobuster.ReadTriage(outputFolder); err != nil {
+		log.Printf("[!] %v", err)
+	} else if len(triage) > 0 {
+		counts := libgobuster.TriageCounts(triage)
+		var parts []string
+		for _, status := range libgobuster.TriageStatuses {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[status], status))
+		}
+		fmt.Printf("Triage: %s\n", strings.Join(parts, ", "))
+	}
+	return matches, nil
+}
+
+// mergeCommand merges the output folders of several runs, from agents
+// or split wordlists, into one and prints the report of the result
+func mergeCommand(args []string) (int, error) {
+	fs := flag.NewFlagSet("merge", flag.ExitOnError)
+	outputFolder := fs.String("of", "", "Path to the output folder the others are merged into, created if needed")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s merge [options] <output folder>...\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return exitOptionsError, nil
+	}
+	if *outputFolder == "" || fs.NArg() == 0 {
+		fs.Usage()
+		return exitOptionsError, nil
+	}
+
+	stats, err := libgobuster.MergeOutputFolders(*outputFolder, fs.Args())
+	if err != nil {
+		return 0, fail(exitAborted, "[!] %v", err)
+	}
+	log.Printf("[+] Merged %d folders into %s: %d all time and shared lines and %d files added, matches combined in merged_matches.txt", fs.NArg(), *outputFolder, stats.Lines, stats.Files)
+	for _, c := range stats.Conflicts {
+		log.Printf("[!] %s differs between the folders, the first one was kept", c)
+	}
+
+	matches, err := printReport(*outputFolder)
+	if err != nil {
+		return 0, err
+	}
+	if matches == 0 {
+		return exitNoFindings, nil
+	}
+	return exitFindings, nil
+}
+
+// triageCommand marks matches of an output folder with a status and
+// notes, or lists their triage when neither is given
+func triageCommand(args []string) (int, error) {
+	fs := flag.NewFlagSet("triage", flag.ExitOnError)
+	outputFolder := fs.String("of", "", "Path to output folder directory")
+	status := fs.String("status", "", "Status of the findings: "+strings.Join(libgobuster.TriageStatuses, ", "))
+	note := fs.String("note", "", "Note added to the findings")
+	author := fs.String("author", os.Getenv("USER"), "Author of the status and note")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s triage [options] [finding]...\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return exitOptionsError, nil
+	}
+	if *outputFolder == "" {
+		fs.Usage()
+		return exitOptionsError, nil
+	}
+
+	if *status != "" || *note != "" {
+		if fs.NArg() == 0 {
+			fs.Usage()
+			return exitOptionsError, nil
+		}
+		if *status != "" {
+			s, err := libgobuster.ParseTriageStatus(*status)
+			if err != nil {
+				return 0, fail(exitOptionsError, "[!] %v", err)
+			}
+			*status = s
+		}
+		// only the matches of the folder can be triaged
+		findings, err := libgobuster.RecordedFindings(*outputFolder, fs.Args())
+		if err != nil {
+			return 0, fail(exitOptionsError, "[!] %v", err)
+		}
+		now := time.Now()
+		var entries []libgobuster.TriageEntry
+		for _, finding := range findings {
+			entries = append(entries, libgobuster.TriageEntry{Finding: finding, Status: *status, Note: *note, Author: *author, Time: now})
+		}
+		if err := libgobuster.AppendTriage(*outputFolder, entries); err != nil {
+			return 0, fail(exitAborted, "[!] %v", err)
+		}
+		log.Printf("[+] Triaged %d findings in %s", len(entries), *outputFolder)
+		return exitFindings, nil
+	}
+
+	triage, err := libgobuster.ReadTriage(*outputFolder)
+	if err != nil {
+		return 0, fail(exitAborted, "[!] %v", err)
+	}
+	wanted := make(map[string]bool)
+	for _, finding := range fs.Args() {
+		wanted[libgobuster.TriageFinding(finding)] = true
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "FINDING\tSTATUS\tUPDATED\tNOTES")
+	listed := 0
+	for _, t := range triage {
+		if len(wanted) > 0 && !wanted[t.Finding] {
+			continue
+		}
+		var notes []string
+		for _, n := range t.Notes {
+			if n.Author != "" {
+				notes = append(notes, n.Author+": "+n.Note)
+			} else {
+				notes = append(notes, n.Note)
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", t.Finding, t.Status, t.Updated.Format("2006-01-02 15:04:05"), strings.Join(notes, "; "))
+		listed++
+	}
+	if listed == 0 {
+		fmt.Printf("No triaged findings in %s\n", *outputFolder)
+		return exitNoFindings, nil
+	}
+	w.Flush()
+	return exitFindings, nil
+}
+
+func main() {
+	code, err := run()
+	if e, ok := err.(*exitError); ok {
+		code = e.code
+	} else if err != nil {
+		code = exitAborted
+	}
+	if err != nil && err.Error() != "" {
+		log.Print(err)
+	}
+	os.Exit(code)
+}
+
+// run runs the command of the arguments and returns its exit code, the
+// errors ending it early carry theirs
+func run() (int, error) {
+	o := libgobuster.NewOptions()
+	if len(os.Args) > 1 && !strings.HasPrefix(os.Args[1], "-") {
+		switch command := os.Args[1]; command {
+		case "history":
+			return historyCommand(os.Args[2:])
+		case "report":
+			return reportCommand(os.Args[2:])
+		case "compare":
+			return compareCommand(os.Args[2:])
+		case "merge":
+			return mergeCommand(os.Args[2:])
+		case "triage":
+			return triageCommand(os.Args[2:])
+		case "completion":
+			return completionCommand(os.Args[2:])
+		case libgobuster.ModeDir, libgobuster.ModeDNS, libgobuster.ModeDNSDir:
+			if err := parseScanFlags(command, os.Args[2:], o); err != nil {
+				return 0, err
+			}
+		default:
+			fmt.Fprintf(os.Stderr, "Unknown command %q\n\n", command)
+			usage()
+			return exitOptionsError, nil
+		}
+	} else if err := parseLegacyFlags(o); err != nil {
+		return 0, err
+	}
+
+	if err := o.ResolveSecrets(); err != nil {
+		return 0, fail(exitOptionsError, "[!] %v", err)
+	}
+
+	// Prompt for PW if not provided
+	if o.Username != "" && o.Password == "" {
+		fmt.Printf("[?] Auth Password: ")
+		passBytes, err := terminal.ReadPassword(int(syscall.Stdin))
+		// print a newline to simulate the newline that was entered
+		// this means that formatting/printing after doesn't look bad.
+		fmt.Println("")
+		if err != nil {
+			return 0, fail(exitOptionsError, "[!] Auth username given but reading of password failed")
+		}
+		o.Password = string(passBytes)
+	}
+
+	multiTarget := o.Mode == libgobuster.ModeDNSDir || (o.Mode == libgobuster.ModeDir && (o.TargetUrls != "" || o.CIDR != ""))
+	if multiTarget {
+		if o.Wordlist == "-" {
+			return 0, fail(exitOptionsError, "[!] WordList (-w): Can not be read from stdin when scanning many targets")
+		}
+		if o.OutputFilename != "" {
+			return 0, fail(exitOptionsError, "[!] Output file (-o): Can not be used when scanning many targets")
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	defer libgobuster.CloseResultStreams()
+
+	// under nohup or CI the output ends up in log files, keep it plain.
+	// Results go to stdout and the progress and errors to stderr, either
+	// one being redirected is enough.
+	plainOutput := !isTerminal(os.Stdout) || !isTerminal(os.Stderr)
+	// legacy Windows consoles print the escape sequences of the colors
+	if !libgobuster.SetupConsole() || plainOutput {
+		color.Disable()
+	}
+
+	if !o.Quiet && !plainOutput {
+		fmt.Println("")
+		ruler()
+		banner()
+		ruler()
+	}
+
+	progress = libgobuster.NewProgressRenderer(os.Stderr, 1)
+
+	o.Pause = libgobuster.NewPauseGate()
+	handleControlSignals(o.Pause)
+
+	// the PAC file is loaded once for the targets of a run
+	if o.PACURL != "" || o.PACFile != "" {
+		pac, err := libgobuster.LoadPAC(o.PACFile, o.PACURL, o.Timeout)
+		if err != nil {
+			return 0, fail(exitOptionsError, "[!] %v", err)
+		}
+		o.PAC = pac
+	}
+
+	// the targets of a run look their hosts up once
+	if o.DNSCacheTTL > 0 {
+		o.DNSCache = libgobuster.NewDNSCache(o)
+	}
+
+	var interrupted int32
+	signalChan := make(chan os.Signal, 1)
+	signal.Notify(signalChan, os.Interrupt)
+	go func() {
+		for range signalChan {
+			// caught CTRL+C
+			if !o.Quiet {
+				fmt.Println("\n[!] Keyboard interrupt detected, terminating.")
+			}
+			atomic.StoreInt32(&interrupted, 1)
+			cancel()
+		}
+	}()
+
+	var matches int
+	var err error
+	if o.Mode == libgobuster.ModeDNSDir {
+		matches, err = runDNSDir(ctx, o)
+	} else if multiTarget {
+		matches, err = runTargets(ctx, o)
+	} else {
+		var gobuster *libgobuster.Gobuster
+		gobuster, err = runScan(ctx, o)
+		if gobuster != nil {
+			matches = gobuster.MatchCount()
+		}
+		if o.Mode == libgobuster.ModeDNS && len(o.WebProbePortsParsed) > 0 && !stopsRun(err) {
+			probeWebPorts(ctx, gobuster)
+		}
+	}
+	switch err.(type) {
+	case *newScanError:
+		return 0, fail(exitOptionsError, "[!] %v", err)
+	case *exitError:
+		return 0, err
+	}
+
+	exitCode := exitFindings
+	if err != nil {
+		exitCode = exitAborted
+	} else if atomic.LoadInt32(&interrupted) == 1 {
+		exitCode = exitAborted
+	} else if matches == 0 {
+		exitCode = exitNoFindings
+	}
+	return exitCode, nil
+}
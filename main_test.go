@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/gosirys/gobuster/libgobuster"
+)
+
+func TestHostQueue(t *testing.T) {
+	t.Parallel()
+
+	q := newHostQueue()
+	got := make(chan []string)
+	go func() {
+		var hosts []string
+		for {
+			host, ok := q.pop(context.Background())
+			if !ok {
+				got <- hosts
+				return
+			}
+			hosts = append(hosts, host)
+		}
+	}()
+	for _, host := range []string{"a.example.com", "b.example.com", "c.example.com"} {
+		q.push(host)
+	}
+	q.close()
+	if hosts := <-got; len(hosts) != 3 || hosts[0] != "a.example.com" || hosts[2] != "c.example.com" {
+		t.Fatalf("expected the hosts in order, got %v", hosts)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, ok := newHostQueue().pop(ctx); ok {
+		t.Fatal("expected no host once cancelled")
+	}
+}
+
+func TestProbeHosts(t *testing.T) {
+	t.Parallel()
+
+	h := httptest.NewServer(http.NotFoundHandler())
+	defer h.Close()
+	u, _ := url.Parse(h.URL)
+	port, _ := strconv.Atoi(u.Port())
+
+	o := libgobuster.NewOptions()
+	o.Quiet = true
+	o.WebProbePortsParsed = []int{port}
+	prober, err := libgobuster.NewWebProber(context.Background(), o)
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	hosts, bases := newHostQueue(), newHostQueue()
+	hosts.push("127.0.0.1")
+	hosts.push("127.0.0.2")
+	hosts.close()
+	probeHosts(context.Background(), o, prober, hosts, bases)
+
+	var got []string
+	for {
+		base, ok := bases.pop(context.Background())
+		if !ok {
+			break
+		}
+		got = append(got, base)
+	}
+	sort.Strings(got)
+	expected := "http://127.0.0.1:" + u.Port() + "/"
+	if len(got) != 1 || got[0] != expected {
+		t.Fatalf("expected %s, got %v", expected, got)
+	}
+}
@@ -0,0 +1,35 @@
+//go:build !windows
+// +build !windows
+
+package main
+
+import (
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/gosirys/gobuster/libgobuster"
+)
+
+// handleControlSignals logs the state of the run on SIGUSR1 and pauses
+// or resumes its workers on SIGUSR2
+func handleControlSignals(pause *libgobuster.PauseGate) {
+	signalChan := make(chan os.Signal, 1)
+	signal.Notify(signalChan, syscall.SIGUSR1, syscall.SIGUSR2)
+	go func() {
+		for s := range signalChan {
+			progress.Clear()
+			switch s {
+			case syscall.SIGUSR1:
+				log.Printf("[+] State: %s", progress.State())
+			case syscall.SIGUSR2:
+				if pause.Toggle() {
+					log.Printf("[!] Paused, send SIGUSR2 again to resume")
+				} else {
+					log.Printf("[+] Resumed")
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,9 @@
+//go:build windows
+// +build windows
+
+package main
+
+import "github.com/gosirys/gobuster/libgobuster"
+
+// handleControlSignals does nothing, Windows has no SIGUSR1 and SIGUSR2
+func handleControlSignals(pause *libgobuster.PauseGate) {}